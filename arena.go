@@ -0,0 +1,111 @@
+package sudoku
+
+import "math/rand/v2"
+
+// gridArena carves Grid cell storage out of large shared buffers, so mass
+// generation reuses the same memory for every puzzle's scratch grids instead of
+// allocating fresh rows on each clone. Grids taken from an arena stay valid only
+// until the next Reset.
+type gridArena struct {
+	ints []int
+	rows [][]int
+}
+
+// newGridArena returns an arena with room for cells ints up front; it grows (by
+// doubling) when a batch needs more.
+func newGridArena(cells int) *gridArena {
+	return &gridArena{ints: make([]int, 0, cells), rows: make([][]int, 0, cells)}
+}
+
+// Reset releases every grid handed out so far and keeps the buffers for reuse.
+func (a *gridArena) Reset() {
+	a.ints = a.ints[:0]
+	a.rows = a.rows[:0]
+}
+
+func (a *gridArena) allocInts(n int) []int {
+	if len(a.ints)+n > cap(a.ints) {
+		a.ints = make([]int, 0, max(2*cap(a.ints), n))
+	}
+	start := len(a.ints)
+	a.ints = a.ints[:start+n]
+	out := a.ints[start : start+n : start+n]
+	clear(out)
+	return out
+}
+
+func (a *gridArena) allocRows(n int) [][]int {
+	if len(a.rows)+n > cap(a.rows) {
+		a.rows = make([][]int, 0, max(2*cap(a.rows), n))
+	}
+	start := len(a.rows)
+	a.rows = a.rows[:start+n]
+	return a.rows[start : start+n : start+n]
+}
+
+// matrix returns a size x size matrix whose rows share one arena block.
+func (a *gridArena) matrix(size int) [][]int {
+	rows := a.allocRows(size)
+	block := a.allocInts(size * size)
+	for r := range rows {
+		rows[r] = block[r*size : (r+1)*size : (r+1)*size]
+	}
+	return rows
+}
+
+// clone is Grid.Clone with cells and regions stored in the arena.
+func (a *gridArena) clone(g Grid) Grid {
+	out := Grid{Size: g.Size, BoxRows: g.BoxRows, BoxCols: g.BoxCols, Cells: a.matrix(g.Size)}
+	for r := 0; r < g.Size; r++ {
+		copy(out.Cells[r], g.Cells[r])
+	}
+	if g.Regions != nil {
+		out.Regions = a.matrix(g.Size)
+		for r := 0; r < g.Size; r++ {
+			copy(out.Regions[r], g.Regions[r])
+		}
+	}
+	if g.Constraints != nil {
+		out.Constraints = append([]Constraint(nil), g.Constraints...)
+	}
+	return out
+}
+
+// GenerateBatch creates n puzzles with the dimensions (and regions) of g.
+// Concurrent calls are safe, as for Generate; the whole batch holds the
+// default Generator.
+func GenerateBatch(g Grid, d Difficulty, n, attempts int) ([]Grid, error) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return defaultGenerator().GenerateBatch(g, d, n, attempts)
+}
+
+// GenerateBatch creates n puzzles with the dimensions (and regions) of g. The
+// output is identical to n successive GenerateGrid calls, but scratch grids come
+// from a single arena reset between puzzles and the results share one backing
+// buffer, which keeps GC pressure flat when producing large datasets.
+func (gen *Generator) GenerateBatch(g Grid, d Difficulty, n, attempts int) ([]Grid, error) {
+	return generateBatch(gen.rng, g, d, n, attempts)
+}
+
+func generateBatch(rng *rand.Rand, g Grid, d Difficulty, n, attempts int) ([]Grid, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	cells := g.Size * g.Size
+	if g.Regions != nil {
+		cells *= 2
+	}
+	scratch := newGridArena(4 * cells) // solved, puzzle and uniqueness work copies
+	results := newGridArena(n * cells)
+	out := make([]Grid, 0, n)
+	for i := 0; i < n; i++ {
+		p, err := g.generateIn(rng, d, attempts, genOptions{arena: scratch, profile: DefaultProfile(g.Size)})
+		if err != nil {
+			return out, err
+		}
+		out = append(out, results.clone(p))
+		scratch.Reset()
+	}
+	return out, nil
+}
@@ -0,0 +1,42 @@
+package sudoku
+
+import "testing"
+
+func TestGenerateBatchMatchesGenerateGrid(t *testing.T) {
+	g, _ := NewGrid(6, 2, 3)
+	batch, err := NewGenerator(5).GenerateBatch(g, Medium, 4, 3)
+	if err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	if len(batch) != 4 {
+		t.Fatalf("want 4 puzzles, got %d", len(batch))
+	}
+	gen := NewGenerator(5)
+	for i, p := range batch {
+		want, err := gen.GenerateGrid(g, Medium, 3)
+		if err != nil {
+			t.Fatalf("generate %d: %v", i, err)
+		}
+		if p.String() != want.String() {
+			t.Fatalf("puzzle %d:\n got %s\nwant %s", i, p, want)
+		}
+		if p.CountSolutions(2) != 1 {
+			t.Fatalf("puzzle %d not unique", i)
+		}
+	}
+}
+
+func TestGridArenaReset(t *testing.T) {
+	g, _ := NewGrid(4, 2, 2)
+	a := newGridArena(16)
+	x := a.clone(g)
+	x.Cells[1][1] = 3
+	a.Reset()
+	y := a.clone(g)
+	if y.Cells[1][1] != 0 {
+		t.Fatalf("arena clone after Reset not cleared")
+	}
+	if &x.Cells[0][0] != &y.Cells[0][0] {
+		t.Fatalf("Reset did not reuse the buffer")
+	}
+}
@@ -0,0 +1,196 @@
+package sudoku
+
+import "context"
+
+// bestEffortCheckEvery is the number of search nodes between context checks.
+const bestEffortCheckEvery = 1024
+
+// SolveBestEffort solves b within the lifetime of ctx, returning whatever could be
+// deduced when the deadline hits instead of nothing.
+//
+// Cells are first filled by logical deduction (naked and hidden singles). If that
+// stalls, a backtracking search continues from the deduced state. The returned board
+// always contains the givens plus every logically forced cell; it is only replaced by
+// a full solution when the search finishes in time. solvedCells is the number of
+// filled cells in partial (givens included) and done reports a complete solution.
+// Invalid or contradictory boards return the deduced partial with done == false.
+func SolveBestEffort(ctx context.Context, b Board) (partial Board, solvedCells int, done bool) {
+	partial = b
+	if err := Validate(b); err != nil {
+		return partial, countClues(partial), false
+	}
+	for ctx.Err() == nil {
+		progress, ok := applySingles(&partial)
+		if !ok {
+			return partial, countClues(partial), false
+		}
+		if !progress {
+			break
+		}
+	}
+	if countClues(partial) == 81 {
+		return partial, 81, true
+	}
+	if ctx.Err() != nil {
+		return partial, countClues(partial), false
+	}
+	work := partial
+	s := &ctxSearch{ctx: ctx}
+	if s.solve(&work) {
+		return work, 81, true
+	}
+	return partial, countClues(partial), false
+}
+
+// candidateMask returns the bitmask (bit v set for value v) of values allowed at r,c.
+func candidateMask(b *Board, r, c int) uint16 {
+	var used uint16
+	for i := 0; i < 9; i++ {
+		used |= 1 << b[r][i]
+		used |= 1 << b[i][c]
+	}
+	br, bc := (r/3)*3, (c/3)*3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			used |= 1 << b[br+i][bc+j]
+		}
+	}
+	return ^used & 0x3fe
+}
+
+// applySingles runs one pass of naked and hidden singles over b. It reports whether
+// any cell was filled and false for ok when a contradiction was found.
+func applySingles(b *Board) (progress, ok bool) {
+	// naked singles
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if b[r][c] != 0 {
+				continue
+			}
+			m := candidateMask(b, r, c)
+			if m == 0 {
+				return progress, false
+			}
+			if m&(m-1) == 0 {
+				b[r][c] = maskValue(m)
+				progress = true
+			}
+		}
+	}
+	// hidden singles per unit
+	for u := 0; u < 27; u++ {
+		cells := unitCells(u)
+		for v := 1; v <= 9; v++ {
+			placed := false
+			spot, spots := -1, 0
+			for i, rc := range cells {
+				if b[rc[0]][rc[1]] == v {
+					placed = true
+					break
+				}
+				if b[rc[0]][rc[1]] == 0 && candidateMask(b, rc[0], rc[1])&(1<<v) != 0 {
+					spot = i
+					spots++
+				}
+			}
+			if placed {
+				continue
+			}
+			if spots == 0 {
+				return progress, false
+			}
+			if spots == 1 {
+				b[cells[spot][0]][cells[spot][1]] = v
+				progress = true
+			}
+		}
+	}
+	return progress, true
+}
+
+// unitCells lists the cells of unit u: rows 0-8, columns 9-17, boxes 18-26.
+func unitCells(u int) [9][2]int {
+	var out [9][2]int
+	for i := 0; i < 9; i++ {
+		switch {
+		case u < 9:
+			out[i] = [2]int{u, i}
+		case u < 18:
+			out[i] = [2]int{i, u - 9}
+		default:
+			bx := u - 18
+			out[i] = [2]int{(bx/3)*3 + i/3, (bx%3)*3 + i%3}
+		}
+	}
+	return out
+}
+
+func maskValue(m uint16) int {
+	for v := 1; v <= 9; v++ {
+		if m == 1<<v {
+			return v
+		}
+	}
+	return 0
+}
+
+// maskValues lists the values set in m in increasing order.
+func maskValues(m uint16) []int {
+	var out []int
+	for v := 1; v <= 9; v++ {
+		if m&(1<<v) != 0 {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// ctxSearch is a plain DFS that gives up once ctx is done, checking it every
+// bestEffortCheckEvery nodes. It remembers the fullest board it reached so a
+// cancelled search can still report how far it got.
+type ctxSearch struct {
+	ctx        context.Context
+	nodes      int
+	stopped    bool
+	filled     int // filled cells on the board being searched
+	best       Board
+	bestFilled int
+	depth      int // current recursion depth
+	maxDepth   int
+	backtracks int // values tried and undone
+}
+
+func (s *ctxSearch) solve(b *Board) bool {
+	s.nodes++
+	if s.nodes%bestEffortCheckEvery == 0 && s.ctx.Err() != nil {
+		s.stopped = true
+	}
+	if s.stopped {
+		return false
+	}
+	if s.filled > s.bestFilled {
+		s.best, s.bestFilled = *b, s.filled
+	}
+	s.maxDepth = max(s.maxDepth, s.depth)
+	r, c, ok := findEmpty(b)
+	if !ok {
+		return true
+	}
+	m := candidateMask(b, r, c)
+	for v := 1; v <= 9 && !s.stopped; v++ {
+		if m&(1<<v) == 0 {
+			continue
+		}
+		b[r][c] = v
+		s.filled++
+		s.depth++
+		if s.solve(b) {
+			return true
+		}
+		s.depth--
+		b[r][c] = 0
+		s.filled--
+		s.backtracks++
+	}
+	return false
+}
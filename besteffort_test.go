@@ -0,0 +1,47 @@
+package sudoku
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSolveBestEffortSolves(t *testing.T) {
+	b, err := FromString(easyPuzzle)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	sol, n, done := SolveBestEffort(context.Background(), b)
+	if !done || n != 81 {
+		t.Fatalf("expected full solve, got done=%v n=%d", done, n)
+	}
+	if err := Validate(sol); err != nil || countClues(sol) != 81 {
+		t.Fatalf("invalid solution: %v", err)
+	}
+}
+
+func TestSolveBestEffortCancelled(t *testing.T) {
+	// Empty board needs search beyond singles; a cancelled context must return
+	// the (unchanged) deduced partial rather than a solution.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var b Board
+	b[0][0] = 5
+	partial, n, done := SolveBestEffort(ctx, b)
+	if done {
+		t.Fatalf("expected incomplete result on cancelled context")
+	}
+	if partial != b || n != 1 {
+		t.Fatalf("expected givens only, got n=%d", n)
+	}
+}
+
+func TestSolveBestEffortContradiction(t *testing.T) {
+	var b Board
+	for c := 1; c < 9; c++ {
+		b[0][c] = c
+	}
+	b[1][0] = 9
+	if _, _, done := SolveBestEffort(context.Background(), b); done {
+		t.Fatalf("expected unsolvable board to report done=false")
+	}
+}
@@ -0,0 +1,125 @@
+package sudoku
+
+// Canonical returns the representative of b's isomorphism class: the
+// lexicographically smallest String form over every transposition, band and stack
+// permutation, row and column permutation within them, and relabeling of digits
+// (labels are assigned in order of first appearance). Two boards are isomorphic
+// exactly when their canonical forms are equal. Empty cells stay empty, so the
+// form applies to puzzles as well as solutions.
+func Canonical(b Board) Board {
+	cs := &canonSearch{}
+	for _, src := range [2]Board{b, Transpose(b)} {
+		for _, cols := range lineOrders() {
+			for r := 0; r < 9; r++ {
+				for c := 0; c < 9; c++ {
+					cs.src[r][c] = src[r][cols[c]]
+				}
+			}
+			cs.rows(0, [10]int{}, 1, false, cs.gen)
+		}
+	}
+	return cs.best
+}
+
+// canonSearch picks rows of src one at a time (keeping bands together) and prunes
+// any prefix that is already larger than the best board found so far.
+type canonSearch struct {
+	src      Board // current transposition with columns already reordered
+	out      Board
+	best     Board
+	haveBest bool
+	gen      int // bumped whenever best changes
+	usedRow  [9]bool
+	usedBand [3]bool
+	bandOf   [3]int // source band of each output band
+}
+
+// rows fills output row i onwards. less reports that out's rows before i are
+// smaller than best's; it is stale (the prefixes are equal) once gen moved on,
+// because best can only have changed to a completion of the current prefix.
+func (cs *canonSearch) rows(i int, label [10]int, next int, less bool, gen int) {
+	if i == 9 {
+		cs.best, cs.haveBest = cs.out, true
+		cs.gen++
+		return
+	}
+	band := i / 3
+	for sr := 0; sr < 9; sr++ {
+		if cs.usedRow[sr] || (i%3 == 0 && cs.usedBand[sr/3]) || (i%3 != 0 && sr/3 != cs.bandOf[band]) {
+			continue
+		}
+		l, n := label, next
+		for c := 0; c < 9; c++ {
+			v := cs.src[sr][c]
+			if v != 0 {
+				if l[v] == 0 {
+					l[v] = n
+					n++
+				}
+				v = l[v]
+			}
+			cs.out[i][c] = v
+		}
+		rowLess := !cs.haveBest || (less && gen == cs.gen)
+		if !rowLess {
+			cmp := compareRows(&cs.out[i], &cs.best[i])
+			if cmp > 0 {
+				continue
+			}
+			rowLess = cmp < 0
+		}
+		cs.usedRow[sr] = true
+		if i%3 == 0 {
+			cs.usedBand[sr/3], cs.bandOf[band] = true, sr/3
+		}
+		cs.rows(i+1, l, n, rowLess, cs.gen)
+		cs.usedRow[sr] = false
+		if i%3 == 0 {
+			cs.usedBand[sr/3] = false
+		}
+	}
+}
+
+func compareRows(a, b *[9]int) int {
+	for c := 0; c < 9; c++ {
+		if a[c] != b[c] {
+			if a[c] < b[c] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// Isomorphic reports whether a can be turned into b by the transformations
+// Canonical considers.
+func Isomorphic(a, b Board) bool {
+	if countClues(a) != countClues(b) {
+		return false
+	}
+	return Canonical(a) == Canonical(b)
+}
+
+// lineOrders lists the 1296 orders of the nine rows (or columns) that keep bands
+// together: 3! band orders times 3! orders within each band.
+func lineOrders() [][9]int {
+	perms := [6][3]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}
+	out := make([][9]int, 0, 1296)
+	for _, bands := range perms {
+		for _, p0 := range perms {
+			for _, p1 := range perms {
+				for _, p2 := range perms {
+					var o [9]int
+					for i, in := range [3][3]int{p0, p1, p2} {
+						for j := 0; j < 3; j++ {
+							o[i*3+j] = bands[i]*3 + in[j]
+						}
+					}
+					out = append(out, o)
+				}
+			}
+		}
+	}
+	return out
+}
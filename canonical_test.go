@@ -0,0 +1,107 @@
+package sudoku
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// randomIsomorph applies a random mix of the transformations Canonical covers.
+func randomIsomorph(t *testing.T, rng *rand.Rand, b Board) Board {
+	t.Helper()
+	if rng.IntN(2) == 1 {
+		b = Transpose(b)
+	}
+	var perm [9]int
+	for i, v := range rng.Perm(9) {
+		perm[i] = v + 1
+	}
+	b, _ = Relabel(b, perm)
+	for i := 0; i < 20; i++ {
+		x, y := rng.IntN(3), rng.IntN(3)
+		band := rng.IntN(3) * 3
+		var err error
+		switch rng.IntN(4) {
+		case 0:
+			b, err = SwapRows(b, band+x, band+y)
+		case 1:
+			b, err = SwapColumns(b, band+x, band+y)
+		case 2:
+			b, err = SwapBands(b, x, y)
+		default:
+			b, err = SwapStacks(b, x, y)
+		}
+		if err != nil {
+			t.Fatalf("transform: %v", err)
+		}
+	}
+	return b
+}
+
+func TestCanonicalInvariant(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for _, s := range []string{
+		easyPuzzle,
+		"534678912672195348198342567859761423426853791713924856961537284287419635345286179",
+	} {
+		b, _ := FromString(s)
+		want := Canonical(b)
+		if Validate(want) != nil || countClues(want) != countClues(b) {
+			t.Fatalf("canonical form is not an equivalent board:\n%s", want)
+		}
+		for i := 0; i < 25; i++ {
+			x := randomIsomorph(t, rng, b)
+			if got := Canonical(x); got != want {
+				t.Fatalf("canonical form differs for an isomorph:\n%s\n%s", got, want)
+			}
+			if !Isomorphic(b, x) {
+				t.Fatalf("isomorph not detected")
+			}
+		}
+		if got := Canonical(Rotate(b)); got != want {
+			t.Fatalf("rotation changed the canonical form")
+		}
+		if PuzzleID(ReflectVertical(b)) != PuzzleID(b) {
+			t.Fatalf("isomorphic puzzles got different IDs")
+		}
+	}
+}
+
+func TestCanonicalIsSmallest(t *testing.T) {
+	b, _ := FromString(easyPuzzle)
+	got := Canonical(b).String()
+	// brute force over rows and columns without transposition
+	orders := lineOrders()
+	for _, rows := range orders {
+		for _, cols := range orders {
+			var label [10]int
+			next := 1
+			buf := make([]byte, 0, 81)
+			for r := 0; r < 9; r++ {
+				for c := 0; c < 9; c++ {
+					v := b[rows[r]][cols[c]]
+					if v != 0 && label[v] == 0 {
+						label[v] = next
+						next++
+					}
+					buf = append(buf, byte('0'+label[v]))
+				}
+			}
+			if string(buf) < got {
+				t.Fatalf("found smaller form %s than %s", buf, got)
+			}
+		}
+	}
+}
+
+func TestIsomorphicDistinguishes(t *testing.T) {
+	a, _ := FromString(easyPuzzle)
+	b, _ := FromString("239700500010900320050020108001549273000000001020080000170000000090057600002100080")
+	if Isomorphic(a, b) {
+		t.Fatalf("unrelated puzzles reported isomorphic")
+	}
+	c := a
+	c[0][0] = 0
+	if Isomorphic(a, c) {
+		t.Fatalf("puzzles with different clue counts reported isomorphic")
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"iter"
+	"sync"
+
+	"go.rumenx.com/sudoku"
+)
+
+// sdmEntry is one line of an .sdm collection: a board or its parse error.
+type sdmEntry struct {
+	board sudoku.Board
+	err   error
+}
+
+// readEntries streams the puzzles of r with sudoku.ReadSDM. Bad lines are
+// yielded as entries; a read error ends the sequence and is stored in *readErr
+// once the sequence is exhausted.
+func readEntries(r io.Reader, readErr *error) iter.Seq[sdmEntry] {
+	src := &failReader{r: r}
+	return func(yield func(sdmEntry) bool) {
+		for b, err := range sudoku.ReadSDM(src) {
+			if err != nil && src.err != nil && errors.Is(err, src.err) {
+				*readErr = err // I/O failure, not a bad puzzle
+				return
+			}
+			if !yield(sdmEntry{b, err}) {
+				return
+			}
+		}
+	}
+}
+
+// inOrder runs fn over items on the given number of workers and passes the
+// results to emit in input order, as soon as each one and all before it are
+// done. At most workers items are in flight or waiting to be emitted, so one
+// slow item stalls the reader instead of growing the reorder buffer. It returns
+// once every item has been emitted.
+func inOrder[T, R any](items iter.Seq[T], workers int, fn func(T) R, emit func(R)) {
+	type job struct {
+		seq  int
+		item T
+	}
+	type done struct {
+		seq int
+		res R
+	}
+	jobs := make(chan job, workers)
+	results := make(chan done, workers)
+	slots := make(chan struct{}, workers) // held from dispatch until emit
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				results <- done{j.seq, fn(j.item)}
+			}
+		}()
+	}
+	go func() {
+		seq := 0
+		for it := range items {
+			slots <- struct{}{}
+			jobs <- job{seq, it}
+			seq++
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+	pending := map[int]R{}
+	next := 0
+	for d := range results {
+		pending[d.seq] = d.res
+		for res, ok := pending[next]; ok; res, ok = pending[next] {
+			delete(pending, next)
+			next++
+			emit(res)
+			<-slots
+		}
+	}
+}
+
+// failReader remembers the read error of r so it can be told apart from the
+// per-line parse errors ReadSDM yields.
+type failReader struct {
+	r   io.Reader
+	err error
+}
+
+func (f *failReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if err != nil && err != io.EOF {
+		f.err = err
+	}
+	return n, err
+}
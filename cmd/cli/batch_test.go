@@ -0,0 +1,41 @@
+package main
+
+import (
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInOrderBoundsPending(t *testing.T) {
+	const workers, n = 3, 50
+	var started, emitted atomic.Int64
+	var got []int
+	items := func(yield func(int) bool) {
+		for i := range n {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	inOrder(items, workers, func(i int) int {
+		started.Add(1)
+		if i == 0 {
+			time.Sleep(50 * time.Millisecond) // the rest must wait for the first
+		}
+		if ahead := started.Load() - emitted.Load(); ahead > workers {
+			t.Errorf("item %d: %d items ahead of emit, want at most %d", i, ahead, workers)
+		}
+		return i
+	}, func(i int) {
+		got = append(got, i)
+		emitted.Add(1)
+	})
+	want := make([]int, n)
+	for i := range want {
+		want[i] = i
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("emitted %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.rumenx.com/sudoku"
+)
+
+// requirement is one -require term, checked against a puzzle that already
+// parsed and validated. It returns "" when b passes.
+type requirement struct {
+	name  string
+	check func(b sudoku.Board) string
+}
+
+// runCheck validates every puzzle in an .sdm file (one 81-character puzzle per
+// line, # comments and blank lines skipped) against -require. It prints nothing
+// and exits 0 when all pass; otherwise it prints one "file:line: term: detail"
+// per violation and exits 1, which makes it a drop-in CI gate for puzzle repos.
+func runCheck(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("sudoku-cli check", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	in := fs.String("in", "-", "puzzle file in sdm format (- for stdin)")
+	require := fs.String("require", "unique", "comma-separated checks: solvable, unique, minimal, rating<=|>=|=LEVEL, clues<=|>=|=N")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	reqs, err := parseRequirements(*require)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	r, name := io.Reader(os.Stdin), "<stdin>"
+	if *in != "-" {
+		f, err := os.Open(*in)
+		if err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 2
+		}
+		defer f.Close()
+		r, name = f, *in
+	}
+	failed := false
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		s := strings.TrimSpace(sc.Text())
+		if s == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+		for _, v := range checkPuzzle(s, reqs) {
+			fmt.Fprintf(stdout, "%s:%d: %s\n", name, line, v)
+			failed = true
+		}
+	}
+	if err := sc.Err(); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// checkPuzzle returns the violations of s. Malformed or invalid puzzles fail
+// every requirement at once, so they are reported once as "valid".
+func checkPuzzle(s string, reqs []requirement) []string {
+	b, err := sudoku.FromString(s)
+	if err == nil {
+		err = sudoku.Validate(b)
+	}
+	if err != nil {
+		return []string{"valid: " + err.Error()}
+	}
+	var out []string
+	for _, req := range reqs {
+		if msg := req.check(b); msg != "" {
+			out = append(out, req.name+": "+msg)
+		}
+	}
+	return out
+}
+
+func parseRequirements(spec string) ([]requirement, error) {
+	var reqs []requirement
+	for _, term := range strings.Split(spec, ",") {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if term == "" {
+			continue
+		}
+		req := requirement{name: term}
+		switch term {
+		case "solvable":
+			req.check = func(b sudoku.Board) string {
+				if sudoku.CountSolutions(b, 1) == 0 {
+					return "no solution"
+				}
+				return ""
+			}
+		case "unique":
+			req.check = func(b sudoku.Board) string {
+				if n := sudoku.CountSolutions(b, 2); n != 1 {
+					return fmt.Sprintf("%s solutions", map[int]string{0: "no", 2: "multiple"}[n])
+				}
+				return ""
+			}
+		case "minimal":
+			req.check = checkMinimal
+		default:
+			key, op, val, ok := splitComparison(term)
+			if !ok {
+				return nil, fmt.Errorf("unknown requirement %q", term)
+			}
+			switch key {
+			case "rating":
+				d, err := parseDifficulty(val)
+				if err != nil || val == "" {
+					return nil, fmt.Errorf("requirement %q: invalid difficulty", term)
+				}
+				want := difficultyRank(d)
+				req.check = func(b sudoku.Board) string {
+					r, err := sudoku.Rate(b)
+					if err != nil {
+						return err.Error()
+					}
+					if !compare(difficultyRank(r.Difficulty), op, want) {
+						return fmt.Sprintf("rated %s (%s)", r.Difficulty, r.Hardest)
+					}
+					return ""
+				}
+			case "clues":
+				n, err := strconv.Atoi(val)
+				if err != nil {
+					return nil, fmt.Errorf("requirement %q: invalid clue count", term)
+				}
+				req.check = func(b sudoku.Board) string {
+					if c := countGivens(b); !compare(c, op, n) {
+						return fmt.Sprintf("%d clues", c)
+					}
+					return ""
+				}
+			default:
+				return nil, fmt.Errorf("unknown requirement %q", term)
+			}
+		}
+		reqs = append(reqs, req)
+	}
+	if len(reqs) == 0 {
+		return nil, errors.New("no requirements given")
+	}
+	return reqs, nil
+}
+
+// checkMinimal reports the first clue that can be removed without losing
+// uniqueness. Non-unique puzzles are left to the unique requirement.
+func checkMinimal(b sudoku.Board) string {
+	if sudoku.CountSolutions(b, 2) != 1 {
+		return "needs a unique solution"
+	}
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if b[r][c] == 0 {
+				continue
+			}
+			v := b[r][c]
+			b[r][c] = 0
+			unique := sudoku.CountSolutions(b, 2) == 1
+			b[r][c] = v
+			if unique {
+				return fmt.Sprintf("clue %s is redundant", sudoku.Cell{Row: r, Col: c})
+			}
+		}
+	}
+	return ""
+}
+
+// splitComparison splits "key<=val", "key>=val" or "key=val".
+func splitComparison(term string) (key, op, val string, ok bool) {
+	for _, op := range []string{"<=", ">=", "="} {
+		if i := strings.Index(term, op); i > 0 {
+			return term[:i], op, term[i+len(op):], true
+		}
+	}
+	return "", "", "", false
+}
+
+func compare(got int, op string, want int) bool {
+	switch op {
+	case "<=":
+		return got <= want
+	case ">=":
+		return got >= want
+	}
+	return got == want
+}
+
+func difficultyRank(d sudoku.Difficulty) int {
+	switch d {
+	case sudoku.Easy:
+		return 0
+	case sudoku.Hard:
+		return 2
+	}
+	return 1
+}
+
+func countGivens(b sudoku.Board) int {
+	n := 0
+	for r := range b {
+		for c := range b[r] {
+			if b[r][c] != 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
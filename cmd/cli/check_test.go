@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func writeSDM(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "puzzles.sdm")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCheckPassesSilently(t *testing.T) {
+	minimal, err := sudoku.Minimize(sudokutest.MustBoard(sudokutest.Hard), sudoku.SymmetryNone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := writeSDM(t, "# minimal set", minimal.String(), "")
+	var out, errBuf bytes.Buffer
+	code := runCLI([]string{"check", "-in", path, "-require", "unique,minimal,rating>=medium,clues<=30"}, &out, &errBuf)
+	if code != 0 || out.Len() != 0 || errBuf.Len() != 0 {
+		t.Fatalf("exit %d, stdout %q, stderr %q", code, out.String(), errBuf.String())
+	}
+}
+
+func TestCheckReportsViolations(t *testing.T) {
+	path := writeSDM(t, sudokutest.Easy, sudokutest.NonUnique, "# skipped", sudokutest.Invalid)
+	var out, errBuf bytes.Buffer
+	code := runCLI([]string{"check", "-in", path, "-require", "unique,minimal,rating=hard"}, &out, &errBuf)
+	if code != 1 {
+		t.Fatalf("exit %d, stderr %q", code, errBuf.String())
+	}
+	got := out.String()
+	for _, want := range []string{
+		path + ":1: minimal: clue",
+		path + ":1: rating=hard: rated easy",
+		path + ":2: unique: multiple solutions",
+		path + ":4: valid:",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestCheckUsageErrors(t *testing.T) {
+	for _, spec := range []string{"fast", "rating<=insane", "clues>=many", ""} {
+		var out, errBuf bytes.Buffer
+		if code := runCLI([]string{"check", "-require", spec}, &out, &errBuf); code != 2 {
+			t.Errorf("require %q: exit %d", spec, code)
+		}
+	}
+}
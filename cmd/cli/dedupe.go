@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
+
+	"go.rumenx.com/sudoku"
+)
+
+// runDedupe copies an .sdm collection keeping only the first puzzle of every
+// isomorphism class (same sudoku.Canonical form), optionally writing the
+// canonical forms instead. Puzzles are canonicalized in parallel but written in
+// input order, so the output is deterministic. Stats go to stderr; unreadable
+// lines are reported there and skipped.
+func runDedupe(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("sudoku-cli dedupe", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	input := fs.String("i", "-", "input .sdm collection (- for stdin)")
+	output := fs.String("o", "", "write unique puzzles to this path instead of stdout")
+	canonical := fs.Bool("canonical", false, "write the canonical form of each puzzle instead of the original")
+	workers := fs.Int("workers", runtime.NumCPU(), "puzzles canonicalized in parallel")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	if *workers < 1 {
+		fmt.Fprintln(stderr, "error: -workers must be >= 1")
+		return 2
+	}
+	var in io.Reader = os.Stdin
+	if *input != "-" {
+		f, err := os.Open(*input)
+		if err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+	out := bufio.NewWriter(stdout)
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+		defer f.Close()
+		out = bufio.NewWriter(f)
+	}
+
+	type canon struct {
+		sdmEntry
+		key string // EncodeID of the canonical form: exact and compact
+		min sudoku.Board
+	}
+	var readErr error
+	seen := map[string]bool{}
+	read, invalid, dups := 0, 0, 0
+	start := time.Now()
+	inOrder(readEntries(in, &readErr), *workers, func(e sdmEntry) canon {
+		c := canon{sdmEntry: e}
+		if e.err == nil {
+			c.min = sudoku.Canonical(e.board)
+			c.key, c.err = sudoku.EncodeID(c.min)
+		}
+		return c
+	}, func(c canon) {
+		read++
+		switch {
+		case c.err != nil:
+			invalid++
+			fmt.Fprintln(stderr, "skipped:", c.err)
+		case seen[c.key]:
+			dups++
+		default:
+			seen[c.key] = true
+			b := c.board
+			if *canonical {
+				b = c.min
+			}
+			out.WriteString(b.String())
+			out.WriteByte('\n')
+		}
+	})
+	if err := out.Flush(); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	if readErr != nil {
+		fmt.Fprintln(stderr, "error:", readErr)
+		return 1
+	}
+	fmt.Fprintf(stderr, "read %d, unique %d, duplicates %d, invalid %d in %s\n",
+		read, len(seen), dups, invalid, time.Since(start).Round(time.Millisecond))
+	return 0
+}
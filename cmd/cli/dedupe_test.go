@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func TestDedupe(t *testing.T) {
+	easy := sudokutest.MustBoard(sudokutest.Easy)
+	twin := sudoku.Transpose(easy) // isomorphic to easy
+	in := writeSDM(t, "# pack", sudokutest.Easy, sudokutest.Hard, twin.String(), "bad line", sudokutest.Easy, sudokutest.Medium)
+	out := filepath.Join(t.TempDir(), "unique.sdm")
+	var stdout, stderr bytes.Buffer
+	if code := runCLI([]string{"dedupe", "-i", in, "-o", out, "-workers", "3"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr.String())
+	}
+	data, _ := os.ReadFile(out)
+	want := sudokutest.Easy + "\n" + sudokutest.Hard + "\n" + sudokutest.Medium + "\n"
+	if string(data) != want {
+		t.Fatalf("output:\n%s\nwant first of each class in input order:\n%s", data, want)
+	}
+	if !strings.Contains(stderr.String(), "read 6, unique 3, duplicates 2, invalid 1") || !strings.Contains(stderr.String(), "skipped: line 5:") {
+		t.Fatalf("stats: %s", stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := runCLI([]string{"dedupe", "-i", in, "-canonical"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr.String())
+	}
+	if first := strings.SplitN(stdout.String(), "\n", 2)[0]; first != sudoku.Canonical(easy).String() {
+		t.Fatalf("-canonical wrote %s", first)
+	}
+	if code := runCLI([]string{"dedupe", "-workers", "0"}, &stdout, &stderr); code != 2 {
+		t.Fatalf("expected usage error, got %d", code)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.rumenx.com/sudoku"
+)
+
+// exampleOpts are the generation/solve flags baked into printed snippets.
+type exampleOpts struct {
+	lang       string
+	server     string
+	difficulty string
+	attempts   int
+	size       int
+	boxR, boxC int
+	solution   bool
+	puzzle     string
+	hint       bool
+}
+
+// runExample prints a ready-to-run snippet calling the library or the HTTP API
+// with the given flags, so users can move from the CLI to their own code.
+func runExample(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("sudoku-cli example", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	lang := fs.String("lang", "go", "snippet language: go|curl|js")
+	server := fs.String("server", "http://localhost:8080", "API base URL for curl/js snippets")
+	diff := fs.String("difficulty", "medium", "difficulty: easy|medium|hard (for generation)")
+	attempts := fs.Int("attempts", 3, "generation attempts for uniqueness (>=1)")
+	showSol := fs.Bool("solve", false, "when generating, also request the solution")
+	size := fs.Int("size", 9, "grid size (SxS), e.g. 4, 6, 9")
+	box := fs.String("box", "3x3", "sub-box dims RxC")
+	hint := fs.Bool("hint", false, "snippet asks for a hint instead of a solution")
+	puzzleS := fs.String("string", "", "81-char puzzle string to solve in the snippet")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	d, err := parseDifficulty(*diff)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	br, bc, err := parseBox(*box, *size)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	// the puzzle is pasted into code and shell commands, so only a valid one is
+	if p := strings.TrimSpace(*puzzleS); p != "" {
+		if _, err := sudoku.FromString(p); err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 2
+		}
+	}
+	o := exampleOpts{
+		lang:       strings.ToLower(*lang),
+		server:     strings.TrimRight(*server, "/"),
+		difficulty: string(d),
+		attempts:   *attempts,
+		size:       *size,
+		boxR:       br,
+		boxC:       bc,
+		solution:   *showSol,
+		puzzle:     strings.TrimSpace(*puzzleS),
+		hint:       *hint,
+	}
+	var snippet string
+	switch o.lang {
+	case "go":
+		snippet = goExample(o)
+	case "curl":
+		snippet, err = curlExample(o)
+	case "js":
+		snippet, err = jsExample(o)
+	default:
+		err = fmt.Errorf("invalid lang: %s", *lang)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	fmt.Fprint(stdout, snippet)
+	return 0
+}
+
+// difficultyIdent returns the exported constant name for the difficulty.
+func (o exampleOpts) difficultyIdent() string {
+	return strings.ToUpper(o.difficulty[:1]) + o.difficulty[1:]
+}
+
+func (o exampleOpts) classic() bool { return o.size == 9 && o.boxR == 3 && o.boxC == 3 }
+
+// request returns the API path and JSON body matching the options.
+func (o exampleOpts) request() (string, string, error) {
+	if o.puzzle != "" {
+		if o.hint {
+			return "", "", errors.New("the HTTP API has no hint endpoint; use -lang go")
+		}
+		b, _ := json.Marshal(map[string]string{"string": o.puzzle})
+		return "/solve", string(b), nil
+	}
+	req := map[string]any{"difficulty": o.difficulty, "attempts": o.attempts}
+	if o.solution {
+		req["includeSolution"] = true
+	}
+	if !o.classic() {
+		req["size"] = o.size
+		req["box"] = fmt.Sprintf("%dx%d", o.boxR, o.boxC)
+	}
+	b, _ := json.Marshal(req)
+	return "/generate", string(b), nil
+}
+
+func curlExample(o exampleOpts) (string, error) {
+	path, body, err := o.request()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("curl -s -X POST %s \\\n  -H 'content-type: application/json' \\\n  -d %s\n", shellQuote(o.server+path), shellQuote(body)), nil
+}
+
+// shellQuote quotes s as one POSIX shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func jsExample(o exampleOpts) (string, error) {
+	path, body, err := o.request()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`const res = await fetch(%q, {
+  method: "POST",
+  headers: { "content-type": "application/json" },
+  body: JSON.stringify(%s),
+});
+if (!res.ok) throw new Error((await res.json()).error);
+console.log(await res.json());
+`, o.server+path, body), nil
+}
+
+func goExample(o exampleOpts) string {
+	var sb strings.Builder
+	sb.WriteString("package main\n\nimport (\n\t\"fmt\"\n\t\"log\"\n\n\t\"go.rumenx.com/sudoku\"\n)\n\nfunc main() {\n")
+	switch {
+	case o.puzzle != "" && o.hint:
+		fmt.Fprintf(&sb, "\tb, err := sudoku.FromString(%q)\n", o.puzzle)
+		sb.WriteString("\tif err != nil {\n\t\tlog.Fatal(err)\n\t}\n")
+		sb.WriteString("\tr, c, v, ok := sudoku.Hint(b)\n\tif !ok {\n\t\tlog.Fatal(\"no hint available\")\n\t}\n")
+		sb.WriteString("\tfmt.Printf(\"Hint: row %d, col %d = %d\\n\", r+1, c+1, v)\n")
+	case o.puzzle != "":
+		fmt.Fprintf(&sb, "\tb, err := sudoku.FromString(%q)\n", o.puzzle)
+		sb.WriteString("\tif err != nil {\n\t\tlog.Fatal(err)\n\t}\n")
+		sb.WriteString("\tsol, ok := sudoku.Solve(b)\n\tif !ok {\n\t\tlog.Fatal(\"unsolvable puzzle\")\n\t}\n")
+		sb.WriteString("\tfmt.Println(sol.String())\n")
+	case o.classic():
+		fmt.Fprintf(&sb, "\tpuz, err := sudoku.Generate(sudoku.%s, %d)\n", o.difficultyIdent(), o.attempts)
+		sb.WriteString("\tif err != nil {\n\t\tlog.Fatal(err)\n\t}\n\tfmt.Println(puz.String())\n")
+		if o.solution {
+			sb.WriteString("\tif sol, ok := sudoku.Solve(puz); ok {\n\t\tfmt.Println(sol.String())\n\t}\n")
+		}
+	default:
+		fmt.Fprintf(&sb, "\tg, err := sudoku.NewGrid(%d, %d, %d)\n", o.size, o.boxR, o.boxC)
+		sb.WriteString("\tif err != nil {\n\t\tlog.Fatal(err)\n\t}\n")
+		fmt.Fprintf(&sb, "\tpuz, err := g.Generate(sudoku.%s, %d)\n", o.difficultyIdent(), o.attempts)
+		sb.WriteString("\tif err != nil {\n\t\tlog.Fatal(err)\n\t}\n\tfmt.Println(puz.String())\n")
+		if o.solution {
+			sb.WriteString("\tif sol, ok := puz.Solve(); ok {\n\t\tfmt.Println(sol.String())\n\t}\n")
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
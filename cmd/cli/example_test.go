@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func TestExampleGoSnippetsParse(t *testing.T) {
+	puzzle := sudokutest.Easy
+	for _, args := range [][]string{
+		{"-lang", "go", "-difficulty", "hard", "-solve"},
+		{"-lang", "go", "-size", "6", "-box", "2x3"},
+		{"-lang", "go", "-string", puzzle},
+		{"-lang", "go", "-string", puzzle, "-hint"},
+	} {
+		var out, errBuf bytes.Buffer
+		if code := runCLI(append([]string{"example"}, args...), &out, &errBuf); code != 0 {
+			t.Fatalf("%v: exit %d, stderr=%s", args, code, errBuf.String())
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), "main.go", out.String(), 0); err != nil {
+			t.Fatalf("%v: snippet does not parse: %v\n%s", args, err, out.String())
+		}
+	}
+}
+
+func TestExampleHTTPSnippets(t *testing.T) {
+	var out, errBuf bytes.Buffer
+	code := runCLI([]string{"example", "-lang", "curl", "-server", "http://api.test/", "-size", "4", "-box", "2x2"}, &out, &errBuf)
+	if code != 0 {
+		t.Fatalf("exit %d, stderr=%s", code, errBuf.String())
+	}
+	s := out.String()
+	if !strings.Contains(s, "http://api.test/generate") || !strings.Contains(s, `"box":"2x2"`) {
+		t.Fatalf("unexpected curl snippet: %s", s)
+	}
+	out.Reset()
+	if code := runCLI([]string{"example", "-lang", "js", "-string", strings.Repeat("0", 81)}, &out, &errBuf); code != 0 {
+		t.Fatalf("js exit %d", code)
+	}
+	if !strings.Contains(out.String(), "/solve") {
+		t.Fatalf("unexpected js snippet: %s", out.String())
+	}
+	// a quote in the server URL stays inside one shell word
+	out.Reset()
+	if code := runCLI([]string{"example", "-lang", "curl", "-server", "http://it's.test"}, &out, &errBuf); code != 0 {
+		t.Fatalf("quoted server: exit %d", code)
+	}
+	if !strings.Contains(out.String(), `curl -s -X POST 'http://it'\''s.test/generate'`) {
+		t.Fatalf("server not shell-quoted: %s", out.String())
+	}
+	// unknown language, hint over HTTP and an invalid puzzle are usage errors
+	for _, args := range [][]string{
+		{"-lang", "rust"},
+		{"-lang", "curl", "-string", strings.Repeat("0", 81), "-hint"},
+		{"-lang", "curl", "-string", "0'; rm -rf ~; echo '" + strings.Repeat("0", 61)},
+	} {
+		if code := runCLI(append([]string{"example"}, args...), &out, &errBuf); code != 2 {
+			t.Fatalf("%v: expected exit 2, got %d", args, code)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/i18n"
+)
+
+// runExplain prints the logical solution of a classic puzzle (sudoku.ExplainSolve),
+// one numbered step per line with its technique, or the steps as a JSON array
+// with -json.
+func runExplain(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("sudoku-cli explain", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	puzzleS := fs.String("string", "", "81-char puzzle string (0 or . for empty)")
+	puzzleF := fs.String("file", "", "puzzle file (81-char line, .sdk, .ss or formatted grid)")
+	asJSON := fs.Bool("json", false, "print the steps as JSON")
+	lang := fs.String("lang", "en", "language of the explanations: "+strings.Join(i18n.Languages(), ", "))
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	p, err := i18n.New(*lang)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	var board sudoku.Board
+	switch {
+	case *puzzleF != "":
+		f, ferr := os.Open(*puzzleF)
+		if ferr != nil {
+			fmt.Fprintln(stderr, "error:", ferr)
+			return 1
+		}
+		board, err = sudoku.ParseAny(f)
+		f.Close()
+	case *puzzleS != "":
+		board, err = sudoku.FromString(strings.TrimSpace(*puzzleS))
+	default:
+		fmt.Fprintln(stderr, "error: explain needs -string or -file")
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	steps, err := sudoku.ExplainSolve(board)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(steps)
+		return 0
+	}
+	hardest := sudoku.TechniqueNakedSingle
+	for i, st := range steps {
+		if st.Technique > hardest {
+			hardest = st.Technique
+		}
+		fmt.Fprintf(stdout, "%3d. %-18s %s\n", i+1, st.Technique, p.Explain(st, len(board)))
+	}
+	info := hardest.Info()
+	fmt.Fprintf(stdout, "%d steps; hardest technique: %s (%s)\n", len(steps), info.Name, p.Difficulty(info.Difficulty))
+	return 0
+}
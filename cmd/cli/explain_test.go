@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func TestExplain(t *testing.T) {
+	var out, errBuf bytes.Buffer
+	if code := runCLI([]string{"explain", "-string", sudokutest.Easy}, &out, &errBuf); code != 0 {
+		t.Fatalf("exit %d: %s", code, errBuf.String())
+	}
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 52 { // 51 empty cells and the summary
+		t.Fatalf("%d lines:\n%s", len(lines), out.String())
+	}
+	if !strings.HasPrefix(lines[0], "  1. hidden-single ") || !strings.HasPrefix(lines[51], "51 steps; hardest technique: Hidden single (easy)") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if code := runCLI([]string{"explain", "-json", "-string", sudokutest.Hard}, &out, &errBuf); code != 0 {
+		t.Fatalf("exit %d: %s", code, errBuf.String())
+	}
+	var steps []sudoku.Step
+	if err := json.Unmarshal(out.Bytes(), &steps); err != nil || len(steps) == 0 {
+		t.Fatalf("decode steps: %v", err)
+	}
+	b := sudokutest.MustBoard(sudokutest.Hard)
+	for _, st := range steps {
+		if st.Value != 0 {
+			b[st.Cell.Row][st.Cell.Col] = st.Value
+		}
+	}
+	if !sudoku.IsSolved(b) {
+		t.Fatal("JSON steps do not solve the puzzle")
+	}
+}
+
+func TestExplainErrors(t *testing.T) {
+	var out, errBuf bytes.Buffer
+	if code := runCLI([]string{"explain", "-string", sudokutest.NonUnique}, &out, &errBuf); code != 1 {
+		t.Fatalf("expected exit 1 for a non-unique puzzle, got %d", code)
+	}
+	if code := runCLI([]string{"explain"}, &out, &errBuf); code != 2 {
+		t.Fatalf("expected usage error, got %d", code)
+	}
+	if code := runCLI([]string{"explain", "-lang", "xx", "-string", sudokutest.Easy}, &out, &errBuf); code != 2 {
+		t.Fatalf("expected exit 2 for an unknown language, got %d", code)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/sudoku"
+)
+
+func TestGeneratePack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pack.sdm")
+	var out, errBuf bytes.Buffer
+	if code := runCLI([]string{"generate", "-n", "5", "-difficulty", "easy", "-seed", "9", "-workers", "2", "-o", path}, &out, &errBuf); code != 0 {
+		t.Fatalf("exit %d: %s", code, errBuf.String())
+	}
+	if !strings.Contains(errBuf.String(), "] 5/5") {
+		t.Fatalf("no progress bar on stderr: %q", errBuf.String())
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	seen := map[sudoku.Board]bool{}
+	for b, err := range sudoku.ReadSDM(f) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[b] || sudoku.CountSolutions(b, 2) != 1 {
+			t.Fatalf("duplicate or non-unique puzzle %s", b)
+		}
+		seen[b] = true
+	}
+	if len(seen) != 5 {
+		t.Fatalf("%d puzzles written, want 5", len(seen))
+	}
+
+	out.Reset()
+	errBuf.Reset()
+	if code := runCLI([]string{"generate", "-n", "2", "-difficulty", "easy", "-seed", "9", "-json", "-quiet"}, &out, &errBuf); code != 0 {
+		t.Fatalf("exit %d: %s", code, errBuf.String())
+	}
+	if errBuf.Len() != 0 {
+		t.Fatalf("-quiet still wrote %q", errBuf.String())
+	}
+	dec := json.NewDecoder(&out)
+	for i := 0; i < 2; i++ {
+		var p sudoku.Puzzle
+		if err := dec.Decode(&p); err != nil || !seen[p.Givens] || p.Difficulty != sudoku.Easy {
+			t.Fatalf("puzzle %d: %+v, %v (same seed should give the same pack)", i, p, err)
+		}
+	}
+}
+
+func TestGenerateRandomSeedPrinted(t *testing.T) {
+	var out, errBuf bytes.Buffer
+	if code := runCLI([]string{"generate", "-n", "2", "-difficulty", "easy", "-quiet"}, &out, &errBuf); code != 0 {
+		t.Fatalf("exit %d: %s", code, errBuf.String())
+	}
+	var seed string
+	if _, err := fmt.Sscanf(errBuf.String(), "Seed: %s\n", &seed); err != nil {
+		t.Fatalf("no seed on stderr: %q", errBuf.String())
+	}
+	var again bytes.Buffer
+	if code := runCLI([]string{"generate", "-n", "2", "-difficulty", "easy", "-quiet", "-seed", seed}, &again, &errBuf); code != 0 || again.String() != out.String() {
+		t.Fatalf("printed seed %s does not reproduce the pack:\n%s\n%s", seed, out.String(), again.String())
+	}
+}
+
+func TestGenerateFlags(t *testing.T) {
+	var out, errBuf bytes.Buffer
+	for _, args := range [][]string{{"-n", "0"}, {"-difficulty", "extreme"}, {"-workers", "0"}} {
+		if code := runCLI(append([]string{"generate"}, args...), &out, &errBuf); code != 2 {
+			t.Fatalf("%v: expected exit 2, got %d", args, code)
+		}
+	}
+	if got := progressBar(1, 4, 8); got != "[##------] 1/4" {
+		t.Fatalf("progressBar = %q", got)
+	}
+}
+
+func TestGenerateSymmetry(t *testing.T) {
+	var out, errBuf bytes.Buffer
+	if code := runCLI([]string{"generate", "-n", "2", "-seed", "3", "-symmetry", "mirror", "-quiet"}, &out, &errBuf); code != 0 {
+		t.Fatalf("exit %d: %s", code, errBuf.String())
+	}
+	for b, err := range sudoku.ReadSDM(&out) {
+		if err != nil || sudoku.SymmetryOf(b)&sudoku.SymmetryMirror == 0 {
+			t.Fatalf("puzzle %s not mirror-symmetric (%v)", b, err)
+		}
+	}
+
+	out.Reset()
+	if code := runCLI([]string{"-seed", "3", "-symmetry", "rotational", "-json"}, &out, &errBuf); code != 0 {
+		t.Fatalf("exit %d: %s", code, errBuf.String())
+	}
+	var res struct{ Puzzle sudoku.Board }
+	if err := json.Unmarshal(out.Bytes(), &res); err != nil || sudoku.SymmetryOf(res.Puzzle)&sudoku.SymmetryRotational == 0 {
+		t.Fatalf("root generation not rotational: %v", err)
+	}
+
+	for _, args := range [][]string{
+		{"generate", "-symmetry", "spiral"},
+		{"-symmetry", "mirror", "-size", "6", "-box", "2x3"},
+	} {
+		if code := runCLI(args, &out, &errBuf); code != 2 {
+			t.Fatalf("%v: expected exit 2, got %d", args, code)
+		}
+	}
+}
@@ -1,16 +1,18 @@
 package main
 
 import (
-	"bufio"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"math"
+	"math/rand/v2"
 	"os"
 	"strings"
 
 	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/i18n"
 )
 
 var (
@@ -30,6 +32,30 @@ func main() {
 
 // runCLI executes the CLI with provided args and I/O, returning a process exit code.
 func runCLI(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "example":
+			return runExample(args[1:], stdout, stderr)
+		case "solve":
+			return runSolve(args[1:], stdout, stderr)
+		case "check":
+			return runCheck(args[1:], stdout, stderr)
+		case "rate":
+			return runRate(args[1:], stdout, stderr)
+		case "validate":
+			return runValidate(args[1:], stdout, stderr)
+		case "generate":
+			return runGenerate(args[1:], stdout, stderr)
+		case "explain":
+			return runExplain(args[1:], stdout, stderr)
+		case "render":
+			return runRender(args[1:], stdout, stderr)
+		case "dedupe":
+			return runDedupe(args[1:], stdout, stderr)
+		case "serve":
+			return runServe(args[1:], stdout, stderr)
+		}
+	}
 	fs := flag.NewFlagSet("sudoku-cli", flag.ContinueOnError)
 	fs.SetOutput(stderr)
 	diff := fs.String("difficulty", "medium", "difficulty: easy|medium|hard (for generation)")
@@ -39,13 +65,30 @@ func runCLI(args []string, stdout, stderr io.Writer) int {
 	box := fs.String("box", "3x3", "sub-box dims RxC, e.g. 2x2 for 4x4, 2x3 for 6x6, 3x3 for 9x9")
 	hint := fs.Bool("hint", false, "print a hint for the provided board/string")
 	puzzleS := fs.String("string", "", "solve: 81-char puzzle string (0 or . for empty)")
-	puzzleF := fs.String("file", "", "solve: path to file containing 81-char puzzle string")
+	puzzleF := fs.String("file", "", "solve: puzzle file (81-char line, .sdk, .ss or formatted grid)")
 	asJSON := fs.Bool("json", false, "print output as JSON")
 	showVersion := fs.Bool("version", false, "print version and exit")
+	unicode := fs.Bool("unicode", false, "draw boards with Unicode box-drawing characters")
+	symmetry := fs.String("symmetry", "none", "givens layout for 9x9 generation: "+symmetryNames)
+	seed := fs.Uint64("seed", 0, "generation seed; the same seed and flags give the same puzzle (0 = random, printed)")
+	lang := fs.String("lang", "en", "language of human-readable output: "+strings.Join(i18n.Languages(), ", "))
 	if err := fs.Parse(args); err != nil {
 		fmt.Fprintln(stderr, "error:", err)
 		return 2
 	}
+	p, err := i18n.New(*lang)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	style := sudoku.FormatASCII
+	if *unicode {
+		style = sudoku.FormatUnicode
+	}
+	fail := func(code int, msg any) int {
+		fmt.Fprintln(stderr, p.Sprintf("error"), msg)
+		return code
+	}
 
 	if *showVersion {
 		fmt.Fprintln(stdout, versionString())
@@ -56,73 +99,75 @@ func runCLI(args []string, stdout, stderr io.Writer) int {
 	enc.SetIndent("", "  ")
 
 	if *puzzleS != "" || *puzzleF != "" {
-		s := *puzzleS
+		var board sudoku.Board
 		if *puzzleF != "" {
-			b, err := os.ReadFile(*puzzleF)
+			f, err := os.Open(*puzzleF)
 			if err != nil {
-				fmt.Fprintln(stderr, "error:", err)
-				return 1
+				return fail(1, err)
 			}
-			s = strings.TrimSpace(string(b))
-		}
-		board, err := sudoku.FromString(strings.TrimSpace(s))
-		if err != nil {
-			fmt.Fprintln(stderr, "error:", err)
-			return 1
+			board, err = sudoku.ParseAny(f) // .sdm, .sdk, .ss or a formatted grid
+			f.Close()
+			if err != nil {
+				return fail(1, err)
+			}
+		} else if board, err = sudoku.FromString(strings.TrimSpace(*puzzleS)); err != nil {
+			return fail(1, err)
 		}
 		if *hint {
 			r, c, v, ok := sudoku.Hint(board)
 			if !ok {
-				fmt.Fprintln(stderr, "error:", "no hint available")
-				return 1
+				return fail(1, p.Sprintf("err.no_hint"))
 			}
 			if *asJSON {
 				_ = enc.Encode(map[string]int{"row": r, "col": c, "val": v})
-			} else {
-				fmt.Fprintf(stdout, "Hint: row %d, col %d = %d\n", r+1, c+1, v)
+				return 0
+			}
+			at := sudoku.Cell{Row: r, Col: c}
+			fmt.Fprintln(stdout, p.Sprintf("hint", r+1, c+1, v))
+			// explain only when the simple techniques find the same placement
+			if step, ok := sudoku.HintExplain(board); ok && step.Cell == at && step.Value == v {
+				fmt.Fprintln(stdout, p.Sprintf("why", p.Explain(step, len(board))))
 			}
 			return 0
 		}
 		solved, ok := sudoku.Solve(board)
 		if !ok {
-			fmt.Fprintln(stderr, "error:", "unsolvable puzzle")
-			return 1
+			return fail(1, p.Sprintf("err.unsolvable"))
 		}
 		if *asJSON {
 			_ = enc.Encode(map[string]any{"solution": solved})
 			return 0
 		}
-		fmt.Fprintln(stdout, "Solution:")
-		printBoardTo(stdout, solved)
+		fmt.Fprintln(stdout, p.Sprintf("solution"))
+		fmt.Fprint(stdout, sudoku.Format(solved, style))
 		return 0
 	}
 
-	var d sudoku.Difficulty
-	switch strings.ToLower(*diff) {
-	case string(sudoku.Easy):
-		d = sudoku.Easy
-	case string(sudoku.Medium), "":
-		d = sudoku.Medium
-	case string(sudoku.Hard):
-		d = sudoku.Hard
-	default:
-		fmt.Fprintln(stderr, "error:", fmt.Errorf("invalid difficulty: %s", *diff))
-		return 2
+	d, err := parseDifficulty(*diff)
+	if err != nil {
+		return fail(2, p.Sprintf("err.invalid_difficulty", *diff))
 	}
 
-	var br, bc int
-	if _, err := fmt.Sscanf(*box, "%dx%d", &br, &bc); err != nil || br <= 0 || bc <= 0 || br*bc != *size {
-		fmt.Fprintln(stderr, "error:", errors.New("invalid box dims; ensure size == R*C"))
-		return 2
+	br, bc, err := parseBox(*box, *size)
+	if err != nil {
+		return fail(2, p.Sprintf("err.box_dims"))
+	}
+	// always generate from an explicit seed so any run can be reproduced
+	if *seed == 0 {
+		*seed = rand.Uint64N(math.MaxUint64) + 1
+	}
+	gen := sudoku.NewGenerator(*seed)
+	sym, err := parseSymmetry(*symmetry)
+	if err != nil {
+		return fail(2, err)
 	}
 	if *size == 9 && br == 3 && bc == 3 {
-		puz, err := sudoku.Generate(d, *attempts)
+		puz, err := gen.GenerateSymmetric(d, *attempts, sym)
 		if err != nil {
-			fmt.Fprintln(stderr, "error:", err)
-			return 1
+			return fail(1, err)
 		}
 		if *asJSON {
-			out := map[string]any{"puzzle": puz}
+			out := map[string]any{"puzzle": puz, "seed": *seed}
 			if *showSol {
 				if sol, ok := sudoku.Solve(puz); ok {
 					out["solution"] = sol
@@ -131,25 +176,27 @@ func runCLI(args []string, stdout, stderr io.Writer) int {
 			_ = enc.Encode(out)
 			return 0
 		}
-		fmt.Fprintf(stdout, "Generated (%s):\n", d)
-		printBoardTo(stdout, puz)
+		fmt.Fprintln(stdout, p.Sprintf("generated", p.Difficulty(d)))
+		fmt.Fprint(stdout, sudoku.Format(puz, style))
 		if *showSol {
 			if sol, ok := sudoku.Solve(puz); ok {
-				fmt.Fprintln(stdout, "\nSolution:")
-				printBoardTo(stdout, sol)
+				fmt.Fprintln(stdout, "\n"+p.Sprintf("solution"))
+				fmt.Fprint(stdout, sudoku.Format(sol, style))
 			}
 		}
+		fmt.Fprintln(stdout, p.Sprintf("seed", *seed))
 		return 0
 	}
+	if sym != sudoku.SymmetryNone {
+		return fail(2, p.Sprintf("err.symmetry_size"))
+	}
 	g, err := sudoku.NewGrid(*size, br, bc)
 	if err != nil {
-		fmt.Fprintln(stderr, "error:", err)
-		return 1
+		return fail(1, err)
 	}
-	gpuz, err := g.Generate(d, *attempts)
+	gpuz, err := gen.GenerateGrid(g, d, *attempts)
 	if err != nil {
-		fmt.Fprintln(stderr, "error:", err)
-		return 1
+		return fail(1, err)
 	}
 	if *asJSON {
 		out := struct {
@@ -157,87 +204,37 @@ func runCLI(args []string, stdout, stderr io.Writer) int {
 			BoxR  int    `json:"boxR"`
 			BoxC  int    `json:"boxC"`
 			Board string `json:"board"`
-		}{gpuz.Size, gpuz.BoxRows, gpuz.BoxCols, gpuz.String()}
+			Seed  uint64 `json:"seed"`
+		}{gpuz.Size, gpuz.BoxRows, gpuz.BoxCols, gpuz.String(), *seed}
 		_ = enc.Encode(out)
 		return 0
 	}
-	fmt.Fprintf(stdout, "%dx%d (%dx%d boxes)\n", gpuz.Size, gpuz.Size, gpuz.BoxRows, gpuz.BoxCols)
-	for r := 0; r < gpuz.Size; r++ {
-		for c := 0; c < gpuz.Size; c++ {
-			v := gpuz.Cells[r][c]
-			if v == 0 {
-				fmt.Fprint(stdout, ".")
-			} else {
-				fmt.Fprint(stdout, v)
-			}
-			if c < gpuz.Size-1 {
-				fmt.Fprint(stdout, " ")
-			}
-		}
-		fmt.Fprintln(stdout)
-	}
+	fmt.Fprintln(stdout, p.Sprintf("grid.dims", gpuz.Size, gpuz.Size, gpuz.BoxRows, gpuz.BoxCols))
+	fmt.Fprint(stdout, gpuz.Format(style))
+	fmt.Fprintln(stdout, p.Sprintf("seed", *seed))
 	return 0
 }
 
-func printBoard(b sudoku.Board) {
-	line := "+-------+-------+-------+"
-	fmt.Println(line)
-	for r := 0; r < 9; r++ {
-		fmt.Print("|")
-		for c := 0; c < 9; c++ {
-			v := b[r][c]
-			ch := '.'
-			if v != 0 {
-				ch = rune('0' + v)
-			}
-			sep := " "
-			if (c+1)%3 == 0 {
-				sep = " |"
-			}
-			fmt.Printf(" %c%s", ch, sep)
-		}
-		fmt.Println()
-		if (r+1)%3 == 0 {
-			fmt.Println(line)
-		}
-	}
-}
-
-func printBoardTo(w io.Writer, b sudoku.Board) {
-	line := "+-------+-------+-------+"
-	fmt.Fprintln(w, line)
-	for r := 0; r < 9; r++ {
-		fmt.Fprint(w, "|")
-		for c := 0; c < 9; c++ {
-			v := b[r][c]
-			ch := '.'
-			if v != 0 {
-				ch = rune('0' + v)
-			}
-			sep := " "
-			if (c+1)%3 == 0 {
-				sep = " |"
-			}
-			fmt.Fprintf(w, " %c%s", ch, sep)
-		}
-		fmt.Fprintln(w)
-		if (r+1)%3 == 0 {
-			fmt.Fprintln(w, line)
-		}
+// parseDifficulty maps a flag value to a Difficulty; empty means medium.
+func parseDifficulty(s string) (sudoku.Difficulty, error) {
+	switch strings.ToLower(s) {
+	case string(sudoku.Easy):
+		return sudoku.Easy, nil
+	case string(sudoku.Medium), "":
+		return sudoku.Medium, nil
+	case string(sudoku.Hard):
+		return sudoku.Hard, nil
 	}
+	return "", fmt.Errorf("invalid difficulty: %s", s)
 }
 
-func readAll(r io.Reader) string {
-	sc := bufio.NewScanner(r)
-	var sb strings.Builder
-	for sc.Scan() {
-		s := strings.TrimSpace(sc.Text())
-		if s == "" || strings.HasPrefix(s, "#") {
-			continue
-		}
-		sb.WriteString(s)
+// parseBox parses RxC sub-box dims and checks them against size.
+func parseBox(box string, size int) (int, int, error) {
+	var br, bc int
+	if _, err := fmt.Sscanf(box, "%dx%d", &br, &bc); err != nil || br <= 0 || bc <= 0 || br*bc != size {
+		return 0, 0, errors.New("invalid box dims; ensure size == R*C")
 	}
-	return sb.String()
+	return br, bc, nil
 }
 
 func check(err error) {
@@ -2,8 +2,14 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/sudokutest"
 )
 
 func TestCLI_MainGenerateJSON(t *testing.T) {
@@ -20,7 +26,7 @@ func TestCLI_MainGenerateJSON(t *testing.T) {
 }
 
 func TestCLI_MainSolveAndHintJSON(t *testing.T) {
-	puzzle := "530070000600195000098000060800060003400803001700020006060000280000419005000080079"
+	puzzle := sudokutest.Easy
 	for _, args := range [][]string{
 		{"-string", puzzle, "-json"},
 		{"-string", puzzle, "-hint", "-json"},
@@ -68,8 +74,7 @@ func TestCLI_BadFlagsAndUnsolvable(t *testing.T) {
 	}
 	// unsolvable but valid puzzle string
 	{
-		// Row0 has 1..8, col0 has 9 -> no legal move at (0,0)
-		puzzle := "012345678" + "900000000" + strings.Repeat("000000000", 7)
+		puzzle := sudokutest.Unsolvable
 		var outBuf, errBuf bytes.Buffer
 		code := runCLI([]string{"-string", puzzle, "-json"}, &outBuf, &errBuf)
 		if code == 0 {
@@ -80,3 +85,132 @@ func TestCLI_BadFlagsAndUnsolvable(t *testing.T) {
 		}
 	}
 }
+
+func TestCLI_Lang(t *testing.T) {
+	var outBuf, errBuf bytes.Buffer
+	if code := runCLI([]string{"-lang", "de", "-string", "0" + sudokutest.EasySolution[1:], "-hint"}, &outBuf, &errBuf); code != 0 {
+		t.Fatalf("exit code %d, stderr=%s", code, errBuf.String())
+	}
+	if out := outBuf.String(); !strings.HasPrefix(out, "Tipp: Zeile 1, Spalte 1 = 5\n") || !strings.Contains(out, "\nWarum: ") {
+		t.Fatalf("expected German hint with explanation, got: %s", out)
+	}
+
+	// the hint is the first empty cell; r1c3 of Easy is not a single
+	outBuf.Reset()
+	if code := runCLI([]string{"-lang", "de", "-string", sudokutest.Easy, "-hint"}, &outBuf, &errBuf); code != 0 || outBuf.String() != "Tipp: Zeile 1, Spalte 3 = 4\n" {
+		t.Fatalf("expected unexplained hint for r1c3, got %d: %s", code, outBuf.String())
+	}
+
+	outBuf.Reset()
+	if code := runCLI([]string{"--lang=fr", "-string", sudokutest.Easy}, &outBuf, &errBuf); code != 0 || !strings.HasPrefix(outBuf.String(), "Solution :\n") {
+		t.Fatalf("expected French solution header, got %d: %s", code, outBuf.String())
+	}
+
+	errBuf.Reset()
+	if code := runCLI([]string{"-lang", "bg", "-difficulty", "nope"}, &outBuf, &errBuf); code != 2 || errBuf.String() != "грешка: невалидна трудност: nope\n" {
+		t.Fatalf("expected Bulgarian error, got %d: %q", code, errBuf.String())
+	}
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-lang", "de", "-size", "6", "-box", "2x2"}, "Fehler: ungültige Blockmaße; size muss R*C sein\n"},
+		{[]string{"-lang", "es", "-size", "6", "-box", "2x3", "-symmetry", "rotational"}, "error: -symmetry necesita una cuadrícula 9x9\n"},
+		{[]string{"solve", "-lang", "fr"}, "erreur : solve nécessite -string ou -file\n"},
+	} {
+		errBuf.Reset()
+		if code := runCLI(tc.args, &outBuf, &errBuf); code != 2 || errBuf.String() != tc.want {
+			t.Errorf("%v: got %d: %q, want %q", tc.args, code, errBuf.String(), tc.want)
+		}
+	}
+
+	errBuf.Reset()
+	if code := runCLI([]string{"-lang", "xx"}, &outBuf, &errBuf); code != 2 || !strings.Contains(errBuf.String(), "unsupported language") {
+		t.Fatalf("expected unsupported language error, got %d: %s", code, errBuf.String())
+	}
+}
+
+func TestCLI_FileFormats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "puzzle.ss")
+	var sb strings.Builder
+	for r, row := range sudokutest.MustBoard(sudokutest.Easy) {
+		if r > 0 && r%3 == 0 {
+			sb.WriteString("-----------\n")
+		}
+		for c, v := range row {
+			if c > 0 && c%3 == 0 {
+				sb.WriteByte('|')
+			}
+			sb.WriteByte(".123456789"[v])
+		}
+		sb.WriteByte('\n')
+	}
+	if err := os.WriteFile(path, []byte(sb.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var outBuf, errBuf bytes.Buffer
+	if code := runCLI([]string{"-file", path, "-json"}, &outBuf, &errBuf); code != 0 || !strings.Contains(outBuf.String(), "solution") {
+		t.Fatalf("exit code %d, stderr=%s, out=%s", code, errBuf.String(), outBuf.String())
+	}
+	outBuf.Reset()
+	if code := runCLI([]string{"solve", "-file", path, "-json"}, &outBuf, &errBuf); code != 0 || !strings.Contains(outBuf.String(), "solution") {
+		t.Fatalf("solve -file: exit code %d, stderr=%s, out=%s", code, errBuf.String(), outBuf.String())
+	}
+}
+
+func TestCLI_ParseAnyReadsOutput(t *testing.T) {
+	var outBuf, errBuf bytes.Buffer
+	if code := runCLI([]string{"-seed", "7", "-json"}, &outBuf, &errBuf); code != 0 {
+		t.Fatalf("exit code %d, stderr=%s", code, errBuf.String())
+	}
+	var want struct{ Puzzle sudoku.Board }
+	if err := json.Unmarshal(outBuf.Bytes(), &want); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"-seed", "7"},
+		{"-seed", "7", "-unicode"},
+		{"-seed", "7", "-unicode", "-solve", "-lang", "fr"},
+	} {
+		outBuf.Reset()
+		if code := runCLI(args, &outBuf, &errBuf); code != 0 {
+			t.Fatalf("%v: exit %d: %s", args, code, errBuf.String())
+		}
+		got, err := sudoku.ParseAny(&outBuf)
+		if err != nil || got != want.Puzzle {
+			t.Fatalf("%v: got %v, %v; want %v", args, got, err, want.Puzzle)
+		}
+	}
+}
+
+func TestCLI_SeedReproducible(t *testing.T) {
+	for _, args := range [][]string{
+		{"-seed", "42", "-difficulty", "easy"},
+		{"-seed", "42", "-size", "6", "-box", "2x3", "-json"},
+	} {
+		var a, b, errBuf bytes.Buffer
+		if code := runCLI(args, &a, &errBuf); code != 0 {
+			t.Fatalf("%v: exit %d: %s", args, code, errBuf.String())
+		}
+		runCLI(args, &b, &errBuf)
+		if a.String() != b.String() {
+			t.Fatalf("%v: same seed gave different output:\n%s\n%s", args, a.String(), b.String())
+		}
+		if !strings.Contains(a.String(), "Seed: 42") && !strings.Contains(a.String(), `"seed": 42`) {
+			t.Fatalf("%v: seed not shown in %s", args, a.String())
+		}
+	}
+	// without -seed the random seed is printed so the run can be repeated
+	var out, errBuf bytes.Buffer
+	runCLI([]string{"-difficulty", "easy"}, &out, &errBuf)
+	i := strings.Index(out.String(), "Seed: ")
+	if i < 0 {
+		t.Fatalf("no seed in %s", out.String())
+	}
+	seed := strings.TrimSpace(out.String()[i+len("Seed: "):])
+	var again bytes.Buffer
+	runCLI([]string{"-difficulty", "easy", "-seed", seed}, &again, &errBuf)
+	if again.String() != out.String() {
+		t.Fatalf("rerun with -seed %s differs", seed)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func TestRate(t *testing.T) {
+	var out, errBuf bytes.Buffer
+	if code := runCLI([]string{"rate", "-string", sudokutest.Easy}, &out, &errBuf); code != 0 {
+		t.Fatalf("exit %d: %s", code, errBuf.String())
+	}
+	for _, want := range []string{"Clues:      30", "Solutions:  unique", "Minimal:    no (clue", "Difficulty: easy (hardest technique:"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("missing %q in\n%s", want, out.String())
+		}
+	}
+
+	minimal, err := sudoku.Minimize(sudokutest.MustBoard(sudokutest.Hard), sudoku.SymmetryNone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if code := runCLI([]string{"rate", "-json", "-string", minimal.String()}, &out, &errBuf); code != 0 {
+		t.Fatalf("exit %d: %s", code, errBuf.String())
+	}
+	var rep rateReport
+	if err := json.Unmarshal(out.Bytes(), &rep); err != nil {
+		t.Fatal(err)
+	}
+	r, _ := sudoku.Rate(minimal)
+	if !rep.Minimal || rep.Solutions != "unique" || rep.Difficulty != r.Difficulty || rep.Hardest != r.Hardest || rep.Clues != r.Clues {
+		t.Fatalf("report %+v, rating %+v", rep, r)
+	}
+}
+
+func TestRateWithoutUniqueSolution(t *testing.T) {
+	var out, errBuf bytes.Buffer
+	if code := runCLI([]string{"rate", "-json", "-string", strings.Repeat("0", 81)}, &out, &errBuf); code != 0 {
+		t.Fatalf("exit %d: %s", code, errBuf.String())
+	}
+	var rep rateReport
+	if err := json.Unmarshal(out.Bytes(), &rep); err != nil || rep.Solutions != "multiple" || rep.Difficulty != "" {
+		t.Fatalf("report %+v: %v", rep, err)
+	}
+	if code := runCLI([]string{"rate", "-string", "55" + strings.Repeat("0", 79)}, &out, &errBuf); code != 1 {
+		t.Fatalf("expected exit 1 for an invalid puzzle, got %d", code)
+	}
+	if code := runCLI([]string{"rate"}, &out, &errBuf); code != 2 {
+		t.Fatalf("expected usage error, got %d", code)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func TestRenderFormats(t *testing.T) {
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		name  string
+		args  []string
+		magic string
+	}{
+		{"p.png", []string{"-cell", "32", "-candidates"}, "\x89PNG"},
+		{"p.svg", []string{"-solve"}, "<svg"},
+		{"p.pdf", []string{"-solve"}, "%PDF-"},
+		{"p.tex", nil, `\begin{tikzpicture}`},
+	} {
+		path := filepath.Join(dir, tc.name)
+		var out, errBuf bytes.Buffer
+		args := append([]string{"render", "-string", sudokutest.Easy, "-o", path}, tc.args...)
+		if code := runCLI(args, &out, &errBuf); code != 0 {
+			t.Fatalf("%s: exit %d: %s", tc.name, code, errBuf.String())
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || !bytes.HasPrefix(data, []byte(tc.magic)) {
+			t.Fatalf("%s: unexpected content (%v)", tc.name, err)
+		}
+		if !strings.Contains(out.String(), "wrote "+path) {
+			t.Fatalf("%s: stdout %q", tc.name, out.String())
+		}
+	}
+	f, _ := os.Open(filepath.Join(dir, "p.png"))
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil || img.Bounds().Dx() != 9*32+2*16 {
+		t.Fatalf("png: %v, bounds %v", err, img.Bounds())
+	}
+	pdf, _ := os.ReadFile(filepath.Join(dir, "p.pdf"))
+	if !bytes.Contains(pdf, []byte("/Count 2")) || !bytes.Contains(pdf, []byte("(1. Easy)")) {
+		t.Fatal("pdf should have a labelled puzzle page and a solution page")
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	dir := t.TempDir()
+	var out, errBuf bytes.Buffer
+	for _, args := range [][]string{
+		{"-string", sudokutest.Easy, "-o", filepath.Join(dir, "p.gif")},
+		{"-string", sudokutest.Easy, "-o", filepath.Join(dir, "p.pdf"), "-candidates"},
+		{"-o", filepath.Join(dir, "p.png")},
+	} {
+		if code := runCLI(append([]string{"render"}, args...), &out, &errBuf); code != 2 {
+			t.Fatalf("%v: expected exit 2, got %d", args, code)
+		}
+	}
+	if code := runCLI([]string{"render", "-string", sudokutest.Unsolvable, "-solve", "-o", filepath.Join(dir, "p.png")}, &out, &errBuf); code != 1 {
+		t.Fatalf("expected exit 1 for an unsolvable puzzle, got %d", code)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.rumenx.com/sudoku/internal/server"
+)
+
+// runServe starts the HTTP API, the same server the sudoku-server binary runs,
+// with the same flags. It returns when interrupted.
+func runServe(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("sudoku-cli serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts := server.RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintln(stderr, "error: unexpected arguments:", fs.Args())
+		return 2
+	}
+	opts.Version, opts.Commit, opts.Date = version, commit, date
+	opts.AccessLog = stdout
+	if err := server.LoadConfig(opts); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	if opts.PrintConfig {
+		if err := server.WriteConfig(stdout, *opts); err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+		return 0
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := server.Run(ctx, *opts); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestServeErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runCLI([]string{"serve", "-port", "x"}, &stdout, &stderr); code != 2 {
+		t.Fatalf("bad flag: exit %d", code)
+	}
+	t.Setenv("SUDOKU_ADMIN_ALLOW", "nope")
+	stderr.Reset()
+	if code := runCLI([]string{"serve", "-port", "0", "-quiet"}, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "SUDOKU_ADMIN_ALLOW") {
+		t.Fatalf("bad env: exit %d: %s", code, stderr.String())
+	}
+}
+
+func TestServePrintConfig(t *testing.T) {
+	t.Setenv("SUDOKU_CONFIG", "")
+	t.Setenv("SUDOKU_RATE_LIMIT", "4")
+	var stdout, stderr bytes.Buffer
+	if code := runCLI([]string{"serve", "-print-config", "-port", "9001"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr.String())
+	}
+	for _, want := range []string{"port: 9001", "rate_limit: 4", "rate_burst: 4"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, stdout.String())
+		}
+	}
+	if code := runCLI([]string{"serve", "-config", "missing.yaml"}, &stdout, &stderr); code != 1 {
+		t.Fatalf("missing config file: exit %d", code)
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/i18n"
+)
+
+// runSolve solves a classic puzzle with the deterministic tracing solver. With
+// -trace-file the full decision trace (see sudoku.Trace) is written as JSON, so
+// traces from two versions can be diffed to spot solver behaviour changes.
+func runSolve(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("sudoku-cli solve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	puzzleS := fs.String("string", "", "81-char puzzle string (0 or . for empty)")
+	puzzleF := fs.String("file", "", "puzzle file (81-char line, .sdk, .ss or formatted grid)")
+	traceFile := fs.String("trace-file", "", "write the decision trace as JSON to this path (- for stdout)")
+	asJSON := fs.Bool("json", false, "print output as JSON")
+	input := fs.String("input", "", "batch: .sdm collection to solve, one puzzle per line (- for stdin)")
+	output := fs.String("output", "", "batch: write results to this path instead of stdout")
+	workers := fs.Int("workers", runtime.NumCPU(), "batch: puzzles solved in parallel")
+	lang := fs.String("lang", "en", "language of human-readable output: "+strings.Join(i18n.Languages(), ", "))
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	if *input != "" {
+		return runSolveBatch(*input, *output, *workers, *asJSON, stdout, stderr)
+	}
+	p, err := i18n.New(*lang)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	fail := func(code int, msg any) int {
+		fmt.Fprintln(stderr, p.Sprintf("error"), msg)
+		return code
+	}
+	var board sudoku.Board
+	switch {
+	case *puzzleF != "":
+		f, ferr := os.Open(*puzzleF)
+		if ferr != nil {
+			return fail(1, ferr)
+		}
+		board, err = sudoku.ParseAny(f) // .sdm, .sdk, .ss or a formatted grid
+		f.Close()
+	case *puzzleS != "":
+		board, err = sudoku.FromString(strings.TrimSpace(*puzzleS))
+	default:
+		return fail(2, p.Sprintf("err.solve_input"))
+	}
+	if err != nil {
+		return fail(1, err)
+	}
+	solved, ok, tr := sudoku.SolveTrace(board)
+	if *traceFile != "" {
+		if err := writeTrace(*traceFile, tr, stdout); err != nil {
+			return fail(1, err)
+		}
+	}
+	if !ok {
+		return fail(1, p.Sprintf("err.unsolvable"))
+	}
+	if *traceFile == "-" {
+		return 0 // stdout carries the trace
+	}
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(map[string]any{"solution": solved})
+		return 0
+	}
+	fmt.Fprintln(stdout, p.Sprintf("solution"))
+	fmt.Fprint(stdout, sudoku.Format(solved, sudoku.FormatASCII))
+	return 0
+}
+
+// batchResult is one line of batch output.
+type batchResult struct {
+	Index     int    `json:"index"` // 1-based position among the puzzles
+	Puzzle    string `json:"puzzle,omitempty"`
+	Solution  string `json:"solution,omitempty"`
+	Status    string `json:"status"` // solved, unsolvable or invalid
+	Error     string `json:"error,omitempty"`
+	ElapsedUs int64  `json:"elapsedUs"`
+}
+
+// runSolveBatch solves every puzzle of an .sdm collection on a pool of workers.
+// Puzzles are streamed with sudoku.ReadSDM and results are written in input
+// order as they complete, so large files never sit in memory whole. The exit
+// code is 1 when any puzzle is invalid or unsolvable.
+func runSolveBatch(input, output string, workers int, asJSON bool, stdout, stderr io.Writer) int {
+	if workers < 1 {
+		fmt.Fprintln(stderr, "error: -workers must be >= 1")
+		return 2
+	}
+	var in io.Reader = os.Stdin
+	if input != "-" {
+		f, err := os.Open(input)
+		if err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+	out := bufio.NewWriter(stdout)
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+		defer f.Close()
+		out = bufio.NewWriter(f)
+	}
+
+	var readErr error
+	enc := json.NewEncoder(out)
+	index, failed := 0, 0
+	start := time.Now()
+	solve := func(e sdmEntry) batchResult {
+		if e.err != nil {
+			return batchResult{Status: "invalid", Error: e.err.Error()}
+		}
+		res := batchResult{Puzzle: e.board.String(), Status: "unsolvable"}
+		t := time.Now()
+		solved, ok := sudoku.Solve(e.board)
+		res.ElapsedUs = time.Since(t).Microseconds()
+		if ok {
+			res.Status, res.Solution = "solved", solved.String()
+		}
+		return res
+	}
+	inOrder(readEntries(in, &readErr), workers, solve, func(res batchResult) {
+		index++
+		res.Index = index
+		if res.Status != "solved" {
+			failed++
+		}
+		if asJSON {
+			_ = enc.Encode(res)
+			return
+		}
+		switch res.Status {
+		case "solved":
+			fmt.Fprintf(out, "%s\t%s\t%s\n", res.Solution, res.Status, time.Duration(res.ElapsedUs)*time.Microsecond)
+		case "unsolvable":
+			fmt.Fprintf(out, "%s\t%s\t%s\n", res.Puzzle, res.Status, time.Duration(res.ElapsedUs)*time.Microsecond)
+		default:
+			fmt.Fprintf(out, "-\t%s\t%s\n", res.Status, res.Error)
+		}
+	})
+	if err := out.Flush(); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	if readErr != nil {
+		fmt.Fprintln(stderr, "error:", readErr)
+		return 1
+	}
+	fmt.Fprintf(stderr, "solved %d/%d puzzles in %s\n", index-failed, index, time.Since(start).Round(time.Millisecond))
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func writeTrace(path string, tr *sudoku.Trace, stdout io.Writer) error {
+	w := stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(tr)
+}
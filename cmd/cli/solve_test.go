@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func TestSolveTraceFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.json")
+	var out, errBuf bytes.Buffer
+	if code := runCLI([]string{"solve", "-string", sudokutest.Hard, "-trace-file", path}, &out, &errBuf); code != 0 {
+		t.Fatalf("exit %d: %s", code, errBuf.String())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read trace: %v", err)
+	}
+	var tr sudoku.Trace
+	if err := json.Unmarshal(data, &tr); err != nil {
+		t.Fatalf("decode trace: %v", err)
+	}
+	if tr.Schema != sudoku.TraceSchemaVersion || !tr.Solved || tr.Puzzle != sudokutest.Hard || len(tr.Events) == 0 {
+		t.Fatalf("unexpected trace header: schema=%d solved=%v events=%d", tr.Schema, tr.Solved, len(tr.Events))
+	}
+	if !bytes.Contains(out.Bytes(), []byte("Solution:")) {
+		t.Fatalf("expected solution on stdout, got %s", out.String())
+	}
+}
+
+func TestSolveTraceUnsolvableAndErrors(t *testing.T) {
+	var out, errBuf bytes.Buffer
+	if code := runCLI([]string{"solve", "-string", sudokutest.Unsolvable, "-trace-file", "-"}, &out, &errBuf); code != 1 {
+		t.Fatalf("expected exit 1 for unsolvable, got %d", code)
+	}
+	var tr sudoku.Trace
+	if err := json.Unmarshal(out.Bytes(), &tr); err != nil || tr.Solved {
+		t.Fatalf("expected unsolved trace on stdout: %v", err)
+	}
+	if code := runCLI([]string{"solve"}, &out, &errBuf); code != 2 {
+		t.Fatalf("expected usage error, got %d", code)
+	}
+}
+
+func TestSolveBatch(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "puzzles.sdm")
+	data := "# sample\n" + sudokutest.Easy + "\n" + sudokutest.Unsolvable + "\nnot a puzzle\n" + sudokutest.Hard + "\n"
+	if err := os.WriteFile(in, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "results.jsonl")
+	var stdout, stderr bytes.Buffer
+	if code := runCLI([]string{"solve", "-input", in, "-output", out, "-json", "-workers", "3"}, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit 1 with failed puzzles, got %d: %s", code, stderr.String())
+	}
+	if stdout.Len() != 0 || !strings.Contains(stderr.String(), "solved 2/4 puzzles") {
+		t.Fatalf("stdout %q, stderr %q", stdout.String(), stderr.String())
+	}
+	f, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []batchResult
+	dec := json.NewDecoder(bytes.NewReader(f))
+	for dec.More() {
+		var r batchResult
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, r)
+	}
+	want := []string{"solved", "unsolvable", "invalid", "solved"}
+	if len(got) != len(want) {
+		t.Fatalf("%d results, want %d", len(got), len(want))
+	}
+	for i, r := range got {
+		if r.Index != i+1 || r.Status != want[i] {
+			t.Fatalf("result %d = %+v, want status %s", i, r, want[i])
+		}
+	}
+	if b, _ := sudoku.FromString(got[3].Solution); !sudoku.IsSolved(b) || got[3].Puzzle != sudokutest.Hard {
+		t.Fatalf("bad solution %q", got[3].Solution)
+	}
+	if !strings.Contains(got[2].Error, "line 4") {
+		t.Fatalf("invalid line error %q", got[2].Error)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	good := filepath.Join(dir, "good.sdm")
+	_ = os.WriteFile(good, []byte(sudokutest.Easy+"\n"+sudokutest.Medium+"\n"), 0o644)
+	if code := runCLI([]string{"solve", "-input", good}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr.String())
+	}
+	if lines := strings.Split(strings.TrimSpace(stdout.String()), "\n"); len(lines) != 2 || !strings.Contains(lines[0], "\tsolved\t") {
+		t.Fatalf("text output %q", stdout.String())
+	}
+	if code := runCLI([]string{"solve", "-input", good, "-workers", "0"}, &stdout, &stderr); code != 2 {
+		t.Fatalf("expected usage error for -workers 0, got %d", code)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.rumenx.com/sudoku"
+)
+
+// conflictPair is two cells of one unit holding the same value.
+type conflictPair struct {
+	Unit  string    `json:"unit"` // e.g. "row 3"
+	Value int       `json:"value"`
+	Cells [2]string `json:"cells"`
+}
+
+// validateReport is the JSON form of the validate subcommand.
+type validateReport struct {
+	Valid     bool           `json:"valid"`
+	Conflicts []conflictPair `json:"conflicts"`
+	Solutions string         `json:"solutions"` // none, unique or multiple
+}
+
+// runValidate checks a classic board against the rules and lists every pair of
+// clashing cells (see sudoku.ValidateAll), then counts solutions up to two.
+// The board is read with sudoku.ParseAnyUnchecked, so any format ParseAny
+// accepts works and conflicting input is kept for inspection. It exits 1 when
+// the board has conflicts or no solution.
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("sudoku-cli validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	puzzleS := fs.String("string", "", "81-char board string (0 or . for empty)")
+	puzzleF := fs.String("file", "", "file with the board in any format sudoku.ParseAny reads")
+	asJSON := fs.Bool("json", false, "print output as JSON")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	in := io.Reader(strings.NewReader(*puzzleS))
+	if *puzzleF != "" {
+		f, err := os.Open(*puzzleF)
+		if err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+		defer f.Close()
+		in = f
+	} else if strings.TrimSpace(*puzzleS) == "" {
+		fmt.Fprintln(stderr, "error: validate needs -string or -file")
+		return 2
+	}
+	board, err := sudoku.ParseAnyUnchecked(in)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+
+	rep := validateReport{Conflicts: []conflictPair{}, Solutions: "none"}
+	for _, c := range sudoku.ValidateAll(board) {
+		unit := sudoku.Unit{Kind: c.Kind, Index: c.Index}.String()
+		for i := 0; i < len(c.Cells); i++ {
+			for j := i + 1; j < len(c.Cells); j++ {
+				rep.Conflicts = append(rep.Conflicts, conflictPair{unit, c.Value, [2]string{c.Cells[i].String(), c.Cells[j].String()}})
+			}
+		}
+	}
+	rep.Valid = len(rep.Conflicts) == 0
+	if rep.Valid {
+		rep.Solutions = map[int]string{0: "none", 1: "unique", 2: "multiple"}[sudoku.CountSolutions(board, 2)]
+	}
+	code := 0
+	if !rep.Valid || rep.Solutions == "none" {
+		code = 1
+	}
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(rep)
+		return code
+	}
+	for _, p := range rep.Conflicts {
+		fmt.Fprintf(stdout, "%s: %d at %s and %s\n", p.Unit, p.Value, p.Cells[0], p.Cells[1])
+	}
+	if rep.Valid {
+		fmt.Fprintln(stdout, "No conflicts")
+	} else {
+		fmt.Fprintf(stdout, "%d conflicting pairs\n", len(rep.Conflicts))
+	}
+	fmt.Fprintf(stdout, "Solutions: %s\n", rep.Solutions)
+	return code
+}
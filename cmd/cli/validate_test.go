@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func TestValidateConflicts(t *testing.T) {
+	// 5 at r1c1, r1c2 (row and box) and r2c1 (column and box)
+	s := "55" + strings.Repeat("0", 7) + "5" + strings.Repeat("0", 71)
+	var out, errBuf bytes.Buffer
+	if code := runCLI([]string{"validate", "-json", "-string", s}, &out, &errBuf); code != 1 {
+		t.Fatalf("expected exit 1, got %d: %s", code, errBuf.String())
+	}
+	var rep validateReport
+	if err := json.Unmarshal(out.Bytes(), &rep); err != nil {
+		t.Fatal(err)
+	}
+	want := []conflictPair{
+		{"row 1", 5, [2]string{"r1c1", "r1c2"}},
+		{"column 1", 5, [2]string{"r1c1", "r2c1"}},
+		{"box 1", 5, [2]string{"r1c1", "r1c2"}},
+		{"box 1", 5, [2]string{"r1c1", "r2c1"}},
+		{"box 1", 5, [2]string{"r1c2", "r2c1"}},
+	}
+	if rep.Valid || rep.Solutions != "none" || len(rep.Conflicts) != len(want) {
+		t.Fatalf("report %+v", rep)
+	}
+	for i, p := range want {
+		if rep.Conflicts[i] != p {
+			t.Fatalf("conflict %d = %+v, want %+v", i, rep.Conflicts[i], p)
+		}
+	}
+
+	out.Reset()
+	runCLI([]string{"validate", "-string", s}, &out, &errBuf)
+	if !strings.Contains(out.String(), "row 1: 5 at r1c1 and r1c2\n") || !strings.Contains(out.String(), "5 conflicting pairs") {
+		t.Fatalf("text output:\n%s", out.String())
+	}
+
+	// the same board as .sdk rows in a file keeps its conflicts too
+	path := filepath.Join(t.TempDir(), "board.sdk")
+	var sdk strings.Builder
+	for r := 0; r < 9; r++ {
+		sdk.WriteString(s[r*9:r*9+9] + "\n")
+	}
+	if err := os.WriteFile(path, []byte(sdk.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if code := runCLI([]string{"validate", "-file", path}, &out, &errBuf); code != 1 || !strings.Contains(out.String(), "5 conflicting pairs") {
+		t.Fatalf("-file: exit %d, output:\n%s", code, out.String())
+	}
+}
+
+func TestValidateSolutionCount(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "board.txt")
+	// the CLI's own grid output, read with ParseAnyUnchecked
+	easy, _ := sudoku.FromString(sudokutest.Easy)
+	if err := os.WriteFile(path, []byte("Puzzle:\n"+sudoku.Format(easy, sudoku.FormatUnicode)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		args []string
+		code int
+		want string
+	}{
+		{[]string{"-file", path}, 0, "Solutions: unique"},
+		{[]string{"-string", strings.Repeat(".", 81)}, 0, "Solutions: multiple"},
+		{[]string{"-string", sudokutest.Unsolvable}, 1, "Solutions: none"},
+	} {
+		var out, errBuf bytes.Buffer
+		if code := runCLI(append([]string{"validate"}, tc.args...), &out, &errBuf); code != tc.code || !strings.Contains(out.String(), tc.want) {
+			t.Fatalf("%v: exit %d, output %q", tc.args, code, out.String())
+		}
+	}
+	var out, errBuf bytes.Buffer
+	if code := runCLI([]string{"validate", "-string", "12x"}, &out, &errBuf); code != 1 {
+		t.Fatalf("expected exit 1 for bad input, got %d", code)
+	}
+	if code := runCLI([]string{"validate"}, &out, &errBuf); code != 2 {
+		t.Fatalf("expected usage error, got %d", code)
+	}
+}
@@ -0,0 +1,24 @@
+module go.rumenx.com/sudoku/cmd/grpc-server
+
+go 1.23.0
+
+require (
+	go.rumenx.com/sudoku v0.0.0
+	go.rumenx.com/sudoku/proto v0.0.0
+	google.golang.org/grpc v1.72.0
+)
+
+require (
+	golang.org/x/net v0.38.0 // indirect
+	golang.org/x/sys v0.31.0 // indirect
+	golang.org/x/text v0.23.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250218202821-56aae31c358a // indirect
+	google.golang.org/protobuf v1.36.5 // indirect
+)
+
+// Built from this repository; the server is its own module so the library
+// does not depend on gRPC.
+replace (
+	go.rumenx.com/sudoku => ../..
+	go.rumenx.com/sudoku/proto => ../../proto
+)
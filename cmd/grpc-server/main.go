@@ -0,0 +1,37 @@
+// Command grpc-server serves the sudoku.v1.Sudoku gRPC API defined in
+// proto/sudoku/v1/sudoku.proto, for callers that prefer protobuf to the JSON API.
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"google.golang.org/grpc"
+
+	sudokuv1 "go.rumenx.com/sudoku/proto/sudoku/v1"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "listen address")
+	flag.Parse()
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s := grpc.NewServer()
+	sudokuv1.RegisterSudokuServer(s, service{})
+	go func() {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+		<-ch
+		s.GracefulStop()
+	}()
+	log.Printf("listening on %s", ln.Addr())
+	if err := s.Serve(ln); err != nil {
+		log.Fatal(err)
+	}
+}
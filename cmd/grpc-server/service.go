@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.rumenx.com/sudoku"
+	sudokuv1 "go.rumenx.com/sudoku/proto/sudoku/v1"
+)
+
+// service implements sudokuv1.SudokuServer on top of the library. Malformed
+// boards fail with InvalidArgument, boards breaking the rules or lacking the
+// solutions a call needs with FailedPrecondition.
+type service struct {
+	sudokuv1.UnimplementedSudokuServer
+}
+
+var difficulties = map[sudokuv1.Difficulty]sudoku.Difficulty{
+	sudokuv1.Difficulty_DIFFICULTY_UNSPECIFIED: sudoku.Medium,
+	sudokuv1.Difficulty_DIFFICULTY_EASY:        sudoku.Easy,
+	sudokuv1.Difficulty_DIFFICULTY_MEDIUM:      sudoku.Medium,
+	sudokuv1.Difficulty_DIFFICULTY_HARD:        sudoku.Hard,
+}
+
+// maxAttempts caps the uniqueness retries one Generate call may ask for.
+const maxAttempts = 10
+
+func (service) Generate(ctx context.Context, req *sudokuv1.GenerateRequest) (*sudokuv1.GenerateResponse, error) {
+	d, ok := difficulties[req.GetDifficulty()]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown difficulty %v", req.GetDifficulty())
+	}
+	attempts := int(req.GetAttempts())
+	if attempts < 1 {
+		attempts = 3
+	}
+	if attempts > maxAttempts {
+		return nil, status.Errorf(codes.InvalidArgument, "attempts must be at most %d", maxAttempts)
+	}
+	seed := req.GetSeed()
+	if seed == 0 {
+		seed = rand.Uint64N(math.MaxUint64) + 1
+	}
+	puz, err := sudoku.NewGenerator(seed).Generate(d, attempts)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "generation failed")
+	}
+	res := &sudokuv1.GenerateResponse{Puzzle: puz.String(), Seed: seed, Id: sudoku.PuzzleID(puz)}
+	if req.GetIncludeSolution() {
+		if sol, ok := sudoku.Solve(puz); ok {
+			res.Solution = sol.String()
+		}
+	}
+	return res, nil
+}
+
+func (service) Solve(ctx context.Context, req *sudokuv1.SolveRequest) (*sudokuv1.SolveResponse, error) {
+	b, err := parsePuzzle(req.GetPuzzle())
+	if err != nil {
+		return nil, err
+	}
+	sol, ok := sudoku.Solve(b)
+	if !ok {
+		return nil, status.Error(codes.FailedPrecondition, "unsolvable")
+	}
+	return &sudokuv1.SolveResponse{Solution: sol.String()}, nil
+}
+
+func (service) Hint(ctx context.Context, req *sudokuv1.HintRequest) (*sudokuv1.HintResponse, error) {
+	b, err := parsePuzzle(req.GetPuzzle())
+	if err != nil {
+		return nil, err
+	}
+	if st, ok := sudoku.HintExplain(b); ok {
+		return &sudokuv1.HintResponse{
+			Row: int32(st.Cell.Row), Col: int32(st.Cell.Col), Value: int32(st.Value),
+			Technique: st.Technique.String(), Explanation: st.Text,
+		}, nil
+	}
+	r, c, v, ok := sudoku.Hint(b)
+	if !ok {
+		return nil, status.Error(codes.FailedPrecondition, "no hint available")
+	}
+	return &sudokuv1.HintResponse{Row: int32(r), Col: int32(c), Value: int32(v)}, nil
+}
+
+func (service) Validate(ctx context.Context, req *sudokuv1.ValidateRequest) (*sudokuv1.ValidateResponse, error) {
+	b, err := parseCells(req.GetPuzzle())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	res := &sudokuv1.ValidateResponse{Valid: true}
+	for _, c := range sudoku.ValidateAll(b) {
+		res.Valid = false
+		pc := &sudokuv1.Conflict{Unit: sudoku.Unit{Kind: c.Kind, Index: c.Index}.String(), Value: int32(c.Value)}
+		for _, cell := range c.Cells {
+			pc.Cells = append(pc.Cells, cell.String())
+		}
+		res.Conflicts = append(res.Conflicts, pc)
+	}
+	return res, nil
+}
+
+func (service) Rate(ctx context.Context, req *sudokuv1.RateRequest) (*sudokuv1.RateResponse, error) {
+	b, err := parsePuzzle(req.GetPuzzle())
+	if err != nil {
+		return nil, err
+	}
+	rating, err := sudoku.Rate(b)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	d := sudokuv1.Difficulty_DIFFICULTY_HARD
+	switch rating.Difficulty {
+	case sudoku.Easy:
+		d = sudokuv1.Difficulty_DIFFICULTY_EASY
+	case sudoku.Medium:
+		d = sudokuv1.Difficulty_DIFFICULTY_MEDIUM
+	}
+	return &sudokuv1.RateResponse{Difficulty: d, Hardest: rating.Hardest, Clues: int32(rating.Clues)}, nil
+}
+
+// parsePuzzle parses and validates an 81-character board, mapping rule
+// violations to FailedPrecondition and anything else to InvalidArgument.
+func parsePuzzle(s string) (sudoku.Board, error) {
+	b, err := sudoku.FromString(s)
+	switch {
+	case errors.Is(err, sudoku.ErrInvalidBoard):
+		return b, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return b, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return b, nil
+}
+
+// parseCells reads an 81-character board without checking the rules, so
+// Validate can report every conflict.
+func parseCells(s string) (sudoku.Board, error) {
+	var b sudoku.Board
+	if len(s) != 81 {
+		return b, errors.New("input must be 81 characters")
+	}
+	for i := range 81 {
+		switch ch := s[i]; {
+		case ch >= '1' && ch <= '9':
+			b[i/9][i%9] = int(ch - '0')
+		case ch != '0' && ch != '.':
+			return b, errors.New("invalid character in board")
+		}
+	}
+	return b, nil
+}
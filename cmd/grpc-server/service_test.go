@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"go.rumenx.com/sudoku"
+	sudokuv1 "go.rumenx.com/sudoku/proto/sudoku/v1"
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+// newClient serves the service over an in-memory listener.
+func newClient(t *testing.T) sudokuv1.SudokuClient {
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	sudokuv1.RegisterSudokuServer(s, service{})
+	go s.Serve(ln)
+	t.Cleanup(s.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return sudokuv1.NewSudokuClient(conn)
+}
+
+func TestGenerateAndSolve(t *testing.T) {
+	c, ctx := newClient(t), context.Background()
+	gen, err := c.Generate(ctx, &sudokuv1.GenerateRequest{Difficulty: sudokuv1.Difficulty_DIFFICULTY_EASY, Seed: 42, IncludeSolution: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := c.Generate(ctx, &sudokuv1.GenerateRequest{Difficulty: sudokuv1.Difficulty_DIFFICULTY_EASY, Seed: 42})
+	if gen.Seed != 42 || again.Puzzle != gen.Puzzle {
+		t.Fatalf("seed 42 not reproducible: %s vs %s", gen.Puzzle, again.Puzzle)
+	}
+	sol, err := c.Solve(ctx, &sudokuv1.SolveRequest{Puzzle: gen.Puzzle})
+	if err != nil || sol.Solution != gen.Solution {
+		t.Fatalf("solve = %v, %v; want %s", sol, err, gen.Solution)
+	}
+}
+
+func TestHintValidateRate(t *testing.T) {
+	c, ctx := newClient(t), context.Background()
+	hint, err := c.Hint(ctx, &sudokuv1.HintRequest{Puzzle: sudokutest.Easy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sol, _ := sudoku.Solve(sudokutest.MustBoard(sudokutest.Easy))
+	if hint.Technique == "" || hint.Explanation == "" || int(hint.Value) != sol[hint.Row][hint.Col] {
+		t.Fatalf("hint = %v", hint)
+	}
+	rate, err := c.Rate(ctx, &sudokuv1.RateRequest{Puzzle: sudokutest.Easy})
+	if err != nil || rate.Difficulty != sudokuv1.Difficulty_DIFFICULTY_EASY {
+		t.Fatalf("rate = %v, %v", rate, err)
+	}
+	bad := "11" + sudokutest.Easy[2:]
+	v, err := c.Validate(ctx, &sudokuv1.ValidateRequest{Puzzle: bad})
+	if err != nil || v.Valid || len(v.Conflicts) == 0 || v.Conflicts[0].Unit != "row 1" {
+		t.Fatalf("validate = %v, %v", v, err)
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	c, ctx := newClient(t), context.Background()
+	_, err := c.Solve(ctx, &sudokuv1.SolveRequest{Puzzle: "123"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("short puzzle: %v", err)
+	}
+	_, err = c.Solve(ctx, &sudokuv1.SolveRequest{Puzzle: "11" + strings.Repeat("0", 79)})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("conflicting puzzle: %v", err)
+	}
+	_, err = c.Rate(ctx, &sudokuv1.RateRequest{Puzzle: strings.Repeat("0", 81)})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("empty puzzle rated: %v", err)
+	}
+	_, err = c.Generate(ctx, &sudokuv1.GenerateRequest{Attempts: maxAttempts + 1})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("attempts over the cap: %v", err)
+	}
+}
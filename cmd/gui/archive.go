@@ -0,0 +1,136 @@
+//go:build gui
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"go.rumenx.com/sudoku"
+)
+
+// dailyArchive fetches past daily puzzles from a server and remembers, per day,
+// the best completion time in the app preferences.
+type dailyArchive struct {
+	server string
+	prefs  fyne.Preferences
+	client *http.Client
+}
+
+func newDailyArchive(server string, prefs fyne.Preferences) *dailyArchive {
+	return &dailyArchive{server: strings.TrimRight(server, "/"), prefs: prefs, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func dailyKey(day time.Time) string { return "daily." + day.Format(time.DateOnly) }
+
+// fetch loads the puzzle of day from GET /daily.
+func (a *dailyArchive) fetch(day time.Time) (sudoku.Board, error) {
+	resp, err := a.client.Get(a.server + "/daily?date=" + url.QueryEscape(day.Format(time.DateOnly)))
+	if err != nil {
+		return sudoku.Board{}, err
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Puzzle sudoku.Board `json:"puzzle"`
+		Error  string       `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return sudoku.Board{}, fmt.Errorf("daily %s: %w", day.Format(time.DateOnly), err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return sudoku.Board{}, fmt.Errorf("daily %s: %s", day.Format(time.DateOnly), body.Error)
+	}
+	return body.Puzzle, nil
+}
+
+// completed returns the best recorded time for day.
+func (a *dailyArchive) completed(day time.Time) (time.Duration, bool) {
+	secs := a.prefs.Int(dailyKey(day))
+	return time.Duration(secs) * time.Second, secs > 0
+}
+
+// record stores d for day unless a faster time is already recorded.
+func (a *dailyArchive) record(day time.Time, d time.Duration) {
+	secs := max(int(d.Round(time.Second).Seconds()), 1)
+	if best, ok := a.completed(day); ok && int(best.Seconds()) <= secs {
+		return
+	}
+	a.prefs.SetInt(dailyKey(day), secs)
+}
+
+// show opens a month calendar of past dailies. Completed days show their time;
+// picking a day fetches its puzzle off the UI goroutine and hands it to open.
+func (a *dailyArchive) show(w fyne.Window, open func(day time.Time, b sudoku.Board)) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	title := widget.NewLabel("")
+	summary := widget.NewLabel("")
+	days := container.NewGridWithColumns(7)
+	var dlg dialog.Dialog
+	var render func()
+	render = func() {
+		title.SetText(month.Format("January 2006"))
+		days.Objects = nil
+		for _, wd := range []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"} {
+			days.Add(widget.NewLabel(wd))
+		}
+		for i := 0; i < (int(month.Weekday())+6)%7; i++ {
+			days.Add(widget.NewLabel(""))
+		}
+		done, total := 0, 0
+		for day := month; day.Month() == month.Month(); day = day.AddDate(0, 0, 1) {
+			label := fmt.Sprintf("%d", day.Day())
+			if t, ok := a.completed(day); ok {
+				label = fmt.Sprintf("%d ✓ %02d:%02d", day.Day(), int(t.Minutes()), int(t.Seconds())%60)
+				done++
+			}
+			day := day
+			var btn *widget.Button
+			btn = widget.NewButton(label, func() {
+				btn.Disable()
+				go func() {
+					b, err := a.fetch(day)
+					fyne.Do(func() {
+						btn.Enable()
+						if err != nil {
+							dialog.ShowError(err, w)
+							return
+						}
+						dlg.Hide()
+						open(day, b)
+					})
+				}()
+			})
+			if day.After(today) {
+				btn.Disable()
+			} else {
+				total++
+			}
+			days.Add(btn)
+		}
+		summary.SetText(fmt.Sprintf("%d of %d completed", done, total))
+		days.Refresh()
+	}
+	prev := widget.NewButton("‹", func() { month = month.AddDate(0, -1, 0); render() })
+	next := widget.NewButton("›", func() {
+		if month.AddDate(0, 1, 0).After(today) {
+			return
+		}
+		month = month.AddDate(0, 1, 0)
+		render()
+	})
+	render()
+	content := container.NewBorder(container.NewHBox(prev, title, next), summary, nil, nil, days)
+	dlg = dialog.NewCustom("Daily archive", "Close", content, w)
+	dlg.Resize(fyne.NewSize(520, 420))
+	dlg.Show()
+}
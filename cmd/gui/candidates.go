@@ -0,0 +1,114 @@
+//go:build gui
+
+package main
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+
+	"go.rumenx.com/sudoku"
+)
+
+// cellMarks shows the candidates or pencil marks of an empty cell as a small
+// 3x3 block of digits drawn over the entry; text does not take taps, so the cell stays
+// editable underneath.
+type cellMarks struct {
+	rows [3]*canvas.Text
+	box  *fyne.Container
+}
+
+func newCellMarks() *cellMarks {
+	m := &cellMarks{}
+	objs := make([]fyne.CanvasObject, len(m.rows))
+	for i := range m.rows {
+		m.rows[i] = canvas.NewText("", color.NRGBA{R: 100, G: 116, B: 139, A: 255})
+		m.rows[i].TextSize = 9
+		m.rows[i].TextStyle = fyne.TextStyle{Monospace: true}
+		objs[i] = m.rows[i]
+	}
+	m.box = container.NewCenter(container.New(layout.NewCustomPaddedVBoxLayout(0), objs...))
+	m.box.Hide()
+	return m
+}
+
+// set shows vs in their keypad positions (1-3 on top), blanks for the rest.
+func (m *cellMarks) set(vs []int) {
+	has := make(map[int]bool, len(vs))
+	for _, v := range vs {
+		has[v] = true
+	}
+	for i, t := range m.rows {
+		digits := make([]string, 3)
+		for j := range digits {
+			digits[j] = " "
+			if v := i*3 + j + 1; has[v] {
+				digits[j] = strconv.Itoa(v)
+			}
+		}
+		t.Text = strings.Join(digits, " ")
+		t.Refresh()
+	}
+	m.box.Show()
+}
+
+// updateMarks redraws the marks of the empty cells: sudoku.CandidateNotes of a
+// classic 9x9 board when auto is set, and the player's pencil marks otherwise
+// (or when the candidates cannot be computed: other sizes, variants and
+// unreadable entries).
+func updateMarks(st *gridState, auto bool) {
+	var notes sudoku.Notes
+	if auto {
+		g, err := gridFromEntries(st)
+		if err == nil {
+			var b sudoku.Board
+			b, err = g.ToBoard()
+			notes = sudoku.CandidateNotes(b)
+		}
+		auto = err == nil
+	}
+	for r := range st.marks {
+		for c, m := range st.marks[r] {
+			e, placeholder := st.entries[r][c], "0"
+			switch {
+			case e.Text != "":
+				m.box.Hide()
+			case auto:
+				m.set(notes.Candidates(r, c))
+				placeholder = "" // the marks take its place
+			case st.pencil[r][c] != 0:
+				m.set(maskValues(st.pencil[r][c]))
+				placeholder = ""
+			default:
+				m.box.Hide()
+			}
+			if e.PlaceHolder != placeholder {
+				e.SetPlaceHolder(placeholder)
+			}
+		}
+	}
+}
+
+// maskValues lists the values whose bits are set in mask, ascending.
+func maskValues(mask uint32) []int {
+	var vs []int
+	for v := 1; v < 32; v++ {
+		if mask&(1<<v) != 0 {
+			vs = append(vs, v)
+		}
+	}
+	return vs
+}
+
+// resetPencil clears every pencil mark, sized for the current grid.
+func resetPencil(st *gridState) {
+	st.pencil = make([][]uint32, st.size)
+	for r := range st.pencil {
+		st.pencil[r] = make([]uint32, st.size)
+	}
+}
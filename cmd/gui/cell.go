@@ -0,0 +1,85 @@
+//go:build gui
+
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+
+	"go.rumenx.com/sudoku"
+)
+
+// cellEntry is a grid cell made for keyboard play: a digit, or on 16x16 a
+// letter A-G for 10-16, replaces the value (0, Backspace and Delete clear it), the arrow keys move to the next
+// open cell, and the undo and redo shortcuts go to the game history rather
+// than the entry's own text history, so Ctrl+Z and Ctrl+Y work the same
+// whether or not a cell has focus. Tab follows the grid row by row.
+type cellEntry struct {
+	widget.Entry
+	maxDigit       int
+	onUndo, onRedo func()
+	onMove         func(e *cellEntry, dr, dc int)
+}
+
+func newCellEntry(maxDigit int) *cellEntry {
+	e := &cellEntry{maxDigit: maxDigit}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+func (e *cellEntry) TypedRune(r rune) {
+	if r == '0' {
+		e.SetText("")
+	} else if v, ok := cellValue(string(unicode.ToUpper(r))); ok && v <= e.maxDigit {
+		e.SetText(cellText(v))
+	}
+}
+
+func (e *cellEntry) TypedKey(k *fyne.KeyEvent) {
+	switch k.Name {
+	case fyne.KeyUp:
+		e.onMove(e, -1, 0)
+	case fyne.KeyDown:
+		e.onMove(e, 1, 0)
+	case fyne.KeyLeft:
+		e.onMove(e, 0, -1)
+	case fyne.KeyRight:
+		e.onMove(e, 0, 1)
+	case fyne.KeyBackspace, fyne.KeyDelete:
+		e.SetText("")
+	default:
+		e.Entry.TypedKey(k)
+	}
+}
+
+func (e *cellEntry) TypedShortcut(s fyne.Shortcut) {
+	switch s.(type) {
+	case *fyne.ShortcutUndo:
+		e.onUndo()
+	case *fyne.ShortcutRedo:
+		e.onRedo()
+	default:
+		e.Entry.TypedShortcut(s)
+	}
+}
+
+// cellText is the text of value v in a cell: its GridAlphabet character, so
+// 10-16 read A-G, or "" for empty.
+func cellText(v int) string {
+	if v <= 0 || v >= len(sudoku.GridAlphabet) {
+		return ""
+	}
+	return sudoku.GridAlphabet[v : v+1]
+}
+
+// cellValue parses a cell text written by cellText; "" is not a value.
+func cellValue(s string) (int, bool) {
+	if len(s) != 1 {
+		return 0, false
+	}
+	v := strings.IndexByte(sudoku.GridAlphabet, s[0])
+	return v, v > 0
+}
@@ -0,0 +1,160 @@
+//go:build gui
+
+package main
+
+import (
+	"fmt"
+	"image/png"
+	"io"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/render"
+)
+
+// puzzleFilter lists the puzzle files Open and Save offer; ParseAny reads them all.
+var puzzleFilter = storage.NewExtensionFileFilter([]string{".sdk", ".sdm", ".ss", ".txt"})
+
+// playImported parses a puzzle from r with sudoku.ParseAny and passes it with
+// its measured difficulty to play; errors, including puzzles without a unique
+// solution, are shown instead.
+func playImported(w fyne.Window, r io.Reader, play func(sudoku.Board, sudoku.Difficulty)) {
+	b, err := sudoku.ParseAny(r)
+	if err != nil {
+		dialog.ShowError(err, w)
+		return
+	}
+	rating, err := sudoku.Rate(b)
+	if err != nil {
+		dialog.ShowError(err, w) // no solution or several: not playable
+		return
+	}
+	play(b, rating.Difficulty)
+}
+
+// showOpenPuzzle picks a puzzle file and plays it; an .sdm collection plays
+// its first puzzle.
+func showOpenPuzzle(w fyne.Window, play func(sudoku.Board, sudoku.Difficulty)) {
+	fd := dialog.NewFileOpen(func(rc fyne.URIReadCloser, err error) {
+		if err != nil || rc == nil {
+			return
+		}
+		defer rc.Close()
+		playImported(w, rc, play)
+	}, w)
+	fd.SetFilter(puzzleFilter)
+	fd.Show()
+}
+
+// showSavePuzzle saves b as a one-line .sdm when the chosen name ends in .sdm,
+// and as a nine-line .sdk otherwise.
+func showSavePuzzle(w fyne.Window, b sudoku.Board) {
+	fd := dialog.NewFileSave(func(wc fyne.URIWriteCloser, err error) {
+		if err != nil || wc == nil {
+			return
+		}
+		if strings.EqualFold(wc.URI().Extension(), ".sdm") {
+			err = sudoku.WriteSDM(wc, []sudoku.Board{b})
+		} else {
+			_, err = io.WriteString(wc, sdkText(b))
+		}
+		if cerr := wc.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("save puzzle: %w", err), w)
+		}
+	}, w)
+	fd.SetFileName("puzzle.sdk")
+	fd.SetFilter(puzzleFilter)
+	fd.Show()
+}
+
+// sdkText is b in SadMan .sdk form: nine lines of nine cells, '.' for empty.
+func sdkText(b sudoku.Board) string {
+	s := strings.ReplaceAll(b.String(), "0", ".")
+	var sb strings.Builder
+	for r := 0; r < 9; r++ {
+		sb.WriteString(s[r*9 : r*9+9])
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// showExport asks for an image format and saves the board g, with the clues in
+// givens drawn as givens (a zero Grid draws every value as one), through the
+// render package. PNG and PDF take any size; SVG and candidate marks are 9x9
+// only, and the PDF worksheet prints every value as a clue.
+func showExport(w fyne.Window, g, givens sudoku.Grid, d sudoku.Difficulty) {
+	format := widget.NewRadioGroup([]string{"PNG", "SVG", "PDF"}, nil)
+	format.Horizontal = true
+	format.SetSelected("PNG")
+	notes := widget.NewCheck("Include candidates (9x9 PNG and SVG)", nil)
+	dialog.ShowCustomConfirm("Export board", "Export", "Cancel", container.NewVBox(format, notes), func(ok bool) {
+		if !ok {
+			return
+		}
+		ext := "." + strings.ToLower(format.Selected)
+		fd := dialog.NewFileSave(func(wc fyne.URIWriteCloser, err error) {
+			if err != nil || wc == nil {
+				return
+			}
+			err = exportBoard(wc, ext, g, givens, d, notes.Checked)
+			if cerr := wc.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("export: %w", err), w)
+			}
+		}, w)
+		fd.SetFileName("sudoku" + ext)
+		fd.SetFilter(storage.NewExtensionFileFilter([]string{ext}))
+		fd.Show()
+	}, w)
+}
+
+// exportBoard writes g to out as ext (".png", ".svg" or ".pdf"); see showExport.
+func exportBoard(out io.Writer, ext string, g, givens sudoku.Grid, d sudoku.Difficulty, notes bool) error {
+	if givens.Size != g.Size {
+		givens = sudoku.Grid{} // left from a game on another size
+	}
+	if ext == ".pdf" {
+		return render.Worksheet(out, []render.WorksheetPuzzle{{Puzzle: g, Difficulty: d}}, render.WorksheetOptions{PerPage: 1})
+	}
+	b, err := g.ToBoard()
+	if err != nil {
+		if ext == ".png" && !notes {
+			return png.Encode(out, render.Grid(g, givens))
+		}
+		return fmt.Errorf("%s export with these options needs a 9x9 board: %w", ext, err)
+	}
+	var opts render.RenderOptions
+	if givens.Cells != nil {
+		if opts.Givens, err = givens.ToBoard(); err != nil {
+			return err
+		}
+	}
+	if notes {
+		n := sudoku.CandidateNotes(b)
+		opts.Notes = &n
+	}
+	if ext == ".svg" {
+		svg, err := render.SVG(b, opts)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(svg)
+		return err
+	}
+	img, err := render.Image(b, opts)
+	if err != nil {
+		return err
+	}
+	return png.Encode(out, img)
+}
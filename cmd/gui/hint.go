@@ -0,0 +1,96 @@
+//go:build gui
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+
+	"go.rumenx.com/sudoku"
+)
+
+// hintState is the progress of a tiered hint. Each press of Hint on an
+// unchanged board reveals more of the same step: first the technique, then
+// the cells involved, and only then the digit.
+type hintState struct {
+	step  sudoku.Step
+	named bool   // step comes from HintExplain; false when no single applies
+	level int    // presses so far: 0 none, 1 technique named, 2 cells shown
+	board string // board the step was found on
+}
+
+// findHint returns the next step for g: a single from sudoku.HintExplain on
+// classic boards, or else the solution value of prefer when it is an empty
+// cell (Row -1 for none), or of the first empty cell.
+func findHint(g sudoku.Grid, prefer sudoku.Cell) (sudoku.Step, bool, bool) {
+	if b, err := g.ToBoard(); err == nil {
+		if step, ok := sudoku.HintExplain(b); ok {
+			return step, true, true
+		}
+	}
+	r, c, v, ok := sudoku.HintGrid(g)
+	if ok && prefer.Row >= 0 && g.Cells[prefer.Row][prefer.Col] == 0 {
+		if sol, solved := g.Solve(); solved {
+			r, c, v = prefer.Row, prefer.Col, sol.Cells[prefer.Row][prefer.Col]
+		}
+	}
+	return sudoku.Step{Cell: sudoku.Cell{Row: r, Col: c}, Value: v}, false, ok
+}
+
+// showHint takes the hint one tier further; a changed board starts over.
+func showHint(w fyne.Window, st *gridState) {
+	g, err := gridFromEntries(st)
+	if err != nil {
+		dialog.ShowError(err, w)
+		return
+	}
+	if st.hint.level == 0 || st.hint.board != g.String() {
+		r, c := findEntry(st, st.selected)
+		step, named, ok := findHint(g, sudoku.Cell{Row: r, Col: c})
+		if !ok {
+			dialog.ShowInformation("No hint", "Board is invalid or solved.", w)
+			return
+		}
+		st.hint = hintState{step: step, named: named, board: g.String()}
+	}
+	st.hint.level++
+	step := st.hint.step
+	switch st.hint.level {
+	case 1:
+		msg := "No single applies here; the next step needs a harder technique."
+		if st.hint.named {
+			info := step.Technique.Info()
+			msg = fmt.Sprintf("Look for a %s: %s", strings.ToLower(info.Name), info.Description)
+		}
+		dialog.ShowInformation("Hint", msg+"\nPress Hint again to see where.", w)
+	case 2:
+		st.guide, st.guideCell = guideCells(step.Cells), &step.Cell
+	default:
+		st.hint = hintState{}
+		st.guide, st.guideCell = nil, nil
+		e := st.entries[step.Cell.Row][step.Cell.Col]
+		e.SetText(cellText(step.Value))
+		e.Enable() // hint is user input
+	}
+}
+
+// guideCells builds a fresh highlight set, so st.guide is only ever replaced
+// whole and never written in place.
+func guideCells(cells []sudoku.Cell) map[sudoku.Cell]bool {
+	m := make(map[sudoku.Cell]bool, len(cells))
+	for _, c := range cells {
+		m[c] = true
+	}
+	return m
+}
+
+// clearHint drops a hint in progress and its highlights after an edit.
+func clearHint(st *gridState) {
+	if st.hint.level >= 2 {
+		st.guide, st.guideCell = nil, nil
+	}
+	st.hint = hintState{}
+}
@@ -0,0 +1,119 @@
+//go:build gui
+
+package main
+
+// cellEdit is one change to a cell's text, or to its pencil marks when notes
+// is set.
+type cellEdit struct {
+	row, col           int
+	old, new           string
+	notes              bool
+	oldMarks, newMarks uint32
+}
+
+// history records the player's cell edits, whether typed, from the number pad
+// or filled by Hint, and their pencil mark changes, for Undo and Redo. Loading
+// a board starts a new history.
+type history struct {
+	done     []cellEdit // oldest first
+	undone   []cellEdit // most recently undone last
+	values   [][]string // last known text of each cell
+	replay   bool       // cells are being written by history or setGrid, not the player
+	onChange func()     // called when undo or redo availability may have changed
+	onMarks  func()     // called after undo or redo changed pencil marks
+}
+
+// reset forgets all edits and takes the cells' current texts as the start.
+func (h *history) reset(st *gridState) {
+	h.done, h.undone = nil, nil
+	h.values = make([][]string, st.size)
+	for r := range h.values {
+		h.values[r] = make([]string, st.size)
+		for c := range h.values[r] {
+			h.values[r][c] = st.entries[r][c].Text
+		}
+	}
+	h.changed()
+}
+
+// record notes that the player changed cell r,c to text; a new edit discards
+// the redo history.
+func (h *history) record(r, c int, text string) {
+	if h.replay || r >= len(h.values) || h.values[r][c] == text {
+		return
+	}
+	h.done = append(h.done, cellEdit{row: r, col: c, old: h.values[r][c], new: text})
+	h.values[r][c] = text
+	h.undone = h.undone[:0]
+	h.changed()
+}
+
+// recordNotes notes that the player changed the pencil marks of r,c from old
+// to marks; like record, it discards the redo history.
+func (h *history) recordNotes(r, c int, old, marks uint32) {
+	if old == marks {
+		return
+	}
+	h.done = append(h.done, cellEdit{row: r, col: c, notes: true, oldMarks: old, newMarks: marks})
+	h.undone = h.undone[:0]
+	h.changed()
+}
+
+// undo reverts the last edit and reports whether there was one.
+func (h *history) undo(st *gridState) bool {
+	if len(h.done) == 0 {
+		return false
+	}
+	e := h.done[len(h.done)-1]
+	h.done = h.done[:len(h.done)-1]
+	h.undone = append(h.undone, e)
+	if e.notes {
+		h.applyMarks(st, e.row, e.col, e.oldMarks)
+	} else {
+		h.apply(st, e.row, e.col, e.old)
+	}
+	return true
+}
+
+// redo re-applies the last undone edit and reports whether there was one.
+func (h *history) redo(st *gridState) bool {
+	if len(h.undone) == 0 {
+		return false
+	}
+	e := h.undone[len(h.undone)-1]
+	h.undone = h.undone[:len(h.undone)-1]
+	h.done = append(h.done, e)
+	if e.notes {
+		h.applyMarks(st, e.row, e.col, e.newMarks)
+	} else {
+		h.apply(st, e.row, e.col, e.new)
+	}
+	return true
+}
+
+func (h *history) apply(st *gridState, r, c int, text string) {
+	h.replay = true
+	st.entries[r][c].SetText(text)
+	h.replay = false
+	h.values[r][c] = text
+	h.changed()
+}
+
+func (h *history) applyMarks(st *gridState, r, c int, marks uint32) {
+	if r < len(st.pencil) {
+		st.pencil[r][c] = marks
+	}
+	if h.onMarks != nil {
+		h.onMarks()
+	}
+	h.changed()
+}
+
+func (h *history) canUndo() bool { return len(h.done) > 0 }
+func (h *history) canRedo() bool { return len(h.undone) > 0 }
+
+func (h *history) changed() {
+	if h.onChange != nil {
+		h.onChange()
+	}
+}
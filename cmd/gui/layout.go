@@ -0,0 +1,157 @@
+//go:build gui
+
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	prefLeftHanded = "layout.leftHanded"
+	prefCompact    = "layout.compact"
+	prefAutoCheck  = "play.autoCheck"
+	prefCandidates = "play.candidates"
+	prefPadBelow   = "layout.padBelow"
+	prefLives      = "play.lives"
+	prefPalette    = "display.palette"
+)
+
+// layoutSettings are the window arrangement and play options, kept in the app
+// preferences.
+type layoutSettings struct {
+	leftHanded bool   // number pad and actions left of the grid instead of right
+	compact    bool   // hide the toolbar while a game is in progress
+	padBelow   bool   // number pad under the grid instead of beside it
+	autoCheck  bool   // mark wrong entries against the solution after every edit
+	candidates bool   // show the candidates of empty 9x9 cells
+	lives      bool   // wrong entries are mistakes and maxMistakes end the game
+	palette    string // name of the cell colour palette
+}
+
+func loadLayout(p fyne.Preferences) layoutSettings {
+	return layoutSettings{
+		leftHanded: p.Bool(prefLeftHanded),
+		compact:    p.Bool(prefCompact),
+		padBelow:   p.Bool(prefPadBelow),
+		autoCheck:  p.Bool(prefAutoCheck),
+		candidates: p.Bool(prefCandidates),
+		lives:      p.Bool(prefLives),
+		palette:    p.StringWithFallback(prefPalette, palettes[0].name),
+	}
+}
+
+func (l layoutSettings) save(p fyne.Preferences) {
+	p.SetBool(prefLeftHanded, l.leftHanded)
+	p.SetBool(prefCompact, l.compact)
+	p.SetBool(prefPadBelow, l.padBelow)
+	p.SetBool(prefAutoCheck, l.autoCheck)
+	p.SetBool(prefCandidates, l.candidates)
+	p.SetBool(prefLives, l.lives)
+	p.SetString(prefPalette, l.palette)
+}
+
+// showLayoutSettings edits l in a dialog; on Save the result is stored and
+// passed to apply.
+func showLayoutSettings(w fyne.Window, p fyne.Preferences, l layoutSettings, apply func(layoutSettings)) {
+	left := widget.NewCheck("Left-handed: number pad and actions on the left", nil)
+	left.SetChecked(l.leftHanded)
+	compact := widget.NewCheck("Compact: hide the toolbar during play", nil)
+	compact.SetChecked(l.compact)
+	padBelow := widget.NewCheck("Number pad below the grid", nil)
+	padBelow.SetChecked(l.padBelow)
+	autoCheck := widget.NewCheck("Auto-check: mark wrong entries as you play", nil)
+	autoCheck.SetChecked(l.autoCheck)
+	candidates := widget.NewCheck("Candidates: show what each empty 9x9 cell can still hold", nil)
+	candidates.SetChecked(l.candidates)
+	lives := widget.NewCheck(fmt.Sprintf("Limited lives: a wrong entry is a mistake, %d end the game", maxMistakes), nil)
+	lives.SetChecked(l.lives)
+	colors := widget.NewSelect(paletteNames(), nil)
+	colors.SetSelected(paletteNamed(l.palette).name)
+	content := container.NewVBox(left, compact, padBelow, autoCheck, candidates, lives,
+		container.NewHBox(widget.NewLabel("Colours:"), colors))
+	dialog.ShowCustomConfirm("Settings", "Save", "Cancel", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		l = layoutSettings{
+			leftHanded: left.Checked,
+			compact:    compact.Checked,
+			padBelow:   padBelow.Checked,
+			autoCheck:  autoCheck.Checked,
+			candidates: candidates.Checked,
+			lives:      lives.Checked,
+			palette:    colors.Selected,
+		}
+		l.save(p)
+		apply(l)
+	}, w)
+}
+
+// newNumberPad returns digit buttons for the current grid size plus erase and
+// notes buttons. They write into the last selected cell, leaving locked givens
+// alone, so a game can be played with one hand on a touch screen. In notes
+// mode a digit toggles a pencil mark of an empty cell instead, erase clears
+// them, and changed is called to redraw the marks; notes need a grid of at
+// most 9x9. Below the grid the pad is laid out in one row, or two for 16x16.
+func newNumberPad(st *gridState, below bool, changed func()) *fyne.Container {
+	cols := 3
+	switch {
+	case below && st.size > 9:
+		cols = (st.size + 2) / 2
+	case below:
+		cols = st.size + 2
+	case st.size > 9:
+		cols = 4
+	}
+	pad := container.NewGridWithColumns(cols)
+	set := func(text string) {
+		e := st.selected
+		if e == nil || e.Disabled() {
+			return
+		}
+		if st.notesMode {
+			if r, c := findEntry(st, e); r >= 0 && e.Text == "" {
+				old := st.pencil[r][c]
+				if v, ok := cellValue(text); ok {
+					st.pencil[r][c] ^= 1 << v
+				} else {
+					st.pencil[r][c] = 0
+				}
+				st.history.recordNotes(r, c, old, st.pencil[r][c])
+				changed()
+			}
+			return
+		}
+		e.SetText(text)
+	}
+	for v := 1; v <= st.size; v++ {
+		text := cellText(v)
+		pad.Add(widget.NewButton(text, func() { set(text) }))
+	}
+	pad.Add(widget.NewButtonWithIcon("", theme.ContentClearIcon(), func() { set("") }))
+	notes := widget.NewButtonWithIcon("Notes", theme.DocumentCreateIcon(), nil)
+	show := func() {
+		notes.Importance = widget.MediumImportance
+		if st.notesMode {
+			notes.Importance = widget.HighImportance
+		}
+		notes.Refresh()
+	}
+	notes.OnTapped = func() {
+		st.notesMode = !st.notesMode
+		show()
+	}
+	if st.size > 9 {
+		st.notesMode = false
+		notes.Disable()
+	}
+	show()
+	pad.Add(notes)
+	return pad
+}
@@ -3,9 +3,13 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"image/color"
-	"strconv"
+	"math/rand/v2"
+	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,17 +23,39 @@ import (
 	"fyne.io/fyne/v2/widget"
 
 	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/render"
 )
 
+// maxMistakes ends a game in limited-lives mode.
+const maxMistakes = 3
+
 // shared state for the GUI grid
 type gridState struct {
 	size, boxR, boxC int
-	entries          [][]*widget.Entry
+	constraints      []sudoku.Constraint // variant rules of the board; nil for classic
+	entries          [][]*cellEntry
 	bgs              [][]*canvas.Rectangle
+	colors           palette        // cell backgrounds and highlights
+	marks            [][]*cellMarks // candidates or pencil marks drawn over empty cells
+	pencil           [][]uint32     // the player's notes: bit v marks value v
+	notesMode        bool           // the number pad toggles notes instead of writing values
 	grid             *fyne.Container
 	timerStart       time.Time
 	timerStop        chan struct{}
 	timerLabel       *widget.Label
+	daily            time.Time            // day of the loaded daily puzzle; zero otherwise
+	givens           sudoku.Grid          // clues of the game in progress; zero when none
+	difficulty       sudoku.Difficulty    // of the game in progress; "" for dailies
+	mistakes         int                  // failed validations, or wrong entries with lives, in the game in progress
+	selected         *cellEntry           // last focused cell; the number pad writes here
+	conflicts        map[sudoku.Cell]bool // cells flagged by the last Validate; cleared on edit
+	solution         sudoku.Grid          // of the game in progress, for Check; zero when none
+	wrong            map[sudoku.Cell]bool // entries the last Check found wrong; cleared on edit
+	guide            map[sudoku.Cell]bool // cells the tutorial is explaining
+	guideCell        *sudoku.Cell         // cell the tutorial asks the player to fill
+	onEdit           func()               // called after any cell edit (tutorial)
+	hint             hintState            // tiered hint in progress; reset by any edit
+	history          history              // cell edits since the board was loaded, for Undo and Redo
 }
 
 func main() {
@@ -42,27 +68,45 @@ func main() {
 	st := &gridState{size: 9, boxR: 3, boxC: 3}
 	var toolbar *fyne.Container
 	var footer *fyne.Container
+	var relayout, updateChrome func()
+	var mistake func() // a wrong entry in limited-lives mode
+	settings := loadLayout(a.Preferences())
+	st.colors = paletteNamed(settings.palette)
+	undo := func() { st.history.undo(st) }
+	redo := func() { st.history.redo(st) }
+	// move focuses the nearest open cell from e in direction dr,dc; givens
+	// cannot take focus and are skipped
+	move := func(e *cellEntry, dr, dc int) {
+		r, c := findEntry(st, e)
+		if r < 0 {
+			return
+		}
+		for r, c = r+dr, c+dc; r >= 0 && r < st.size && c >= 0 && c < st.size; r, c = r+dr, c+dc {
+			if !st.entries[r][c].Disabled() {
+				w.Canvas().Focus(st.entries[r][c])
+				return
+			}
+		}
+	}
 
 	// Builders
 	rebuild := func() {
 		// recreate entries and backgrounds
-		st.entries = make([][]*widget.Entry, st.size)
+		st.entries = make([][]*cellEntry, st.size)
 		st.bgs = make([][]*canvas.Rectangle, st.size)
+		st.marks = make([][]*cellMarks, st.size)
 		grid := container.NewGridWithColumns(st.size)
 		for r := 0; r < st.size; r++ {
-			st.entries[r] = make([]*widget.Entry, st.size)
+			st.entries[r] = make([]*cellEntry, st.size)
 			st.bgs[r] = make([]*canvas.Rectangle, st.size)
+			st.marks[r] = make([]*cellMarks, st.size)
 			for c := 0; c < st.size; c++ {
 				// background with alternating sub-box colour
-				base := color.NRGBA{R: 245, G: 247, B: 250, A: 255} // light
-				alt := color.NRGBA{R: 230, G: 235, B: 240, A: 255}
-				if ((r/st.boxR)+(c/st.boxC))%2 == 1 {
-					base = alt
-				}
-				bg := canvas.NewRectangle(base)
-				bg.SetMinSize(fyne.NewSize(36, 36))
+				bg := canvas.NewRectangle(cellColor(st, r, c))
+				bg.SetMinSize(fyne.NewSize(cellSize(st.size), cellSize(st.size)))
 
-				e := widget.NewEntry()
+				e := newCellEntry(st.size)
+				e.onUndo, e.onRedo, e.onMove = undo, redo, move
 				e.SetPlaceHolder("0")
 				e.TextStyle = fyne.TextStyle{Monospace: true}
 				e.MultiLine = false
@@ -71,30 +115,54 @@ func main() {
 					if s == "" {
 						return nil
 					}
-					if len(s) > 1 {
+					v, ok := cellValue(s)
+					if !ok {
 						return fmt.Errorf("1 digit")
 					}
-					if s[0] < '0' || s[0] > '9' {
-						return fmt.Errorf("digit 0-9")
-					}
-					v := int(s[0] - '0')
 					if v > maxDigit {
-						return fmt.Errorf("max %d", maxDigit)
+						return fmt.Errorf("max %s", cellText(maxDigit))
 					}
 					return nil
 				}
+				e.OnChanged = func(s string) {
+					// only the player's own entries cost lives, not undo, redo or a new board
+					v, ok := cellValue(s)
+					wrong := ok && settings.lives && !st.history.replay && st.givens.Cells != nil &&
+						st.solution.Cells != nil && v != st.solution.Cells[r][c]
+					st.history.record(r, c, s)
+					st.conflicts, st.wrong = nil, nil
+					clearHint(st)
+					if settings.autoCheck {
+						st.wrong = wrongEntries(st)
+					}
+					updateMarks(st, settings.candidates)
+					if st.onEdit != nil {
+						st.onEdit()
+					}
+					if wrong {
+						mistake()
+					}
+				}
 
+				m := newCellMarks()
 				st.entries[r][c] = e
 				st.bgs[r][c] = bg
-				grid.Add(container.NewMax(bg, e))
+				st.marks[r][c] = m
+				grid.Add(container.NewMax(bg, e, m.box))
 			}
 		}
 		st.grid = grid
+		st.selected = nil
+		resetPencil(st)
+		st.history.reset(st)
 	}
 
 	// Controls
-	sizeSelect := widget.NewSelect([]string{"4x4 (2x2)", "6x6 (2x3)", "9x9 (3x3)"}, func(s string) {
+	var variantSelect *widget.Select
+	sizeSelect := widget.NewSelect([]string{"4x4 (2x2)", "6x6 (2x3)", "9x9 (3x3)", "16x16 (4x4)"}, func(s string) {
 		switch {
+		case strings.HasPrefix(s, "16x16"):
+			st.size, st.boxR, st.boxC = 16, 4, 4
 		case strings.HasPrefix(s, "4x4"):
 			st.size, st.boxR, st.boxC = 4, 2, 2
 		case strings.HasPrefix(s, "6x6"):
@@ -102,9 +170,14 @@ func main() {
 		default:
 			st.size, st.boxR, st.boxC = 9, 3, 3
 		}
+		if st.size != 9 {
+			variantSelect.SetSelected(variantNames[0])
+			variantSelect.Disable()
+		} else {
+			variantSelect.Enable()
+		}
 		rebuild()
-		content := container.NewBorder(toolbar, footer, nil, nil, st.grid)
-		w.SetContent(content)
+		relayout()
 	})
 	sizeSelect.Selected = "9x9 (3x3)"
 
@@ -115,22 +188,25 @@ func main() {
 
 	// Timer
 	st.timerLabel = widget.NewLabel("Time 00:00")
-	startTimer := func() {
+	// startTimer runs the game clock from elapsed (non-zero for a resumed game)
+	startTimer := func(elapsed time.Duration) {
 		if st.timerStop != nil {
 			close(st.timerStop)
 		}
 		st.timerStop = make(chan struct{})
-		st.timerStart = time.Now()
+		st.timerStart = time.Now().Add(-elapsed)
 		go func(ch <-chan struct{}) {
 			ticker := time.NewTicker(time.Second)
 			defer ticker.Stop()
 			for {
 				select {
 				case <-ticker.C:
-					d := time.Since(st.timerStart).Round(time.Second)
-					m := int(d.Minutes())
-					s := int(d.Seconds()) % 60
-					st.timerLabel.SetText(fmt.Sprintf("Time %02d:%02d", m, s))
+					fyne.Do(func() {
+						d := time.Since(st.timerStart).Round(time.Second)
+						m := int(d.Minutes())
+						s := int(d.Seconds()) % 60
+						st.timerLabel.SetText(fmt.Sprintf("Time %02d:%02d", m, s))
+					})
 				case <-ch:
 					return
 				}
@@ -146,38 +222,48 @@ func main() {
 
 	highlightSelected := func() {
 		// Reset all to base, highlight focused cell
-		var focused *widget.Entry
+		var focused *cellEntry
 		if f := w.Canvas().Focused(); f != nil {
-			if e, ok := f.(*widget.Entry); ok {
+			if e, ok := f.(*cellEntry); ok {
 				focused = e
 			}
 		}
 		for r := 0; r < st.size; r++ {
 			for c := 0; c < st.size; c++ {
-				// base colour
-				base := color.NRGBA{R: 245, G: 247, B: 250, A: 255}
-				alt := color.NRGBA{R: 230, G: 235, B: 240, A: 255}
-				if ((r/st.boxR)+(c/st.boxC))%2 == 1 {
-					base = alt
+				st.bgs[r][c].FillColor = cellColor(st, r, c)
+				cell := sudoku.Cell{Row: r, Col: c}
+				switch {
+				case st.conflicts[cell]:
+					st.bgs[r][c].FillColor = st.colors.conflict
+				case st.wrong[cell]:
+					st.bgs[r][c].FillColor = st.colors.wrong
+				case st.guideCell != nil && *st.guideCell == cell:
+					st.bgs[r][c].FillColor = st.colors.guideCell
+				case st.guide[cell]:
+					st.bgs[r][c].FillColor = st.colors.guide
 				}
-				st.bgs[r][c].FillColor = base
 				if focused != nil && st.entries[r][c] == focused {
-					st.bgs[r][c].FillColor = color.NRGBA{R: 204, G: 231, B: 255, A: 255}
+					st.bgs[r][c].FillColor = st.colors.selected
+					st.selected = focused
 				}
 				st.bgs[r][c].Refresh()
 			}
 		}
 	}
-	// poll focus changes lightly
+	// poll focus changes lightly; the grid state belongs to the UI goroutine
 	go func() {
 		ticker := time.NewTicker(150 * time.Millisecond)
 		defer ticker.Stop()
 		for range ticker.C {
-			highlightSelected()
+			fyne.Do(highlightSelected)
 		}
 	}()
 
-	btnGenerate := widget.NewButton("Generate", func() {
+	stats := playerStats{prefs: a.Preferences()}
+	replay := newReplayer()
+
+	var btnGenerate *widget.Button
+	btnGenerate = widget.NewButton("Generate", func() {
 		var d sudoku.Difficulty
 		switch difficulty.Selected { // default to medium
 		case string(sudoku.Easy):
@@ -187,28 +273,142 @@ func main() {
 		default:
 			d = sudoku.Medium
 		}
+		replay.halt()
 		g, _ := sudoku.NewGrid(st.size, st.boxR, st.boxC)
-		puz, err := g.Generate(d, 1)
+		g.Constraints = st.constraints
+		// a 16x16 takes seconds, so generate off the UI goroutine; the button
+		// shows it is busy and cannot start a second one meanwhile
+		btnGenerate.SetText("Generating…")
+		btnGenerate.Disable()
+		go func() {
+			// keep the generator's solution: solving a 16x16 again can take far longer
+			puz, sol, err := sudoku.NewGenerator(rand.Uint64()).GenerateGridWithSolution(g, d, 1)
+			fyne.Do(func() {
+				btnGenerate.SetText("Generate")
+				btnGenerate.Enable()
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				if st.size != g.Size || st.boxR != g.BoxRows || !slices.Equal(st.constraints, g.Constraints) {
+					return // the board shape changed while generating
+				}
+				setGrid(st, puz, true)
+				st.daily = time.Time{}
+				st.givens, st.difficulty, st.mistakes = puz.Clone(), d, 0
+				st.solution = sol
+				st.wrong = nil
+				stats.started(st.size, d)
+				startTimer(0)
+				updateChrome()
+			})
+		}()
+	})
+
+	// playBoard starts a 9x9 game on b; day is set for daily puzzles
+	playBoard := func(b sudoku.Board, d sudoku.Difficulty, day time.Time) {
+		if st.size != 9 {
+			sizeSelect.SetSelected("9x9 (3x3)")
+		}
+		variantSelect.SetSelected(variantNames[0])
+		replay.halt()
+		g := b.ToGrid()
+		setGrid(st, g, true)
+		st.daily = day
+		st.givens, st.difficulty, st.mistakes = g, d, 0
+		st.solution = sudoku.Grid{}
+		if sol, ok := sudoku.Solve(b); ok { // 9x9 puzzles solve at once
+			st.solution = sol.ToGrid()
+		}
+		st.wrong = nil
+		stats.started(st.size, d)
+		startTimer(0)
+		updateChrome()
+	}
+
+	// Daily archive, available when SUDOKU_SERVER points at a running server
+	var archive *dailyArchive
+	var btnArchive *widget.Button
+	if server := os.Getenv("SUDOKU_SERVER"); server != "" {
+		archive = newDailyArchive(server, a.Preferences())
+		btnArchive = widget.NewButton("Archive", func() {
+			archive.show(w, func(day time.Time, b sudoku.Board) { playBoard(b, "", day) })
+		})
+	}
+
+	playImport := func(b sudoku.Board, d sudoku.Difficulty) { playBoard(b, d, time.Time{}) }
+	btnImport := widget.NewButton("Import", func() { showImport(w, playImport) })
+
+	// exportBoard is the puzzle Copy and Save share: the givens of the game in
+	// progress, or else whatever is on the board
+	exportBoard := func() (sudoku.Board, bool) {
+		g := st.givens
+		if g.Cells == nil {
+			var err error
+			if g, err = gridFromEntries(st); err != nil {
+				dialog.ShowError(err, w)
+				return sudoku.Board{}, false
+			}
+		}
+		b, err := g.ToBoard()
 		if err != nil {
-			dialog.ShowError(err, w)
-			return
+			dialog.ShowError(fmt.Errorf("only 9x9 puzzles can be exported: %w", err), w)
+			return sudoku.Board{}, false
 		}
-		setGrid(st, puz, true)
-		startTimer()
-	})
+		return b, true
+	}
+	w.SetMainMenu(fyne.NewMainMenu(fyne.NewMenu("File",
+		fyne.NewMenuItem("Export…", func() {
+			g, err := gridFromEntries(st)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			showExport(w, g, st.givens, st.difficulty)
+		}),
+	), fyne.NewMenu("Puzzle",
+		fyne.NewMenuItem("Paste puzzle…", func() { showImport(w, playImport) }),
+		fyne.NewMenuItem("Open file…", func() { showOpenPuzzle(w, playImport) }),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Copy puzzle", func() {
+			if b, ok := exportBoard(); ok {
+				a.Clipboard().SetContent(b.String())
+			}
+		}),
+		fyne.NewMenuItem("Save puzzle as…", func() {
+			if b, ok := exportBoard(); ok {
+				showSavePuzzle(w, b)
+			}
+		}),
+	)))
 
-	btnSolve := widget.NewButton("Solve", func() {
+	var btnSolve *widget.Button
+	btnSolve = widget.NewButton("Solve", func() {
+		replay.halt()
 		g, err := gridFromEntries(st)
 		if err != nil {
 			dialog.ShowError(err, w)
 			return
 		}
-		if sol, ok := g.Solve(); ok {
-			setGrid(st, sol, false)
-			stopTimer()
-		} else {
-			dialog.ShowInformation("Unsolvable", "This puzzle has no solution.", w)
-		}
+		btnSolve.Disable()
+		solveInBackground(g, func(sol sudoku.Grid, err error) {
+			btnSolve.Enable()
+			if now, nerr := gridFromEntries(st); nerr != nil || now.String() != g.String() {
+				return // the board changed while solving
+			}
+			switch {
+			case err == nil:
+				setGrid(st, sol, false)
+				st.daily = time.Time{} // auto-solved games do not count as completed
+				st.givens, st.solution = sudoku.Grid{}, sudoku.Grid{}
+				stopTimer()
+				updateChrome()
+			case errors.Is(err, context.DeadlineExceeded):
+				dialog.ShowInformation("Too hard", "No solution was found in time.", w)
+			default:
+				dialog.ShowInformation("Unsolvable", "This puzzle has no solution.", w)
+			}
+		})
 	})
 
 	btnValidate := widget.NewButton("Validate", func() {
@@ -218,77 +418,319 @@ func main() {
 			return
 		}
 		if err := g.Validate(); err != nil {
+			if st.givens.Cells != nil && !settings.lives {
+				st.mistakes++
+			}
+			// flag every clashing cell at once, not just the first conflict
+			conflicts := g.ValidateAll()
+			flagged := make(map[sudoku.Cell]bool)
+			for _, cf := range conflicts {
+				for _, cell := range cf.Cells {
+					flagged[cell] = true
+				}
+			}
+			st.conflicts = flagged
+			if len(conflicts) > 1 {
+				err = fmt.Errorf("%w (and %d more conflicts)", err, len(conflicts)-1)
+			}
 			dialog.ShowError(fmt.Errorf("invalid: %w", err), w)
+		} else if st.givens.Cells != nil && g.IsComplete() {
+			elapsed := time.Since(st.timerStart)
+			stopTimer()
+			stats.completed(st.size, st.difficulty, elapsed)
+			title, date := "Solved", time.Now()
+			if !st.daily.IsZero() {
+				if archive != nil {
+					archive.record(st.daily, elapsed)
+				}
+				title, date = "Daily complete", st.daily
+			}
+			showRecap(a, w, title, render.Recap{Final: g, Givens: st.givens, Elapsed: elapsed,
+				Difficulty: st.difficulty, Mistakes: st.mistakes, Date: date})
+			st.daily, st.givens, st.solution = time.Time{}, sudoku.Grid{}, sudoku.Grid{}
+			updateChrome()
 		} else {
 			dialog.ShowInformation("OK", "Board is valid (no duplicate rows/cols/boxes).", w)
 		}
 	})
 
-	btnHint := widget.NewButton("Hint", func() {
-		g, err := gridFromEntries(st)
-		if err != nil {
-			dialog.ShowError(err, w)
+	btnCheck := widget.NewButton("Check", func() {
+		if st.solution.Cells == nil {
+			dialog.ShowInformation("Check", "Check needs a generated, daily or imported puzzle in progress.", w)
 			return
 		}
-		if r, c, v, ok := sudoku.HintGrid(g); ok {
-			// if a specific cell is focused and empty, prefer that
-			if f := w.Canvas().Focused(); f != nil {
-				if fe, okE := f.(*widget.Entry); okE {
-					rr, cc := findEntry(st, fe)
-					if rr >= 0 && g.Cells[rr][cc] == 0 {
-						r, c = rr, cc
-						// compute value from solution
-						if sol, ok2 := g.Solve(); ok2 {
-							v = sol.Cells[r][c]
-						}
-					}
-				}
-			}
-			st.entries[r][c].SetText(strconv.Itoa(v))
-			st.entries[r][c].Enable() // hint is user input
-		} else {
-			dialog.ShowInformation("No hint", "Board is invalid or solved.", w)
+		st.wrong = wrongEntries(st)
+		if len(st.wrong) == 0 {
+			dialog.ShowInformation("Check", "No mistakes so far.", w)
 		}
 	})
 
-	btnClear := widget.NewButton("Clear", func() {
+	btnHint := widget.NewButton("Hint", func() { showHint(w, st) })
+
+	clearBoard := func() {
+		replay.halt()
 		g, _ := sudoku.NewGrid(st.size, st.boxR, st.boxC)
 		setGrid(st, g, false)
+		st.daily, st.givens, st.solution = time.Time{}, sudoku.Grid{}, sudoku.Grid{}
 		stopTimer()
 		st.timerLabel.SetText("Time 00:00")
+		updateChrome()
+	}
+	btnClear := widget.NewButton("Clear", clearBoard)
+
+	// Watch solve animates the solver from the current board; like Solve, it
+	// ends the game without counting it as completed
+	btnWatch := widget.NewButtonWithIcon("Watch solve", theme.MediaPlayIcon(), func() {
+		g, err := gridFromEntries(st)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		frames, err := replayFrames(g)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		st.daily, st.givens, st.solution = time.Time{}, sudoku.Grid{}, sudoku.Grid{}
+		st.conflicts, st.wrong = nil, nil
+		stopTimer()
+		updateChrome()
+		replay.play(st, frames)
+	})
+
+	// a new variant changes the rules, so it starts from an empty board
+	variantSelect = widget.NewSelect(variantNames, func(s string) {
+		cons := variantConstraints(s)
+		if slices.Equal(cons, st.constraints) {
+			return
+		}
+		st.constraints = cons
+		st.conflicts, st.wrong = nil, nil
+		clearBoard()
+	})
+	variantSelect.Selected = variantNames[0]
+
+	mistake = func() {
+		st.mistakes++
+		st.wrong = wrongEntries(st)
+		if st.mistakes < maxMistakes {
+			updateChrome()
+			return
+		}
+		stopTimer()
+		for _, row := range st.entries {
+			for _, e := range row {
+				e.Disable()
+			}
+		}
+		st.history.reset(st)
+		st.daily, st.givens, st.solution = time.Time{}, sudoku.Grid{}, sudoku.Grid{}
+		updateChrome()
+		dialog.ShowInformation("Game over", fmt.Sprintf("%d mistakes end the game. Generate a new puzzle to play again.", maxMistakes), w)
+	}
+
+	btnUndo := widget.NewButtonWithIcon("Undo", theme.ContentUndoIcon(), undo)
+	btnRedo := widget.NewButtonWithIcon("Redo", theme.ContentRedoIcon(), redo)
+	st.history.onMarks = func() { updateMarks(st, settings.candidates) }
+	st.history.onChange = func() {
+		if st.history.canUndo() {
+			btnUndo.Enable()
+		} else {
+			btnUndo.Disable()
+		}
+		if st.history.canRedo() {
+			btnRedo.Enable()
+		} else {
+			btnRedo.Disable()
+		}
+	}
+	// with no cell focused the canvas gets the shortcuts; cells forward them
+	w.Canvas().AddShortcut(&fyne.ShortcutUndo{}, func(fyne.Shortcut) { undo() })
+	w.Canvas().AddShortcut(&fyne.ShortcutRedo{}, func(fyne.Shortcut) { redo() })
+
+	btnLayout := widget.NewButtonWithIcon("", theme.SettingsIcon(), func() {
+		showLayoutSettings(w, a.Preferences(), settings, func(l layoutSettings) {
+			settings = l
+			st.colors = paletteNamed(l.palette)
+			relayout()
+		})
 	})
 
 	// Toolbar with theme-aware background for good contrast in light/dark modes
 	labelSize := widget.NewLabel("Size:")
 	labelDiff := widget.NewLabel("Difficulty:")
+	labelVariant := widget.NewLabel("Variant:")
 	labelSize.TextStyle = fyne.TextStyle{Bold: true}
+	labelVariant.TextStyle = fyne.TextStyle{Bold: true}
 	labelDiff.TextStyle = fyne.TextStyle{Bold: true}
 	// Put difficulty group over a subtle contrasting background to improve legibility
 	diffBG := canvas.NewRectangle(color.NRGBA{R: 0, G: 0, B: 0, A: 0}) // transparent (theme handles colors)
 	diffWrap := container.NewMax(diffBG, container.NewPadded(difficulty))
 	tbInner := container.NewHBox(
 		labelSize, sizeSelect,
+		labelVariant, variantSelect,
 		labelDiff, diffWrap,
-		btnGenerate, btnSolve, btnValidate, btnHint, btnClear,
+		btnGenerate,
 	)
+	tbInner.Add(btnImport)
+	if btnArchive != nil {
+		tbInner.Add(btnArchive)
+	}
+	tour := newTutorial(st, a.Preferences(), btnHint, func(b sudoku.Board) { playBoard(b, sudoku.Easy, time.Time{}) })
+	btnTour := widget.NewButtonWithIcon("", theme.HelpIcon(), func() {
+		if err := tour.start(); err != nil {
+			dialog.ShowError(err, w)
+		}
+	})
+	btnStats := widget.NewButton("Statistics", func() { stats.show(w) })
+	tbInner.Add(btnStats)
+	tbInner.Add(btnLayout)
+	tbInner.Add(btnTour)
 	tbBG := canvas.NewRectangle(theme.BackgroundColor())
 	tbBG.SetMinSize(fyne.NewSize(0, 40))
 	toolbar = container.NewMax(tbBG, container.NewPadded(tbInner))
 
-	footer = container.NewHBox(widget.NewLabel("Press Hint to fill a cell"), layout.NewSpacer(), st.timerLabel)
+	// in compact mode the menu button brings the hidden toolbar back mid-game
+	btnMenu := widget.NewButtonWithIcon("", theme.MenuIcon(), func() {
+		if toolbar.Visible() {
+			toolbar.Hide()
+		} else {
+			toolbar.Show()
+		}
+		w.Content().Refresh()
+	})
+	badges := newRatingBadges()
+	livesLabel := widget.NewLabel("")
+	footer = container.NewHBox(btnMenu, widget.NewLabel("Arrows move, digits fill, 0 clears; or use the pad"), layout.NewSpacer(), badges.box, livesLabel, st.timerLabel)
+
+	// Number pad and actions sit beside the grid, on the left for left-handed use
+	actions := container.NewVBox(btnSolve, btnWatch, btnValidate, btnCheck, btnHint, btnClear, container.NewGridWithColumns(2, btnUndo, btnRedo))
+	relayout = func() {
+		pad := newNumberPad(st, settings.padBelow, func() { updateMarks(st, settings.candidates) })
+		var side fyne.CanvasObject = container.NewVBox(pad, widget.NewSeparator(), actions)
+		bottom := container.NewVBox(replay.bar, tour.card, footer)
+		if settings.padBelow {
+			side = actions
+			bottom = container.NewVBox(container.NewCenter(pad), replay.bar, tour.card, footer)
+		}
+		var left, right fyne.CanvasObject = nil, side
+		if settings.leftHanded {
+			left, right = side, nil
+		}
+		w.SetContent(container.NewBorder(toolbar, bottom, left, right, st.grid))
+		updateChrome()
+	}
+	updateChrome = func() {
+		badges.show(st.givens)
+		if settings.lives && st.givens.Cells != nil {
+			livesLabel.SetText(fmt.Sprintf("Mistakes %d/%d", st.mistakes, maxMistakes))
+			livesLabel.Show()
+		} else {
+			livesLabel.Hide()
+		}
+		updateMarks(st, settings.candidates)
+		if settings.compact && st.givens.Cells != nil {
+			toolbar.Hide()
+			btnMenu.Show()
+		} else {
+			toolbar.Show()
+			btnMenu.Hide()
+		}
+		w.Content().Refresh()
+	}
+
+	// resumeGame restores a game saved on exit
+	resumeGame := func(sg savedGame) {
+		for _, opt := range sizeSelect.Options {
+			if strings.HasPrefix(opt, fmt.Sprintf("%dx%d ", sg.Givens.Size, sg.Givens.Size)) && opt != sizeSelect.Selected {
+				sizeSelect.SetSelected(opt)
+			}
+		}
+		for _, name := range variantNames {
+			if slices.Equal(variantConstraints(name), sg.Givens.Constraints) {
+				variantSelect.SetSelected(name)
+			}
+		}
+		setGrid(st, sg.Givens, true)
+		st.history.replay = true
+		for r, row := range sg.Cells {
+			for c, v := range row {
+				if sg.Givens.Cells[r][c] == 0 && v != 0 {
+					st.entries[r][c].SetText(cellText(v))
+				}
+			}
+		}
+		st.history.replay = false
+		st.history.reset(st)
+		if sg.Pencil != nil {
+			st.pencil = sg.Pencil
+		}
+		st.daily = time.Time{}
+		if day, err := time.Parse(time.DateOnly, sg.Daily); err == nil {
+			st.daily = day
+		}
+		st.givens, st.difficulty, st.mistakes = sg.Givens, sg.Difficulty, sg.Mistakes
+		st.solution = sudoku.Grid{}
+		givens := sg.Givens.String()
+		solveInBackground(sg.Givens, func(sol sudoku.Grid, err error) {
+			if err == nil && st.givens.Cells != nil && st.givens.String() == givens {
+				st.solution = sol
+			}
+		})
+		st.wrong = nil
+		startTimer(time.Duration(sg.Elapsed) * time.Second)
+		updateChrome()
+	}
+	// the game in progress is kept on exit and offered again on the next launch
+	w.SetCloseIntercept(func() {
+		saveGame(a.Preferences(), st)
+		w.Close()
+	})
 
 	// initial build
 	rebuild()
-	content := container.NewBorder(toolbar, footer, nil, nil, st.grid)
-	w.SetContent(content)
+	relayout()
+	if tour.pending() {
+		if err := tour.start(); err != nil {
+			tour.finish()
+		}
+	} else if sg, ok := loadGame(a.Preferences()); ok {
+		a.Preferences().RemoveValue(prefSavedGame)
+		dialog.ShowConfirm("Resume", "Resume your last game ("+sg.summary()+")?", func(ok bool) {
+			if ok {
+				resumeGame(sg)
+			}
+		}, w)
+	}
 	w.ShowAndRun()
 }
 
+// setGrid loads g into the cells and starts a new undo history.
+// solveTimeout bounds the solves the GUI runs for the player.
+const solveTimeout = 10 * time.Second
+
+// solveInBackground solves g off the UI goroutine, giving up after
+// solveTimeout, and hands the result to done on the UI goroutine.
+func solveInBackground(g sudoku.Grid, done func(sudoku.Grid, error)) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), solveTimeout)
+		defer cancel()
+		sol, err := g.SolveContext(ctx)
+		fyne.Do(func() { done(sol, err) })
+	}()
+}
+
 func setGrid(st *gridState, g sudoku.Grid, lockNonZero bool) {
 	// re-dimension if needed
 	if st.size != g.Size || st.boxR != g.BoxRows || st.boxC != g.BoxCols {
 		st.size, st.boxR, st.boxC = g.Size, g.BoxRows, g.BoxCols
 	}
+	resetPencil(st)
+	st.history.replay = true
+	defer func() {
+		st.history.replay = false
+		st.history.reset(st)
+	}()
 	for r := 0; r < st.size; r++ {
 		for c := 0; c < st.size; c++ {
 			v := g.Cells[r][c]
@@ -297,7 +739,7 @@ func setGrid(st *gridState, g sudoku.Grid, lockNonZero bool) {
 				st.entries[r][c].Enable()
 				continue
 			}
-			st.entries[r][c].SetText(strconv.Itoa(v))
+			st.entries[r][c].SetText(cellText(v))
 			if lockNonZero {
 				st.entries[r][c].Disable()
 			} else {
@@ -307,8 +749,27 @@ func setGrid(st *gridState, g sudoku.Grid, lockNonZero bool) {
 	}
 }
 
+// wrongEntries returns the player's entries that differ from st.solution,
+// without revealing the right values; nil when no solution is known.
+func wrongEntries(st *gridState) map[sudoku.Cell]bool {
+	if st.solution.Cells == nil || st.solution.Size != st.size {
+		return nil
+	}
+	wrong := make(map[sudoku.Cell]bool)
+	for r := 0; r < st.size; r++ {
+		for c := 0; c < st.size; c++ {
+			e := st.entries[r][c]
+			if v, ok := cellValue(e.Text); ok && !e.Disabled() && v != st.solution.Cells[r][c] {
+				wrong[sudoku.Cell{Row: r, Col: c}] = true
+			}
+		}
+	}
+	return wrong
+}
+
 func gridFromEntries(st *gridState) (sudoku.Grid, error) {
 	g, _ := sudoku.NewGrid(st.size, st.boxR, st.boxC)
+	g.Constraints = st.constraints
 	for r := 0; r < st.size; r++ {
 		for c := 0; c < st.size; c++ {
 			s := st.entries[r][c].Text
@@ -316,10 +777,10 @@ func gridFromEntries(st *gridState) (sudoku.Grid, error) {
 				g.Cells[r][c] = 0
 				continue
 			}
-			if len(s) != 1 || s[0] < '0' || s[0] > '9' {
+			v, ok := cellValue(s)
+			if !ok {
 				return sudoku.Grid{}, fmt.Errorf("invalid value at (%d,%d)", r+1, c+1)
 			}
-			v := int(s[0] - '0')
 			if v > st.size {
 				return sudoku.Grid{}, fmt.Errorf("value exceeds grid size at (%d,%d)", r+1, c+1)
 			}
@@ -329,7 +790,16 @@ func gridFromEntries(st *gridState) (sudoku.Grid, error) {
 	return g, nil
 }
 
-func findEntry(st *gridState, e *widget.Entry) (int, int) {
+// cellSize is the side of a cell in a size x size grid: smaller cells keep a
+// 16x16 board on screen.
+func cellSize(size int) float32 {
+	if size > 9 {
+		return 28
+	}
+	return 36
+}
+
+func findEntry(st *gridState, e *cellEntry) (int, int) {
 	for r := 0; r < st.size; r++ {
 		for c := 0; c < st.size; c++ {
 			if st.entries[r][c] == e {
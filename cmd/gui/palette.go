@@ -0,0 +1,72 @@
+//go:build gui
+
+package main
+
+import "image/color"
+
+// palette is the set of cell colours: the two box shades and the highlights.
+type palette struct {
+	name      string
+	base, alt color.NRGBA // alternating box backgrounds
+	selected  color.NRGBA // focused cell
+	conflict  color.NRGBA // cells flagged by Validate
+	wrong     color.NRGBA // entries Check found wrong
+	guideCell color.NRGBA // cell a hint or the tutorial asks for
+	guide     color.NRGBA // cells a hint or the tutorial relies on
+}
+
+// palettes are the choices of the palette setting, the default first. The
+// colour-blind one uses the Okabe-Ito hues, which stay apart with deuteranopia
+// and protanopia, so no two highlights differ by red against green alone; the
+// high-contrast one uses saturated colours on white.
+var palettes = []palette{
+	{
+		name:      "Standard",
+		base:      color.NRGBA{R: 245, G: 247, B: 250, A: 255},
+		alt:       color.NRGBA{R: 230, G: 235, B: 240, A: 255},
+		selected:  color.NRGBA{R: 204, G: 231, B: 255, A: 255},
+		conflict:  color.NRGBA{R: 255, G: 205, B: 205, A: 255},
+		wrong:     color.NRGBA{R: 255, G: 214, B: 170, A: 255},
+		guideCell: color.NRGBA{R: 190, G: 235, B: 190, A: 255},
+		guide:     color.NRGBA{R: 255, G: 236, B: 179, A: 255},
+	},
+	{
+		name:      "Colour-blind safe",
+		base:      color.NRGBA{R: 245, G: 247, B: 250, A: 255},
+		alt:       color.NRGBA{R: 230, G: 235, B: 240, A: 255},
+		selected:  color.NRGBA{R: 160, G: 210, B: 240, A: 255}, // sky blue
+		conflict:  color.NRGBA{R: 240, G: 190, B: 100, A: 255}, // orange
+		wrong:     color.NRGBA{R: 220, G: 160, B: 200, A: 255}, // reddish purple
+		guideCell: color.NRGBA{R: 110, G: 170, B: 220, A: 255}, // blue
+		guide:     color.NRGBA{R: 245, G: 235, B: 130, A: 255}, // yellow
+	},
+	{
+		name:      "High contrast",
+		base:      color.NRGBA{R: 255, G: 255, B: 255, A: 255},
+		alt:       color.NRGBA{R: 205, G: 205, B: 205, A: 255},
+		selected:  color.NRGBA{R: 255, G: 230, B: 0, A: 255},
+		conflict:  color.NRGBA{R: 255, G: 70, B: 70, A: 255},
+		wrong:     color.NRGBA{R: 255, G: 140, B: 0, A: 255},
+		guideCell: color.NRGBA{R: 0, G: 200, B: 255, A: 255},
+		guide:     color.NRGBA{R: 150, G: 230, B: 255, A: 255},
+	},
+}
+
+// paletteNamed returns the palette called name, or the default.
+func paletteNamed(name string) palette {
+	for _, p := range palettes {
+		if p.name == name {
+			return p
+		}
+	}
+	return palettes[0]
+}
+
+// paletteNames lists the palettes for the settings dialog.
+func paletteNames() []string {
+	names := make([]string, len(palettes))
+	for i, p := range palettes {
+		names[i] = p.name
+	}
+	return names
+}
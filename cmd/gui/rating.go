@@ -0,0 +1,126 @@
+//go:build gui
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"go.rumenx.com/sudoku"
+)
+
+// badgeColors tint the difficulty badge by measured grade.
+var badgeColors = map[sudoku.Difficulty]color.NRGBA{
+	sudoku.Easy:   {R: 187, G: 247, B: 208, A: 255},
+	sudoku.Medium: {R: 254, G: 240, B: 138, A: 255},
+	sudoku.Hard:   {R: 254, G: 202, B: 202, A: 255},
+}
+
+// ratingBadges show the clue count of the current puzzle and, for 9x9, its
+// measured difficulty (sudoku.Rate), the hardest technique it needs and its
+// sudoku.PuzzleID. Other sizes are not rated.
+type ratingBadges struct {
+	box        *fyne.Container
+	rated      *fyne.Container // grade and technique, 9x9 only
+	grade      *canvas.Rectangle
+	gradeText  *widget.Label
+	techText   *widget.Label
+	cluesText  *widget.Label
+	idText     *widget.Label
+	ratedClues string // givens the badges describe, to skip re-rating
+}
+
+func newRatingBadges() *ratingBadges {
+	rb := &ratingBadges{
+		grade:     canvas.NewRectangle(color.Transparent),
+		gradeText: widget.NewLabel(""),
+		techText:  widget.NewLabel(""),
+		cluesText: widget.NewLabel(""),
+		idText:    widget.NewLabel(""),
+	}
+	rb.grade.CornerRadius = 6
+	rb.techText.TextStyle = fyne.TextStyle{Italic: true}
+	rb.idText.TextStyle = fyne.TextStyle{Monospace: true}
+	rb.rated = container.NewHBox(container.NewStack(rb.grade, rb.gradeText), rb.techText)
+	rb.box = container.NewHBox(rb.rated, rb.cluesText, rb.idText)
+	rb.box.Hide()
+	return rb
+}
+
+// show rates givens and updates the badges; a zero grid hides them.
+func (rb *ratingBadges) show(givens sudoku.Grid) {
+	if givens.Cells == nil {
+		rb.ratedClues = ""
+		rb.box.Hide()
+		return
+	}
+	if givens.String() == rb.ratedClues {
+		return
+	}
+	rb.ratedClues = givens.String()
+	rb.cluesText.SetText(fmt.Sprintf("%d clues", countClues(givens)))
+	b, err := givens.ToBoard()
+	if err != nil {
+		rb.rated.Hide()
+		rb.idText.Hide()
+		rb.box.Show()
+		return
+	}
+	rb.idText.SetText("#" + sudoku.PuzzleID(b))
+	rb.rated.Show()
+	rb.idText.Show()
+	r, err := sudoku.Rate(b)
+	if err != nil {
+		rb.grade.FillColor = color.NRGBA{R: 226, G: 232, B: 240, A: 255}
+		rb.gradeText.SetText("Unrated")
+		rb.techText.SetText(err.Error())
+	} else {
+		rb.grade.FillColor = badgeColors[r.Difficulty]
+		rb.gradeText.SetText(strings.ToUpper(string(r.Difficulty[:1])) + string(r.Difficulty[1:]))
+		rb.techText.SetText("needs " + techniqueName(r.Hardest))
+	}
+	rb.grade.Refresh()
+	rb.box.Show()
+}
+
+// countClues is the number of filled cells in givens.
+func countClues(givens sudoku.Grid) int {
+	n := 0
+	for _, row := range givens.Cells {
+		for _, v := range row {
+			if v != 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// techniqueName is the display name of a Technique ID.
+func techniqueName(id string) string {
+	if t, err := sudoku.ParseTechnique(id); err == nil {
+		return strings.ToLower(t.Info().Name)
+	}
+	return id
+}
+
+// showImport asks for a puzzle in any format sudoku.ParseAny reads and plays it
+// with playImported.
+func showImport(w fyne.Window, play func(sudoku.Board, sudoku.Difficulty)) {
+	text := widget.NewMultiLineEntry()
+	text.SetPlaceHolder("Paste a puzzle: 81 digits, .sdk, .ss or a formatted grid")
+	text.SetMinRowsVisible(11)
+	text.TextStyle = fyne.TextStyle{Monospace: true}
+	dialog.ShowCustomConfirm("Import puzzle", "Play", "Cancel", text, func(ok bool) {
+		if ok {
+			playImported(w, strings.NewReader(text.Text), play)
+		}
+	}, w)
+}
@@ -0,0 +1,43 @@
+//go:build gui
+
+package main
+
+import (
+	"fmt"
+	"image/png"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"go.rumenx.com/sudoku/render"
+)
+
+// showRecap presents the recap image of a finished game with buttons to save it
+// as PNG or copy its text summary (the fyne clipboard only carries text).
+func showRecap(a fyne.App, w fyne.Window, title string, r render.Recap) {
+	img := r.Image()
+	pic := canvas.NewImageFromImage(img)
+	pic.FillMode = canvas.ImageFillContain
+	pic.SetMinSize(fyne.NewSize(float32(img.Bounds().Dx())*0.75, float32(img.Bounds().Dy())*0.75))
+
+	dlg := dialog.NewCustomWithoutButtons(title, pic, w)
+	save := widget.NewButton("Save PNG", func() {
+		fd := dialog.NewFileSave(func(wc fyne.URIWriteCloser, err error) {
+			if err != nil || wc == nil {
+				return
+			}
+			defer wc.Close()
+			if err := png.Encode(wc, img); err != nil {
+				dialog.ShowError(fmt.Errorf("save recap: %w", err), w)
+			}
+		}, w)
+		fd.SetFileName("sudoku-" + r.Date.Format(time.DateOnly) + ".png")
+		fd.Show()
+	})
+	copyText := widget.NewButton("Copy", func() { a.Clipboard().SetContent(r.Summary()) })
+	dlg.SetButtons([]fyne.CanvasObject{save, copyText, widget.NewButton("Close", dlg.Hide)})
+	dlg.Show()
+}
@@ -0,0 +1,149 @@
+//go:build gui
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"go.rumenx.com/sudoku"
+)
+
+// replayFrame is one cell change of a solve replay; value 0 clears the cell.
+type replayFrame struct {
+	cell  sudoku.Cell
+	value int
+	text  string // what the step shows under the grid
+}
+
+// replayFrames returns the steps to fill g. Classic boards with a unique
+// solution replay sudoku.ExplainSolve, so every placement comes with its
+// reason; other classic boards replay the guesses and backtracks of
+// sudoku.SolveTrace. Other sizes and variants fill the solution cell by cell.
+func replayFrames(g sudoku.Grid) ([]replayFrame, error) {
+	b, err := g.ToBoard()
+	if err != nil {
+		sol, ok := g.Solve()
+		if !ok {
+			return nil, errors.New("this puzzle has no solution")
+		}
+		var frames []replayFrame
+		for r := 0; r < g.Size; r++ {
+			for c := 0; c < g.Size; c++ {
+				if g.Cells[r][c] == 0 {
+					cell := sudoku.Cell{Row: r, Col: c}
+					frames = append(frames, replayFrame{cell, sol.Cells[r][c], fmt.Sprintf("%s = %s", cell, cellText(sol.Cells[r][c]))})
+				}
+			}
+		}
+		return frames, nil
+	}
+	if steps, err := sudoku.ExplainSolve(b); err == nil {
+		var frames []replayFrame
+		for _, s := range steps {
+			if s.Value != 0 { // eliminations change no cell
+				frames = append(frames, replayFrame{s.Cell, s.Value, s.Text})
+			}
+		}
+		return frames, nil
+	}
+	_, ok, tr := sudoku.SolveTrace(b)
+	if !ok {
+		return nil, errors.New("this puzzle has no solution")
+	}
+	var frames, placed []replayFrame // placed: cells filled so far, for undoing guesses
+	for _, ev := range tr.Events {
+		cell, err := sudoku.ParseCell(ev.Cell)
+		if err != nil {
+			return nil, err
+		}
+		switch ev.Op {
+		case sudoku.TraceAssign:
+			f := replayFrame{cell, ev.Value, fmt.Sprintf("%s = %d (%s)", cell, ev.Value, techniqueName(ev.Reason))}
+			frames, placed = append(frames, f), append(placed, f)
+		case sudoku.TraceBacktrack:
+			// undo everything back to and including the failed guess
+			for len(placed) > 0 {
+				last := placed[len(placed)-1]
+				placed = placed[:len(placed)-1]
+				frames = append(frames, replayFrame{last.cell, 0, fmt.Sprintf("backtrack: %s is not %d", cell, ev.Value)})
+				if last.cell == cell && last.value == ev.Value {
+					break
+				}
+			}
+		}
+	}
+	return frames, nil
+}
+
+// replayer animates replay frames on the board from a bar under the grid with
+// a speed slider (steps per second) and a Stop button.
+type replayer struct {
+	bar    *fyne.Container
+	status *widget.Label
+	delay  atomic.Int64  // nanoseconds between frames
+	stop   chan struct{} // closed to end the replay in progress; nil when none
+	st     *gridState
+}
+
+func newReplayer() *replayer {
+	p := &replayer{status: widget.NewLabel("")}
+	p.status.Wrapping = fyne.TextWrapWord
+	speed := widget.NewSlider(1, 20)
+	speed.OnChanged = func(v float64) { p.delay.Store(int64(float64(time.Second) / v)) }
+	speed.SetValue(4)
+	stop := widget.NewButton("Stop", p.halt)
+	p.bar = container.NewBorder(nil, nil, widget.NewLabel("Speed"), stop, container.NewVBox(p.status, speed))
+	p.bar.Hide()
+	return p
+}
+
+// play writes frames into the cells of st one at a time, outside the undo
+// history, and hides the bar after the last one. Must be called from the UI
+// goroutine, like halt.
+func (p *replayer) play(st *gridState, frames []replayFrame) {
+	p.halt()
+	stop := make(chan struct{})
+	p.stop, p.st = stop, st
+	p.status.SetText(fmt.Sprintf("%d steps", len(frames)))
+	p.bar.Show()
+	go func() {
+		for _, f := range frames {
+			select {
+			case <-stop:
+				return
+			case <-time.After(time.Duration(p.delay.Load())):
+			}
+			fyne.Do(func() {
+				if p.stop != stop {
+					return // halted while the frame was queued
+				}
+				st.history.replay = true
+				st.entries[f.cell.Row][f.cell.Col].SetText(cellText(f.value))
+				st.history.replay = false
+				p.status.SetText(f.text)
+			})
+		}
+		fyne.Do(func() {
+			if p.stop == stop {
+				p.halt()
+			}
+		})
+	}()
+}
+
+// halt stops a replay in progress, leaving the board as far as it got.
+func (p *replayer) halt() {
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+		p.st.history.reset(p.st) // the replayed cells are the new starting point
+	}
+	p.bar.Hide()
+}
@@ -0,0 +1,92 @@
+//go:build gui
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"go.rumenx.com/sudoku"
+)
+
+const prefSavedGame = "game.saved"
+
+// savedGame is the game in progress, kept in the app preferences on exit so
+// the next launch can offer to resume it.
+type savedGame struct {
+	Givens     sudoku.Grid       `json:"givens"` // with the variant constraints
+	Cells      [][]int           `json:"cells"`  // the board as left, givens included
+	Pencil     [][]uint32        `json:"pencil,omitempty"`
+	Elapsed    int               `json:"elapsed"` // seconds on the timer
+	Difficulty sudoku.Difficulty `json:"difficulty,omitempty"`
+	Daily      string            `json:"daily,omitempty"` // date of a daily puzzle
+	Mistakes   int               `json:"mistakes,omitempty"`
+}
+
+// saveGame stores the game in progress, or forgets the saved one when no game
+// is running.
+func saveGame(p fyne.Preferences, st *gridState) {
+	if st.givens.Cells == nil {
+		p.RemoveValue(prefSavedGame)
+		return
+	}
+	g, err := gridFromEntries(st)
+	if err != nil {
+		g = st.givens // unreadable entries: keep at least the puzzle
+	}
+	sg := savedGame{
+		Givens:     st.givens,
+		Cells:      g.Cells,
+		Pencil:     st.pencil,
+		Elapsed:    int(time.Since(st.timerStart).Seconds()),
+		Difficulty: st.difficulty,
+		Mistakes:   st.mistakes,
+	}
+	if !st.daily.IsZero() {
+		sg.Daily = st.daily.Format(time.DateOnly)
+	}
+	data, err := json.Marshal(sg)
+	if err != nil {
+		fyne.LogError("save game", err)
+		return
+	}
+	p.SetString(prefSavedGame, string(data))
+}
+
+// loadGame returns the saved game, if there is a well-formed one.
+func loadGame(p fyne.Preferences) (savedGame, bool) {
+	data := p.String(prefSavedGame)
+	if data == "" {
+		return savedGame{}, false
+	}
+	var sg savedGame
+	if err := json.Unmarshal([]byte(data), &sg); err != nil {
+		fyne.LogError("load saved game", err)
+		return savedGame{}, false
+	}
+	n := sg.Givens.Size
+	if len(sg.Givens.Cells) != n || len(sg.Cells) != n || (sg.Pencil != nil && len(sg.Pencil) != n) {
+		return savedGame{}, false
+	}
+	for r := 0; r < n; r++ {
+		if len(sg.Cells[r]) != n || (sg.Pencil != nil && len(sg.Pencil[r]) != n) {
+			return savedGame{}, false
+		}
+	}
+	return sg, true
+}
+
+// summary describes sg for the Resume prompt, e.g. "medium 9x9 puzzle, 04:12 in".
+func (sg savedGame) summary() string {
+	kind := string(sg.Difficulty)
+	if sg.Daily != "" {
+		kind = "daily " + sg.Daily
+	}
+	if kind != "" {
+		kind += " "
+	}
+	return fmt.Sprintf("%s%dx%d puzzle, %s in", kind, sg.Givens.Size, sg.Givens.Size, clock(sg.Elapsed))
+}
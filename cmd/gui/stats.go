@@ -0,0 +1,125 @@
+//go:build gui
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"go.rumenx.com/sudoku"
+)
+
+// statSizes and statKinds are the buckets the Statistics dialog lists; dailies
+// have no chosen difficulty and count under "daily".
+var (
+	statSizes = []int{4, 6, 9, 16}
+	statKinds = []string{string(sudoku.Easy), string(sudoku.Medium), string(sudoku.Hard), "daily"}
+)
+
+// playerStats counts games per grid size and difficulty in the app preferences:
+// games started, games completed, and the best and total completion seconds.
+type playerStats struct {
+	prefs fyne.Preferences
+}
+
+// statKey is the preference key of field for size and kind, e.g. "stats.9.hard.won".
+func statKey(size int, kind, field string) string {
+	return fmt.Sprintf("stats.%d.%s.%s", size, kind, field)
+}
+
+// statKind is the bucket of a game of difficulty d; "" is a daily.
+func statKind(d sudoku.Difficulty) string {
+	if d == "" {
+		return "daily"
+	}
+	return string(d)
+}
+
+// started counts a new game. Games left unfinished lower the completion rate.
+func (s playerStats) started(size int, d sudoku.Difficulty) {
+	k := statKey(size, statKind(d), "played")
+	s.prefs.SetInt(k, s.prefs.Int(k)+1)
+}
+
+// completed records a game solved by the player in elapsed.
+func (s playerStats) completed(size int, d sudoku.Difficulty, elapsed time.Duration) {
+	kind := statKind(d)
+	secs := max(int(elapsed.Round(time.Second).Seconds()), 1)
+	won := statKey(size, kind, "won")
+	s.prefs.SetInt(won, s.prefs.Int(won)+1)
+	total := statKey(size, kind, "total")
+	s.prefs.SetInt(total, s.prefs.Int(total)+secs)
+	if best := s.prefs.Int(statKey(size, kind, "best")); best == 0 || secs < best {
+		s.prefs.SetInt(statKey(size, kind, "best"), secs)
+	}
+}
+
+// reset forgets every recorded game.
+func (s playerStats) reset() {
+	for _, size := range statSizes {
+		for _, kind := range statKinds {
+			for _, field := range []string{"played", "won", "total", "best"} {
+				s.prefs.RemoveValue(statKey(size, kind, field))
+			}
+		}
+	}
+}
+
+// clock formats seconds as mm:ss.
+func clock(secs int) string { return fmt.Sprintf("%02d:%02d", secs/60, secs%60) }
+
+// show opens the Statistics dialog: one row per size and difficulty played.
+func (s playerStats) show(w fyne.Window) {
+	table := container.NewGridWithColumns(6)
+	render := func() {
+		table.Objects = nil
+		for _, h := range []string{"Size", "Difficulty", "Played", "Completed", "Best", "Average"} {
+			l := widget.NewLabel(h)
+			l.TextStyle = fyne.TextStyle{Bold: true}
+			table.Add(l)
+		}
+		rows := 0
+		for _, size := range statSizes {
+			for _, kind := range statKinds {
+				played := s.prefs.Int(statKey(size, kind, "played"))
+				if played == 0 {
+					continue
+				}
+				won := s.prefs.Int(statKey(size, kind, "won"))
+				best, avg := "–", "–"
+				if won > 0 {
+					best = clock(s.prefs.Int(statKey(size, kind, "best")))
+					avg = clock(s.prefs.Int(statKey(size, kind, "total")) / won)
+				}
+				for _, cell := range []string{
+					fmt.Sprintf("%dx%d", size, size), kind, fmt.Sprint(played),
+					fmt.Sprintf("%d (%d%%)", won, won*100/played), best, avg,
+				} {
+					table.Add(widget.NewLabel(cell))
+				}
+				rows++
+			}
+		}
+		if rows == 0 {
+			table.Add(widget.NewLabel("No games yet"))
+		}
+		table.Refresh()
+	}
+	render()
+	btnReset := widget.NewButton("Reset", func() {
+		dialog.ShowConfirm("Reset statistics", "Forget all recorded games?", func(ok bool) {
+			if ok {
+				s.reset()
+				render()
+			}
+		}, w)
+	})
+	dlg := dialog.NewCustom("Statistics", "Close", container.NewBorder(nil, btnReset, nil, nil, table), w)
+	dlg.Resize(fyne.NewSize(560, 0))
+	dlg.Show()
+}
@@ -0,0 +1,152 @@
+//go:build gui
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"go.rumenx.com/sudoku"
+)
+
+const prefTutorialDone = "tutorial.done"
+
+// tutorial is the first-run tour. It plays a real easy puzzle and walks through
+// entry, notes, hints and checking in an instruction card under the grid; the
+// cells a step talks about are highlighted on the board (see highlightSelected)
+// and the teaching text comes from sudoku.HintExplain.
+type tutorial struct {
+	st    *gridState
+	prefs fyne.Preferences
+	hint  *widget.Button // emphasised during the hint step
+	play  func(sudoku.Board)
+
+	card *fyne.Container
+	text *widget.Label
+	next *widget.Button
+	step int
+	want *sudoku.Step // placement the current step waits for
+}
+
+func newTutorial(st *gridState, prefs fyne.Preferences, hint *widget.Button, play func(sudoku.Board)) *tutorial {
+	t := &tutorial{st: st, prefs: prefs, hint: hint, play: play}
+	t.text = widget.NewLabel("")
+	t.text.Wrapping = fyne.TextWrapWord
+	t.next = widget.NewButton("Next", t.advance)
+	skip := widget.NewButton("Skip tour", t.finish)
+	t.card = container.NewBorder(nil, container.NewHBox(skip, widget.NewLabel(""), t.next), nil, nil, t.text)
+	t.card.Hide()
+	return t
+}
+
+// pending reports whether the tour has not been completed or skipped yet.
+func (t *tutorial) pending() bool { return !t.prefs.Bool(prefTutorialDone) }
+
+// start loads a fresh easy puzzle and shows the first step.
+func (t *tutorial) start() error {
+	b, err := sudoku.Generate(sudoku.Easy, 3)
+	if err != nil {
+		return err
+	}
+	t.play(b)
+	t.step = 0
+	t.st.onEdit = t.edited
+	t.card.Show()
+	t.show()
+	return nil
+}
+
+func (t *tutorial) advance() {
+	t.step++
+	t.show()
+}
+
+// show renders the current step.
+func (t *tutorial) show() {
+	t.want, t.st.guide, t.st.guideCell = nil, nil, nil
+	t.hint.Importance = widget.MediumImportance
+	t.next.Enable()
+	t.next.SetText("Next")
+	switch t.step {
+	case 0:
+		t.text.SetText("Welcome! This short tour plays a real easy puzzle with you. " +
+			"Grey cells are givens; fill the rest so every row, column and box holds each digit once.")
+	case 1:
+		if !t.teach() {
+			return
+		}
+		t.text.SetText(fmt.Sprintf("Entry: click the green cell %s and type %d, or tap %d on the number pad.\nWhy: %s",
+			t.want.Cell, t.want.Value, t.want.Value, t.want.Text))
+	case 2:
+		if !t.teach() {
+			return
+		}
+		b, _ := t.board()
+		notes := sudoku.CandidateNotes(b)
+		var cands []string
+		for _, v := range notes.Candidates(t.want.Cell.Row, t.want.Cell.Col) {
+			cands = append(cands, strconv.Itoa(v))
+		}
+		t.text.SetText(fmt.Sprintf("Notes: before writing a digit, list what a cell can still hold. "+
+			"%s could be %s, but %s\nEnter %d to continue.", t.want.Cell, strings.Join(cands, ", "), t.want.Text, t.want.Value))
+	case 3:
+		t.hint.Importance = widget.HighImportance
+		t.text.SetText("Hints: stuck? Press the highlighted Hint button. The first press names a technique, " +
+			"the second shows the cells it uses, and the third fills in the digit.")
+	case 4:
+		t.text.SetText("Check: press Validate at any time. Clashing cells turn red, and validating a " +
+			"finished board shows your recap. Enjoy the rest of the puzzle!")
+		t.next.SetText("Finish")
+	default:
+		t.finish()
+		return
+	}
+	t.hint.Refresh()
+}
+
+// teach highlights the next logical placement and waits for it. It skips ahead
+// when the board offers no single (e.g. the player already filled it in).
+func (t *tutorial) teach() bool {
+	b, err := t.board()
+	step, ok := sudoku.HintExplain(b)
+	if err != nil || !ok {
+		t.advance()
+		return false
+	}
+	t.want = &step
+	t.st.guide, t.st.guideCell = guideCells(step.Cells), &step.Cell
+	t.next.Disable()
+	return true
+}
+
+// edited advances once the awaited digit is entered.
+func (t *tutorial) edited() {
+	if t.want == nil {
+		return
+	}
+	if e := t.st.entries[t.want.Cell.Row][t.want.Cell.Col]; e.Text == strconv.Itoa(t.want.Value) {
+		t.advance()
+	}
+}
+
+func (t *tutorial) board() (sudoku.Board, error) {
+	g, err := gridFromEntries(t.st)
+	if err != nil {
+		return sudoku.Board{}, err
+	}
+	return g.ToBoard()
+}
+
+// finish ends the tour for good; the puzzle stays on the board as a normal game.
+func (t *tutorial) finish() {
+	t.prefs.SetBool(prefTutorialDone, true)
+	t.want, t.st.guide, t.st.guideCell, t.st.onEdit = nil, nil, nil, nil
+	t.hint.Importance = widget.MediumImportance
+	t.hint.Refresh()
+	t.card.Hide()
+}
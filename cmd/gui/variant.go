@@ -0,0 +1,56 @@
+//go:build gui
+
+package main
+
+import (
+	"image/color"
+
+	"go.rumenx.com/sudoku"
+)
+
+// variantNames are the choices of the variant selector, classic first. The
+// variants are played on 9x9 only.
+var variantNames = []string{"Classic", "X (diagonals)", "Hyper (windows)"}
+
+// variantConstraints are the extra rules of a variant name; nil for classic.
+func variantConstraints(name string) []sudoku.Constraint {
+	switch name {
+	case variantNames[1]:
+		return []sudoku.Constraint{sudoku.DiagonalConstraint}
+	case variantNames[2]:
+		return []sudoku.Constraint{sudoku.HyperConstraint}
+	}
+	return nil
+}
+
+// inVariantUnit reports whether r,c lies on a diagonal or in a window that
+// the board's variant rules cover.
+func inVariantUnit(st *gridState, r, c int) bool {
+	for _, con := range st.constraints {
+		switch con {
+		case sudoku.DiagonalConstraint:
+			if r == c || r+c == st.size-1 {
+				return true
+			}
+		case sudoku.HyperConstraint:
+			// windows are box-sized, one cell in from the edge and one apart
+			if r%(st.boxR+1) != 0 && c%(st.boxC+1) != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cellColor is the resting background of r,c: alternating box shades, tinted
+// where a variant rule applies.
+func cellColor(st *gridState, r, c int) color.NRGBA {
+	base := st.colors.base
+	if ((r/st.boxR)+(c/st.boxC))%2 == 1 {
+		base = st.colors.alt
+	}
+	if inVariantUnit(st, r, c) {
+		base.R, base.G = base.R-20, base.G-18 // a violet tint that keeps the box contrast
+	}
+	return base
+}
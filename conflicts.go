@@ -0,0 +1,63 @@
+package sudoku
+
+// IsLegalMove reports whether placing v at r,c keeps b free of duplicates: r, c
+// and v are in range and no peer in the same row, column or box already holds
+// v. The current value of r,c is ignored (overwriting is allowed) and v == 0,
+// clearing the cell, is always legal. Givens are not tracked by a Board; use
+// Puzzle.IsGiven for that.
+func IsLegalMove(b Board, r, c, v int) bool {
+	if r < 0 || r > 8 || c < 0 || c > 8 || v < 0 || v > 9 {
+		return false
+	}
+	return len(Conflicts(b, r, c, v)) == 0
+}
+
+// Conflicts returns the peers of r,c that already hold v, in row-major order and
+// each listed once, so front-ends can highlight exactly the clashing cells of a
+// tentative placement. It returns nil for v == 0 and out-of-range arguments.
+func Conflicts(b Board, r, c, v int) []Cell {
+	if r < 0 || r > 8 || c < 0 || c > 8 || v < 1 || v > 9 {
+		return nil
+	}
+	var out []Cell
+	for pr := 0; pr < 9; pr++ {
+		for pc := 0; pc < 9; pc++ {
+			if (pr == r && pc == c) || b[pr][pc] != v {
+				continue
+			}
+			if pr == r || pc == c || (pr/3 == r/3 && pc/3 == c/3) {
+				out = append(out, Cell{pr, pc})
+			}
+		}
+	}
+	return out
+}
+
+// IsComplete reports whether every cell of b holds a value. It does not check
+// the rules; see IsSolved.
+func IsComplete(b Board) bool { return countClues(b) == 81 }
+
+// IsSolved reports whether b is complete and breaks no rule.
+func IsSolved(b Board) bool { return IsComplete(b) && Validate(b) == nil }
+
+// IsComplete reports whether every cell of g holds a value.
+func (g Grid) IsComplete() bool {
+	if len(g.Cells) != g.Size {
+		return false
+	}
+	for _, row := range g.Cells {
+		if len(row) != g.Size {
+			return false
+		}
+		for _, v := range row {
+			if v == 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IsSolved reports whether g is complete and satisfies its regions and
+// constraints.
+func (g Grid) IsSolved() bool { return g.IsComplete() && g.Validate() == nil }
@@ -0,0 +1,59 @@
+package sudoku
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConflicts(t *testing.T) {
+	b, _ := FromString(easyPuzzle)
+	// r1c1 shares both the row and the box with r1c3 but is listed once
+	if got, want := Conflicts(b, 0, 2, 5), []Cell{{0, 0}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Conflicts(r1c3=5) = %v, want %v", got, want)
+	}
+	// 9 at r2c2: the row has r2c5, the column and box both have r3c2
+	if got, want := Conflicts(b, 1, 1, 9), []Cell{{1, 4}, {2, 1}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Conflicts(r2c2=9) = %v, want %v", got, want)
+	}
+	if Conflicts(b, 0, 2, 4) != nil || Conflicts(b, 0, 2, 0) != nil || Conflicts(b, 9, 0, 1) != nil {
+		t.Fatalf("expected no conflicts")
+	}
+	if !IsLegalMove(b, 0, 2, 4) || !IsLegalMove(b, 0, 0, 0) || !IsLegalMove(b, 0, 0, 5) {
+		t.Fatalf("legal moves rejected")
+	}
+	if IsLegalMove(b, 0, 2, 5) || IsLegalMove(b, 0, 2, 10) || IsLegalMove(b, -1, 0, 1) {
+		t.Fatalf("illegal moves accepted")
+	}
+}
+
+func TestIsCompleteIsSolved(t *testing.T) {
+	puz, _ := FromString(easyPuzzle)
+	sol, ok := Solve(puz)
+	if !ok {
+		t.Fatal("solve failed")
+	}
+	if IsComplete(puz) || IsSolved(puz) {
+		t.Fatal("puzzle reported complete")
+	}
+	if !IsComplete(sol) || !IsSolved(sol) {
+		t.Fatal("solution not reported solved")
+	}
+	bad := sol
+	bad[0][0], bad[0][1] = bad[0][1], bad[0][0]
+	if !IsComplete(bad) || IsSolved(bad) {
+		t.Fatal("swapped solution: want complete but not solved")
+	}
+
+	g, _ := NewGrid(4, 2, 2)
+	if g.IsComplete() || g.IsSolved() {
+		t.Fatal("empty grid reported complete")
+	}
+	gs, ok := g.Solve()
+	if !ok || !gs.IsComplete() || !gs.IsSolved() {
+		t.Fatal("solved grid not reported solved")
+	}
+	gs.Cells[0][0], gs.Cells[0][1] = gs.Cells[0][1], gs.Cells[0][0]
+	if !gs.IsComplete() || gs.IsSolved() {
+		t.Fatal("swapped grid: want complete but not solved")
+	}
+}
@@ -0,0 +1,251 @@
+package sudoku
+
+import "slices"
+
+// Constraint is a placement rule for a Grid. The solver asks every constraint
+// whether a value may go into a cell, so variants compose by listing their rules
+// in Grid.Constraints instead of needing their own solver.
+type Constraint interface {
+	// Allows reports whether v may be placed at r,c given the other filled cells
+	// of g. The current content of r,c itself is ignored.
+	Allows(g *Grid, r, c, v int) bool
+	// Validate returns an error wrapping ErrInvalidBoard if the filled cells of
+	// g break the rule.
+	Validate(g *Grid) error
+}
+
+// Built-in constraints. The first three are the standard rules every Grid obeys;
+// the others are opt-in variants for Grid.Constraints. CageConstraint and
+// ParityConstraint build the killer and even/odd rules for a given layout.
+var (
+	// RowConstraint forbids repeated values within a row.
+	RowConstraint Constraint = rowConstraint{}
+	// ColumnConstraint forbids repeated values within a column.
+	ColumnConstraint Constraint = columnConstraint{}
+	// BoxConstraint forbids repeated values within a box or jigsaw region.
+	BoxConstraint Constraint = boxConstraint{}
+	// DiagonalConstraint forbids repeated values on either main diagonal (X-sudoku).
+	DiagonalConstraint Constraint = diagonalConstraint{}
+	// AntiKnightConstraint forbids equal values a chess knight's move apart.
+	AntiKnightConstraint Constraint = antiKnightConstraint{}
+	// HyperConstraint forbids repeated values within the extra windows of
+	// Hyper (Windoku) sudoku: box-sized squares set one cell in from the edge
+	// and one apart, rows and columns 2-4 and 6-8 on 9x9.
+	HyperConstraint Constraint = hyperConstraint{}
+)
+
+var standardConstraints = [...]Constraint{RowConstraint, ColumnConstraint, BoxConstraint}
+
+// StandardConstraints returns the row, column and box rules.
+func StandardConstraints() []Constraint { return append([]Constraint(nil), standardConstraints[:]...) }
+
+// allows reports whether v fits at r,c of w under the standard rules and g's
+// extra constraints.
+func (g Grid) allows(w *Grid, r, c, v int) bool {
+	for _, con := range standardConstraints {
+		if !con.Allows(w, r, c, v) {
+			return false
+		}
+	}
+	return g.allowsExtra(w, r, c, v)
+}
+
+// allowsExtra checks only g's extra constraints.
+func (g Grid) allowsExtra(w *Grid, r, c, v int) bool {
+	for _, con := range g.Constraints {
+		if !con.Allows(w, r, c, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// firstConflict returns the first of cs as an error, or nil.
+func firstConflict(cs []Conflict) error {
+	if len(cs) > 0 {
+		return &ConflictError{cs[0]}
+	}
+	return nil
+}
+
+// validateByAllows checks every filled cell of g against con.
+func validateByAllows(con Constraint, g *Grid) error {
+	for r := 0; r < g.Size; r++ {
+		for c := 0; c < g.Size; c++ {
+			if v := g.Cells[r][c]; v != 0 && !con.Allows(g, r, c, v) {
+				return ErrInvalidBoard
+			}
+		}
+	}
+	return nil
+}
+
+type rowConstraint struct{}
+
+func (rowConstraint) Allows(g *Grid, r, c, v int) bool {
+	for i := 0; i < g.Size; i++ {
+		if i != c && g.Cells[r][i] == v {
+			return false
+		}
+	}
+	return true
+}
+
+func (rowConstraint) Validate(g *Grid) error { return gridConflict(g, UnitRow) }
+
+type columnConstraint struct{}
+
+func (columnConstraint) Allows(g *Grid, r, c, v int) bool {
+	for i := 0; i < g.Size; i++ {
+		if i != r && g.Cells[i][c] == v {
+			return false
+		}
+	}
+	return true
+}
+
+func (columnConstraint) Validate(g *Grid) error { return gridConflict(g, UnitColumn) }
+
+type boxConstraint struct{}
+
+func (boxConstraint) Allows(g *Grid, r, c, v int) bool {
+	if g.Regions != nil {
+		reg := g.Regions[r][c]
+		for i := 0; i < g.Size; i++ {
+			for j := 0; j < g.Size; j++ {
+				if g.Regions[i][j] == reg && (i != r || j != c) && g.Cells[i][j] == v {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	br := (r / g.BoxRows) * g.BoxRows
+	bc := (c / g.BoxCols) * g.BoxCols
+	for i := br; i < br+g.BoxRows; i++ {
+		for j := bc; j < bc+g.BoxCols; j++ {
+			if (i != r || j != c) && g.Cells[i][j] == v {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (boxConstraint) Validate(g *Grid) error { return gridConflict(g, UnitBox) }
+
+type diagonalConstraint struct{}
+
+func (diagonalConstraint) Allows(g *Grid, r, c, v int) bool {
+	n := g.Size
+	for i := 0; i < n; i++ {
+		if r == c && i != r && g.Cells[i][i] == v {
+			return false
+		}
+		if r+c == n-1 && i != r && g.Cells[i][n-1-i] == v {
+			return false
+		}
+	}
+	return true
+}
+
+func (k diagonalConstraint) Validate(g *Grid) error { return firstConflict(k.conflicts(g)) }
+
+func (diagonalConstraint) conflicts(g *Grid) []Conflict {
+	return unitConflicts(g, UnitDiagonal, 2, func(r, c int) []int {
+		var in []int
+		if r == c {
+			in = append(in, 0)
+		}
+		if r+c == g.Size-1 {
+			in = append(in, 1)
+		}
+		return in
+	})
+}
+
+type antiKnightConstraint struct{}
+
+var knightMoves = [8][2]int{{-2, -1}, {-2, 1}, {-1, -2}, {-1, 2}, {1, -2}, {1, 2}, {2, -1}, {2, 1}}
+
+func (antiKnightConstraint) Allows(g *Grid, r, c, v int) bool {
+	for _, m := range knightMoves {
+		rr, cc := r+m[0], c+m[1]
+		if rr >= 0 && rr < g.Size && cc >= 0 && cc < g.Size && g.Cells[rr][cc] == v {
+			return false
+		}
+	}
+	return true
+}
+
+func (k antiKnightConstraint) Validate(g *Grid) error { return firstConflict(k.conflicts(g)) }
+
+// conflicts reports every pair of equal values a knight's move apart as a
+// UnitConstraint conflict indexed by the constraint's entry in g.Constraints.
+func (antiKnightConstraint) conflicts(g *Grid) []Conflict {
+	idx := slices.IndexFunc(g.Constraints, func(con Constraint) bool {
+		_, ok := con.(antiKnightConstraint)
+		return ok
+	})
+	var out []Conflict
+	for r := 0; r < g.Size; r++ {
+		for c := 0; c < g.Size; c++ {
+			v := g.Cells[r][c]
+			if v <= 0 || v > g.Size {
+				continue
+			}
+			for _, m := range knightMoves[4:] { // moves down the grid, so each pair is seen once
+				rr, cc := r+m[0], c+m[1]
+				if rr < g.Size && cc >= 0 && cc < g.Size && g.Cells[rr][cc] == v {
+					out = append(out, Conflict{Kind: UnitConstraint, Index: idx, Value: v, Cells: []Cell{{r, c}, {rr, cc}}})
+				}
+			}
+		}
+	}
+	return out
+}
+
+type hyperConstraint struct{}
+
+// hyperStart returns the first row (or column) of the window line holding i
+// when windows of side box start at 1, box+2, 2*box+3, ... and fit within n.
+func hyperStart(i, box, n int) (int, bool) {
+	if i < 1 {
+		return 0, false
+	}
+	s := 1 + (i-1)/(box+1)*(box+1)
+	return s, i < s+box && s+box <= n
+}
+
+func (hyperConstraint) Allows(g *Grid, r, c, v int) bool {
+	wr, okR := hyperStart(r, g.BoxRows, g.Size)
+	wc, okC := hyperStart(c, g.BoxCols, g.Size)
+	if !okR || !okC {
+		return true
+	}
+	for i := wr; i < wr+g.BoxRows; i++ {
+		for j := wc; j < wc+g.BoxCols; j++ {
+			if (i != r || j != c) && g.Cells[i][j] == v {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (k hyperConstraint) Validate(g *Grid) error { return firstConflict(k.conflicts(g)) }
+
+func (hyperConstraint) conflicts(g *Grid) []Conflict {
+	if g.BoxRows == 0 || g.BoxCols == 0 {
+		return nil // jigsaw grids have no windows
+	}
+	perRow, rows := g.Size/(g.BoxCols+1), g.Size/(g.BoxRows+1)
+	return unitConflicts(g, UnitWindow, rows*perRow, func(r, c int) []int {
+		wr, okR := hyperStart(r, g.BoxRows, g.Size)
+		wc, okC := hyperStart(c, g.BoxCols, g.Size)
+		if !okR || !okC {
+			return nil
+		}
+		return []int{(wr-1)/(g.BoxRows+1)*perRow + (wc-1)/(g.BoxCols+1)}
+	})
+}
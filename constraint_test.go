@@ -0,0 +1,131 @@
+package sudoku
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestConstraintValidate(t *testing.T) {
+	g, _ := NewGrid(9, 3, 3)
+	g.Cells[0][0], g.Cells[8][8] = 5, 5
+	if err := g.Validate(); err != nil {
+		t.Fatalf("standard rules should accept: %v", err)
+	}
+	g.Constraints = []Constraint{DiagonalConstraint}
+	if err := g.Validate(); err == nil {
+		t.Fatalf("expected repeated value on the diagonal to be invalid")
+	}
+	g.Cells[8][8] = 0
+	g.Cells[0][2], g.Cells[1][4] = 7, 7 // a knight's move apart, different boxes
+	if err := g.Validate(); err != nil {
+		t.Fatalf("diagonal rule should accept: %v", err)
+	}
+	g.Constraints = append(g.Constraints, AntiKnightConstraint)
+	var ce *ConflictError
+	if err := g.Validate(); !errors.As(err, &ce) || !errors.Is(err, ErrInvalidBoard) {
+		t.Fatalf("expected knight's move repeat to be invalid, got %v", err)
+	}
+	want := Conflict{Kind: UnitConstraint, Index: 1, Value: 7, Cells: []Cell{{0, 2}, {1, 4}}}
+	if !reflect.DeepEqual(ce.Conflict, want) {
+		t.Fatalf("conflict %v, want %v", ce.Conflict, want)
+	}
+	if g.Clone().Constraints[1] != AntiKnightConstraint {
+		t.Fatalf("Clone dropped constraints")
+	}
+}
+
+func TestHyperConstraint(t *testing.T) {
+	g, _ := NewGrid(9, 3, 3)
+	g.Constraints = []Constraint{HyperConstraint}
+	g.Cells[1][1], g.Cells[3][3] = 4, 4 // same window, different boxes
+	if err := g.Validate(); err == nil {
+		t.Fatalf("expected repeat in the top-left window to be invalid")
+	}
+	g.Cells[3][3] = 0
+	g.Cells[4][4] = 4 // the centre row and column are outside every window
+	if err := g.Validate(); err != nil {
+		t.Fatalf("hyper rule should accept: %v", err)
+	}
+	if !HyperConstraint.Allows(&g, 7, 7, 4) || !HyperConstraint.Allows(&g, 5, 5, 4) {
+		t.Fatalf("bottom-right window has no 4 yet")
+	}
+	g.Cells[6][6] = 4
+	if HyperConstraint.Allows(&g, 7, 7, 4) {
+		t.Fatalf("expected 4 to be blocked in the bottom-right window")
+	}
+}
+
+func TestConstraintGenerateSolve(t *testing.T) {
+	gen := NewGenerator(3)
+	for _, cons := range [][]Constraint{{DiagonalConstraint}, {AntiKnightConstraint}, {HyperConstraint}} {
+		g, _ := NewGrid(9, 3, 3)
+		g.Constraints = cons
+		puz, err := gen.GenerateGrid(g, Medium, 3)
+		if err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+		if err := puz.Validate(); err != nil {
+			t.Fatalf("generated invalid puzzle: %v", err)
+		}
+		if n := puz.CountSolutions(2); n != 1 {
+			t.Fatalf("expected unique puzzle, got %d solutions", n)
+		}
+		sol, ok := puz.Solve()
+		if !ok || sol.countClues(sol) != 81 || sol.Validate() != nil {
+			t.Fatalf("solve failed")
+		}
+	}
+}
+
+func TestCageAndParityConstraintsCompose(t *testing.T) {
+	g6, _ := NewGrid(6, 2, 3)
+	k, err := NewGenerator(7).GenerateKiller(g6, Medium, 3)
+	if err != nil {
+		t.Fatalf("generate killer: %v", err)
+	}
+	sol, ok := k.Solve()
+	if !ok {
+		t.Fatalf("killer unsolved")
+	}
+	// the same cages on a plain Grid, with an even/odd mask taken from the solution
+	g := k.Grid.Clone()
+	mask := make([][]Parity, g.Size)
+	for r := range mask {
+		mask[r] = make([]Parity, g.Size)
+		for c := range mask[r] {
+			mask[r][c] = ParityEven + Parity(sol.Cells[r][c]%2)
+		}
+	}
+	g.Constraints = []Constraint{CageConstraint(k.Cages), ParityConstraint(mask)}
+	got, ok := g.Solve()
+	if !ok || !gridEqual(got, sol.Grid) || got.Validate() != nil {
+		t.Fatalf("composed solve = %v %v", ok, got)
+	}
+	if !CageConstraint(k.Cages).Allows(&got, 0, 0, got.Cells[0][0]) {
+		t.Fatalf("cage rejects its own solution")
+	}
+	if ParityConstraint(mask).Allows(&got, 0, 0, got.Cells[0][0]%g.Size+1) {
+		t.Fatalf("parity allows a value of the wrong parity")
+	}
+}
+
+func TestStandardConstraintsMatchIsSafe(t *testing.T) {
+	g, _ := FromStringN("2040012004000234", 4, 2, 2)
+	for r := 0; r < 4; r++ {
+		for c := 0; c < 4; c++ {
+			if g.Cells[r][c] != 0 {
+				continue
+			}
+			for v := 1; v <= 4; v++ {
+				ok := true
+				for _, con := range StandardConstraints() {
+					ok = ok && con.Allows(&g, r, c, v)
+				}
+				if ok != g.isSafe(g, r, c, v) {
+					t.Fatalf("mismatch at r%dc%d v=%d", r+1, c+1, v)
+				}
+			}
+		}
+	}
+}
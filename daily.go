@@ -0,0 +1,19 @@
+package sudoku
+
+import "time"
+
+// DailyDifficulty is the difficulty of the puzzle of the day.
+const DailyDifficulty = Medium
+
+// DailySeed returns the generator seed for the puzzle of date's calendar day
+// (YYYYMMDD in date's location), so every client derives the same puzzle.
+func DailySeed(date time.Time) uint64 {
+	y, m, d := date.Date()
+	return uint64(y*10000 + int(m)*100 + d)
+}
+
+// Daily returns the puzzle of the day for date. It does not touch the default
+// Generator, so it is safe for concurrent use.
+func Daily(date time.Time) (Board, error) {
+	return NewGenerator(DailySeed(date)).Generate(DailyDifficulty, 3)
+}
@@ -0,0 +1,26 @@
+package sudoku
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDailyStablePerDay(t *testing.T) {
+	morning := time.Date(2024, 3, 9, 1, 0, 0, 0, time.UTC)
+	evening := time.Date(2024, 3, 9, 23, 0, 0, 0, time.UTC)
+	if DailySeed(morning) != 20240309 || DailySeed(morning) != DailySeed(evening) {
+		t.Fatalf("unexpected seeds %d %d", DailySeed(morning), DailySeed(evening))
+	}
+	a, err := Daily(morning)
+	if err != nil {
+		t.Fatalf("daily: %v", err)
+	}
+	b, _ := Daily(evening)
+	c, _ := Daily(morning.AddDate(0, 0, 1))
+	if a != b {
+		t.Fatalf("same day produced different puzzles")
+	}
+	if a == c {
+		t.Fatalf("consecutive days produced the same puzzle")
+	}
+}
@@ -0,0 +1,75 @@
+package sudoku
+
+import "fmt"
+
+// CellChange is one cell that differs between two boards: Old is its value in
+// the first board and New its value in the second (0 for empty).
+type CellChange struct {
+	Cell Cell `json:"cell"`
+	Old  int  `json:"old"`
+	New  int  `json:"new"`
+}
+
+// Equal reports whether a and b hold the same value in every cell.
+func Equal(a, b Board) bool { return a == b }
+
+// Diff lists the cells where a and b differ, in row-major order; nil when they
+// are equal. Diff(board, solution) gives a player's wrong and missing entries,
+// the basis of a "check my progress" feature.
+func Diff(a, b Board) []CellChange {
+	var out []CellChange
+	for r := range a {
+		for c := range a[r] {
+			if a[r][c] != b[r][c] {
+				out = append(out, CellChange{Cell{r, c}, a[r][c], b[r][c]})
+			}
+		}
+	}
+	return out
+}
+
+// Equal reports whether g and o have the same dimensions and cell values.
+// Regions and Constraints are not compared.
+func (g Grid) Equal(o Grid) bool {
+	if !g.sameShape(o) {
+		return false
+	}
+	for r := range g.Cells {
+		for c := range g.Cells[r] {
+			if g.Cells[r][c] != o.Cells[r][c] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Diff lists the cells where g and o differ, in row-major order. It fails when
+// the grids have different dimensions.
+func (g Grid) Diff(o Grid) ([]CellChange, error) {
+	if !g.sameShape(o) {
+		return nil, fmt.Errorf("grid dimensions differ: %dx%d (%dx%d boxes) vs %dx%d (%dx%d boxes)",
+			g.Size, g.Size, g.BoxRows, g.BoxCols, o.Size, o.Size, o.BoxRows, o.BoxCols)
+	}
+	var out []CellChange
+	for r := range g.Cells {
+		for c := range g.Cells[r] {
+			if g.Cells[r][c] != o.Cells[r][c] {
+				out = append(out, CellChange{Cell{r, c}, g.Cells[r][c], o.Cells[r][c]})
+			}
+		}
+	}
+	return out, nil
+}
+
+func (g Grid) sameShape(o Grid) bool {
+	if g.Size != o.Size || g.BoxRows != o.BoxRows || g.BoxCols != o.BoxCols || len(g.Cells) != len(o.Cells) {
+		return false
+	}
+	for r := range g.Cells {
+		if len(g.Cells[r]) != len(o.Cells[r]) {
+			return false
+		}
+	}
+	return true
+}
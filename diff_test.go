@@ -0,0 +1,46 @@
+package sudoku
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEqualDiff(t *testing.T) {
+	puz, _ := FromString(easyPuzzle)
+	if !Equal(puz, puz) || Diff(puz, puz) != nil {
+		t.Fatal("board differs from itself")
+	}
+	b := puz
+	b[0][2] = 4 // player entry
+	b[0][0] = 0 // cleared given
+	want := []CellChange{{Cell{0, 0}, 5, 0}, {Cell{0, 2}, 0, 4}}
+	if Equal(puz, b) {
+		t.Fatal("Equal on different boards")
+	}
+	if got := Diff(puz, b); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Diff = %v, want %v", got, want)
+	}
+}
+
+func TestGridEqualDiff(t *testing.T) {
+	a, _ := NewGrid(4, 2, 2)
+	b := a.Clone()
+	if !a.Equal(b) {
+		t.Fatal("clone not equal")
+	}
+	b.Cells[3][1] = 2
+	if a.Equal(b) {
+		t.Fatal("Equal on different grids")
+	}
+	got, err := a.Diff(b)
+	if err != nil || !reflect.DeepEqual(got, []CellChange{{Cell{3, 1}, 0, 2}}) {
+		t.Fatalf("Diff = %v, %v", got, err)
+	}
+	c, _ := NewGrid(6, 2, 3)
+	if a.Equal(c) {
+		t.Fatal("grids of different sizes reported equal")
+	}
+	if _, err := a.Diff(c); err == nil {
+		t.Fatal("expected dimension error")
+	}
+}
@@ -0,0 +1,154 @@
+package sudoku
+
+// SolveDLX solves b with Knuth's Algorithm X on dancing links: an exact cover
+// over 324 constraints (cell, row-value, column-value, box-value) with one option
+// per candidate placement. It is deterministic and independent of the
+// backtracking solver, which makes it useful for cross-checking.
+func SolveDLX(b Board) (Board, bool) {
+	if Validate(b) != nil {
+		return Board{}, false
+	}
+	d := newDLX(b)
+	if d == nil || !d.search() {
+		return Board{}, false
+	}
+	out := b
+	for _, n := range d.solution {
+		row := d.nodes[n].row
+		out[row/81][row/9%9] = row%9 + 1
+	}
+	return out, true
+}
+
+type dlxNode struct {
+	left, right, up, down, col int
+	row                        int // option index r*81+c*9+(v-1); -1 for headers
+}
+
+// dlx holds the toroidal linked lists. Node 0 is the root, 1..324 are column
+// headers and the rest are option nodes, all in one slice.
+type dlx struct {
+	nodes    []dlxNode
+	size     []int // live nodes per column header
+	solution []int
+}
+
+const dlxCols = 324
+
+func newDLX(b Board) *dlx {
+	d := &dlx{nodes: make([]dlxNode, dlxCols+1, dlxCols+1+729*4), size: make([]int, dlxCols+1)}
+	for i := 0; i <= dlxCols; i++ {
+		d.nodes[i] = dlxNode{left: (i + dlxCols) % (dlxCols + 1), right: (i + 1) % (dlxCols + 1), up: i, down: i, col: i, row: -1}
+	}
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			for v := 1; v <= 9; v++ {
+				if b[r][c] != 0 && b[r][c] != v {
+					continue
+				}
+				box := (r/3)*3 + c/3
+				d.addRow(r*81+c*9+v-1, [4]int{
+					1 + r*9 + c,
+					1 + 81 + r*9 + v - 1,
+					1 + 162 + c*9 + v - 1,
+					1 + 243 + box*9 + v - 1,
+				})
+			}
+		}
+	}
+	// givens are forced: select their options up front
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			v := b[r][c]
+			if v == 0 {
+				continue
+			}
+			col := 1 + r*9 + c
+			n := d.nodes[col].down
+			if n == col { // already covered by a conflicting given
+				return nil
+			}
+			d.cover(col)
+			for j := d.nodes[n].right; j != n; j = d.nodes[j].right {
+				d.cover(d.nodes[j].col)
+			}
+			d.solution = append(d.solution, n)
+		}
+	}
+	return d
+}
+
+func (d *dlx) addRow(row int, cols [4]int) {
+	first := len(d.nodes)
+	for i, col := range cols {
+		n := len(d.nodes)
+		up := d.nodes[col].up
+		d.nodes = append(d.nodes, dlxNode{left: n - 1, right: n + 1, up: up, down: col, col: col, row: row})
+		d.nodes[up].down = n
+		d.nodes[col].up = n
+		d.size[col]++
+		if i == 0 {
+			d.nodes[n].left = first + 3
+		}
+	}
+	d.nodes[first+3].right = first
+}
+
+func (d *dlx) cover(col int) {
+	nd := d.nodes
+	nd[nd[col].right].left = nd[col].left
+	nd[nd[col].left].right = nd[col].right
+	for i := nd[col].down; i != col; i = nd[i].down {
+		for j := nd[i].right; j != i; j = nd[j].right {
+			nd[nd[j].down].up = nd[j].up
+			nd[nd[j].up].down = nd[j].down
+			d.size[nd[j].col]--
+		}
+	}
+}
+
+func (d *dlx) uncover(col int) {
+	nd := d.nodes
+	for i := nd[col].up; i != col; i = nd[i].up {
+		for j := nd[i].left; j != i; j = nd[j].left {
+			d.size[nd[j].col]++
+			nd[nd[j].down].up = j
+			nd[nd[j].up].down = j
+		}
+	}
+	nd[nd[col].right].left = col
+	nd[nd[col].left].right = col
+}
+
+// search finds the first exact cover, choosing the column with fewest options.
+func (d *dlx) search() bool {
+	nd := d.nodes
+	if nd[0].right == 0 {
+		return true
+	}
+	col, best := 0, 1<<30
+	for c := nd[0].right; c != 0; c = nd[c].right {
+		if d.size[c] < best {
+			col, best = c, d.size[c]
+		}
+	}
+	if best == 0 {
+		return false
+	}
+	d.cover(col)
+	for r := nd[col].down; r != col; r = nd[r].down {
+		d.solution = append(d.solution, r)
+		for j := nd[r].right; j != r; j = nd[j].right {
+			d.cover(nd[j].col)
+		}
+		if d.search() {
+			return true
+		}
+		for j := nd[r].left; j != r; j = nd[j].left {
+			d.uncover(nd[j].col)
+		}
+		d.solution = d.solution[:len(d.solution)-1]
+	}
+	d.uncover(col)
+	return false
+}
@@ -0,0 +1,33 @@
+package sudoku
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSolveDLX(t *testing.T) {
+	for _, s := range []string{
+		easyPuzzle,
+		"009700500010900320050020108000049073000000001020080000170000000090057000002100080",
+		"010050000204100730000640009001006000070230090000000340300000067806300000020800000",
+	} {
+		b, _ := FromString(s)
+		got, ok := SolveDLX(b)
+		want, _ := Solve(b)
+		if !ok || got != want {
+			t.Fatalf("%s: dlx=%v ok=%v", s, got, ok)
+		}
+	}
+	empty, ok := SolveDLX(Board{})
+	if !ok || Validate(empty) != nil || countClues(empty) != 81 {
+		t.Fatalf("empty board not solved")
+	}
+	b, _ := FromString("123456780000000009" + strings.Repeat("0", 63))
+	if _, ok := SolveDLX(b); ok {
+		t.Fatalf("expected unsolvable board to fail")
+	}
+	b[0][0], b[0][1] = 1, 1
+	if _, ok := SolveDLX(b); ok {
+		t.Fatalf("expected invalid board to fail")
+	}
+}
@@ -0,0 +1,54 @@
+package sudoku
+
+// RatingEngineVersion identifies the grading rules of Rate. It is bumped
+// whenever a change to the techniques or their thresholds can move a puzzle
+// to another grade; store it next to saved ratings so DetectDrift results can
+// be attributed to an upgrade.
+const RatingEngineVersion = 1
+
+// RatedPuzzle is a puzzle together with the rating it was stored with.
+type RatedPuzzle struct {
+	Puzzle Board  `json:"puzzle"`
+	Rating Rating `json:"rating"`
+}
+
+// Drift is one puzzle whose rating no longer matches the stored one. Err is set
+// when the current engine cannot rate the puzzle at all.
+type Drift struct {
+	Index   int    `json:"index"` // position in the input
+	Puzzle  Board  `json:"puzzle"`
+	Stored  Rating `json:"stored"`
+	Current Rating `json:"current"`
+	Err     string `json:"error,omitempty"`
+}
+
+// GradeChanged reports whether the difficulty itself moved, as opposed to only
+// the hardest technique within the same grade.
+func (d Drift) GradeChanged() bool { return d.Err != "" || d.Stored.Difficulty != d.Current.Difficulty }
+
+// DriftReport summarises a DetectDrift run.
+type DriftReport struct {
+	Engine  int     `json:"engine"`  // RatingEngineVersion used for the re-rating
+	Checked int     `json:"checked"` // puzzles re-rated
+	Drifted []Drift `json:"drifted"` // in input order
+}
+
+// DetectDrift re-rates every stored puzzle with the current Rate and reports
+// those whose difficulty or hardest technique changed, so a publisher can
+// review a collection after upgrading the library instead of hearing about
+// regraded puzzles from customers.
+func DetectDrift(stored []RatedPuzzle) DriftReport {
+	rep := DriftReport{Engine: RatingEngineVersion, Checked: len(stored), Drifted: []Drift{}}
+	for i, sp := range stored {
+		cur, err := Rate(sp.Puzzle)
+		d := Drift{Index: i, Puzzle: sp.Puzzle, Stored: sp.Rating, Current: cur}
+		switch {
+		case err != nil:
+			d.Err = err.Error()
+		case cur.Difficulty == sp.Rating.Difficulty && cur.Hardest == sp.Rating.Hardest:
+			continue
+		}
+		rep.Drifted = append(rep.Drifted, d)
+	}
+	return rep
+}
@@ -0,0 +1,39 @@
+package sudoku
+
+import "testing"
+
+func TestDetectDrift(t *testing.T) {
+	easy, _ := FromString(easyPuzzle)
+	hard, _ := FromString(inkala)
+	amb, _ := FromString("239718546014965320056423190681549273945372861327681954178296435493857612562134789")
+	easyNow, _ := Rate(easy)
+	hardNow, _ := Rate(hard)
+	stale := hardNow
+	stale.Difficulty = Medium
+	technique := easyNow
+	technique.Hardest = ReasonNakedPair
+
+	rep := DetectDrift([]RatedPuzzle{
+		{easy, easyNow},
+		{hard, stale},
+		{easy, technique},
+		{amb, Rating{Difficulty: Easy}},
+		{hard, hardNow},
+	})
+	if rep.Checked != 5 || rep.Engine != RatingEngineVersion || len(rep.Drifted) != 3 {
+		t.Fatalf("report %+v", rep)
+	}
+	got := rep.Drifted
+	if got[0].Index != 1 || got[0].Current.Difficulty != Hard || !got[0].GradeChanged() {
+		t.Fatalf("regraded puzzle %+v", got[0])
+	}
+	if got[1].Index != 2 || got[1].GradeChanged() || got[1].Current.Hardest == ReasonNakedPair {
+		t.Fatalf("technique-only drift %+v", got[1])
+	}
+	if got[2].Index != 3 || got[2].Err == "" || !got[2].GradeChanged() {
+		t.Fatalf("unratable puzzle %+v", got[2])
+	}
+	if rep := DetectDrift(nil); rep.Drifted == nil || len(rep.Drifted) != 0 {
+		t.Fatalf("empty input: %+v", rep)
+	}
+}
@@ -0,0 +1,199 @@
+package sudoku
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Step is one logical deduction with a plain-language reason, for teaching and
+// for hints that explain themselves.
+//
+// Placements set Cell and Value. Elimination steps (locked candidates, naked
+// pairs) leave Value zero and list the cells that lost Digits in Eliminated.
+type Step struct {
+	Technique  Technique `json:"technique"`
+	Cell       Cell      `json:"cell"`
+	Value      int       `json:"value"`
+	Unit       *Unit     `json:"unit,omitempty"` // where a hidden single, locked candidate or pair was found
+	Cells      []Cell    `json:"cells"`          // cells the reasoning relies on
+	Eliminated []Cell    `json:"eliminated,omitempty"`
+	Digits     []int     `json:"digits,omitempty"` // candidates removed from Eliminated
+	// FromCandidates marks steps that rely on candidates removed by earlier
+	// steps rather than on the filled cells alone; Text is their only wording.
+	FromCandidates bool   `json:"fromCandidates,omitempty"`
+	Text           string `json:"text"`
+}
+
+// HintExplain returns the next placement a human finds by singles, in the order
+// they are usually taught: a hidden single in a box, then in a row or column,
+// then a naked single. Cells lists the filled cells that justify it, ready to
+// be highlighted. It reports false for invalid boards and when singles are not
+// enough. Deductions follow from the board as given, so wrong entries can lead
+// to wrong steps.
+func HintExplain(b Board) (Step, bool) {
+	if Validate(b) != nil {
+		return Step{}, false
+	}
+	for _, kind := range []UnitKind{UnitBox, UnitRow, UnitColumn} {
+		for i := 0; i < 9; i++ {
+			if st, ok := hiddenSingleStep(&b, Unit{kind, i}); ok {
+				return st, true
+			}
+		}
+	}
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if m := candidateMask(&b, r, c); b[r][c] == 0 && m&(m-1) == 0 && m != 0 {
+				return nakedSingleStep(&b, Cell{r, c}, maskValue(m)), true
+			}
+		}
+	}
+	return Step{}, false
+}
+
+// ExplainSolve solves b the way Rate grades it and returns every step in order:
+// singles the filled cells justify (as HintExplain finds them), then singles,
+// locked candidates and naked pairs on the candidates left by earlier steps,
+// and a TechniqueGuess placement of the solution value in the cell with the
+// fewest candidates whenever logic stalls. Invalid puzzles and puzzles without
+// a unique solution return an error.
+func ExplainSolve(b Board) ([]Step, error) {
+	if err := checkUnique(b); err != nil {
+		return nil, err
+	}
+	solution, _ := Solve(b)
+	st := newLogicState(b)
+	st.trace = true
+	for countClues(st.b) < 81 {
+		if step, ok := HintExplain(st.b); ok {
+			st.place(step.Cell.Row, step.Cell.Col, step.Value)
+			st.steps = append(st.steps, step)
+			continue
+		}
+		progress := false
+		for _, tech := range logicTechniques {
+			if tech.apply(&st) {
+				progress = true
+				break
+			}
+		}
+		if progress {
+			continue
+		}
+		at, best := Cell{}, 10
+		for r := 0; r < 9; r++ {
+			for c := 0; c < 9; c++ {
+				if n := bits.OnesCount16(st.cand[r][c]); st.b[r][c] == 0 && n < best {
+					at, best = Cell{r, c}, n
+				}
+			}
+		}
+		v := solution[at.Row][at.Col]
+		cands := maskValues(st.cand[at.Row][at.Col])
+		st.place(at.Row, at.Col, v)
+		st.record(Step{Technique: TechniqueGuess, Cell: at, Value: v,
+			Text: fmt.Sprintf("No logical step is left: %s could be %s; trying %d leads to the solution.",
+				at, intList(cands), v)})
+	}
+	return st.steps, nil
+}
+
+func hiddenSingleStep(b *Board, u Unit) (Step, bool) {
+	cells := u.Cells()
+	for v := 1; v <= 9; v++ {
+		var at Cell
+		n := 0
+		for _, p := range cells {
+			if b[p.Row][p.Col] == v {
+				n = -1
+				break
+			}
+			if b[p.Row][p.Col] == 0 && candidateMask(b, p.Row, p.Col)&(1<<v) != 0 {
+				at, n = p, n+1
+			}
+		}
+		if n != 1 {
+			continue
+		}
+		// every other empty cell of u sees a v elsewhere: collect those blockers
+		var why []Cell
+		for _, p := range cells {
+			if p == at || b[p.Row][p.Col] != 0 {
+				continue
+			}
+			if q, ok := seenValue(b, p, v, u); ok && !containsCell(why, q) {
+				why = append(why, q)
+			}
+		}
+		unit := u
+		return Step{
+			Technique: TechniqueHiddenSingle, Cell: at, Value: v, Unit: &unit, Cells: why,
+			Text: fmt.Sprintf("%d fits nowhere else in %s: every other empty cell there already sees a %d, so %s is %d.",
+				v, u, v, at, v),
+		}, true
+	}
+	return Step{}, false
+}
+
+func nakedSingleStep(b *Board, at Cell, v int) Step {
+	var why []Cell
+	var others []string
+	for d := 1; d <= 9; d++ {
+		if d == v {
+			continue
+		}
+		others = append(others, strconv.Itoa(d))
+		if q, ok := seenValue(b, at, d, Unit{Kind: -1}); ok && !containsCell(why, q) {
+			why = append(why, q)
+		}
+	}
+	return Step{
+		Technique: TechniqueNakedSingle, Cell: at, Value: v, Cells: why,
+		Text: fmt.Sprintf("%s can only be %d: its row, column and box already hold %s.",
+			at, v, strings.Join(others, ", ")),
+	}
+}
+
+// seenValue returns the first peer of p holding v, outside unit skip.
+func seenValue(b *Board, p Cell, v int, skip Unit) (Cell, bool) {
+	for _, u := range UnitsOf(p) {
+		if u == skip {
+			continue
+		}
+		for _, q := range u.Cells() {
+			if b[q.Row][q.Col] == v {
+				return q, true
+			}
+		}
+	}
+	return Cell{}, false
+}
+
+// cellList joins cells as "r1c1, r1c2".
+func cellList(cells []Cell) string {
+	out := make([]string, len(cells))
+	for i, c := range cells {
+		out[i] = c.String()
+	}
+	return strings.Join(out, ", ")
+}
+
+// intList joins values as "3, 7".
+func intList(values []int) string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = strconv.Itoa(v)
+	}
+	return strings.Join(out, ", ")
+}
+
+func containsCell(cells []Cell, c Cell) bool {
+	for _, x := range cells {
+		if x == c {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package sudoku
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHintExplainWalksEasyPuzzle(t *testing.T) {
+	b, _ := FromString(easyPuzzle)
+	sol, _ := FromString("534678912672195348198342567859761423426853791713924856961537284287419635345286179")
+	for countClues(b) < 81 {
+		st, ok := HintExplain(b)
+		if !ok {
+			t.Fatalf("singles stalled on an easy puzzle at %d clues", countClues(b))
+		}
+		if b[st.Cell.Row][st.Cell.Col] != 0 || sol[st.Cell.Row][st.Cell.Col] != st.Value {
+			t.Fatalf("wrong step %+v", st)
+		}
+		if st.Text == "" || !strings.Contains(st.Text, st.Cell.String()) {
+			t.Fatalf("explanation %q does not name the cell", st.Text)
+		}
+		for _, c := range st.Cells {
+			if b[c.Row][c.Col] == 0 {
+				t.Fatalf("step %+v relies on empty cell %s", st, c)
+			}
+		}
+		b[st.Cell.Row][st.Cell.Col] = st.Value
+	}
+}
+
+func TestHintExplainTechniques(t *testing.T) {
+	// r1c1 holds the only empty spot for 1 in box 1
+	b, _ := FromString(easyPuzzle)
+	st, ok := HintExplain(b)
+	if !ok || st.Technique != TechniqueHiddenSingle || st.Unit == nil || st.Unit.Kind != UnitBox {
+		t.Fatalf("first step %+v", st)
+	}
+
+	// a row missing only its last digit: the other eight are the reason
+	var n Board
+	copy(n[0][:], []int{1, 2, 3, 4, 5, 6, 7, 8, 0})
+	st = nakedSingleStep(&n, Cell{0, 8}, 9)
+	if st.Technique != TechniqueNakedSingle || len(st.Cells) != 8 || !strings.Contains(st.Text, "hold 1, 2, 3, 4, 5, 6, 7, 8.") {
+		t.Fatalf("naked single %+v", st)
+	}
+	if st2, _ := HintExplain(Board{}); st2.Value != 0 {
+		t.Fatalf("empty board has no single: %+v", st2)
+	}
+	if _, ok := HintExplain(Board{{1, 1}}); ok {
+		t.Fatalf("invalid board explained")
+	}
+}
+
+func TestExplainSolve(t *testing.T) {
+	for _, tc := range []struct {
+		puzzle string
+		want   Technique // hardest technique used
+	}{
+		{easyPuzzle, TechniqueHiddenSingle},
+		{"000700000000005040381000007000071400000000600093082000020050300010020004800049260", TechniqueLockedCandidates},
+		{"800000000003600000070090200050007000000045700000100030001000068008500010090000400", TechniqueGuess},
+	} {
+		b, _ := FromString(tc.puzzle)
+		sol, _ := Solve(b)
+		steps, err := ExplainSolve(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hardest := TechniqueNakedSingle
+		for _, st := range steps {
+			if st.Technique > hardest {
+				hardest = st.Technique
+			}
+			if st.Text == "" {
+				t.Fatalf("step %+v has no text", st)
+			}
+			if st.Value == 0 {
+				// elimination: never removes a solution digit
+				if len(st.Eliminated) == 0 || len(st.Digits) == 0 {
+					t.Fatalf("empty elimination %+v", st)
+				}
+				for _, c := range st.Eliminated {
+					for _, d := range st.Digits {
+						if sol[c.Row][c.Col] == d {
+							t.Fatalf("step %+v removes the solution %d from %s", st, d, c)
+						}
+					}
+				}
+				continue
+			}
+			if b[st.Cell.Row][st.Cell.Col] != 0 || sol[st.Cell.Row][st.Cell.Col] != st.Value {
+				t.Fatalf("wrong placement %+v", st)
+			}
+			b[st.Cell.Row][st.Cell.Col] = st.Value
+		}
+		if b != sol {
+			t.Fatalf("%s: steps do not complete the grid", tc.puzzle)
+		}
+		orig, _ := FromString(tc.puzzle)
+		if r, _ := Rate(orig); hardest != tc.want || hardest.Info().Difficulty != r.Difficulty {
+			t.Fatalf("%s: hardest step %s, want %s (Rate says %s)", tc.puzzle, hardest, tc.want, r.Difficulty)
+		}
+	}
+	if _, err := ExplainSolve(Board{}); err == nil {
+		t.Fatal("expected error for a puzzle with many solutions")
+	}
+}
@@ -0,0 +1,77 @@
+package sudoku
+
+import "strings"
+
+// FormatStyle selects the border characters of Format.
+type FormatStyle int
+
+const (
+	FormatASCII   FormatStyle = iota // +-------+ borders and | separators
+	FormatUnicode                    // box-drawing characters: ┌───┬───┐
+)
+
+// border characters: left, junction, right and horizontal line for the top,
+// inner and bottom rules, then the vertical bar.
+var formatBorders = [...]struct {
+	top, mid, bottom [3]string
+	line, bar        string
+}{
+	FormatASCII:   {[3]string{"+", "+", "+"}, [3]string{"+", "+", "+"}, [3]string{"+", "+", "+"}, "-", "|"},
+	FormatUnicode: {[3]string{"┌", "┬", "┐"}, [3]string{"├", "┼", "┤"}, [3]string{"└", "┴", "┘"}, "─", "│"},
+}
+
+// Format draws b as a bordered grid with '.' for empty cells, one line per row
+// plus box rules, each line ending in a newline:
+//
+//	+-------+-------+-------+
+//	| 5 3 . | . 7 . | . . . |
+func Format(b Board, style FormatStyle) string { return b.ToGrid().Format(style) }
+
+// Format draws g like the package-level Format, with rules between its
+// BoxRows x BoxCols boxes and values from GridAlphabet. Jigsaw regions are not
+// drawn; such grids get only the outer border.
+func (g Grid) Format(style FormatStyle) string {
+	if style < 0 || int(style) >= len(formatBorders) {
+		style = FormatASCII
+	}
+	br, bc := g.BoxRows, g.BoxCols
+	if g.Regions != nil || br <= 0 || bc <= 0 {
+		br, bc = g.Size, g.Size
+	}
+	bd := formatBorders[style]
+	segment := strings.Repeat(bd.line, 2*bc+1)
+	rule := func(ends [3]string) string {
+		parts := make([]string, max(g.Size/bc, 1))
+		for i := range parts {
+			parts[i] = segment
+		}
+		return ends[0] + strings.Join(parts, ends[1]) + ends[2] + "\n"
+	}
+	var sb strings.Builder
+	sb.WriteString(rule(bd.top))
+	for r := 0; r < g.Size; r++ {
+		sb.WriteString(bd.bar)
+		for c := 0; c < g.Size; c++ {
+			sb.WriteByte(' ')
+			switch v := g.Cells[r][c]; {
+			case v == 0:
+				sb.WriteByte('.')
+			case v > 0 && v < len(GridAlphabet):
+				sb.WriteByte(GridAlphabet[v])
+			default:
+				sb.WriteByte('?')
+			}
+			if (c+1)%bc == 0 {
+				sb.WriteString(" " + bd.bar)
+			}
+		}
+		sb.WriteByte('\n')
+		switch {
+		case r == g.Size-1:
+			sb.WriteString(rule(bd.bottom))
+		case (r+1)%br == 0:
+			sb.WriteString(rule(bd.mid))
+		}
+	}
+	return sb.String()
+}
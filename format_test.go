@@ -0,0 +1,40 @@
+package sudoku
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	b, _ := FromString(easyPuzzle)
+	ascii := Format(b, FormatASCII)
+	lines := strings.Split(strings.TrimSuffix(ascii, "\n"), "\n")
+	if len(lines) != 13 || lines[0] != "+-------+-------+-------+" || lines[1] != "| 5 3 . | . 7 . | . . . |" || lines[4] != lines[0] {
+		t.Fatalf("ASCII:\n%s", ascii)
+	}
+	uni := Format(b, FormatUnicode)
+	lines = strings.Split(strings.TrimSuffix(uni, "\n"), "\n")
+	if lines[0] != "┌───────┬───────┬───────┐" || lines[1] != "│ 5 3 . │ . 7 . │ . . . │" ||
+		lines[4] != "├───────┼───────┼───────┤" || lines[12] != "└───────┴───────┴───────┘" {
+		t.Fatalf("Unicode:\n%s", uni)
+	}
+}
+
+func TestGridFormat(t *testing.T) {
+	g, _ := FromStringN("1.....2.........", 4, 2, 2)
+	g6, _ := NewGrid(6, 2, 3)
+	g6.Cells[0][0] = 6
+	want4 := "+-----+-----+\n| 1 . | . . |\n| . . | 2 . |\n+-----+-----+\n| . . | . . |\n| . . | . . |\n+-----+-----+\n"
+	if got := g.Format(FormatASCII); got != want4 {
+		t.Fatalf("4x4:\n%s", got)
+	}
+	lines := strings.Split(g6.Format(FormatASCII), "\n")
+	if lines[0] != "+-------+-------+" || lines[1] != "| 6 . . | . . . |" || lines[3] != lines[0] {
+		t.Fatalf("6x6:\n%s", strings.Join(lines, "\n"))
+	}
+	g16, _ := NewGrid(16, 4, 4)
+	g16.Cells[0][0] = 16
+	if !strings.HasPrefix(strings.Split(g16.Format(FormatUnicode), "\n")[1], "│ G . . . │") {
+		t.Fatalf("16x16:\n%s", g16.Format(FormatUnicode))
+	}
+}
@@ -0,0 +1,134 @@
+package sudoku
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+// ParseAny reads one classic puzzle in any of the common text formats:
+//
+//   - one-line .sdm: 81 characters per line (the first puzzle is used)
+//   - SadMan .sdk: nine lines of nine characters, with optional [Section]
+//     headers
+//   - Simple Sudoku .ss: rows like "..6|...|..1" with "-----------" separators
+//   - formatted grids using spaces, pipes and +---+ or Unicode box-drawing
+//     borders, including the CLI's output
+//
+// Digits 1-9 are values; '0', '.', 'x', '*' and '_' are empty cells. Lines
+// starting with '#' are comments, and label lines ending in ':' (the CLI's
+// "Generated (easy):" header) are skipped. The board is validated like FromString.
+func ParseAny(r io.Reader) (Board, error) {
+	b, err := ParseAnyUnchecked(r)
+	if err != nil {
+		return Board{}, err
+	}
+	if err := Validate(b); err != nil {
+		return Board{}, err
+	}
+	return b, nil
+}
+
+// ParseAnyUnchecked reads a board like ParseAny but keeps duplicate values, so
+// conflicting input can be inspected with ValidateAll.
+func ParseAnyUnchecked(r io.Reader) (Board, error) {
+	sc := bufio.NewScanner(r)
+	var cells []byte
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || text[0] == '#' || text[0] == '[' || strings.HasSuffix(text, ":") {
+			continue
+		}
+		row, err := formatCells(text)
+		if err != nil {
+			return Board{}, fmt.Errorf("line %d: %w", line, err)
+		}
+		if len(row) == 81 && len(cells) == 0 {
+			cells = row // .sdm: a whole puzzle on one line
+			break
+		}
+		cells = append(cells, row...)
+		if len(cells) >= 81 {
+			break
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return Board{}, err
+	}
+	if len(cells) != 81 {
+		return Board{}, fmt.Errorf("found %d cells, want 81", len(cells))
+	}
+	var b Board
+	for i, ch := range cells {
+		if ch != '0' {
+			b[i/9][i%9] = int(ch - '0')
+		}
+	}
+	return b, nil
+}
+
+// ReadSDM streams a one-puzzle-per-line .sdm collection, reading one line at a
+// time so large datasets never sit in memory whole. Blank lines and lines
+// starting with '#' are skipped, and anything after the first space, tab or
+// comma is ignored, so "puzzle,solution" CSV rows work too. A bad line yields
+// its error (with the line number) and iteration continues; a read error is
+// yielded last.
+func ReadSDM(r io.Reader) iter.Seq2[Board, error] {
+	return func(yield func(Board, error) bool) {
+		sc := bufio.NewScanner(r)
+		line := 0
+		for sc.Scan() {
+			line++
+			text := strings.TrimSpace(sc.Text())
+			if text == "" || text[0] == '#' {
+				continue
+			}
+			if i := strings.IndexAny(text, " \t,"); i >= 0 {
+				text = text[:i]
+			}
+			b, err := FromString(text)
+			if err != nil {
+				err = fmt.Errorf("line %d: %w", line, err)
+			}
+			if !yield(b, err) {
+				return
+			}
+		}
+		if err := sc.Err(); err != nil {
+			yield(Board{}, err)
+		}
+	}
+}
+
+// WriteSDM writes boards one per line in the 81-character String form.
+func WriteSDM(w io.Writer, boards []Board) error {
+	bw := bufio.NewWriter(w)
+	for _, b := range boards {
+		bw.WriteString(b.String())
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// formatCells extracts the cells of one line as FromString characters,
+// skipping separators.
+func formatCells(text string) ([]byte, error) {
+	var out []byte
+	for _, ch := range text {
+		switch {
+		case ch >= '1' && ch <= '9':
+			out = append(out, byte(ch))
+		case ch == '0' || ch == '.' || ch == 'x' || ch == 'X' || ch == '*' || ch == '_':
+			out = append(out, '0')
+		case ch == ' ' || ch == '\t' || ch == '|' || ch == '+' || ch == '-' || ch == '=' || ch == ',':
+		case ch >= 0x2500 && ch <= 0x257f: // box drawing
+		default:
+			return nil, fmt.Errorf("unexpected character %q", ch)
+		}
+	}
+	return out, nil
+}
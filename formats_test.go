@@ -0,0 +1,144 @@
+package sudoku
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseAny(t *testing.T) {
+	const want = easyPuzzle
+	inputs := map[string]string{
+		"sdm": "# collection\n" + want + "\n" + strings.Repeat("0", 81) + "\n",
+		"sdk": `[Puzzle]
+53..7....
+6..195...
+.98....6.
+8...6...3
+4..8.3..1
+7...2...6
+.6....28.
+...419..5
+....8..79
+`,
+		"ss": `53.|.7.|...
+6..|195|...
+.98|...|.6.
+-----------
+8..|.6.|..3
+4..|8.3|..1
+7..|.2.|..6
+-----------
+.6.|...|28.
+...|419|..5
+...|.8.|.79
+`,
+		"cli": `+-------+-------+-------+
+| 5 3 . | . 7 . | . . . |
+| 6 . . | 1 9 5 | . . . |
+| . 9 8 | . . . | . 6 . |
++-------+-------+-------+
+| 8 . . | . 6 . | . . 3 |
+| 4 . . | 8 . 3 | . . 1 |
+| 7 . . | . 2 . | . . 6 |
++-------+-------+-------+
+| . 6 . | . . . | 2 8 . |
+| . . . | 4 1 9 | . . 5 |
+| . . . | . 8 . | . 7 9 |
++-------+-------+-------+
+`,
+		"spaces": `5 3 0 0 7 0 0 0 0
+6 0 0 1 9 5 0 0 0
+0 9 8 0 0 0 0 6 0
+8 0 0 0 6 0 0 0 3
+4 0 0 8 0 3 0 0 1
+7 0 0 0 2 0 0 0 6
+0 6 0 0 0 0 2 8 0
+0 0 0 4 1 9 0 0 5
+0 0 0 0 8 0 0 7 9`,
+	}
+	for name, in := range inputs {
+		b, err := ParseAny(strings.NewReader(in))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if b.String() != want {
+			t.Fatalf("%s: got %s", name, b)
+		}
+	}
+}
+
+func TestParseAnyErrors(t *testing.T) {
+	for name, in := range map[string]string{
+		"short":     "53..7....\n6..195...\n",
+		"bad char":  "53..7..a.\n",
+		"duplicate": "55" + strings.Repeat(".", 79),
+		"empty":     "",
+	} {
+		if _, err := ParseAny(strings.NewReader(in)); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestParseAnyUnchecked(t *testing.T) {
+	in := "55.|...|...\n" + strings.Repeat("...|...|...\n", 8)
+	b, err := ParseAnyUnchecked(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b[0][0] != 5 || b[0][1] != 5 || len(ValidateAll(b)) == 0 {
+		t.Fatalf("duplicates not kept:\n%s", b)
+	}
+	if _, err := ParseAny(strings.NewReader(in)); !errors.Is(err, ErrInvalidBoard) {
+		t.Fatalf("ParseAny: expected ErrInvalidBoard, got %v", err)
+	}
+}
+
+func TestReadSDM(t *testing.T) {
+	const a = easyPuzzle
+	b, _ := FromString(a)
+	in := "# collection\n" + a + "\n\n" + strings.Repeat(".", 81) + ",extra\n" + "55" + strings.Repeat("0", 79) + "\n" + a + " 25\n"
+	var got []Board
+	var errs []error
+	for board, err := range ReadSDM(strings.NewReader(in)) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		got = append(got, board)
+	}
+	if len(got) != 3 || got[0] != b || got[1] != (Board{}) || got[2] != b {
+		t.Fatalf("boards = %v", got)
+	}
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "line 5:") {
+		t.Fatalf("errors = %v", errs)
+	}
+
+	n := 0
+	for range ReadSDM(strings.NewReader(in)) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Fatal("iteration did not stop on break")
+	}
+}
+
+func TestWriteSDM(t *testing.T) {
+	b, _ := FromString(easyPuzzle)
+	var sb strings.Builder
+	if err := WriteSDM(&sb, []Board{b, {}}); err != nil {
+		t.Fatal(err)
+	}
+	var back []Board
+	for board, err := range ReadSDM(strings.NewReader(sb.String())) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		back = append(back, board)
+	}
+	if len(back) != 2 || back[0] != b || back[1] != (Board{}) {
+		t.Fatalf("round trip = %v", back)
+	}
+}
@@ -0,0 +1,192 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrGivenCell is returned when a move targets one of the puzzle's clues.
+var ErrGivenCell = errors.New("cell is a given")
+
+// Move is one change to a Game's board; Old and New are 0 for an empty cell.
+type Move struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+	Old int `json:"old"`
+	New int `json:"new"`
+}
+
+// Game is an interactive session on a Puzzle: it keeps the player's entries apart
+// from the givens, logs every move for Undo and Redo, and tracks play time. The
+// clock stops while paused and once the board matches the solution. A Game is not
+// safe for concurrent use.
+type Game struct {
+	puzzle  Puzzle
+	board   Board
+	moves   []Move // applied, oldest first
+	redo    []Move // undone, most recent last
+	elapsed time.Duration
+	since   time.Time // start of the running stretch; zero while stopped
+	paused  bool
+	now     func() time.Time
+}
+
+// NewGame starts a game on p with the clock running.
+func NewGame(p Puzzle) *Game {
+	g := &Game{puzzle: p, board: p.Givens, now: time.Now}
+	g.since = g.now()
+	return g
+}
+
+// Puzzle returns the puzzle being played.
+func (g *Game) Puzzle() Puzzle { return g.puzzle }
+
+// Board returns the current board: givens plus the player's entries.
+func (g *Game) Board() Board { return g.board }
+
+// Moves returns the applied moves, oldest first.
+func (g *Game) Moves() []Move { return append([]Move(nil), g.moves...) }
+
+// Set enters v (1-9, or 0 to clear) at r,c. Givens cannot change. Setting a cell
+// to its current value is not a move. A new move discards the redo history.
+func (g *Game) Set(r, c, v int) error {
+	if r < 0 || r > 8 || c < 0 || c > 8 {
+		return fmt.Errorf("cell r%dc%d out of range", r+1, c+1)
+	}
+	if v < 0 || v > 9 {
+		return fmt.Errorf("value %d out of range", v)
+	}
+	if g.puzzle.IsGiven(r, c) {
+		return ErrGivenCell
+	}
+	if g.board[r][c] == v {
+		return nil
+	}
+	g.moves = append(g.moves, Move{Row: r, Col: c, Old: g.board[r][c], New: v})
+	g.board[r][c] = v
+	g.redo = g.redo[:0]
+	g.updateClock()
+	return nil
+}
+
+// Undo reverts the last move and reports whether there was one.
+func (g *Game) Undo() bool {
+	if len(g.moves) == 0 {
+		return false
+	}
+	m := g.moves[len(g.moves)-1]
+	g.moves = g.moves[:len(g.moves)-1]
+	g.board[m.Row][m.Col] = m.Old
+	g.redo = append(g.redo, m)
+	g.updateClock()
+	return true
+}
+
+// Redo reapplies the last undone move and reports whether there was one.
+func (g *Game) Redo() bool {
+	if len(g.redo) == 0 {
+		return false
+	}
+	m := g.redo[len(g.redo)-1]
+	g.redo = g.redo[:len(g.redo)-1]
+	g.board[m.Row][m.Col] = m.New
+	g.moves = append(g.moves, m)
+	g.updateClock()
+	return true
+}
+
+// CanUndo and CanRedo report whether Undo or Redo would do anything.
+func (g *Game) CanUndo() bool { return len(g.moves) > 0 }
+
+func (g *Game) CanRedo() bool { return len(g.redo) > 0 }
+
+// Restart clears every entry and the history and restarts the clock from zero.
+func (g *Game) Restart() {
+	g.board, g.moves, g.redo = g.puzzle.Givens, nil, nil
+	g.elapsed, g.paused = 0, false
+	g.since = g.now()
+}
+
+// Solved reports whether the board matches the solution.
+func (g *Game) Solved() bool { return g.board == g.puzzle.Solution }
+
+// Elapsed returns the play time so far, excluding pauses.
+func (g *Game) Elapsed() time.Duration {
+	if g.since.IsZero() {
+		return g.elapsed
+	}
+	return g.elapsed + g.now().Sub(g.since)
+}
+
+// Pause stops the clock; Resume restarts it unless the game is solved.
+func (g *Game) Pause() {
+	g.paused = true
+	g.updateClock()
+}
+
+func (g *Game) Resume() {
+	g.paused = false
+	g.updateClock()
+}
+
+// updateClock runs the clock exactly when the game is neither paused nor solved.
+func (g *Game) updateClock() {
+	run := !g.paused && !g.Solved()
+	switch {
+	case run && g.since.IsZero():
+		g.since = g.now()
+	case !run && !g.since.IsZero():
+		g.elapsed += g.now().Sub(g.since)
+		g.since = time.Time{}
+	}
+}
+
+// gameJSON is the savegame form of a Game.
+type gameJSON struct {
+	Puzzle  Puzzle `json:"puzzle"`
+	Moves   []Move `json:"moves"`
+	Redo    []Move `json:"redo,omitempty"`
+	Elapsed int64  `json:"elapsedMs"`
+	Paused  bool   `json:"paused,omitempty"`
+}
+
+// MarshalJSON saves the puzzle, move history and play time.
+func (g *Game) MarshalJSON() ([]byte, error) {
+	return json.Marshal(gameJSON{Puzzle: g.puzzle, Moves: g.moves, Redo: g.redo,
+		Elapsed: g.Elapsed().Milliseconds(), Paused: g.paused})
+}
+
+// UnmarshalJSON restores a saved game by replaying its moves; a restored game's
+// clock runs unless it was saved paused or solved.
+func (g *Game) UnmarshalJSON(data []byte) error {
+	var s gameJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	out := Game{puzzle: s.Puzzle, board: s.Puzzle.Givens, now: time.Now}
+	if g.now != nil {
+		out.now = g.now
+	}
+	valid := func(m Move) bool {
+		return m.Row >= 0 && m.Row <= 8 && m.Col >= 0 && m.Col <= 8 && m.New >= 0 && m.New <= 9 &&
+			m.Old >= 0 && m.Old <= 9 && !s.Puzzle.IsGiven(m.Row, m.Col)
+	}
+	for _, m := range s.Moves {
+		if !valid(m) || out.board[m.Row][m.Col] != m.Old {
+			return fmt.Errorf("invalid move %+v in saved game", m)
+		}
+		out.board[m.Row][m.Col] = m.New
+	}
+	for _, m := range s.Redo {
+		if !valid(m) {
+			return fmt.Errorf("invalid move %+v in saved game", m)
+		}
+	}
+	out.moves, out.redo = s.Moves, s.Redo
+	out.elapsed, out.paused = time.Duration(s.Elapsed)*time.Millisecond, s.Paused
+	out.updateClock()
+	*g = out
+	return nil
+}
@@ -0,0 +1,114 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestGame(t *testing.T) (*Game, *time.Time) {
+	t.Helper()
+	b, _ := FromString(easyPuzzle)
+	p, err := newPuzzle(b, Easy, 0)
+	if err != nil {
+		t.Fatalf("puzzle: %v", err)
+	}
+	now := time.Unix(1000, 0)
+	g := NewGame(p)
+	g.now = func() time.Time { return now }
+	g.since = now
+	return g, &now
+}
+
+func TestGameMovesUndoRedo(t *testing.T) {
+	g, _ := newTestGame(t)
+	if err := g.Set(0, 0, 1); !errors.Is(err, ErrGivenCell) {
+		t.Fatalf("given overwritten: %v", err)
+	}
+	if err := g.Set(0, 2, 10); err == nil {
+		t.Fatalf("out of range value accepted")
+	}
+	g.Set(0, 2, 1)
+	g.Set(0, 2, 4)
+	g.Set(0, 3, 6)
+	if len(g.Moves()) != 3 || g.Board()[0][2] != 4 {
+		t.Fatalf("moves %v board %v", g.Moves(), g.Board()[0])
+	}
+	if !g.Undo() || !g.Undo() || g.Board()[0][2] != 1 || g.Board()[0][3] != 0 {
+		t.Fatalf("undo: %v", g.Board()[0])
+	}
+	if !g.Redo() || g.Board()[0][2] != 4 || !g.CanRedo() {
+		t.Fatalf("redo: %v", g.Board()[0])
+	}
+	g.Set(8, 0, 3) // a new move drops the redo history
+	if g.CanRedo() || g.Redo() {
+		t.Fatalf("redo survived a new move")
+	}
+	g.Restart()
+	if g.Board() != g.Puzzle().Givens || g.CanUndo() {
+		t.Fatalf("restart kept entries")
+	}
+}
+
+func TestGameClock(t *testing.T) {
+	g, now := newTestGame(t)
+	*now = now.Add(10 * time.Second)
+	g.Pause()
+	*now = now.Add(time.Hour)
+	if g.Elapsed() != 10*time.Second {
+		t.Fatalf("clock ran while paused: %v", g.Elapsed())
+	}
+	g.Resume()
+	*now = now.Add(5 * time.Second)
+	// finishing the puzzle stops the clock
+	sol := g.Puzzle().Solution
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if !g.Puzzle().IsGiven(r, c) {
+				g.Set(r, c, sol[r][c])
+			}
+		}
+	}
+	*now = now.Add(time.Minute)
+	if !g.Solved() || g.Elapsed() != 15*time.Second {
+		t.Fatalf("solved=%v elapsed=%v", g.Solved(), g.Elapsed())
+	}
+	g.Undo() // unsolving restarts it
+	*now = now.Add(time.Second)
+	if g.Elapsed() != 16*time.Second {
+		t.Fatalf("clock after undo: %v", g.Elapsed())
+	}
+}
+
+func TestGameJSONRoundTrip(t *testing.T) {
+	g, now := newTestGame(t)
+	g.Set(0, 2, 4)
+	g.Set(0, 3, 6)
+	g.Undo()
+	*now = now.Add(90 * time.Second)
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var back Game
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if back.Board() != g.Board() || len(back.Moves()) != 1 || !back.CanRedo() || back.Elapsed() < 90*time.Second {
+		t.Fatalf("restored game differs: %+v", back)
+	}
+	var bad Game
+	if err := json.Unmarshal([]byte(`{"puzzle":`+string(mustJSON(t, g.Puzzle()))+`,"moves":[{"row":0,"col":0,"old":5,"new":1}]}`), &bad); err == nil {
+		t.Fatalf("move on a given accepted")
+	}
+}
+
+func mustJSON(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return data
+}
@@ -0,0 +1,122 @@
+package sudoku
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// GenerateOption configures GenerateN.
+type GenerateOption func(*generateNConfig)
+
+type generateNConfig struct {
+	workers  int
+	attempts int
+	seed     uint64
+	seeded   bool
+	symmetry Symmetry
+	progress func(done, total int)
+}
+
+// WithWorkers sets the number of parallel workers (default GOMAXPROCS).
+func WithWorkers(n int) GenerateOption { return func(c *generateNConfig) { c.workers = n } }
+
+// WithAttempts sets the per-puzzle attempts passed to Generate (default 3).
+func WithAttempts(n int) GenerateOption { return func(c *generateNConfig) { c.attempts = n } }
+
+// WithSeed makes GenerateN reproducible: the same seed, n and difficulty yield
+// the same puzzles in the same order regardless of the worker count.
+func WithSeed(seed uint64) GenerateOption {
+	return func(c *generateNConfig) { c.seed, c.seeded = seed, true }
+}
+
+// WithSymmetry lays out the givens of every puzzle with sym (see
+// GenerateSymmetric).
+func WithSymmetry(sym Symmetry) GenerateOption { return func(c *generateNConfig) { c.symmetry = sym } }
+
+// WithProgress calls fn after each generated puzzle with the number done so far
+// and n. Calls are serialized but come from worker goroutines; done can step
+// back slightly when duplicates are dropped and regenerated.
+func WithProgress(fn func(done, total int)) GenerateOption {
+	return func(c *generateNConfig) { c.progress = fn }
+}
+
+// GenerateN generates n puzzles of difficulty d in parallel and drops any that are
+// isomorphic to one already produced (same Canonical form), generating more until
+// n distinct puzzles exist. Each puzzle gets its own seed, drawn in order from the
+// WithSeed seed or the default Generator. If ctx ends first, the puzzles finished
+// so far are returned with ctx's error.
+func GenerateN(ctx context.Context, n int, d Difficulty, opts ...GenerateOption) ([]Puzzle, error) {
+	cfg := generateNConfig{workers: runtime.GOMAXPROCS(0), attempts: 3}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	if cfg.workers < 1 {
+		cfg.workers = 1
+	}
+	if !cfg.seeded {
+		defaultMu.Lock()
+		cfg.seed = defaultGenerator().rng.Uint64()
+		defaultMu.Unlock()
+	}
+	seeds := NewGenerator(cfg.seed)
+	out := make([]Puzzle, 0, max(n, 0))
+	seen := make(map[string]bool, max(n, 0))
+	for len(out) < n {
+		// generate one round of the missing count, then merge in seed order so the
+		// result does not depend on which worker finished first
+		round := make([]uint64, n-len(out))
+		for i := range round {
+			round[i] = seeds.rng.Uint64()
+		}
+		puzzles, errs := generateRound(ctx, round, d, cfg, len(out), n)
+		for i := range round {
+			if errs[i] != nil {
+				if err := ctx.Err(); err != nil {
+					return out, err
+				}
+				return out, errs[i]
+			}
+			if !seen[puzzles[i].ID] {
+				seen[puzzles[i].ID] = true
+				out = append(out, puzzles[i])
+			}
+		}
+	}
+	return out, nil
+}
+
+// generateRound builds one puzzle per seed on cfg.workers goroutines.
+// Progress is reported as done+finished out of total.
+func generateRound(ctx context.Context, seeds []uint64, d Difficulty, cfg generateNConfig, done, total int) ([]Puzzle, []error) {
+	puzzles := make([]Puzzle, len(seeds))
+	errs := make([]error, len(seeds))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for w := 0; w < min(cfg.workers, len(seeds)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					continue
+				}
+				puzzles[i], errs[i] = generatePuzzle(seeds[i], d, cfg.attempts, cfg.symmetry)
+				if cfg.progress != nil && errs[i] == nil {
+					mu.Lock()
+					done++
+					cfg.progress(done, total)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range seeds {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return puzzles, errs
+}
@@ -0,0 +1,60 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGenerateNDistinctAndReproducible(t *testing.T) {
+	ctx := context.Background()
+	a, err := GenerateN(ctx, 6, Easy, WithSeed(3), WithWorkers(4))
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if len(a) != 6 {
+		t.Fatalf("got %d puzzles", len(a))
+	}
+	ids := map[string]bool{}
+	for _, p := range a {
+		if ids[p.ID] {
+			t.Fatalf("duplicate canonical id %s", p.ID)
+		}
+		ids[p.ID] = true
+		if p.Difficulty != Easy || CountSolutions(p.Givens, 2) != 1 {
+			t.Fatalf("bad puzzle %+v", p)
+		}
+	}
+	b, _ := GenerateN(ctx, 6, Easy, WithSeed(3), WithWorkers(1))
+	for i := range a {
+		if a[i].Givens != b[i].Givens {
+			t.Fatalf("puzzle %d depends on the worker count", i)
+		}
+	}
+}
+
+func TestGenerateNCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	out, err := GenerateN(ctx, 3, Easy)
+	if !errors.Is(err, context.Canceled) || len(out) != 0 {
+		t.Fatalf("want no puzzles and context.Canceled, got %d, %v", len(out), err)
+	}
+}
+
+func TestGenerateNProgress(t *testing.T) {
+	var calls, last int
+	_, err := GenerateN(context.Background(), 4, Easy, WithSeed(5), WithWorkers(2), WithProgress(func(done, total int) {
+		calls++
+		if total != 4 || done < 1 || done > total {
+			t.Errorf("progress %d/%d", done, total)
+		}
+		last = done
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls < 4 || last != 4 {
+		t.Fatalf("%d progress calls, last %d", calls, last)
+	}
+}
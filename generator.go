@@ -0,0 +1,81 @@
+package sudoku
+
+import (
+	"math/rand/v2"
+	"sync"
+)
+
+// Generator produces puzzles and solutions from its own random source, so its
+// output depends only on its seed. A Generator is not safe for concurrent use;
+// give each goroutine its own. The package-level functions share a lazily
+// created default Generator guarded by a mutex.
+type Generator struct {
+	rng *rand.Rand
+}
+
+// NewGenerator returns a Generator seeded for reproducible output.
+func NewGenerator(seed uint64) *Generator {
+	return &Generator{rng: rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))}
+}
+
+// Generate creates a 9x9 puzzle with a unique solution (see Generate).
+func (gen *Generator) Generate(d Difficulty, attempts int) (Board, error) {
+	return generateBoard(gen.rng, d, attempts, SymmetryNone, nil)
+}
+
+// GenerateSymmetric creates a 9x9 puzzle with symmetric givens (see
+// GenerateSymmetric).
+func (gen *Generator) GenerateSymmetric(d Difficulty, attempts int, sym Symmetry) (Board, error) {
+	return generateBoard(gen.rng, d, attempts, sym, nil)
+}
+
+// Solve solves b, picking among multiple solutions with the Generator's source.
+func (gen *Generator) Solve(b Board) (Board, bool) { return solveBoard(b, gen.rng) }
+
+// GenerateGrid creates a puzzle with the dimensions (and regions) of g.
+func (gen *Generator) GenerateGrid(g Grid, d Difficulty, attempts int) (Grid, error) {
+	return g.generate(gen.rng, d, attempts)
+}
+
+// GenerateGridWithSolution is GenerateGrid that also returns the solution the
+// puzzle was carved from, so callers need not solve it again; on 16x16 and
+// larger grids that solve can cost more than the generation.
+func (gen *Generator) GenerateGridWithSolution(g Grid, d Difficulty, attempts int) (puzzle, solution Grid, err error) {
+	puzzle, err = g.generateIn(gen.rng, d, attempts, genOptions{profile: DefaultProfile(g.Size), solution: &solution})
+	return puzzle, solution, err
+}
+
+// SolveGrid solves g, picking among multiple solutions with the Generator's source.
+func (gen *Generator) SolveGrid(g Grid) (Grid, bool) { return g.solve(gen.rng) }
+
+// GenerateKiller creates a killer puzzle with the dimensions of g (see GenerateKiller).
+func (gen *Generator) GenerateKiller(g Grid, d Difficulty, attempts int) (KillerGrid, error) {
+	return generateKiller(gen.rng, g, d, attempts)
+}
+
+// RandomJigsawGrid returns an empty jigsaw grid with a solvable random layout.
+func (gen *Generator) RandomJigsawGrid(size int) (Grid, error) {
+	return newRandomJigsawGrid(gen.rng, size)
+}
+
+var (
+	defaultMu  sync.Mutex
+	defaultGen *Generator // created on first use; replaced by SetRandSeed
+)
+
+// defaultGenerator returns the shared Generator; callers must hold defaultMu.
+func defaultGenerator() *Generator {
+	if defaultGen == nil {
+		defaultGen = NewGenerator(rand.Uint64())
+	}
+	return defaultGen
+}
+
+// forkDefaultRand returns a private source seeded from the default Generator, so
+// solvers can run without holding defaultMu for the whole search.
+func forkDefaultRand() *rand.Rand {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	seed := defaultGenerator().rng.Uint64()
+	return rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+}
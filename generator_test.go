@@ -0,0 +1,88 @@
+package sudoku
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestGeneratorReproducible(t *testing.T) {
+	a, err := NewGenerator(99).Generate(Medium, 1)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	b, _ := NewGenerator(99).Generate(Medium, 1)
+	if a != b {
+		t.Fatalf("same seed produced different puzzles:\n%s\n%s", a, b)
+	}
+	// SetRandSeed makes the package-level function match an explicit generator
+	SetRandSeed(99)
+	if c, _ := Generate(Medium, 1); c != a {
+		t.Fatalf("default generator diverged from NewGenerator with the same seed")
+	}
+}
+
+func TestGenerateGridWithSolution(t *testing.T) {
+	g, _ := NewGrid(16, 4, 4)
+	puz, sol, err := NewGenerator(7).GenerateGridWithSolution(g, Hard, 1)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if !sol.IsSolved() {
+		t.Fatalf("solution not solved")
+	}
+	for r := range puz.Cells {
+		for c, v := range puz.Cells[r] {
+			if v != 0 && sol.Cells[r][c] != v {
+				t.Fatalf("solution disagrees with given at %d,%d", r, c)
+			}
+		}
+	}
+	// same seed, same puzzle as GenerateGrid
+	if plain, _ := NewGenerator(7).GenerateGrid(g, Hard, 1); plain.String() != puz.String() {
+		t.Fatalf("GenerateGridWithSolution diverged from GenerateGrid")
+	}
+}
+
+func TestPackageFunctionsConcurrent(t *testing.T) {
+	g, _ := NewGrid(4, 2, 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b, err := Generate(Easy, 1)
+			if err != nil {
+				t.Errorf("generate: %v", err)
+				return
+			}
+			if _, ok := Solve(b); !ok {
+				t.Errorf("solve failed")
+			}
+			if _, err := g.Generate(Easy, 3); err != nil {
+				t.Errorf("grid generate: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestGenerateSymmetric(t *testing.T) {
+	for _, sym := range []Symmetry{SymmetryRotational, SymmetryMirror, SymmetryDiagonal, SymmetryRotational | SymmetryQuarterTurn} {
+		b, err := NewGenerator(11).GenerateSymmetric(Medium, 3, sym)
+		if err != nil {
+			t.Fatalf("symmetry %d: %v", sym, err)
+		}
+		if SymmetryOf(b)&sym != sym {
+			t.Fatalf("symmetry %d missing from %s", sym, b)
+		}
+		if CountSolutions(b, 2) != 1 {
+			t.Fatalf("symmetry %d: puzzle not unique", sym)
+		}
+	}
+	// SymmetryNone is plain generation
+	a, _ := NewGenerator(5).Generate(Easy, 1)
+	b, _ := NewGenerator(5).GenerateSymmetric(Easy, 1, SymmetryNone)
+	if a != b {
+		t.Fatal("SymmetryNone differs from Generate")
+	}
+}
@@ -1,8 +1,10 @@
 package sudoku
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math/rand/v2"
 )
 
 // Maximum allowed grid size to prevent excessive memory usage.
@@ -10,11 +12,15 @@ const MaxGridSize = 25
 
 // Grid is a generalised Sudoku grid of size SxS with sub-boxes boxRows x boxCols,
 // where S == boxRows*boxCols. Values are in [0..S], 0 meaning empty.
+// Jigsaw grids replace the boxes with irregular Regions (see NewJigsawGrid).
+// Constraints adds variant rules (see Constraint) on top of the standard ones.
 type Grid struct {
-	Size    int
-	BoxRows int
-	BoxCols int
-	Cells   [][]int // length Size, each length Size
+	Size        int
+	BoxRows     int
+	BoxCols     int
+	Cells       [][]int      // length Size, each length Size
+	Regions     [][]int      // optional region id per cell; nil means BoxRows x BoxCols boxes
+	Constraints []Constraint // optional extra rules, e.g. DiagonalConstraint
 }
 
 // NewGrid creates an empty grid with given dimensions.
@@ -32,86 +38,147 @@ func NewGrid(size, boxRows, boxCols int) (Grid, error) {
 	return g, nil
 }
 
+// ToGrid returns b as a classic 9x9 Grid with 3x3 boxes.
+func (b Board) ToGrid() Grid {
+	g, _ := NewGrid(9, 3, 3)
+	for r := range b {
+		copy(g.Cells[r], b[r][:])
+	}
+	return g
+}
+
+// ToBoard converts a classic grid back to a Board. It fails for other sizes and
+// for grids whose jigsaw regions or extra constraints a Board cannot express.
+func (g Grid) ToBoard() (Board, error) {
+	if g.Size != 9 || g.BoxRows != 3 || g.BoxCols != 3 || len(g.Cells) != 9 {
+		return Board{}, fmt.Errorf("grid is %dx%d with %dx%d boxes, want 9x9 with 3x3 boxes", g.Size, g.Size, g.BoxRows, g.BoxCols)
+	}
+	if g.Regions != nil || len(g.Constraints) > 0 {
+		return Board{}, errors.New("grid has regions or constraints a Board cannot hold")
+	}
+	var b Board
+	for r, row := range g.Cells {
+		if len(row) != 9 {
+			return Board{}, fmt.Errorf("grid row %d has %d cells, want 9", r, len(row))
+		}
+		copy(b[r][:], row)
+	}
+	return b, nil
+}
+
 // Clone returns a deep copy of the grid.
 func (g Grid) Clone() Grid {
-	out, _ := NewGrid(g.Size, g.BoxRows, g.BoxCols)
+	out := Grid{Size: g.Size, BoxRows: g.BoxRows, BoxCols: g.BoxCols, Cells: make([][]int, g.Size)}
 	for r := 0; r < g.Size; r++ {
+		out.Cells[r] = make([]int, g.Size)
 		copy(out.Cells[r], g.Cells[r])
 	}
+	if g.Regions != nil {
+		out.Regions = make([][]int, g.Size)
+		for r := 0; r < g.Size; r++ {
+			out.Regions[r] = append([]int(nil), g.Regions[r]...)
+		}
+	}
+	if g.Constraints != nil {
+		out.Constraints = append([]Constraint(nil), g.Constraints...)
+	}
 	return out
 }
 
-// Validate checks that values are in [0..Size] and no row/col/box duplicates (ignoring zeros).
+// regionOf returns the box or jigsaw region index of cell r,c.
+func (g Grid) regionOf(r, c int) int {
+	if g.Regions != nil {
+		return g.Regions[r][c]
+	}
+	return (r/g.BoxRows)*(g.Size/g.BoxCols) + c/g.BoxCols
+}
+
+// Validate checks that values are in [0..Size] and that the filled cells satisfy
+// the standard rules and every extra constraint (ignoring zeros).
 func (g Grid) Validate() error {
 	s := g.Size
-	// rows and cols
-	for i := 0; i < s; i++ {
-		row := make([]bool, s+1)
-		col := make([]bool, s+1)
-		for j := 0; j < s; j++ {
-			rv := g.Cells[i][j]
-			cv := g.Cells[j][i]
-			if rv < 0 || rv > s || cv < 0 || cv > s {
-				return ErrInvalidBoard
-			}
-			if rv != 0 {
-				if row[rv] {
-					return ErrInvalidBoard
-				}
-				row[rv] = true
-			}
-			if cv != 0 {
-				if col[cv] {
-					return ErrInvalidBoard
-				}
-				col[cv] = true
-			}
+	if g.Regions != nil {
+		if err := validateRegions(g.Regions); err != nil || len(g.Regions) != s {
+			return ErrInvalidBoard
 		}
 	}
-	// boxes
-	for br := 0; br < s; br += g.BoxRows {
-		for bc := 0; bc < s; bc += g.BoxCols {
-			seen := make([]bool, s+1)
-			for r := br; r < br+g.BoxRows; r++ {
-				for c := bc; c < bc+g.BoxCols; c++ {
-					v := g.Cells[r][c]
-					if v != 0 {
-						if seen[v] {
-							return ErrInvalidBoard
-						}
-						seen[v] = true
-					}
-				}
+	for r := 0; r < s; r++ {
+		for c := 0; c < s; c++ {
+			if v := g.Cells[r][c]; v < 0 || v > s {
+				return &RangeError{Cell{r, c}, v}
 			}
 		}
 	}
+	for _, con := range append(StandardConstraints(), g.Constraints...) {
+		if err := con.Validate(&g); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // Solve tries to solve the grid using backtracking. Returns solved grid and ok.
-func (g Grid) Solve() (Grid, bool) {
+// Grids above 9x9 use the most-constrained-cell search, where trying the first
+// empty cell can take hours. It is safe for concurrent use.
+func (g Grid) Solve() (Grid, bool) { return g.solve(forkDefaultRand()) }
+
+// SolveContext solves g with the most-constrained-cell search, checking ctx
+// every 1024 nodes, so callers can bound the work on large or hostile grids
+// that Solve may take hours on. It returns ctx's error when ctx ends first,
+// ErrInvalidBoard (or the failing constraint's error) for invalid grids and
+// ErrUnsolvable when there is no solution.
+func (g Grid) SolveContext(ctx context.Context) (Grid, error) {
+	if err := g.Validate(); err != nil {
+		return Grid{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return Grid{}, err
+	}
+	ks := newMaskSolver(g)
+	ks.ctx = ctx
+	var sol Grid
+	ks.search(func(w Grid) bool {
+		sol = w.Clone()
+		return true
+	})
+	switch {
+	case sol.Cells != nil:
+		return sol, nil
+	case ks.stopped:
+		return Grid{}, ctx.Err()
+	}
+	return Grid{}, ErrUnsolvable
+}
+
+func (g Grid) solve(rng *rand.Rand) (Grid, bool) {
 	work := g.Clone()
-	if !g.backtrack(&work) {
+	fill := g.backtrack
+	if g.useMaskSolver() || g.Size > 9 {
+		fill = g.maskFill
+	}
+	if !fill(&work, rng) {
 		return Grid{}, false
 	}
 	return work, true
 }
 
-func (g Grid) backtrack(w *Grid) bool {
+func (g Grid) backtrack(w *Grid, rng *rand.Rand) bool {
 	r, c, ok := g.findEmpty(w)
 	if !ok {
 		return true
 	}
-	// try values 1..Size shuffled for variety
+	// try values 1..Size shuffled for variety (in order when rng is nil)
 	vals := make([]int, g.Size)
 	for i := 0; i < g.Size; i++ {
 		vals[i] = i + 1
 	}
-	globalRand.Shuffle(len(vals), func(i, j int) { vals[i], vals[j] = vals[j], vals[i] })
+	if rng != nil {
+		rng.Shuffle(len(vals), func(i, j int) { vals[i], vals[j] = vals[j], vals[i] })
+	}
 	for _, v := range vals {
 		if g.isSafe(*w, r, c, v) {
 			w.Cells[r][c] = v
-			if g.backtrack(w) {
+			if g.backtrack(w, rng) {
 				return true
 			}
 			w.Cells[r][c] = 0
@@ -131,42 +198,74 @@ func (g Grid) findEmpty(w *Grid) (int, int, bool) {
 	return 0, 0, false
 }
 
-func (g Grid) isSafe(w Grid, r, c, v int) bool {
-	for i := 0; i < g.Size; i++ {
-		if w.Cells[r][i] == v || w.Cells[i][c] == v {
-			return false
-		}
-	}
-	br := (r / g.BoxRows) * g.BoxRows
-	bc := (c / g.BoxCols) * g.BoxCols
-	for i := 0; i < g.BoxRows; i++ {
-		for j := 0; j < g.BoxCols; j++ {
-			if w.Cells[br+i][bc+j] == v {
-				return false
-			}
-		}
-	}
-	return true
-}
+func (g Grid) isSafe(w Grid, r, c, v int) bool { return g.allows(&w, r, c, v) }
+
+// useMaskSolver reports whether g needs the most-constrained-cell search: irregular
+// regions and extra constraints make the plain first-empty backtracking too slow.
+func (g Grid) useMaskSolver() bool { return g.Regions != nil || len(g.Constraints) > 0 }
 
 // Generate creates a puzzle with a unique solution.
+// Concurrent calls are safe, as for the package-level Generate.
 func (g Grid) Generate(d Difficulty, attempts int) (Grid, error) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return defaultGenerator().GenerateGrid(g, d, attempts)
+}
+
+func (g Grid) generate(rng *rand.Rand, d Difficulty, attempts int) (Grid, error) {
+	return g.generateIn(rng, d, attempts, genOptions{profile: DefaultProfile(g.Size)})
+}
+
+// genOptions tune one generateIn call.
+type genOptions struct {
+	arena    *gridArena // scratch grids; nil means the heap
+	profile  SolverProfile
+	progress func(Progress) // optional
+	solution *Grid          // optional; receives the grid the puzzle was carved from
+}
+
+// generateIn is generate with the search tuned by o. With an arena the returned
+// puzzle lives in it too, so callers must copy it before o.arena.Reset.
+func (g Grid) generateIn(rng *rand.Rand, d Difficulty, attempts int, o genOptions) (Grid, error) {
 	if attempts < 1 {
 		attempts = 1
 	}
+	clone := Grid.Clone
+	a, p := o.arena, o.profile
+	fill, unique := g.profileFill(p), g.profileUnique(p)
+	if a != nil && p.Propagation == PropagateNone && !g.useMaskSolver() {
+		clone = a.clone
+		work := a.clone(g)
+		unique = func(w Grid, limit int) bool {
+			for r := range w.Cells {
+				copy(work.Cells[r], w.Cells[r])
+			}
+			return g.countIn(&work, limit) == 1
+		}
+	}
+	var pr Progress
+	report := func() {
+		if o.progress != nil {
+			o.progress(pr)
+		}
+	}
 	var lastErr error
 	for try := 0; try < attempts; try++ {
-		solved := g.Clone()
-		solved.fillDiagonalBoxes()
-		if !g.backtrack(&solved) {
+		pr = Progress{Attempt: try + 1, Attempts: attempts, Target: g.cluesFor(d), Checks: pr.Checks}
+		solved := clone(g)
+		if g.Constraints == nil { // extra rules may span the prefilled boxes
+			solved.fillDiagonalBoxes(rng)
+		}
+		if !fill(&solved, rng) {
 			lastErr = errors.New("failed to build solved grid")
 			continue
 		}
-		target := g.cluesFor(d)
-		puzzle := solved.Clone()
-		rmOrder := globalRand.Perm(g.Size * g.Size)
+		pr.Clues = g.Size * g.Size
+		report()
+		puzzle := clone(solved)
+		rmOrder := rng.Perm(g.Size * g.Size)
 		for _, idx := range rmOrder {
-			if g.countClues(puzzle) <= target {
+			if pr.Clues <= pr.Target {
 				break
 			}
 			r := idx / g.Size
@@ -176,11 +275,19 @@ func (g Grid) Generate(d Difficulty, attempts int) (Grid, error) {
 				continue
 			}
 			puzzle.Cells[r][c] = 0
-			if !g.hasUniqueSolution(puzzle, 2) {
+			pr.Checks++
+			if unique(puzzle, 2) {
+				pr.Removed++
+				pr.Clues--
+			} else {
 				puzzle.Cells[r][c] = old
 			}
+			report()
 		}
-		if g.hasUniqueSolution(puzzle, 2) {
+		if unique(puzzle, 2) {
+			if o.solution != nil {
+				*o.solution = solved.Clone()
+			}
 			return puzzle, nil
 		}
 		lastErr = errors.New("puzzle uniqueness not achieved")
@@ -220,10 +327,25 @@ func (g Grid) countClues(w Grid) int {
 	return cnt
 }
 
+// CountSolutions returns the number of solutions of g (regions included), stopping
+// once limit are found. Invalid grids have none.
+func (g Grid) CountSolutions(limit int) int {
+	if g.Validate() != nil {
+		return 0
+	}
+	sols, _ := KillerGrid{Grid: g}.solutions(limit, 0)
+	return len(sols)
+}
+
 // hasUniqueSolution returns true if there is exactly one solution, with early stop at limit.
 func (g Grid) hasUniqueSolution(w Grid, limit int) bool {
-	count := 0
 	work := w.Clone()
+	return g.countIn(&work, limit) == 1
+}
+
+// countIn counts solutions up to limit, searching in place on work.
+func (g Grid) countIn(work *Grid, limit int) int {
+	count := 0
 	var dfs func(*Grid) bool
 	dfs = func(cur *Grid) bool {
 		r, c, ok := g.findEmpty(cur)
@@ -242,11 +364,25 @@ func (g Grid) hasUniqueSolution(w Grid, limit int) bool {
 		}
 		return false
 	}
-	dfs(&work)
-	return count == 1
+	dfs(work)
+	return count
 }
 
-func (g *Grid) fillDiagonalBoxes() {
+func (g *Grid) fillDiagonalBoxes(rng *rand.Rand) {
+	if g.Regions != nil {
+		// Jigsaw regions share rows and columns; seeding one region is always safe.
+		vals := rng.Perm(g.Size)
+		idx := 0
+		for r := 0; r < g.Size; r++ {
+			for c := 0; c < g.Size; c++ {
+				if g.Regions[r][c] == 0 {
+					g.Cells[r][c] = vals[idx] + 1
+					idx++
+				}
+			}
+		}
+		return
+	}
 	// For rectangular boxes, step across the diagonal in box coordinates.
 	// Number of box rows and cols:
 	nRowBoxes := g.Size / g.BoxRows
@@ -258,12 +394,12 @@ func (g *Grid) fillDiagonalBoxes() {
 	for i := 0; i < steps; i++ {
 		br := i * g.BoxRows
 		bc := i * g.BoxCols
-		g.fillBox(br, bc)
+		g.fillBox(br, bc, rng)
 	}
 }
 
-func (g *Grid) fillBox(br, bc int) {
-	vals := globalRand.Perm(g.Size)
+func (g *Grid) fillBox(br, bc int, rng *rand.Rand) {
+	vals := rng.Perm(g.Size)
 	idx := 0
 	for r := 0; r < g.BoxRows; r++ {
 		for c := 0; c < g.BoxCols; c++ {
@@ -273,8 +409,13 @@ func (g *Grid) fillBox(br, bc int) {
 	}
 }
 
-// FromStringN parses a size*size characters string into a Grid.
-// Digits 1-9 are values; 0 or '.' are empty. Supports sizes up to 9.
+// GridAlphabet maps cell values to characters: the character at index v encodes
+// value v, so 1-9 are digits and 10-25 are the letters A-P (base 36). '0' and '.'
+// both mean empty; letters are parsed case-insensitively.
+const GridAlphabet = "0123456789ABCDEFGHIJKLMNOP"
+
+// FromStringN parses a size*size characters string into a Grid using GridAlphabet,
+// so 16x16 and 25x25 grids round-trip with Grid.String.
 func FromStringN(s string, size, boxRows, boxCols int) (Grid, error) {
 	if size != boxRows*boxCols {
 		return Grid{}, fmt.Errorf("invalid dims: size=%d boxRows=%d boxCols=%d", size, boxRows, boxCols)
@@ -283,23 +424,19 @@ func FromStringN(s string, size, boxRows, boxCols int) (Grid, error) {
 	if len(s) != expected {
 		return Grid{}, fmt.Errorf("input must be %d characters", expected)
 	}
-	g, _ := NewGrid(size, boxRows, boxCols)
+	g, err := NewGrid(size, boxRows, boxCols)
+	if err != nil {
+		return Grid{}, err
+	}
 	for i := 0; i < expected; i++ {
-		ch := s[i]
-		r := i / size
-		c := i % size
-		switch ch {
-		case '1', '2', '3', '4', '5', '6', '7', '8', '9':
-			v := int(ch - '0')
-			if v > size {
-				return Grid{}, errors.New("digit exceeds grid size")
-			}
-			g.Cells[r][c] = v
-		case '0', '.':
-			g.Cells[r][c] = 0
-		default:
+		v, ok := gridValue(s[i])
+		if !ok {
 			return Grid{}, errors.New("invalid character in grid")
 		}
+		if v > size {
+			return Grid{}, errors.New("digit exceeds grid size")
+		}
+		g.Cells[i/size][i%size] = v
 	}
 	if err := g.Validate(); err != nil {
 		return Grid{}, err
@@ -307,16 +444,51 @@ func FromStringN(s string, size, boxRows, boxCols int) (Grid, error) {
 	return g, nil
 }
 
-// String returns the compact representation of a Grid (size*size runes, 0 for empty).
+// FromRowsN builds a Grid from len(rows) rows of len(rows) values with
+// boxRows x boxCols boxes, rejecting ragged rows and out-of-range values and
+// validating the rules like FromStringN.
+func FromRowsN(rows [][]int, boxRows, boxCols int) (Grid, error) {
+	g, err := NewGrid(len(rows), boxRows, boxCols)
+	if err != nil {
+		return Grid{}, err
+	}
+	for r, row := range rows {
+		if len(row) != g.Size {
+			return Grid{}, fmt.Errorf("grid row %d has %d cells, want %d", r, len(row), g.Size)
+		}
+		copy(g.Cells[r], row)
+	}
+	if err := g.Validate(); err != nil {
+		return Grid{}, err
+	}
+	return g, nil
+}
+
+// gridValue decodes one GridAlphabet character ('.' is empty).
+func gridValue(ch byte) (int, bool) {
+	switch {
+	case ch == '.' || ch == '0':
+		return 0, true
+	case ch >= '1' && ch <= '9':
+		return int(ch - '0'), true
+	case ch >= 'A' && ch <= 'P':
+		return int(ch-'A') + 10, true
+	case ch >= 'a' && ch <= 'p':
+		return int(ch-'a') + 10, true
+	}
+	return 0, false
+}
+
+// String returns the compact representation of a Grid (size*size characters from
+// GridAlphabet, 0 for empty). Values outside GridAlphabet print as '?'.
 func (g Grid) String() string {
 	buf := make([]byte, 0, g.Size*g.Size)
 	for r := 0; r < g.Size; r++ {
 		for c := 0; c < g.Size; c++ {
-			v := g.Cells[r][c]
-			if v == 0 {
-				buf = append(buf, '0')
+			if v := g.Cells[r][c]; v >= 0 && v < len(GridAlphabet) {
+				buf = append(buf, GridAlphabet[v])
 			} else {
-				buf = append(buf, byte('0'+v))
+				buf = append(buf, '?')
 			}
 		}
 	}
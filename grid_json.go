@@ -0,0 +1,129 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// gridJSON is the wire form of a Grid.
+type gridJSON struct {
+	Size        int      `json:"size"`
+	BoxRows     int      `json:"boxRows"`
+	BoxCols     int      `json:"boxCols"`
+	Cells       [][]int  `json:"cells"`
+	Regions     [][]int  `json:"regions,omitempty"`
+	Constraints []string `json:"constraints,omitempty"`
+}
+
+// builtinConstraints are the built-in extra constraints in wire order.
+var builtinConstraints = []Constraint{DiagonalConstraint, AntiKnightConstraint, HyperConstraint}
+
+// constraintName returns the wire name of a built-in extra constraint. It
+// matches on the dynamic type, so custom constraints that are not comparable
+// (slice, map or func fields) are reported rather than panicking a map lookup.
+func constraintName(con Constraint) (string, bool) {
+	switch con.(type) {
+	case diagonalConstraint:
+		return "diagonal", true
+	case antiKnightConstraint:
+		return "anti-knight", true
+	case hyperConstraint:
+		return "hyper", true
+	}
+	return "", false
+}
+
+// MarshalJSON encodes g with its dimensions, regions and built-in extra
+// constraints. Custom constraints cannot be encoded and return an error.
+func (g Grid) MarshalJSON() ([]byte, error) {
+	out := gridJSON{Size: g.Size, BoxRows: g.BoxRows, BoxCols: g.BoxCols, Cells: g.Cells, Regions: g.Regions}
+	if out.Cells == nil {
+		out.Cells = [][]int{}
+	}
+	for _, con := range g.Constraints {
+		name, ok := constraintName(con)
+		if !ok {
+			return nil, fmt.Errorf("unsupported constraint %T: only built-in constraints can be encoded", con)
+		}
+		out.Constraints = append(out.Constraints, name)
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a Grid written by MarshalJSON and rejects inconsistent
+// dimensions, ragged rows and rule violations.
+func (g *Grid) UnmarshalJSON(data []byte) error {
+	var in gridJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	var out Grid
+	var err error
+	if in.Regions != nil {
+		if len(in.Regions) != in.Size {
+			return fmt.Errorf("regions have %d rows, want %d", len(in.Regions), in.Size)
+		}
+		out, err = NewJigsawGrid(in.Regions) // checks the region layout
+	} else {
+		out, err = NewGrid(in.Size, in.BoxRows, in.BoxCols)
+	}
+	if err != nil {
+		return err
+	}
+	if len(in.Cells) != in.Size {
+		return fmt.Errorf("grid has %d rows, want %d", len(in.Cells), in.Size)
+	}
+	for r, row := range in.Cells {
+		if len(row) != in.Size {
+			return fmt.Errorf("grid row %d has %d cells, want %d", r, len(row), in.Size)
+		}
+		copy(out.Cells[r], row)
+	}
+	for _, name := range in.Constraints {
+		con, ok := constraintByName(name)
+		if !ok {
+			return fmt.Errorf("unknown constraint %q", name)
+		}
+		out.Constraints = append(out.Constraints, con)
+	}
+	if err := out.Validate(); err != nil {
+		return err
+	}
+	*g = out
+	return nil
+}
+
+func constraintByName(name string) (Constraint, bool) {
+	for _, con := range builtinConstraints {
+		if n, _ := constraintName(con); n == name {
+			return con, true
+		}
+	}
+	return nil, false
+}
+
+// killerJSON is the wire form of a KillerGrid; without it the embedded Grid's
+// methods would drop the cages.
+type killerJSON struct {
+	Grid  Grid   `json:"grid"`
+	Cages []Cage `json:"cages"`
+}
+
+// MarshalJSON encodes k as its grid plus cages.
+func (k KillerGrid) MarshalJSON() ([]byte, error) {
+	return json.Marshal(killerJSON{Grid: k.Grid, Cages: k.Cages})
+}
+
+// UnmarshalJSON decodes a KillerGrid and checks the cage layout.
+func (k *KillerGrid) UnmarshalJSON(data []byte) error {
+	var in killerJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	out, err := NewKillerGrid(in.Grid, in.Cages)
+	if err != nil {
+		return err
+	}
+	*k = out
+	return nil
+}
@@ -0,0 +1,102 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGridJSONRoundTrip(t *testing.T) {
+	g, _ := FromStringN("156020000061500006642000000600000053", 6, 2, 3)
+	g.Constraints = []Constraint{DiagonalConstraint}
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"boxRows":2`) || !strings.Contains(string(data), `"constraints":["diagonal"]`) {
+		t.Fatalf("unexpected encoding %s", data)
+	}
+	var back Grid
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !gridEqual(g, back) || back.BoxCols != 3 || len(back.Constraints) != 1 || back.Constraints[0] != DiagonalConstraint {
+		t.Fatalf("round trip mismatch: %+v", back)
+	}
+
+	j := jigsawForTest(t)
+	data, _ = json.Marshal(j)
+	var jb Grid
+	if err := json.Unmarshal(data, &jb); err != nil || jb.Regions == nil || jb.regionOf(5, 5) != j.regionOf(5, 5) {
+		t.Fatalf("jigsaw round trip: %v", err)
+	}
+}
+
+func TestGridJSONRejects(t *testing.T) {
+	for name, in := range map[string]string{
+		"ragged":      `{"size":4,"boxRows":2,"boxCols":2,"cells":[[0,0,0,0],[0,0,0],[0,0,0,0],[0,0,0,0]]}`,
+		"rows":        `{"size":4,"boxRows":2,"boxCols":2,"cells":[[0,0,0,0]]}`,
+		"dims":        `{"size":4,"boxRows":3,"boxCols":2,"cells":[]}`,
+		"duplicate":   `{"size":4,"boxRows":2,"boxCols":2,"cells":[[1,1,0,0],[0,0,0,0],[0,0,0,0],[0,0,0,0]]}`,
+		"range":       `{"size":4,"boxRows":2,"boxCols":2,"cells":[[5,0,0,0],[0,0,0,0],[0,0,0,0],[0,0,0,0]]}`,
+		"constraint":  `{"size":4,"boxRows":2,"boxCols":2,"cells":[[0,0,0,0],[0,0,0,0],[0,0,0,0],[0,0,0,0]],"constraints":["queen"]}`,
+		"regionShape": `{"size":4,"boxRows":2,"boxCols":2,"cells":[[0,0,0,0],[0,0,0,0],[0,0,0,0],[0,0,0,0]],"regions":[[0]]}`,
+	} {
+		var g Grid
+		if err := json.Unmarshal([]byte(in), &g); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+type customConstraint struct{}
+
+func (customConstraint) Allows(*Grid, int, int, int) bool { return true }
+func (customConstraint) Validate(*Grid) error             { return nil }
+
+// cellsConstraint is not comparable, so it cannot be a map key.
+type cellsConstraint struct{ cells []Cell }
+
+func (cellsConstraint) Allows(*Grid, int, int, int) bool { return true }
+func (cellsConstraint) Validate(*Grid) error             { return nil }
+
+func TestGridJSONCustomConstraint(t *testing.T) {
+	g, _ := NewGrid(4, 2, 2)
+	for _, con := range []Constraint{customConstraint{}, cellsConstraint{cells: []Cell{{0, 0}}}} {
+		g.Constraints = []Constraint{con}
+		if _, err := json.Marshal(g); err == nil || !strings.Contains(err.Error(), "unsupported constraint") {
+			t.Fatalf("%T: got %v, want unsupported constraint", con, err)
+		}
+	}
+}
+
+func TestKillerJSONKeepsCages(t *testing.T) {
+	g, _ := NewGrid(4, 2, 2)
+	k, _ := NewKillerGrid(g, []Cage{{Sum: 3, Cells: []Cell{{0, 0}, {0, 1}}}})
+	data, err := json.Marshal(k)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var back KillerGrid
+	if err := json.Unmarshal(data, &back); err != nil || len(back.Cages) != 1 || back.Size != 4 {
+		t.Fatalf("round trip: %v %+v", err, back)
+	}
+}
+
+// jigsawForTest builds a fixed 6x6 jigsaw layout.
+func jigsawForTest(t *testing.T) Grid {
+	t.Helper()
+	ids := "221111200031200031223335444355444555"
+	regions := make([][]int, 6)
+	for r := range regions {
+		regions[r] = make([]int, 6)
+		for c := range regions[r] {
+			regions[r][c] = int(ids[r*6+c] - '0')
+		}
+	}
+	g, err := NewJigsawGrid(regions)
+	if err != nil {
+		t.Fatalf("jigsaw: %v", err)
+	}
+	return g
+}
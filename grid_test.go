@@ -1,6 +1,12 @@
 package sudoku
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestNewGridErrors(t *testing.T) {
 	if _, err := NewGrid(9, 2, 5); err == nil { // 2*5 != 9
@@ -31,6 +37,34 @@ func TestGridValidateAndSolve4x4(t *testing.T) {
 	}
 }
 
+func TestGridSolveContext(t *testing.T) {
+	g, _ := NewGrid(16, 4, 4)
+	sol, err := g.SolveContext(context.Background())
+	if err != nil || sol.Validate() != nil || g.countClues(sol) != 256 {
+		t.Fatalf("empty 16x16: %v", err)
+	}
+	// row 15 leaves 15 and 16 for its last two cells, and column 15 already
+	// has both: plain backtracking takes hours to find out
+	for c := 0; c < 14; c++ {
+		g.Cells[15][c] = c + 1
+	}
+	g.Cells[0][15], g.Cells[1][15] = 15, 16
+	start := time.Now()
+	if _, err := g.SolveContext(context.Background()); !errors.Is(err, ErrUnsolvable) || time.Since(start) > time.Second {
+		t.Fatalf("dead 16x16: %v after %v", err, time.Since(start))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	big, _ := NewGrid(25, 5, 5)
+	if _, err := big.SolveContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled: %v", err)
+	}
+	g.Cells[0][0] = 1 // conflicts with row 15 in column 0
+	if _, err := g.SolveContext(context.Background()); err == nil || errors.Is(err, ErrUnsolvable) {
+		t.Fatalf("invalid grid: %v", err)
+	}
+}
+
 func TestGridGenerateAndHint(t *testing.T) {
 	for _, cfg := range []struct{ size, br, bc int }{{4, 2, 2}, {6, 2, 3}, {9, 3, 3}} {
 		g, err := NewGrid(cfg.size, cfg.br, cfg.bc)
@@ -81,3 +115,84 @@ func TestFromStringN(t *testing.T) {
 		t.Fatalf("expected invalid char error")
 	}
 }
+
+func TestFromRowsN(t *testing.T) {
+	g, err := FromRowsN([][]int{{1, 0, 0, 0}, {0, 0, 1, 0}, {0, 1, 0, 0}, {0, 0, 0, 1}}, 2, 2)
+	if err != nil || g.Size != 4 || g.Cells[1][2] != 1 {
+		t.Fatalf("FromRowsN = %v, %v", g, err)
+	}
+	if _, err := FromRowsN([][]int{{1, 0, 0, 0}, {0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}}, 2, 2); err == nil {
+		t.Fatal("expected ragged row error")
+	}
+	if _, err := FromRowsN([][]int{{5, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}}, 2, 2); err == nil {
+		t.Fatal("expected range error")
+	}
+	if _, err := FromRowsN([][]int{{0, 0, 0, 0}}, 2, 2); err == nil {
+		t.Fatal("expected dimension error")
+	}
+}
+
+func TestGridCountSolutions(t *testing.T) {
+	g, _ := FromStringN("2040012004000234", 4, 2, 2)
+	if n := g.CountSolutions(2); n != 1 {
+		t.Fatalf("expected 1 solution, got %d", n)
+	}
+	empty, _ := NewGrid(4, 2, 2)
+	if n := empty.CountSolutions(2); n != 2 {
+		t.Fatalf("expected limit on empty grid, got %d", n)
+	}
+}
+
+func TestFromStringNLetters(t *testing.T) {
+	g, _ := NewGrid(16, 4, 4)
+	for r := 0; r < 16; r++ {
+		for c := 0; c < 16; c++ {
+			g.Cells[r][c] = (4*(r%4)+r/4+c)%16 + 1
+		}
+	}
+	s := g.String()
+	if s[:16] != "123456789ABCDEFG" {
+		t.Fatalf("unexpected encoding %q", s[:16])
+	}
+	back, err := FromStringN(strings.ToLower(s), 16, 4, 4)
+	if err != nil {
+		t.Fatalf("parse 16x16: %v", err)
+	}
+	if !gridEqual(g, back) {
+		t.Fatalf("round trip mismatch")
+	}
+	if _, err := FromStringN("G"+strings.Repeat(".", 80), 9, 3, 3); err == nil {
+		t.Fatalf("expected letter beyond size to fail")
+	}
+	if _, err := FromStringN("Q"+strings.Repeat(".", 624), 25, 5, 5); err == nil {
+		t.Fatalf("expected character outside the alphabet to fail")
+	}
+}
+
+func TestGridStringOutOfRange(t *testing.T) {
+	g, _ := NewGrid(4, 2, 2)
+	g.Cells[0][0], g.Cells[0][1], g.Cells[3][3] = -1, 3, 99
+	if s := g.String(); s != "?3"+strings.Repeat("0", 13)+"?" {
+		t.Fatalf("String = %q", s)
+	}
+}
+
+func TestBoardGridConversion(t *testing.T) {
+	b, _ := FromString(easyPuzzle)
+	g := b.ToGrid()
+	if g.Size != 9 || g.BoxRows != 3 || g.Cells[0][1] != 3 || g.String() != b.String() {
+		t.Fatalf("ToGrid = %v", g)
+	}
+	back, err := g.ToBoard()
+	if err != nil || back != b {
+		t.Fatalf("ToBoard = %v, %v", back, err)
+	}
+	small, _ := NewGrid(6, 2, 3)
+	if _, err := small.ToBoard(); err == nil {
+		t.Fatal("expected size error")
+	}
+	g.Constraints = []Constraint{DiagonalConstraint}
+	if _, err := g.ToBoard(); err == nil {
+		t.Fatal("expected constraint error")
+	}
+}
@@ -0,0 +1,132 @@
+// Package i18n holds the translated strings of the sudoku CLI; the GUI is not
+// translated.
+//
+// Bundles are flat JSON objects of message key to fmt format string, one file
+// per language in locales/. English is complete and is the fallback for keys a
+// bundle lacks, so a partial translation never prints a bare key.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.rumenx.com/sudoku"
+)
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// Printer formats messages in one language.
+type Printer struct {
+	lang     string
+	msgs     map[string]string
+	fallback map[string]string
+}
+
+var bundles = map[string]map[string]string{}
+
+func init() {
+	files, _ := fs.Glob(locales, "locales/*.json")
+	for _, f := range files {
+		data, err := locales.ReadFile(f)
+		if err != nil {
+			panic(err)
+		}
+		var msgs map[string]string
+		if err := json.Unmarshal(data, &msgs); err != nil {
+			panic(fmt.Sprintf("i18n: %s: %v", f, err))
+		}
+		bundles[strings.TrimSuffix(strings.TrimPrefix(f, "locales/"), ".json")] = msgs
+	}
+}
+
+// Languages lists the available language codes, sorted.
+func Languages() []string {
+	out := make([]string, 0, len(bundles))
+	for l := range bundles {
+		out = append(out, l)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// New returns a Printer for lang, a code like "de" or a tag such as "de-AT" or
+// "de_DE.UTF-8" (only the language part is used). An empty lang is English.
+func New(lang string) (*Printer, error) {
+	code := strings.ToLower(lang)
+	if i := strings.IndexAny(code, "-_."); i >= 0 {
+		code = code[:i]
+	}
+	if code == "" {
+		code = "en"
+	}
+	msgs, ok := bundles[code]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language %q (available: %s)", lang, strings.Join(Languages(), ", "))
+	}
+	return &Printer{lang: code, msgs: msgs, fallback: bundles["en"]}, nil
+}
+
+// Lang returns the language code of p.
+func (p *Printer) Lang() string { return p.lang }
+
+// Sprintf formats the message key with args. Keys missing from every bundle are
+// returned as is.
+func (p *Printer) Sprintf(key string, args ...any) string {
+	format, ok := p.msgs[key]
+	if !ok {
+		if format, ok = p.fallback[key]; !ok {
+			format = key
+		}
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Unit names a row, column or box, e.g. "row 3".
+func (p *Printer) Unit(u sudoku.Unit) string {
+	key := "unit.box"
+	switch u.Kind {
+	case sudoku.UnitRow:
+		key = "unit.row"
+	case sudoku.UnitColumn:
+		key = "unit.column"
+	}
+	return p.Sprintf(key, u.Index+1)
+}
+
+// Difficulty names d; unknown values are returned unchanged.
+func (p *Printer) Difficulty(d sudoku.Difficulty) string {
+	if _, ok := p.fallback["difficulty."+string(d)]; !ok {
+		return string(d)
+	}
+	return p.Sprintf("difficulty." + string(d))
+}
+
+// Explain renders the reasoning of a HintExplain step on a grid of size
+// digits; steps of techniques without a translation, and ExplainSolve steps
+// that rely on earlier eliminations, keep their English Text.
+func (p *Printer) Explain(st sudoku.Step, size int) string {
+	if st.FromCandidates {
+		return st.Text
+	}
+	switch st.Technique {
+	case sudoku.TechniqueHiddenSingle:
+		if st.Unit != nil {
+			return p.Sprintf("explain.hidden-single", st.Value, p.Unit(*st.Unit), st.Cell)
+		}
+	case sudoku.TechniqueNakedSingle:
+		var others []string
+		for d := 1; d <= size; d++ {
+			if d != st.Value {
+				others = append(others, strconv.Itoa(d))
+			}
+		}
+		return p.Sprintf("explain.naked-single", st.Cell, st.Value, strings.Join(others, ", "))
+	}
+	return st.Text
+}
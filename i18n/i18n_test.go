@@ -0,0 +1,108 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func TestBundlesComplete(t *testing.T) {
+	en := bundles["en"]
+	for _, lang := range Languages() {
+		msgs := bundles[lang]
+		for key, format := range en {
+			tr, ok := msgs[key]
+			if !ok {
+				t.Errorf("%s: missing %q", lang, key)
+				continue
+			}
+			// same verbs in the same argument slots
+			if verbs(tr) != verbs(format) {
+				t.Errorf("%s: %q uses %s, English uses %s", lang, key, verbs(tr), verbs(format))
+			}
+		}
+		for key := range msgs {
+			if _, ok := en[key]; !ok {
+				t.Errorf("%s: unknown key %q", lang, key)
+			}
+		}
+	}
+}
+
+// verbs summarises the fmt verbs of a format by argument index.
+func verbs(format string) string {
+	seen := map[int]byte{}
+	next := 1
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		if i < len(format) && format[i] == '%' {
+			continue
+		}
+		arg := next
+		if i < len(format) && format[i] == '[' {
+			end := strings.IndexByte(format[i:], ']')
+			fmt.Sscanf(format[i+1:i+end], "%d", &arg)
+			i += end + 1
+		}
+		if i < len(format) {
+			seen[arg] = format[i]
+		}
+		next = arg + 1
+	}
+	return fmt.Sprint(seen)
+}
+
+func TestNew(t *testing.T) {
+	for _, tag := range []string{"de", "DE-at", "de_DE.UTF-8"} {
+		p, err := New(tag)
+		if err != nil || p.Lang() != "de" {
+			t.Fatalf("New(%q) = %v, %v", tag, p, err)
+		}
+	}
+	if p, err := New(""); err != nil || p.Lang() != "en" {
+		t.Fatalf("New(\"\") = %v, %v", p, err)
+	}
+	if _, err := New("xx"); err == nil || !strings.Contains(err.Error(), "en") {
+		t.Fatalf("expected unsupported language error listing languages, got %v", err)
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	p, _ := New("de")
+	if got := p.Sprintf("hint", 1, 3, 4); got != "Tipp: Zeile 1, Spalte 3 = 4" {
+		t.Fatalf("hint = %q", got)
+	}
+	if got := p.Sprintf("no.such.key"); got != "no.such.key" {
+		t.Fatalf("unknown key = %q", got)
+	}
+	if got := p.Difficulty(sudoku.Hard); got != "schwer" {
+		t.Fatalf("difficulty = %q", got)
+	}
+
+	b, _ := sudoku.FromString(sudokutest.Easy)
+	st, ok := sudoku.HintExplain(b)
+	if !ok {
+		t.Fatal("no step")
+	}
+	en, _ := New("en")
+	if got := en.Explain(st, 9); got != st.Text {
+		t.Fatalf("English explanation %q differs from library text %q", got, st.Text)
+	}
+	naked := sudoku.Step{Technique: sudoku.TechniqueNakedSingle, Cell: sudoku.Cell{Row: 4, Col: 4}, Value: 5}
+	if got := p.Explain(naked, 9); got != "r5c5 kann nur 5 sein: Zeile, Spalte und Block enthalten schon 1, 2, 3, 4, 6, 7, 8, 9." {
+		t.Fatalf("naked single = %q", got)
+	}
+	if got := p.Explain(naked, 6); got != "r5c5 kann nur 5 sein: Zeile, Spalte und Block enthalten schon 1, 2, 3, 4, 6." {
+		t.Fatalf("6x6 naked single = %q", got)
+	}
+	naked.FromCandidates, naked.Text = true, "r5c5 can only be 5: the steps above removed its other candidates."
+	if got := p.Explain(naked, 9); got != naked.Text {
+		t.Fatalf("candidate-based step = %q, want its Text", got)
+	}
+}
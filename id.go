@@ -0,0 +1,83 @@
+package sudoku
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// idVersion is the first byte of every ID so the packing can change later
+// without breaking links already shared.
+const idVersion = 1
+
+// EncodeID packs b into a short URL-safe code for sharing links: a version
+// byte, an 81-bit map of the filled cells and their values four bits each,
+// encoded as unpadded base64url. A typical puzzle of 25 clues becomes a
+// 34-character code instead of the 81-character string. Values outside 0-9
+// fail with a *RangeError, since four bits would silently wrap them into a
+// different, valid-looking ID.
+func EncodeID(b Board) (string, error) {
+	var filled []int
+	buf := make([]byte, 12, 12+41)
+	buf[0] = idVersion
+	for i := 0; i < 81; i++ {
+		v := b[i/9][i%9]
+		if v < 0 || v > 9 {
+			return "", &RangeError{Cell{i / 9, i % 9}, v}
+		}
+		if v != 0 {
+			buf[1+i/8] |= 1 << (7 - i%8)
+			filled = append(filled, v)
+		}
+	}
+	for i := 0; i < len(filled); i += 2 {
+		x := byte(filled[i]) << 4
+		if i+1 < len(filled) {
+			x |= byte(filled[i+1])
+		}
+		buf = append(buf, x)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// DecodeID reverses EncodeID and validates the board like FromString.
+func DecodeID(id string) (Board, error) {
+	data, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return Board{}, fmt.Errorf("invalid puzzle id: %w", err)
+	}
+	if len(data) < 12 || data[0] != idVersion {
+		return Board{}, errors.New("invalid puzzle id: unknown format")
+	}
+	// the last map byte only uses its top bit
+	if data[11]&0x7f != 0 {
+		return Board{}, errors.New("invalid puzzle id: corrupt cell map")
+	}
+	var b Board
+	values := data[12:]
+	n := 0
+	for i := 0; i < 81; i++ {
+		if data[1+i/8]&(1<<(7-i%8)) == 0 {
+			continue
+		}
+		if n/2 >= len(values) {
+			return Board{}, errors.New("invalid puzzle id: truncated")
+		}
+		v := values[n/2] >> 4
+		if n%2 == 1 {
+			v = values[n/2] & 0x0f
+		}
+		if v < 1 || v > 9 {
+			return Board{}, fmt.Errorf("invalid puzzle id: value %d at %s", v, Cell{i / 9, i % 9})
+		}
+		b[i/9][i%9] = int(v)
+		n++
+	}
+	if len(values) != (n+1)/2 || (n%2 == 1 && values[n/2]&0x0f != 0) {
+		return Board{}, errors.New("invalid puzzle id: trailing data")
+	}
+	if err := Validate(b); err != nil {
+		return Board{}, err
+	}
+	return b, nil
+}
@@ -0,0 +1,72 @@
+package sudoku
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncodeDecodeID(t *testing.T) {
+	g := NewGenerator(7)
+	puzzle, err := g.Generate(Medium, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	solved, _ := g.Solve(puzzle)
+	for _, b := range []Board{puzzle, solved, {}} {
+		id := mustEncodeID(t, b)
+		if strings.ContainsAny(id, "+/=") {
+			t.Fatalf("id %q is not URL-safe", id)
+		}
+		back, err := DecodeID(id)
+		if err != nil || back != b {
+			t.Fatalf("round trip of %s via %q: %v", b, id, err)
+		}
+	}
+	clues := 81 - strings.Count(puzzle.String(), "0")
+	id := mustEncodeID(t, puzzle)
+	if want := base64.RawURLEncoding.EncodedLen(12 + (clues+1)/2); len(id) != want || want >= 81 {
+		t.Fatalf("id length %d for %d clues, want %d", len(id), clues, want)
+	}
+}
+
+func TestEncodeIDRange(t *testing.T) {
+	for _, v := range []int{-1, 10, 16, 17} {
+		var b Board
+		b[3][4] = v
+		var re *RangeError
+		if _, err := EncodeID(b); !errors.As(err, &re) || re.Cell != (Cell{3, 4}) || re.Value != v {
+			t.Fatalf("value %d: got %v", v, err)
+		}
+	}
+}
+
+func mustEncodeID(t *testing.T, b Board) string {
+	t.Helper()
+	id, err := EncodeID(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestDecodeIDErrors(t *testing.T) {
+	var b Board
+	b[0][0] = 5
+	good := mustEncodeID(t, b)
+	b[0][1] = 5
+	dup := mustEncodeID(t, b)
+	for name, id := range map[string]string{
+		"not base64": "!!!",
+		"short":      good[:8],
+		"version":    "B" + good[1:],
+		"truncated":  mustEncodeID(t, Board{{1, 2, 3}})[:base64.RawURLEncoding.EncodedLen(12)], // cell map only
+		"trailing":   good + "AA",
+		"duplicate":  dup,
+	} {
+		if _, err := DecodeID(id); err == nil {
+			t.Fatalf("%s: expected error for %q", name, id)
+		}
+	}
+}
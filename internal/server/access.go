@@ -0,0 +1,165 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Route groups for access control. Admin covers the metrics endpoints,
+// collection uploads and every DELETE; everything else is public. Health probes are never
+// restricted so load balancers keep working.
+const (
+	groupPublic = "public"
+	groupAdmin  = "admin"
+)
+
+var adminRoutes = map[string]bool{"/metrics": true, "/metrics/sla": true, "/collections": true}
+
+func routeGroup(r *http.Request) string {
+	if adminRoutes[r.URL.Path] || r.Method == http.MethodDelete {
+		return groupAdmin
+	}
+	return groupPublic
+}
+
+// accessPolicy restricts one route group: Allow lists the client networks
+// admitted (empty admits everyone) and MTLS requires a verified client
+// certificate.
+type accessPolicy struct {
+	Allow []netip.Prefix
+	MTLS  bool
+}
+
+func (p accessPolicy) admits(addr netip.Addr) bool {
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, pfx := range p.Allow {
+		if pfx.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// accessControl maps a route group to its policy.
+type accessControl map[string]accessPolicy
+
+// accessFromEnv reads SUDOKU_{ADMIN,PUBLIC}_ALLOW, comma-separated CIDRs or
+// bare IPs (e.g. "10.0.0.0/8,192.168.1.7"), and SUDOKU_{ADMIN,PUBLIC}_MTLS
+// (true requires a client certificate signed by SUDOKU_TLS_CLIENT_CA).
+func accessFromEnv(getenv func(string) string) (accessControl, error) {
+	ac := accessControl{}
+	for _, g := range []string{groupAdmin, groupPublic} {
+		var p accessPolicy
+		env := "SUDOKU_" + strings.ToUpper(g) + "_ALLOW"
+		if v := getenv(env); v != "" {
+			for _, s := range strings.Split(v, ",") {
+				pfx, err := parsePrefix(strings.TrimSpace(s))
+				if err != nil {
+					return nil, fmt.Errorf("%s: invalid entry %q", env, s)
+				}
+				p.Allow = append(p.Allow, pfx)
+			}
+		}
+		env = "SUDOKU_" + strings.ToUpper(g) + "_MTLS"
+		if v := getenv(env); v != "" {
+			var err error
+			if p.MTLS, err = strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("%s: invalid value %q", env, v)
+			}
+		}
+		ac[g] = p
+	}
+	return ac, nil
+}
+
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		pfx, err := netip.ParsePrefix(s)
+		return pfx.Masked(), err
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// needsMTLS reports whether any group requires client certificates.
+func (ac accessControl) needsMTLS() bool {
+	for _, p := range ac {
+		if p.MTLS {
+			return true
+		}
+	}
+	return false
+}
+
+// wrap enforces the policy of each request's route group. The client address
+// is the connection's peer; forwarding headers are not trusted.
+func (ac accessControl) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isProbe(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		p := ac[routeGroup(r)]
+		if len(p.Allow) > 0 {
+			ap, err := netip.ParseAddrPort(r.RemoteAddr)
+			if err != nil || !p.admits(ap.Addr().Unmap()) {
+				writeJSON(w, http.StatusForbidden, errMsg("address not allowed"))
+				return
+			}
+		}
+		if p.MTLS && (r.TLS == nil || len(r.TLS.VerifiedChains) == 0) {
+			writeJSON(w, http.StatusForbidden, errMsg("client certificate required"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tlsFromEnv builds the server TLS config from SUDOKU_TLS_CERT and
+// SUDOKU_TLS_KEY (PEM files; both unset serves plain HTTP) and
+// SUDOKU_TLS_CLIENT_CA, a PEM bundle of CAs accepted for client certificates.
+// Certificates are verified when presented; accessControl decides which
+// groups require one.
+func tlsFromEnv(getenv func(string) string, requireClientCA bool) (*tls.Config, error) {
+	certFile, keyFile := getenv("SUDOKU_TLS_CERT"), getenv("SUDOKU_TLS_KEY")
+	caFile := getenv("SUDOKU_TLS_CLIENT_CA")
+	if certFile == "" && keyFile == "" {
+		if caFile != "" || requireClientCA {
+			return nil, fmt.Errorf("client certificates need SUDOKU_TLS_CERT and SUDOKU_TLS_KEY")
+		}
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("SUDOKU_TLS_CERT/SUDOKU_TLS_KEY: %w", err)
+	}
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if caFile == "" {
+		if requireClientCA {
+			return nil, fmt.Errorf("SUDOKU_TLS_CLIENT_CA is required when a group sets _MTLS")
+		}
+		return cfg, nil
+	}
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("SUDOKU_TLS_CLIENT_CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("SUDOKU_TLS_CLIENT_CA: no certificates in %s", caFile)
+	}
+	cfg.ClientCAs, cfg.ClientAuth = pool, tls.VerifyClientCertIfGiven
+	return cfg, nil
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAccessAllowlist(t *testing.T) {
+	t.Setenv("SUDOKU_ADMIN_ALLOW", "10.0.0.0/8, 192.168.1.7, ::1")
+	ac, err := accessFromEnv(os.Getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := ac.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }))
+	for _, tc := range []struct {
+		path, remote string
+		want         int
+	}{
+		{"/metrics/sla", "10.1.2.3:5000", http.StatusNoContent},
+		{"/metrics/sla", "192.168.1.7:5000", http.StatusNoContent},
+		{"/metrics/sla", "[::1]:5000", http.StatusNoContent},
+		{"/collections", "192.168.1.8:5000", http.StatusForbidden},
+		{"/metrics/sla", "[::ffff:10.0.0.1]:5000", http.StatusNoContent},
+		{"/generate", "203.0.113.9:5000", http.StatusNoContent}, // public is open
+		{"/collections/abc/next", "203.0.113.9:5000", http.StatusNoContent},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		req.RemoteAddr = tc.remote
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s from %s: status %d, want %d", tc.path, tc.remote, rec.Code, tc.want)
+		}
+	}
+	req := httptest.NewRequest(http.MethodDelete, "/puzzles/abc", nil) // deletes are admin
+	req.RemoteAddr = "203.0.113.9:5000"
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("DELETE from outside: status %d", rec.Code)
+	}
+
+	t.Setenv("SUDOKU_PUBLIC_ALLOW", "10.0.0.0/33")
+	if _, err := accessFromEnv(os.Getenv); err == nil {
+		t.Fatal("expected invalid CIDR error")
+	}
+	t.Setenv("SUDOKU_PUBLIC_ALLOW", "")
+	t.Setenv("SUDOKU_ADMIN_MTLS", "maybe")
+	if _, err := accessFromEnv(os.Getenv); err == nil {
+		t.Fatal("expected invalid bool error")
+	}
+}
+
+func TestAccessHealthAlwaysOpen(t *testing.T) {
+	ac := accessControl{groupPublic: {Allow: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}, MTLS: true}}
+	h := ac.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req.RemoteAddr = "203.0.113.9:5000"
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("health probe blocked: %d", rec.Code)
+	}
+}
+
+func TestAccessMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := newTestCert(t, nil, nil, "test CA")
+	srv, srvKey := newTestCert(t, ca, caKey, "localhost")
+	client, clientKey := newTestCert(t, ca, caKey, "ops")
+	writePEM(t, filepath.Join(dir, "ca.pem"), "CERTIFICATE", ca.Raw)
+	writePEM(t, filepath.Join(dir, "srv.pem"), "CERTIFICATE", srv.Raw)
+	writeKey(t, filepath.Join(dir, "srv.key"), srvKey)
+
+	t.Setenv("SUDOKU_ADMIN_MTLS", "true")
+	ac, err := accessFromEnv(os.Getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tlsFromEnv(os.Getenv, ac.needsMTLS()); err == nil {
+		t.Fatal("expected error: mTLS without TLS")
+	}
+	t.Setenv("SUDOKU_TLS_CERT", filepath.Join(dir, "srv.pem"))
+	t.Setenv("SUDOKU_TLS_KEY", filepath.Join(dir, "srv.key"))
+	if _, err := tlsFromEnv(os.Getenv, ac.needsMTLS()); err == nil {
+		t.Fatal("expected error: mTLS without client CA")
+	}
+	t.Setenv("SUDOKU_TLS_CLIENT_CA", filepath.Join(dir, "ca.pem"))
+	cfg, err := tlsFromEnv(os.Getenv, ac.needsMTLS())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewUnstartedServer(ac.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+	ts.TLS = cfg
+	ts.StartTLS()
+	defer ts.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	get := func(path string, certs ...tls.Certificate) int {
+		c := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: certs}}}
+		res, err := c.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+	if code := get("/generate"); code != http.StatusNoContent {
+		t.Fatalf("public route without cert: %d", code)
+	}
+	if code := get("/metrics/sla"); code != http.StatusForbidden {
+		t.Fatalf("admin route without cert: %d", code)
+	}
+	cert := tls.Certificate{Certificate: [][]byte{client.Raw}, PrivateKey: clientKey}
+	if code := get("/metrics/sla", cert); code != http.StatusNoContent {
+		t.Fatalf("admin route with cert: %d", code)
+	}
+}
+
+func newTestCert(t *testing.T, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, cn string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{cn},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if parent == nil {
+		tmpl.IsCA, tmpl.BasicConstraintsValid = true, true
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeKey(t *testing.T, path string, key *ecdsa.PrivateKey) {
+	t.Helper()
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, path, "EC PRIVATE KEY", der)
+}
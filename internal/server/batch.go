@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	mrand "math/rand/v2"
+	"net/http"
+	"sync"
+
+	"go.rumenx.com/sudoku"
+)
+
+const (
+	maxBatchCount = 100 // puzzles per /generate/batch request
+	batchWorkers  = 4   // generators running at once per request
+)
+
+// handleGenerateBatch generates count puzzles in one request on a bounded
+// worker pool. It takes the /generate fields plus count; classic puzzles are
+// distinct up to isomorphism (see sudoku.GenerateN) and carry their canonical
+// fingerprint. Batch puzzles are not stored. Generation stops when the
+// client goes away.
+func handleGenerateBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, errMsg("method not allowed"))
+		return
+	}
+	var req struct {
+		Count           int    `json:"count"`
+		Difficulty      string `json:"difficulty"`
+		IncludeSolution bool   `json:"includeSolution"`
+		Size            int    `json:"size"`
+		Box             string `json:"box"`
+		Attempts        int    `json:"attempts"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errMsg("invalid json"))
+		return
+	}
+	if req.Count < 1 || req.Count > maxBatchCount {
+		writeJSON(w, http.StatusBadRequest, errMsg("count must be between 1 and 100"))
+		return
+	}
+	d, ok := parseDifficulty(req.Difficulty)
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, errMsg("invalid difficulty"))
+		return
+	}
+	if req.Attempts < 1 {
+		req.Attempts = 3
+	}
+	if req.Attempts > maxAttempts {
+		writeJSON(w, http.StatusBadRequest, errMsg("attempts must be at most 10"))
+		return
+	}
+	items := make([]map[string]any, 0, req.Count)
+	if req.Size == 0 && req.Box == "" {
+		puzzles, err := sudoku.GenerateN(r.Context(), req.Count, d, sudoku.WithWorkers(batchWorkers), sudoku.WithAttempts(req.Attempts))
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, errMsg("generation failed"))
+			return
+		}
+		for _, p := range puzzles {
+			item := map[string]any{"puzzle": p.Givens, "fingerprint": p.ID}
+			if req.IncludeSolution {
+				item["solution"] = p.Solution
+			}
+			items = append(items, item)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"count": len(items), "puzzles": items})
+		return
+	}
+	g, err := parseGrid(req.Size, req.Box)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errMsg(err.Error()))
+		return
+	}
+	grids, sols, err := generateGrids(r.Context(), g, d, req.Attempts, req.Count)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errMsg("generation failed"))
+		return
+	}
+	for i, puz := range grids {
+		item := map[string]any{"grid": puz}
+		if req.IncludeSolution {
+			item["solution"] = sols[i]
+		}
+		items = append(items, item)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"count": len(items), "puzzles": items})
+}
+
+// generateGrids builds n puzzles of shape g and their solutions on
+// batchWorkers goroutines, each with its own Generator so they do not contend
+// for the default one.
+func generateGrids(ctx context.Context, g sudoku.Grid, d sudoku.Difficulty, attempts, n int) ([]sudoku.Grid, []sudoku.Grid, error) {
+	out, sols := make([]sudoku.Grid, n), make([]sudoku.Grid, n)
+	errs := make([]error, n)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(batchWorkers, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			gen := sudoku.NewGenerator(mrand.Uint64())
+			for i := range jobs {
+				if errs[i] = ctx.Err(); errs[i] == nil {
+					out[i], sols[i], errs[i] = gen.GenerateGridWithSolution(g, d, attempts)
+				}
+			}
+		}()
+	}
+	for i := range n {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return out, sols, nil
+}
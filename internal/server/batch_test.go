@@ -0,0 +1,75 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.rumenx.com/sudoku"
+)
+
+func postBatch(t *testing.T, body map[string]any) (*http.Response, map[string]json.RawMessage) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	data, _ := json.Marshal(body)
+	handleGenerateBatch(rec, httptest.NewRequest(http.MethodPost, "/generate/batch", bytes.NewReader(data)))
+	var res map[string]json.RawMessage
+	json.Unmarshal(rec.Body.Bytes(), &res)
+	return rec.Result(), res
+}
+
+func TestGenerateBatch(t *testing.T) {
+	resp, res := postBatch(t, map[string]any{"count": 5, "difficulty": "easy", "includeSolution": true})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d: %s", resp.StatusCode, res["error"])
+	}
+	var puzzles []struct {
+		Puzzle   sudoku.Board `json:"puzzle"`
+		Solution sudoku.Board `json:"solution"`
+		ID       string       `json:"fingerprint"`
+	}
+	json.Unmarshal(res["puzzles"], &puzzles)
+	if len(puzzles) != 5 {
+		t.Fatalf("got %d puzzles", len(puzzles))
+	}
+	seen := map[string]bool{}
+	for _, p := range puzzles {
+		if sol, ok := sudoku.Solve(p.Puzzle); !ok || sol != p.Solution {
+			t.Fatalf("solution does not match puzzle %s", p.Puzzle)
+		}
+		if seen[p.ID] {
+			t.Fatalf("duplicate puzzle %s", p.ID)
+		}
+		seen[p.ID] = true
+	}
+}
+
+func TestGenerateBatch_Grid(t *testing.T) {
+	resp, res := postBatch(t, map[string]any{"count": 3, "size": 6, "box": "2x3"})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d: %s", resp.StatusCode, res["error"])
+	}
+	var puzzles []struct {
+		Grid sudoku.Grid `json:"grid"`
+	}
+	json.Unmarshal(res["puzzles"], &puzzles)
+	if len(puzzles) != 3 || puzzles[2].Grid.Size != 6 {
+		t.Fatalf("puzzles = %+v", puzzles)
+	}
+}
+
+func TestGenerateBatch_Errors(t *testing.T) {
+	for _, body := range []map[string]any{
+		{"count": 0},
+		{"count": maxBatchCount + 1},
+		{"count": 2, "difficulty": "impossible"},
+		{"count": 2, "size": 6, "box": "3x3"},
+		{"count": 2, "attempts": maxAttempts + 1},
+	} {
+		if resp, _ := postBatch(t, body); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%v: status = %d, want 400", body, resp.StatusCode)
+		}
+	}
+}
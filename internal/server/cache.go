@@ -0,0 +1,122 @@
+package server
+
+import (
+	"container/list"
+	"fmt"
+	mrand "math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"go.rumenx.com/sudoku"
+)
+
+const (
+	cacheKeys       = 64 // parameter sets cached, least recently used evicted
+	cachePerKey     = 8  // puzzles generated per parameter set before serving from cache
+	defaultCacheTTL = time.Minute
+)
+
+// cacheKey is a set of /generate parameters; classic puzzles have size 0.
+type cacheKey struct {
+	size int
+	box  string
+	d    sudoku.Difficulty
+}
+
+type cacheEntry struct {
+	key     cacheKey
+	puzzles []PuzzleRecord // oldest first
+}
+
+// puzzleCache keeps the puzzles /generate made recently per parameter set.
+// Once a set has cachePerKey live puzzles, requests get one of them at random
+// instead of a new one, so bursts cost little CPU but still see some variety.
+type puzzleCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration // zero disables the cache
+	now     func() time.Time
+	order   *list.List // of *cacheEntry, most recently used first
+	entries map[cacheKey]*list.Element
+}
+
+func newPuzzleCache(ttl time.Duration) *puzzleCache {
+	return &puzzleCache{ttl: ttl, now: time.Now, order: list.New(), entries: make(map[cacheKey]*list.Element)}
+}
+
+// cacheTTLFromEnv reads SUDOKU_CACHE_TTL, how long generated puzzles are
+// reused (default 1m; 0 disables the cache).
+func cacheTTLFromEnv(getenv func(string) string) (time.Duration, error) {
+	v := getenv("SUDOKU_CACHE_TTL")
+	if v == "" {
+		return defaultCacheTTL, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("SUDOKU_CACHE_TTL: invalid duration %q", v)
+	}
+	return d, nil
+}
+
+// get returns a cached puzzle for k once k has cachePerKey live ones.
+func (c *puzzleCache) get(k cacheKey) (PuzzleRecord, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[k]
+	if !ok {
+		return PuzzleRecord{}, false
+	}
+	c.order.MoveToFront(el)
+	e := el.Value.(*cacheEntry)
+	c.expire(e)
+	if len(e.puzzles) < cachePerKey {
+		return PuzzleRecord{}, false
+	}
+	return e.puzzles[mrand.IntN(len(e.puzzles))], true
+}
+
+// add caches p under k, dropping k's oldest puzzle when it has enough.
+func (c *puzzleCache) add(k cacheKey, p PuzzleRecord) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[k]
+	if !ok {
+		el = c.order.PushFront(&cacheEntry{key: k})
+		c.entries[k] = el
+		if c.order.Len() > cacheKeys {
+			last := c.order.Back()
+			c.order.Remove(last)
+			delete(c.entries, last.Value.(*cacheEntry).key)
+		}
+	}
+	c.order.MoveToFront(el)
+	e := el.Value.(*cacheEntry)
+	c.expire(e)
+	if len(e.puzzles) == cachePerKey {
+		e.puzzles = e.puzzles[1:]
+	}
+	e.puzzles = append(e.puzzles, p)
+}
+
+// drop removes the puzzle with the given id, once it is revealed or deleted.
+func (c *puzzleCache) drop(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, el := range c.entries {
+		e := el.Value.(*cacheEntry)
+		e.puzzles = slices.DeleteFunc(e.puzzles, func(p PuzzleRecord) bool { return p.ID == id })
+	}
+}
+
+// expire drops e's puzzles created more than ttl ago.
+func (c *puzzleCache) expire(e *cacheEntry) {
+	cutoff := c.now().Add(-c.ttl)
+	i := 0
+	for i < len(e.puzzles) && !e.puzzles[i].Created.After(cutoff) {
+		i++
+	}
+	e.puzzles = e.puzzles[i:]
+}
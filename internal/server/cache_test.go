@@ -0,0 +1,137 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.rumenx.com/sudoku"
+)
+
+func TestPuzzleCache(t *testing.T) {
+	c := newPuzzleCache(time.Minute)
+	now := time.Unix(1000, 0)
+	c.now = func() time.Time { return now }
+	k := cacheKey{0, "", sudoku.Easy}
+	ids := map[string]bool{}
+	for i := range cachePerKey {
+		if _, ok := c.get(k); ok {
+			t.Fatalf("hit with %d puzzles cached", i)
+		}
+		id := fmt.Sprint("p", i)
+		ids[id] = true
+		c.add(k, PuzzleRecord{ID: id, Created: now})
+	}
+	for range 20 {
+		if p, ok := c.get(k); !ok || !ids[p.ID] {
+			t.Fatalf("get = %v, %v", p.ID, ok)
+		}
+	}
+	// a dropped puzzle leaves the set short of cachePerKey
+	c.drop("p0")
+	if p, ok := c.get(k); ok {
+		t.Fatalf("hit after drop: %s", p.ID)
+	}
+	c.add(k, PuzzleRecord{ID: "p0", Created: now})
+	if _, ok := c.get(cacheKey{6, "2x3", sudoku.Easy}); ok {
+		t.Fatal("hit for other parameters")
+	}
+
+	// an old puzzle expires, and the set is short again
+	c.add(k, PuzzleRecord{ID: "new", Created: now.Add(30 * time.Second)})
+	now = now.Add(time.Minute)
+	if _, ok := c.get(k); ok {
+		t.Fatal("expired puzzles served")
+	}
+
+	// least recently used parameter sets are evicted
+	for i := range cacheKeys {
+		c.add(cacheKey{i + 1, "", sudoku.Hard}, PuzzleRecord{Created: now})
+	}
+	if _, ok := c.entries[k]; ok || len(c.entries) != cacheKeys {
+		t.Fatalf("kept %d sets, including the oldest", len(c.entries))
+	}
+
+	off := newPuzzleCache(0)
+	off.add(k, PuzzleRecord{Created: now})
+	if len(off.entries) != 0 {
+		t.Fatal("disabled cache stored a puzzle")
+	}
+}
+
+func TestGenerateServesCache(t *testing.T) {
+	h, a, err := newHandler(Options{Quiet: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(h)
+	t.Cleanup(ts.Close)
+
+	rec := testPuzzleRecord(t)
+	ids := map[string]bool{}
+	for i := range cachePerKey {
+		rec.ID, rec.Created = fmt.Sprint("cached", i), time.Now()
+		ids[rec.ID] = true
+		a.generated.add(cacheKey{0, "", sudoku.Easy}, rec)
+	}
+	generate := func(body string) (string, string) {
+		t.Helper()
+		resp, err := http.Post(ts.URL+"/generate", "application/json", bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var out struct {
+			ID       string  `json:"id"`
+			Solution [][]int `json:"solution"`
+		}
+		json.NewDecoder(resp.Body).Decode(&out)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: status %d", body, resp.StatusCode)
+		}
+		return out.ID, resp.Header.Get("X-Cache")
+	}
+
+	hit, cache := generate(`{"difficulty":"easy"}`)
+	if cache != "hit" || !ids[hit] {
+		t.Fatalf("cached request: %s %s", hit, cache)
+	}
+	// a solution takes the puzzle off the leaderboards, so it is never shared
+	if id, cache := generate(`{"difficulty":"easy","includeSolution":true}`); cache != "miss" || ids[id] {
+		t.Fatalf("request with solution: %s %s", id, cache)
+	}
+	resp, err := http.Get(ts.URL + "/puzzles/" + hit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("cached puzzle not stored: %d", resp.StatusCode)
+	}
+	if id, cache := generate(`{"difficulty":"easy","fresh":true}`); cache != "miss" || ids[id] {
+		t.Fatalf("fresh request: %s %s", id, cache)
+	}
+	if _, cache := generate(`{"difficulty":"medium"}`); cache != "miss" {
+		t.Fatalf("other difficulty: %s", cache)
+	}
+
+	// revealing a cached puzzle takes it out of the cache and keeps it revealed
+	resp, err = http.Get(ts.URL + "/puzzles/" + hit + "?includeSolution=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	for range 4 * cachePerKey {
+		if id, _ := generate(`{"difficulty":"easy"}`); id == hit {
+			t.Fatal("revealed puzzle served from the cache")
+		}
+	}
+	if p, err := a.puzzles.LoadPuzzle(context.Background(), hit); err != nil || !p.Revealed {
+		t.Fatalf("revealed flag lost: %+v, %v", p.Revealed, err)
+	}
+}
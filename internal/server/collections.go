@@ -0,0 +1,350 @@
+package server
+
+import (
+	"bufio"
+	crand "crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	mrand "math/rand/v2"
+	"mime"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"go.rumenx.com/sudoku"
+)
+
+const (
+	maxCollectionBytes   = 8 << 20
+	maxCollectionPuzzles = 5000
+	maxCollections       = 64   // kept in memory; the oldest upload goes first
+	maxCursors           = 4096 // client cursors kept; the least recently used goes first
+)
+
+// collectionPuzzle is one accepted puzzle of a collection. Fingerprint is the
+// canonical PuzzleID, so isomorphic copies count as duplicates.
+type collectionPuzzle struct {
+	Puzzle      sudoku.Board  `json:"puzzle"`
+	Fingerprint string        `json:"fingerprint"`
+	Rating      sudoku.Rating `json:"rating"`
+}
+
+type collection struct {
+	ID      string             `json:"id"`
+	Name    string             `json:"name"`
+	Created time.Time          `json:"created"`
+	Puzzles []collectionPuzzle `json:"puzzles"`
+}
+
+// collectionCursor records which puzzles of a collection one client has been served.
+type collectionCursor struct {
+	mu     sync.Mutex
+	served []bool
+}
+
+// importLine is one raw puzzle read from an upload, with its 1-based line.
+type importLine struct {
+	line int
+	text string // 81-character form, or "" when err is set
+	err  error
+}
+
+type importProblem struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+type importSummary struct {
+	ID         string          `json:"id,omitempty"`
+	Name       string          `json:"name"`
+	Format     string          `json:"format"`
+	Accepted   int             `json:"accepted"`
+	Duplicates int             `json:"duplicates"`
+	Invalid    []importProblem `json:"invalid"`
+}
+
+// handleCollections imports a collection from a raw body or a multipart "file"
+// field. The format is taken from ?format=sdm|csv|ndjson, else the file extension
+// or Content-Type, defaulting to sdm (one 81-character puzzle per line, # comments).
+// CSV reads the "puzzle" column (or the first one); NDJSON reads {"puzzle": ...}
+// objects with a string or 9x9 array. Every puzzle is validated, required to be
+// unique and rated on a worker of the generation pool; duplicates within the
+// upload are skipped. Only the latest maxCollections uploads are kept.
+func (a *api) handleCollections(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, errMsg("method not allowed"))
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxCollectionBytes)
+	body, name, format, err := collectionUpload(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errMsg(err.Error()))
+		return
+	}
+	defer body.Close()
+	lines, err := readCollection(body, format)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errMsg(err.Error()))
+		return
+	}
+	if len(lines) > maxCollectionPuzzles {
+		writeJSON(w, http.StatusRequestEntityTooLarge, errMsg(fmt.Sprintf("at most %d puzzles per upload", maxCollectionPuzzles)))
+		return
+	}
+	if !a.generators.acquire(w, r) {
+		return
+	}
+	defer a.generators.release()
+	col := &collection{Name: name, Created: time.Now().UTC()}
+	sum := importSummary{Name: name, Format: format, Invalid: []importProblem{}}
+	seen := make(map[string]bool)
+	for _, l := range lines {
+		if l.err != nil {
+			sum.Invalid = append(sum.Invalid, importProblem{l.line, l.err.Error()})
+			continue
+		}
+		b, err := sudoku.FromString(l.text)
+		if err != nil {
+			sum.Invalid = append(sum.Invalid, importProblem{l.line, err.Error()})
+			continue
+		}
+		rating, err := sudoku.Rate(b)
+		if err != nil {
+			sum.Invalid = append(sum.Invalid, importProblem{l.line, err.Error()})
+			continue
+		}
+		fp := sudoku.PuzzleID(b)
+		if seen[fp] {
+			sum.Duplicates++
+			continue
+		}
+		seen[fp] = true
+		col.Puzzles = append(col.Puzzles, collectionPuzzle{Puzzle: b, Fingerprint: fp, Rating: rating})
+	}
+	sum.Accepted = len(col.Puzzles)
+	if sum.Accepted == 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, sum)
+		return
+	}
+	col.ID = newCollectionID()
+	a.collections.Put(col.ID, col)
+	sum.ID = col.ID
+	writeJSON(w, http.StatusCreated, sum)
+}
+
+// collectionUpload returns the upload body, its name and format.
+func collectionUpload(r *http.Request) (io.ReadCloser, string, string, error) {
+	name := r.URL.Query().Get("name")
+	format := r.URL.Query().Get("format")
+	body := r.Body
+	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if ct == "multipart/form-data" {
+		f, hdr, err := r.FormFile("file")
+		if err != nil {
+			return nil, "", "", errors.New("missing file field")
+		}
+		if name == "" {
+			name = strings.TrimSuffix(hdr.Filename, path.Ext(hdr.Filename))
+		}
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(path.Ext(hdr.Filename)), ".")
+		}
+		body = f
+	}
+	if format == "" {
+		switch ct {
+		case "text/csv":
+			format = "csv"
+		case "application/x-ndjson", "application/jsonl":
+			format = "ndjson"
+		default:
+			format = "sdm"
+		}
+	}
+	switch format {
+	case "sdm", "txt":
+		format = "sdm"
+	case "ndjson", "jsonl":
+		format = "ndjson"
+	case "csv":
+	default:
+		return nil, "", "", fmt.Errorf("unsupported format %q", format)
+	}
+	return body, name, format, nil
+}
+
+// readCollection splits an upload into puzzle strings.
+func readCollection(body io.Reader, format string) ([]importLine, error) {
+	var out []importLine
+	switch format {
+	case "csv":
+		cr := csv.NewReader(body)
+		cr.FieldsPerRecord = -1
+		col, first := 0, true
+		for {
+			rec, err := cr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("csv: %w", err)
+			}
+			line, _ := cr.FieldPos(0)
+			if first {
+				first = false
+				if i := headerIndex(rec, "puzzle"); i >= 0 {
+					col = i
+					continue
+				}
+			}
+			if col >= len(rec) {
+				out = append(out, importLine{line: line, err: errors.New("missing puzzle column")})
+				continue
+			}
+			out = append(out, importLine{line: line, text: strings.TrimSpace(rec[col])})
+		}
+	default:
+		sc := bufio.NewScanner(body)
+		for line := 1; sc.Scan(); line++ {
+			text := strings.TrimSpace(sc.Text())
+			if text == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+			if format == "sdm" {
+				out = append(out, importLine{line: line, text: text})
+				continue
+			}
+			var rec struct {
+				Puzzle *json.RawMessage `json:"puzzle"`
+			}
+			if err := json.Unmarshal([]byte(text), &rec); err != nil || rec.Puzzle == nil {
+				out = append(out, importLine{line: line, err: errors.New("invalid json object")})
+				continue
+			}
+			b, err := decodeBoard(*rec.Puzzle)
+			if err != nil {
+				out = append(out, importLine{line: line, err: err})
+				continue
+			}
+			out = append(out, importLine{line: line, text: b.String()})
+		}
+		if err := sc.Err(); err != nil {
+			return nil, fmt.Errorf("%s: %w", format, err)
+		}
+	}
+	return out, nil
+}
+
+// handleCollection deletes a collection; its puzzles stop being served at once.
+func (a *api) handleCollection(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		w.Header().Set("Allow", http.MethodDelete)
+		writeJSON(w, http.StatusMethodNotAllowed, errMsg("method not allowed"))
+		return
+	}
+	if !a.collections.Delete(r.PathValue("id")) {
+		writeJSON(w, http.StatusNotFound, errMsg("collection not found"))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleCollectionNext serves the next puzzle of a collection that the client
+// has not seen yet: in upload order, or a random unseen one with ?order=random.
+// ?difficulty= restricts the pick to puzzles of that rating. Clients are told
+// apart by ?client=, the X-Client-ID header or their address; 404 means there is
+// nothing left for them.
+func (a *api) handleCollectionNext(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeJSON(w, http.StatusMethodNotAllowed, errMsg("method not allowed"))
+		return
+	}
+	id := r.PathValue("id")
+	col, ok := a.collections.Get(id)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errMsg("collection not found"))
+		return
+	}
+	q := r.URL.Query()
+	d := sudoku.Difficulty(q.Get("difficulty"))
+	switch d {
+	case "", sudoku.Easy, sudoku.Medium, sudoku.Hard:
+	default:
+		writeJSON(w, http.StatusBadRequest, errMsg("invalid difficulty"))
+		return
+	}
+	random := false
+	switch q.Get("order") {
+	case "", "sequential":
+	case "random":
+		random = true
+	default:
+		writeJSON(w, http.StatusBadRequest, errMsg("order must be sequential or random"))
+		return
+	}
+	client := q.Get("client")
+	if client == "" {
+		client = r.Header.Get("X-Client-ID")
+	}
+	if client == "" {
+		client, _, _ = net.SplitHostPort(r.RemoteAddr)
+	}
+	key := id + "/" + client
+	// one cursor per client even when its requests race; getting it refreshes the idle expiry
+	cur := a.cursors.GetOrPut(key, func() *collectionCursor {
+		return &collectionCursor{served: make([]bool, len(col.Puzzles))}
+	})
+
+	cur.mu.Lock()
+	var open []int
+	for i, p := range col.Puzzles {
+		if !cur.served[i] && (d == "" || p.Rating.Difficulty == d) {
+			open = append(open, i)
+		}
+	}
+	if len(open) == 0 {
+		cur.mu.Unlock()
+		writeJSON(w, http.StatusNotFound, errMsg("no unseen puzzles left"))
+		return
+	}
+	pick := open[0]
+	if random {
+		pick = open[mrand.IntN(len(open))]
+	}
+	cur.served[pick] = true
+	cur.mu.Unlock()
+
+	p := col.Puzzles[pick]
+	writeJSON(w, http.StatusOK, map[string]any{
+		"collection":  id,
+		"index":       pick,
+		"puzzle":      p.Puzzle,
+		"fingerprint": p.Fingerprint,
+		"rating":      p.Rating,
+		"remaining":   len(open) - 1,
+	})
+}
+
+func headerIndex(rec []string, name string) int {
+	for i, f := range rec {
+		if strings.EqualFold(strings.TrimSpace(f), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func newCollectionID() string {
+	var b [8]byte
+	_, _ = crand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
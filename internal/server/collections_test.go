@@ -0,0 +1,217 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func postCollection(t *testing.T, a *api, target, contentType string, body *bytes.Buffer) (int, importSummary) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, target, body)
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	a.handleCollections(rec, req)
+	var sum importSummary
+	if err := json.NewDecoder(rec.Body).Decode(&sum); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return rec.Code, sum
+}
+
+func TestCollectionsImportSDM(t *testing.T) {
+	b, _ := sudoku.FromString(sudokutest.Easy)
+	iso := sudoku.Rotate(b).String() // isomorphic copy counts as a duplicate
+	sdm := strings.Join([]string{"# sample", sudokutest.Easy, sudokutest.Medium, "", iso, sudokutest.NonUnique, "123"}, "\n")
+	a := newAPI()
+	code, sum := postCollection(t, a, "/collections?name=sample", "text/plain", bytes.NewBufferString(sdm))
+	if code != http.StatusCreated || sum.Format != "sdm" || sum.Accepted != 2 || sum.Duplicates != 1 || len(sum.Invalid) != 2 {
+		t.Fatalf("status %d summary %+v", code, sum)
+	}
+	if sum.Invalid[0].Line != 6 || sum.Invalid[1].Line != 7 {
+		t.Fatalf("invalid lines %+v", sum.Invalid)
+	}
+	col, ok := a.collections.Get(sum.ID)
+	if !ok || col.Name != "sample" || len(col.Puzzles) != 2 || col.Puzzles[0].Rating.Difficulty != sudoku.Easy {
+		t.Fatalf("stored collection %+v", col)
+	}
+	if col.Puzzles[0].Fingerprint != sudoku.PuzzleID(b) {
+		t.Fatalf("fingerprint not the canonical id")
+	}
+}
+
+func TestCollectionsBounded(t *testing.T) {
+	a := newAPI()
+	a.generators = newWorkerPool(GenerationPool{Workers: 1, QueueWait: -1})
+	first := ""
+	for i := 0; i <= maxCollections; i++ {
+		code, sum := postCollection(t, a, "/collections", "text/plain", bytes.NewBufferString(sudokutest.Easy))
+		if code != http.StatusCreated {
+			t.Fatalf("upload %d: status %d", i, code)
+		}
+		if i == 0 {
+			first = sum.ID
+		}
+	}
+	if _, ok := a.collections.Get(first); ok || a.collections.Len() != maxCollections {
+		t.Fatalf("%d collections kept, oldest still there: %v", a.collections.Len(), ok)
+	}
+	a.generators.slots <- struct{}{} // every worker busy
+	if code, _ := postCollection(t, a, "/collections", "text/plain", bytes.NewBufferString(sudokutest.Easy)); code != http.StatusTooManyRequests {
+		t.Fatalf("upload with the pool busy: status %d, want 429", code)
+	}
+}
+
+func TestHandlerCollectionsBounded(t *testing.T) {
+	h, a, err := newHandler(Options{Quiet: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := ""
+	for i := 0; i <= maxCollections; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/collections", bytes.NewBufferString(sudokutest.Easy))
+		req.Header.Set("Content-Type", "text/plain")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("upload %d: status %d", i, rec.Code)
+		}
+		if i == 0 {
+			var sum importSummary
+			if err := json.NewDecoder(rec.Body).Decode(&sum); err != nil {
+				t.Fatal(err)
+			}
+			first = sum.ID
+		}
+	}
+	if _, ok := a.collections.Get(first); ok || a.collections.Len() != maxCollections {
+		t.Fatalf("%d collections kept, oldest still there: %v", a.collections.Len(), ok)
+	}
+}
+
+func TestCollectionCursorsBounded(t *testing.T) {
+	h, a, err := newHandler(Options{Quiet: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/collections", bytes.NewBufferString(sudokutest.Easy))
+	req.Header.Set("Content-Type", "text/plain")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var sum importSummary
+	if err := json.NewDecoder(rec.Body).Decode(&sum); err != nil || rec.Code != http.StatusCreated {
+		t.Fatalf("import: %d %v", rec.Code, err)
+	}
+	for i := 0; i <= maxCursors; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/collections/%s/next?client=c%d", sum.ID, i), nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("client %d: status %d", i, rec.Code)
+		}
+	}
+	if _, ok := a.cursors.Get(sum.ID + "/c0"); ok || a.cursors.Len() != maxCursors {
+		t.Fatalf("%d cursors kept, oldest still there: %v", a.cursors.Len(), ok)
+	}
+}
+
+func TestCollectionsImportCSVAndNDJSON(t *testing.T) {
+	csvBody := "id,puzzle\n1," + sudokutest.Easy + "\n2," + sudokutest.Hard + "\n"
+	a := newAPI()
+	code, sum := postCollection(t, a, "/collections", "text/csv", bytes.NewBufferString(csvBody))
+	if code != http.StatusCreated || sum.Format != "csv" || sum.Accepted != 2 {
+		t.Fatalf("csv: status %d summary %+v", code, sum)
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, _ := mw.CreateFormFile("file", "set.ndjson")
+	fw.Write([]byte(`{"puzzle":"` + sudokutest.Medium + `"}` + "\n" + `{"nope":1}` + "\n"))
+	mw.Close()
+	code, sum = postCollection(t, a, "/collections", mw.FormDataContentType(), &buf)
+	if code != http.StatusCreated || sum.Format != "ndjson" || sum.Name != "set" || sum.Accepted != 1 || len(sum.Invalid) != 1 {
+		t.Fatalf("ndjson: status %d summary %+v", code, sum)
+	}
+}
+
+func TestCollectionsImportRejects(t *testing.T) {
+	a := newAPI()
+	code, sum := postCollection(t, a, "/collections", "text/plain", bytes.NewBufferString(sudokutest.Invalid+"\n"))
+	if code != http.StatusUnprocessableEntity || sum.Accepted != 0 || sum.ID != "" {
+		t.Fatalf("status %d summary %+v", code, sum)
+	}
+	rec := httptest.NewRecorder()
+	a.handleCollections(rec, httptest.NewRequest(http.MethodPost, "/collections?format=xml", strings.NewReader("x")))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("unknown format: status %d", rec.Code)
+	}
+}
+
+func TestCollectionNext(t *testing.T) {
+	sdm := strings.Join([]string{sudokutest.Easy, sudokutest.Medium, sudokutest.Hard}, "\n")
+	a := newAPI()
+	code, sum := postCollection(t, a, "/collections", "text/plain", bytes.NewBufferString(sdm))
+	if code != http.StatusCreated || sum.Accepted != 3 {
+		t.Fatalf("import: %d %+v", code, sum)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/collections/{id}/next", a.handleCollectionNext)
+	next := func(query string) (int, map[string]any) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/collections/"+sum.ID+"/next"+query, nil))
+		var out map[string]any
+		_ = json.NewDecoder(rec.Body).Decode(&out)
+		return rec.Code, out
+	}
+	// sequential order per client, no repeats
+	for want := 0; want < 3; want++ {
+		code, out := next("?client=a")
+		if code != http.StatusOK || out["index"] != float64(want) {
+			t.Fatalf("client a pick %d: %d %v", want, code, out)
+		}
+	}
+	if code, _ := next("?client=a"); code != http.StatusNotFound {
+		t.Fatalf("exhausted collection: status %d", code)
+	}
+	// another client starts over; random order still never repeats
+	seen := map[float64]bool{}
+	for i := 0; i < 3; i++ {
+		code, out := next("?client=b&order=random")
+		idx, _ := out["index"].(float64)
+		if code != http.StatusOK || seen[idx] {
+			t.Fatalf("client b pick %d: %d %v", i, code, out)
+		}
+		seen[idx] = true
+	}
+	code, out := next("?client=c&difficulty=easy")
+	if rating, _ := out["rating"].(map[string]any); code != http.StatusOK || rating["difficulty"] != "easy" {
+		t.Fatalf("difficulty filter: %d %v", code, out)
+	}
+	if code, _ := next("?client=c&difficulty=nightmare"); code != http.StatusBadRequest {
+		t.Fatalf("bad difficulty: status %d", code)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/collections/missing/next", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown collection: status %d", rec.Code)
+	}
+
+	mux.HandleFunc("/collections/{id}", a.handleCollection)
+	for _, want := range []int{http.StatusNoContent, http.StatusNotFound} {
+		rec = httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/collections/"+sum.ID, nil))
+		if rec.Code != want {
+			t.Fatalf("DELETE collection: status %d, want %d", rec.Code, want)
+		}
+	}
+	if code, _ := next("?client=d"); code != http.StatusNotFound {
+		t.Fatalf("deleted collection still served: status %d", code)
+	}
+}
@@ -0,0 +1,395 @@
+package server
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultTimeout    = 10 * time.Second // read and write timeouts
+	defaultDrainDelay = 5 * time.Second
+)
+
+// Config is the server configuration file, YAML (.yaml, .yml) or TOML
+// (.toml). Every setting stands for an environment variable, which wins over
+// the file; flags win over both. Zero values leave a setting unset, except for
+// the pointer fields, where a set 0s means no wait. The file never changes the
+// process environment: Options carry it to Handler and Run.
+type Config struct {
+	Port         int            `yaml:"port,omitempty" toml:"port,omitempty"`
+	ReadTimeout  time.Duration  `yaml:"read_timeout,omitempty" toml:"read_timeout,omitempty"`
+	WriteTimeout time.Duration  `yaml:"write_timeout,omitempty" toml:"write_timeout,omitempty"`
+	MaxBody      int64          `yaml:"max_body,omitempty" toml:"max_body,omitempty"`
+	DrainDelay   *time.Duration `yaml:"drain_delay,omitempty" toml:"drain_delay,omitempty"` // 0s: stop without draining
+	LogLevel     string         `yaml:"log_level,omitempty" toml:"log_level,omitempty"`     // info logs requests; warn or error do not
+	Database     string         `yaml:"database,omitempty" toml:"database,omitempty"`       // SQLite file
+	RateLimit    float64        `yaml:"rate_limit,omitempty" toml:"rate_limit,omitempty"`
+	RateBurst    int            `yaml:"rate_burst,omitempty" toml:"rate_burst,omitempty"`
+	Workers      int            `yaml:"workers,omitempty" toml:"workers,omitempty"`
+	QueueWait    *time.Duration `yaml:"queue_wait,omitempty" toml:"queue_wait,omitempty"` // 0s: reject at once when busy
+	CacheTTL     time.Duration  `yaml:"cache_ttl,omitempty" toml:"cache_ttl,omitempty"`
+
+	CORS struct {
+		Origins []string `yaml:"origins,omitempty" toml:"origins,omitempty"`
+		Methods []string `yaml:"methods,omitempty" toml:"methods,omitempty"`
+		Headers []string `yaml:"headers,omitempty" toml:"headers,omitempty"`
+	} `yaml:"cors" toml:"cors"`
+	TLS struct {
+		Cert     string `yaml:"cert,omitempty" toml:"cert,omitempty"`
+		Key      string `yaml:"key,omitempty" toml:"key,omitempty"`
+		ClientCA string `yaml:"client_ca,omitempty" toml:"client_ca,omitempty"`
+	} `yaml:"tls" toml:"tls"`
+	Admin  accessConfig `yaml:"admin" toml:"admin"`
+	Public accessConfig `yaml:"public" toml:"public"`
+
+	Retention struct {
+		Puzzles time.Duration `yaml:"puzzles,omitempty" toml:"puzzles,omitempty"`
+		Grace   time.Duration `yaml:"grace,omitempty" toml:"grace,omitempty"`
+		Sweep   time.Duration `yaml:"sweep,omitempty" toml:"sweep,omitempty"`
+	} `yaml:"retention" toml:"retention"`
+	SLA struct {
+		P95 string `yaml:"p95,omitempty" toml:"p95,omitempty"` // e.g. generate=500ms,solve=50ms
+		P99 string `yaml:"p99,omitempty" toml:"p99,omitempty"`
+	} `yaml:"sla" toml:"sla"`
+	Shadow struct {
+		Solver      string `yaml:"solver,omitempty" toml:"solver,omitempty"`
+		Concurrency int    `yaml:"concurrency,omitempty" toml:"concurrency,omitempty"`
+	} `yaml:"shadow" toml:"shadow"`
+}
+
+// accessConfig is the access policy of a route group.
+type accessConfig struct {
+	Allow []string `yaml:"allow,omitempty" toml:"allow,omitempty"` // CIDRs or bare IPs
+	MTLS  bool     `yaml:"mtls,omitempty" toml:"mtls,omitempty"`
+}
+
+// configVar ties a setting to its environment variable.
+type configVar struct {
+	env string
+	dst any
+}
+
+func (c *Config) vars() []configVar {
+	return []configVar{
+		{"PORT", &c.Port},
+		{"SUDOKU_READ_TIMEOUT", &c.ReadTimeout},
+		{"SUDOKU_WRITE_TIMEOUT", &c.WriteTimeout},
+		{"SUDOKU_MAX_BODY", &c.MaxBody},
+		{"SUDOKU_DRAIN_DELAY", &c.DrainDelay},
+		{"SUDOKU_LOG_LEVEL", &c.LogLevel},
+		{"SUDOKU_DB", &c.Database},
+		{"SUDOKU_RATE_LIMIT", &c.RateLimit},
+		{"SUDOKU_RATE_BURST", &c.RateBurst},
+		{"SUDOKU_WORKERS", &c.Workers},
+		{"SUDOKU_QUEUE_WAIT", &c.QueueWait},
+		{"SUDOKU_CACHE_TTL", &c.CacheTTL},
+		{"SUDOKU_CORS_ORIGINS", &c.CORS.Origins},
+		{"SUDOKU_CORS_METHODS", &c.CORS.Methods},
+		{"SUDOKU_CORS_HEADERS", &c.CORS.Headers},
+		{"SUDOKU_TLS_CERT", &c.TLS.Cert},
+		{"SUDOKU_TLS_KEY", &c.TLS.Key},
+		{"SUDOKU_TLS_CLIENT_CA", &c.TLS.ClientCA},
+		{"SUDOKU_ADMIN_ALLOW", &c.Admin.Allow},
+		{"SUDOKU_ADMIN_MTLS", &c.Admin.MTLS},
+		{"SUDOKU_PUBLIC_ALLOW", &c.Public.Allow},
+		{"SUDOKU_PUBLIC_MTLS", &c.Public.MTLS},
+		{"SUDOKU_RETENTION_PUZZLES", &c.Retention.Puzzles},
+		{"SUDOKU_RETENTION_GRACE", &c.Retention.Grace},
+		{"SUDOKU_RETENTION_SWEEP", &c.Retention.Sweep},
+		{"SUDOKU_SLA_P95", &c.SLA.P95},
+		{"SUDOKU_SLA_P99", &c.SLA.P99},
+		{"SUDOKU_SHADOW_SOLVER", &c.Shadow.Solver},
+		{"SUDOKU_SHADOW_CONCURRENCY", &c.Shadow.Concurrency},
+	}
+}
+
+// readConfig parses the file at path, rejecting unknown settings.
+func readConfig(path string) (Config, error) {
+	var c Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&c); err != nil && err != io.EOF {
+			return c, fmt.Errorf("%s: %w", path, err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(data), &c)
+		if err != nil {
+			return c, fmt.Errorf("%s: %w", path, err)
+		}
+		if extra := md.Undecoded(); len(extra) > 0 {
+			return c, fmt.Errorf("%s: unknown setting %q", path, extra[0].String())
+		}
+	default:
+		return c, fmt.Errorf("%s: config files must be .yaml, .yml or .toml, not %q", path, ext)
+	}
+	return c, nil
+}
+
+// lookup returns the setting c holds for the environment variable env,
+// formatted as the variable would be, or "" when c leaves it unset.
+func (c *Config) lookup(env string) string {
+	for _, v := range c.vars() {
+		if v.env != env {
+			continue
+		}
+		var s string
+		switch p := v.dst.(type) {
+		case *int:
+			if *p != 0 {
+				s = strconv.Itoa(*p)
+			}
+		case *int64:
+			if *p != 0 {
+				s = strconv.FormatInt(*p, 10)
+			}
+		case *float64:
+			if *p != 0 {
+				s = strconv.FormatFloat(*p, 'g', -1, 64)
+			}
+		case *bool:
+			if *p {
+				s = "true"
+			}
+		case *string:
+			s = *p
+		case *[]string:
+			s = strings.Join(*p, ",")
+		case *time.Duration:
+			if *p != 0 {
+				s = p.String()
+			}
+		case **time.Duration:
+			if *p != nil {
+				s = (*p).String()
+			}
+		}
+		return s
+	}
+	return ""
+}
+
+// getenv reads the environment variable key, falling back to the setting of
+// o.Config when the variable is not set at all.
+func (o Options) getenv(key string) string {
+	if v, set := os.LookupEnv(key); set || o.Config == nil {
+		return v
+	}
+	return o.Config.lookup(key)
+}
+
+// readEnv fills c through getenv.
+func (c *Config) readEnv(getenv func(string) string) error {
+	for _, v := range c.vars() {
+		s := getenv(v.env)
+		if s == "" {
+			continue
+		}
+		var err error
+		switch p := v.dst.(type) {
+		case *int:
+			*p, err = strconv.Atoi(s)
+		case *int64:
+			*p, err = strconv.ParseInt(s, 10, 64)
+		case *float64:
+			*p, err = strconv.ParseFloat(s, 64)
+		case *bool:
+			*p, err = strconv.ParseBool(s)
+		case *string:
+			*p = s
+		case *[]string:
+			*p = nil
+			for _, f := range strings.Split(s, ",") {
+				if f = strings.TrimSpace(f); f != "" {
+					*p = append(*p, f)
+				}
+			}
+		case *time.Duration:
+			*p, err = time.ParseDuration(s)
+		case **time.Duration:
+			var d time.Duration
+			d, err = time.ParseDuration(s)
+			*p = &d
+		}
+		if err != nil {
+			return fmt.Errorf("%s: invalid value %q", v.env, s)
+		}
+	}
+	return nil
+}
+
+// fromEnv fills the settings o leaves zero from SUDOKU_READ_TIMEOUT,
+// SUDOKU_WRITE_TIMEOUT, SUDOKU_MAX_BODY, SUDOKU_DRAIN_DELAY, SUDOKU_LOG_LEVEL
+// and SUDOKU_DB, then from the defaults.
+func (o *Options) fromEnv() error {
+	for _, f := range []struct {
+		env string
+		dst *time.Duration
+	}{
+		{"SUDOKU_READ_TIMEOUT", &o.ReadTimeout},
+		{"SUDOKU_WRITE_TIMEOUT", &o.WriteTimeout},
+	} {
+		if *f.dst != 0 {
+			continue
+		}
+		*f.dst = defaultTimeout
+		if v := o.getenv(f.env); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				return fmt.Errorf("%s: invalid duration %q", f.env, v)
+			}
+			*f.dst = d
+		}
+	}
+	if v := o.getenv("SUDOKU_MAX_BODY"); v != "" && o.MaxBodyBytes == 0 {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return fmt.Errorf("SUDOKU_MAX_BODY: invalid size %q", v)
+		}
+		o.MaxBodyBytes = n
+	}
+	if o.DrainDelay == 0 {
+		o.DrainDelay = defaultDrainDelay
+		if v := o.getenv("SUDOKU_DRAIN_DELAY"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d < 0 {
+				return fmt.Errorf("SUDOKU_DRAIN_DELAY: invalid duration %q", v)
+			}
+			o.DrainDelay = d
+			if d == 0 {
+				o.DrainDelay = -1
+			}
+		}
+	}
+	switch v := o.getenv("SUDOKU_LOG_LEVEL"); v {
+	case "", "info":
+	case "warn", "error":
+		o.Quiet = true
+	default:
+		return fmt.Errorf("SUDOKU_LOG_LEVEL: unknown level %q (want info, warn or error)", v)
+	}
+	if o.Database == "" {
+		o.Database = o.getenv("SUDOKU_DB")
+	}
+	return nil
+}
+
+// LoadConfig reads the config file o.ConfigFile (default $SUDOKU_CONFIG) into
+// o.Config, below the environment, then validates every setting, so mistakes
+// surface before the server starts.
+func LoadConfig(o *Options) error {
+	path := o.ConfigFile
+	if path == "" {
+		path = os.Getenv("SUDOKU_CONFIG")
+	}
+	if path != "" {
+		c, err := readConfig(path)
+		if err != nil {
+			return err
+		}
+		o.Config = &c
+	}
+	_, err := effectiveConfig(*o)
+	return err
+}
+
+// WriteConfig writes the settings o runs with, after the config file,
+// environment, flags and defaults, as YAML.
+func WriteConfig(w io.Writer, o Options) error {
+	c, err := effectiveConfig(o)
+	if err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// effectiveConfig validates the settings of o and the environment the way
+// Handler and Run read them, and collects the values they end up with.
+func effectiveConfig(o Options) (Config, error) {
+	var c Config
+	if err := c.readEnv(o.getenv); err != nil {
+		return c, err
+	}
+	if err := o.fromEnv(); err != nil {
+		return c, err
+	}
+	if _, err := strconv.Atoi(strings.TrimPrefix(o.Addr(), ":")); err != nil {
+		return c, fmt.Errorf("PORT: invalid port %q", strings.TrimPrefix(o.Addr(), ":"))
+	}
+	c.Port, _ = strconv.Atoi(strings.TrimPrefix(o.Addr(), ":"))
+	c.ReadTimeout, c.WriteTimeout, c.MaxBody, c.Database = o.ReadTimeout, o.WriteTimeout, o.MaxBodyBytes, o.Database
+	drain := max(o.DrainDelay, 0)
+	c.DrainDelay = &drain
+	c.LogLevel = "info"
+	if o.Quiet {
+		c.LogLevel = cmp.Or(o.getenv("SUDOKU_LOG_LEVEL"), "warn")
+	}
+
+	if _, err := rateFromEnv(o.getenv); err != nil {
+		return c, err
+	}
+	if o.RateLimit.Rate > 0 {
+		c.RateLimit = o.RateLimit.Rate
+	}
+	if o.RateLimit.Burst > 0 {
+		c.RateBurst = o.RateLimit.Burst
+	} else if c.RateBurst == 0 && c.RateLimit > 0 {
+		c.RateBurst = RateLimit{Rate: c.RateLimit}.withDefaultBurst().Burst
+	}
+	pool, err := poolFromEnv(o.getenv)
+	if err != nil {
+		return c, err
+	}
+	if o.Generation.Workers > 0 {
+		pool.Workers = o.Generation.Workers
+	}
+	if o.Generation.QueueWait != 0 {
+		pool.QueueWait = o.Generation.QueueWait
+	}
+	wp := newWorkerPool(pool)
+	c.Workers, c.QueueWait = cap(wp.slots), &wp.wait
+	if c.CacheTTL, err = cacheTTLFromEnv(o.getenv); err != nil {
+		return c, err
+	}
+	cors := corsFromEnv(o.getenv)
+	c.CORS.Methods, c.CORS.Headers = strings.Split(cors.methods, ", "), strings.Split(cors.headers, ", ")
+	if _, err := accessFromEnv(o.getenv); err != nil {
+		return c, err
+	}
+	ret, err := retentionFromEnv(o.getenv)
+	if err != nil {
+		return c, err
+	}
+	c.Retention.Grace, c.Retention.Sweep = ret.Grace, ret.Sweep
+	if _, err := slaFromEnv(o.getenv); err != nil {
+		return c, err
+	}
+	sh, err := shadowFromEnv(o.getenv, func(string, ...any) {})
+	if err != nil {
+		return c, err
+	}
+	if sh != nil {
+		c.Shadow.Concurrency = cap(sh.sem)
+	}
+	return c, nil
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearConfigEnv unsets every configurable variable for the test.
+func clearConfigEnv(t *testing.T) {
+	t.Helper()
+	var c Config
+	for _, v := range append(c.vars(), configVar{env: "SUDOKU_CONFIG"}) {
+		t.Setenv(v.env, "")
+		os.Unsetenv(v.env)
+	}
+}
+
+func writeConfigFile(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	files := map[string]string{
+		"sudoku.yaml": `
+port: 9000
+read_timeout: 3s
+max_body: 65536
+log_level: warn
+rate_limit: 2.5
+workers: 3
+cors:
+  origins: [https://a.example, https://b.example]
+admin:
+  allow: [10.0.0.0/8]
+retention:
+  puzzles: 720h
+`,
+		"sudoku.toml": `
+port = 9000
+read_timeout = "3s"
+max_body = 65536
+log_level = "warn"
+rate_limit = 2.5
+workers = 3
+
+[cors]
+origins = ["https://a.example", "https://b.example"]
+
+[admin]
+allow = ["10.0.0.0/8"]
+
+[retention]
+puzzles = "720h"
+`,
+	}
+	for name, data := range files {
+		t.Run(name, func(t *testing.T) {
+			clearConfigEnv(t)
+			t.Setenv("SUDOKU_WORKERS", "5") // the environment wins over the file
+			o := Options{ConfigFile: writeConfigFile(t, name, data), WriteTimeout: time.Minute}
+			if err := LoadConfig(&o); err != nil {
+				t.Fatal(err)
+			}
+			for env, want := range map[string]string{
+				"PORT": "9000", "SUDOKU_READ_TIMEOUT": "3s", "SUDOKU_MAX_BODY": "65536", "SUDOKU_LOG_LEVEL": "warn",
+				"SUDOKU_RATE_LIMIT": "2.5", "SUDOKU_WORKERS": "5", "SUDOKU_CORS_ORIGINS": "https://a.example,https://b.example",
+				"SUDOKU_ADMIN_ALLOW": "10.0.0.0/8", "SUDOKU_RETENTION_PUZZLES": "720h0m0s", "SUDOKU_TLS_CERT": "",
+			} {
+				if got := o.getenv(env); got != want {
+					t.Errorf("%s = %q, want %q", env, got, want)
+				}
+			}
+			if v, set := os.LookupEnv("PORT"); set {
+				t.Errorf("config file leaked into the environment: PORT=%q", v)
+			}
+
+			var out bytes.Buffer
+			if err := WriteConfig(&out, o); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range []string{"port: 9000", "read_timeout: 3s", "write_timeout: 1m0s", "log_level: warn",
+				"workers: 5", "rate_burst: 3", "- https://b.example", "puzzles: 720h0m0s", "sweep: 1m0s"} {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("effective config lacks %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestWriteConfigKeepsZeroWaits(t *testing.T) {
+	for name, data := range map[string]string{
+		"zero.yaml": "queue_wait: 0s\ndrain_delay: 0s\n",
+		"zero.toml": "queue_wait = \"0s\"\ndrain_delay = \"0s\"\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			clearConfigEnv(t)
+			for range 2 { // then read back the printed config
+				o := Options{ConfigFile: writeConfigFile(t, name, data)}
+				if err := LoadConfig(&o); err != nil {
+					t.Fatal(err)
+				}
+				if o.getenv("SUDOKU_QUEUE_WAIT") != "0s" || o.getenv("SUDOKU_DRAIN_DELAY") != "0s" {
+					t.Fatalf("zero waits not read: queue %q, drain %q", o.getenv("SUDOKU_QUEUE_WAIT"), o.getenv("SUDOKU_DRAIN_DELAY"))
+				}
+				var out bytes.Buffer
+				if err := WriteConfig(&out, o); err != nil {
+					t.Fatal(err)
+				}
+				for _, want := range []string{"queue_wait: 0s", "drain_delay: 0s"} {
+					if !strings.Contains(out.String(), want) {
+						t.Fatalf("effective config lacks %q:\n%s", want, out.String())
+					}
+				}
+				name, data = "printed.yaml", out.String()
+			}
+		})
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"typo.yaml":   "prot: 80\n",
+		"typo.toml":   "prot = 80\n",
+		"bad.yaml":    "admin:\n  allow: [nope]\n",
+		"level.yaml":  "log_level: loud\n",
+		"sudoku.json": "{}",
+		"type.toml":   `workers = "many"`,
+	} {
+		clearConfigEnv(t)
+		o := Options{ConfigFile: writeConfigFile(t, name, data)}
+		if err := LoadConfig(&o); err == nil {
+			t.Errorf("%s accepted", name)
+		}
+	}
+	clearConfigEnv(t)
+	t.Setenv("SUDOKU_READ_TIMEOUT", "soon")
+	if err := LoadConfig(&Options{}); err == nil || !strings.Contains(err.Error(), "SUDOKU_READ_TIMEOUT") {
+		t.Errorf("bad environment: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// pingTimeout bounds each store check of /readyz.
+const pingTimeout = 2 * time.Second
+
+// pinger is implemented by stores with a backend that can become unreachable.
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// isProbe reports whether path is a health probe; probes bypass access
+// control and rate limiting.
+func isProbe(path string) bool {
+	switch path {
+	case "/health", "/healthz", "/livez", "/readyz":
+		return true
+	}
+	return false
+}
+
+// probe restricts a health handler to GET and HEAD, as documented.
+func probe(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeJSON(w, http.StatusMethodNotAllowed, errMsg("method not allowed"))
+			return
+		}
+		h(w, r)
+	}
+}
+
+// handleLivez answers while the process serves requests at all.
+func handleLivez(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReadyz answers 200 when the instance should take traffic: it is not
+// shutting down, has a free generation worker and reaches its stores. Otherwise
+// it answers 503 naming the failed checks.
+func (a *api) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	checks := map[string]string{"shutdown": "ok", "workers": "ok", "puzzles": "ok", "completions": "ok"}
+	ready := true
+	fail := func(check, msg string) {
+		checks[check], ready = msg, false
+	}
+	if a.draining.Load() {
+		fail("shutdown", "shutting down")
+	}
+	if a.generators.busy() {
+		fail("workers", "all generation workers busy")
+	}
+	for name, s := range map[string]any{"puzzles": a.puzzles, "completions": a.completions} {
+		if p, ok := s.(pinger); ok {
+			ctx, cancel := context.WithTimeout(r.Context(), pingTimeout)
+			if err := p.Ping(ctx); err != nil {
+				fail(name, "unreachable: "+err.Error())
+			}
+			cancel()
+		}
+	}
+	if !ready {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"status": "not ready", "checks": checks})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"status": "ready", "checks": checks})
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestProbes(t *testing.T) {
+	h, a, err := newHandler(Options{Quiet: true, Database: filepath.Join(t.TempDir(), "p.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		a.puzzles.(*sqlPuzzleStore).Close()
+		a.completions.(*sqlCompletionStore).Close()
+	})
+	probe := func(path string) (int, map[string]string) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var out struct {
+			Checks map[string]string `json:"checks"`
+		}
+		json.NewDecoder(rec.Body).Decode(&out)
+		return rec.Code, out.Checks
+	}
+
+	if code, _ := probe("/livez"); code != http.StatusOK {
+		t.Fatalf("livez = %d", code)
+	}
+	if code, checks := probe("/readyz"); code != http.StatusOK || checks["puzzles"] != "ok" {
+		t.Fatalf("readyz = %d %v", code, checks)
+	}
+
+	for range cap(a.generators.slots) {
+		a.generators.slots <- struct{}{}
+	}
+	code, checks := probe("/readyz")
+	for range cap(a.generators.slots) {
+		<-a.generators.slots
+	}
+	if code != http.StatusServiceUnavailable || checks["workers"] == "ok" {
+		t.Fatalf("busy readyz = %d %v", code, checks)
+	}
+
+	a.draining.Store(true)
+	code, checks = probe("/readyz")
+	a.draining.Store(false)
+	if code != http.StatusServiceUnavailable || checks["shutdown"] == "ok" {
+		t.Fatalf("draining readyz = %d %v", code, checks)
+	}
+
+	a.completions.(*sqlCompletionStore).Close()
+	if code, checks := probe("/readyz"); code != http.StatusServiceUnavailable || checks["completions"] == "ok" || checks["puzzles"] != "ok" {
+		t.Fatalf("closed store readyz = %d %v", code, checks)
+	}
+	if code, _ := probe("/livez"); code != http.StatusOK {
+		t.Fatalf("livez with a closed store = %d", code)
+	}
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	idempotencyTTL    = 24 * time.Hour
+	maxIdempotencyKey = 255
+	maxIdempotentBody = maxCollectionBytes + 1<<20 // room for multipart framing
+	maxIdempotentKeys = 4096                       // oldest keys are forgotten first
+	maxRecordedBody   = 64 << 10                   // larger responses are not kept
+	idempotencyReplay = "Idempotent-Replayed"
+	idempotencyHeader = "Idempotency-Key"
+)
+
+// idemResponse is the recorded outcome of the first request with a key. done is
+// closed once the response is stored; retries arriving earlier get 409.
+type idemResponse struct {
+	fingerprint [32]byte // method, path, query and body of the original request
+	done        chan struct{}
+	status      int
+	header      http.Header
+	body        []byte
+}
+
+// idempotent lets clients retry a POST safely: the first response for an
+// Idempotency-Key (scoped to the path) is kept for a day and replayed verbatim,
+// with Idempotent-Replayed: true, to retries carrying the same request.
+// Reusing a key for a different request answers 422, and a retry that races
+// the original answers 409. Server errors are not cached so they can be retried,
+// and neither are responses over maxRecordedBody, such as large batches; at
+// most maxIdempotentKeys responses are kept, so memory stays bounded however
+// many keys clients send. Requests without the header, or not POST, pass
+// straight through.
+func (a *api) idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyHeader)
+		if key == "" || r.Method != http.MethodPost {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKey {
+			writeJSON(w, http.StatusBadRequest, errMsg("Idempotency-Key longer than "+strconv.Itoa(maxIdempotencyKey)+" characters"))
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBody))
+		if err != nil {
+			var tooBig *http.MaxBytesError
+			if errors.As(err, &tooBig) {
+				writeJSON(w, http.StatusRequestEntityTooLarge, errMsg("request body too large"))
+				return
+			}
+			writeJSON(w, http.StatusBadRequest, errMsg("could not read request body"))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		h := sha256.New()
+		io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+		h.Write(body)
+		var fp [32]byte
+		h.Sum(fp[:0])
+		id := r.URL.Path + " " + key
+
+		a.idemMu.Lock()
+		prev, ok := a.idempotency.Get(id)
+		if !ok {
+			prev = &idemResponse{fingerprint: fp, done: make(chan struct{})}
+			a.idempotency.Put(id, prev)
+		}
+		a.idemMu.Unlock()
+		if ok {
+			replayIdempotent(w, prev, fp)
+			return
+		}
+
+		rec := &recordingWriter{ResponseWriter: w}
+		defer func() {
+			if rec.status >= 500 || rec.status == 0 || rec.truncated {
+				a.idempotency.Delete(id) // let the client retry for real
+			}
+			prev.status, prev.header, prev.body = rec.status, w.Header().Clone(), rec.body.Bytes()
+			close(prev.done)
+		}()
+		next(rec, r)
+	}
+}
+
+func replayIdempotent(w http.ResponseWriter, prev *idemResponse, fp [32]byte) {
+	if prev.fingerprint != fp {
+		writeJSON(w, http.StatusUnprocessableEntity, errMsg("Idempotency-Key was already used for a different request"))
+		return
+	}
+	select {
+	case <-prev.done:
+	default:
+		writeJSON(w, http.StatusConflict, errMsg("a request with this Idempotency-Key is still in progress"))
+		return
+	}
+	for k, v := range prev.header {
+		w.Header()[k] = v
+	}
+	w.Header().Set(idempotencyReplay, "true")
+	w.WriteHeader(prev.status)
+	_, _ = w.Write(prev.body)
+}
+
+// recordingWriter passes a response through while keeping a copy of up to
+// maxRecordedBody bytes of it.
+type recordingWriter struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	truncated bool // the body outgrew maxRecordedBody and was dropped
+}
+
+func (rw *recordingWriter) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *recordingWriter) Write(p []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	if !rw.truncated {
+		if rw.body.Len()+len(p) > maxRecordedBody {
+			rw.truncated = true
+			rw.body = bytes.Buffer{}
+		} else {
+			rw.body.Write(p)
+		}
+	}
+	return rw.ResponseWriter.Write(p)
+}
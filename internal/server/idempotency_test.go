@@ -0,0 +1,133 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func postWithKey(h http.HandlerFunc, target, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	rec := httptest.NewRecorder()
+	h(rec, req)
+	return rec
+}
+
+func TestIdempotentReplay(t *testing.T) {
+	calls := 0
+	h := newAPI().idempotent(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Location", "/things/1")
+		writeJSON(w, http.StatusCreated, map[string]int{"call": calls})
+	})
+	first := postWithKey(h, "/things", "k-replay", `{"a":1}`)
+	again := postWithKey(h, "/things", "k-replay", `{"a":1}`)
+	if calls != 1 || again.Code != http.StatusCreated || again.Body.String() != first.Body.String() {
+		t.Fatalf("calls=%d replay %d %s", calls, again.Code, again.Body.String())
+	}
+	if again.Header().Get("Idempotent-Replayed") != "true" || again.Header().Get("Location") != "/things/1" {
+		t.Fatalf("replay headers %v", again.Header())
+	}
+	if rec := postWithKey(h, "/things", "k-replay", `{"a":2}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("reused key with new body: %d", rec.Code)
+	}
+	if postWithKey(h, "/other", "k-replay", `{"a":1}`); calls != 2 {
+		t.Fatalf("keys should be scoped to the path")
+	}
+	if postWithKey(h, "/things", "", `{"a":1}`); calls != 3 {
+		t.Fatalf("requests without a key must not be deduplicated")
+	}
+}
+
+func TestIdempotentBounded(t *testing.T) {
+	a := newAPI()
+	calls := 0
+	h := a.idempotent(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+		w.Write(bytes.Repeat([]byte("x"), size))
+	})
+	big := "/things?size=" + strconv.Itoa(maxRecordedBody+1)
+	if rec := postWithKey(h, big, "k-big", ""); rec.Body.Len() != maxRecordedBody+1 {
+		t.Fatalf("big response cut to %d bytes", rec.Body.Len())
+	}
+	if rec := postWithKey(h, big, "k-big", ""); calls != 2 || rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("response over the limit was kept")
+	}
+	for i := 0; i < maxIdempotentKeys+10; i++ {
+		postWithKey(h, "/things?size=10", "k-"+strconv.Itoa(i), "")
+	}
+	if n := a.idempotency.Len(); n != maxIdempotentKeys {
+		t.Fatalf("%d keys kept, want %d", n, maxIdempotentKeys)
+	}
+}
+
+func TestIdempotentErrorsAndInFlight(t *testing.T) {
+	fail := true
+	h := newAPI().idempotent(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			writeJSON(w, http.StatusInternalServerError, errMsg("boom"))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"ok": "yes"})
+	})
+	postWithKey(h, "/flaky", "k-5xx", "x")
+	fail = false
+	if rec := postWithKey(h, "/flaky", "k-5xx", "x"); rec.Code != http.StatusOK || rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("server error was cached: %d", rec.Code)
+	}
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	slow := newAPI().idempotent(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	})
+	done := make(chan int)
+	go func() { done <- postWithKey(slow, "/slow", "k-slow", "").Code }()
+	<-started
+	if rec := postWithKey(slow, "/slow", "k-slow", ""); rec.Code != http.StatusConflict {
+		t.Fatalf("concurrent retry: %d", rec.Code)
+	}
+	close(release)
+	if code := <-done; code != http.StatusNoContent {
+		t.Fatalf("original request: %d", code)
+	}
+	if rec := postWithKey(slow, "/slow", "k-slow", ""); rec.Code != http.StatusNoContent || rec.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("replay after completion: %d", rec.Code)
+	}
+	if rec := postWithKey(h, "/x", strings.Repeat("k", 256), ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("overlong key: %d", rec.Code)
+	}
+}
+
+func TestIdempotentCollectionUpload(t *testing.T) {
+	a := newAPI()
+	h := a.idempotent(a.handleCollections)
+	ids := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/collections", bytes.NewBufferString(sudokutest.Easy+"\n"))
+		req.Header.Set("Content-Type", "text/plain")
+		req.Header.Set("Idempotency-Key", "upload-1")
+		rec := httptest.NewRecorder()
+		h(rec, req)
+		var sum importSummary
+		if err := json.NewDecoder(rec.Body).Decode(&sum); err != nil || rec.Code != http.StatusCreated {
+			t.Fatalf("upload %d: %d %v", i, rec.Code, err)
+		}
+		ids[sum.ID] = true
+	}
+	if len(ids) != 1 {
+		t.Fatalf("retried upload created %d collections", len(ids))
+	}
+}
@@ -0,0 +1,341 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"go.rumenx.com/sudoku"
+)
+
+const (
+	maxPlayerName       = 32
+	maxLeaderboardLimit = 100
+	maxGameSeconds      = 7 * 24 * 60 * 60
+)
+
+// Completion is a finished game on a leaderboard.
+type Completion struct {
+	Player    string    `json:"player"`
+	Seconds   int       `json:"seconds"`
+	Hints     int       `json:"hints"`
+	Mistakes  int       `json:"mistakes"`
+	Completed time.Time `json:"completed"`
+}
+
+// better orders completions: faster first, then fewer hints, fewer mistakes
+// and the earlier finish.
+func (c Completion) better(o Completion) bool {
+	if c.Seconds != o.Seconds {
+		return c.Seconds < o.Seconds
+	}
+	if c.Hints != o.Hints {
+		return c.Hints < o.Hints
+	}
+	if c.Mistakes != o.Mistakes {
+		return c.Mistakes < o.Mistakes
+	}
+	return c.Completed.Before(o.Completed)
+}
+
+// CompletionStore keeps the leaderboards, each holding the best completion of
+// every player. Implementations must be safe for concurrent use.
+type CompletionStore interface {
+	// AddCompletion records c on board unless the player already has a better one.
+	AddCompletion(ctx context.Context, board string, c Completion) error
+	// Fastest returns up to limit completions of board, best first.
+	Fastest(ctx context.Context, board string, limit int) ([]Completion, error)
+}
+
+type memCompletionStore struct {
+	mu     sync.Mutex
+	boards map[string]map[string]Completion // board, player
+}
+
+func newMemCompletionStore() *memCompletionStore {
+	return &memCompletionStore{boards: make(map[string]map[string]Completion)}
+}
+
+func (s *memCompletionStore) AddCompletion(_ context.Context, board string, c Completion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := s.boards[board]
+	if b == nil {
+		b = make(map[string]Completion)
+		s.boards[board] = b
+	}
+	if old, ok := b[c.Player]; !ok || c.better(old) {
+		b[c.Player] = c
+	}
+	return nil
+}
+
+func (s *memCompletionStore) Fastest(_ context.Context, board string, limit int) ([]Completion, error) {
+	s.mu.Lock()
+	out := make([]Completion, 0, len(s.boards[board]))
+	for _, c := range s.boards[board] {
+		out = append(out, c)
+	}
+	s.mu.Unlock()
+	slices.SortFunc(out, func(a, b Completion) int {
+		if a.better(b) {
+			return -1
+		}
+		if b.better(a) {
+			return 1
+		}
+		return 0
+	})
+	return out[:min(limit, len(out))], nil
+}
+
+// sqlCompletionStore keeps the leaderboards in the SQLite database that holds
+// the puzzles.
+type sqlCompletionStore struct{ db *sql.DB }
+
+func openSQLiteCompletions(path string) (*sqlCompletionStore, error) {
+	db, err := openSQLite(path, `CREATE TABLE IF NOT EXISTS completions (
+	board     TEXT NOT NULL,
+	player    TEXT NOT NULL,
+	seconds   INTEGER NOT NULL,
+	hints     INTEGER NOT NULL,
+	mistakes  INTEGER NOT NULL,
+	completed INTEGER NOT NULL, -- unix nanoseconds
+	PRIMARY KEY (board, player)
+)`)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlCompletionStore{db}, nil
+}
+
+func (s *sqlCompletionStore) AddCompletion(ctx context.Context, board string, c Completion) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO completions VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (board, player) DO UPDATE SET
+	seconds = excluded.seconds, hints = excluded.hints, mistakes = excluded.mistakes, completed = excluded.completed
+WHERE (excluded.seconds, excluded.hints, excluded.mistakes, excluded.completed) <
+	(completions.seconds, completions.hints, completions.mistakes, completions.completed)`,
+		board, c.Player, c.Seconds, c.Hints, c.Mistakes, c.Completed.UnixNano())
+	return err
+}
+
+func (s *sqlCompletionStore) Fastest(ctx context.Context, board string, limit int) ([]Completion, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT player, seconds, hints, mistakes, completed FROM completions
+WHERE board = ? ORDER BY seconds, hints, mistakes, completed LIMIT ?`, board, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Completion{}
+	for rows.Next() {
+		var c Completion
+		var completed int64
+		if err := rows.Scan(&c.Player, &c.Seconds, &c.Hints, &c.Mistakes, &completed); err != nil {
+			return nil, err
+		}
+		c.Completed = time.Unix(0, completed).UTC()
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (s *sqlCompletionStore) Ping(ctx context.Context) error { return s.db.PingContext(ctx) }
+
+func (s *sqlCompletionStore) Close() error { return s.db.Close() }
+
+// completionStoreFor picks the leaderboard store the way puzzleStoreFor picks
+// the puzzle store, sharing its database.
+func completionStoreFor(o Options) (CompletionStore, error) {
+	switch {
+	case o.Completions != nil:
+		return o.Completions, nil
+	case o.Database != "":
+		return openSQLiteCompletions(o.Database)
+	}
+	return newMemCompletionStore(), nil
+}
+
+// dailyBoard and difficultyBoard name the leaderboards: one per daily puzzle
+// and one per difficulty and grid size.
+func dailyBoard(day time.Time) string { return "daily/" + day.Format(time.DateOnly) }
+
+func difficultyBoard(d sudoku.Difficulty, size int) string {
+	if size == 9 {
+		return "difficulty/" + string(d)
+	}
+	return fmt.Sprintf("difficulty/%s/%dx%d", d, size, size)
+}
+
+// handleCompletions records a finished game of the puzzle of the day (date)
+// or of a stored puzzle (puzzleId). The submitted solution must be the
+// puzzle's, and the time, hints and mistakes must be plausible for it. These
+// checks keep out bogus entries, not determined cheats: anyone can solve a
+// puzzle with /solve and submit a believable time.
+func (a *api) handleCompletions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, errMsg("method not allowed"))
+		return
+	}
+	var req struct {
+		PuzzleID string          `json:"puzzleId"`
+		Date     string          `json:"date"`
+		Player   string          `json:"player"`
+		Solution json.RawMessage `json:"solution"` // 2D array or, for 9x9, 81 characters
+		Seconds  int             `json:"seconds"`
+		Hints    int             `json:"hints"`
+		Mistakes int             `json:"mistakes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errMsg("invalid json"))
+		return
+	}
+	player := strings.TrimSpace(req.Player)
+	if player == "" || len([]rune(player)) > maxPlayerName || strings.IndexFunc(player, func(r rune) bool { return !unicode.IsPrint(r) }) >= 0 {
+		writeJSON(w, http.StatusBadRequest, errMsg(fmt.Sprintf("player must be 1 to %d printable characters", maxPlayerName)))
+		return
+	}
+	var board string
+	var givens, solution [][]int
+	var created time.Time // zero for daily puzzles
+	switch {
+	case req.Date != "" && req.PuzzleID != "":
+		writeJSON(w, http.StatusBadRequest, errMsg("give either date or puzzleId"))
+		return
+	case req.Date != "":
+		day, err := time.Parse(time.DateOnly, req.Date)
+		if err != nil || day.After(time.Now().UTC()) {
+			writeJSON(w, http.StatusBadRequest, errMsg("invalid date"))
+			return
+		}
+		puz, ok := a.daily(w, r, day)
+		if !ok {
+			return
+		}
+		sol, _ := sudoku.Solve(puz)
+		board, givens, solution = dailyBoard(day), puz.ToGrid().Cells, sol.ToGrid().Cells
+	case req.PuzzleID != "":
+		p, err := a.puzzles.LoadPuzzle(r.Context(), req.PuzzleID)
+		if err != nil {
+			writeJSON(w, http.StatusNotFound, errMsg("puzzle not found"))
+			return
+		}
+		if p.Revealed {
+			writeJSON(w, http.StatusUnprocessableEntity, errMsg("the solution was revealed; puzzle not eligible for leaderboards"))
+			return
+		}
+		board, givens, solution = difficultyBoard(p.Difficulty, p.Puzzle.Size), p.Puzzle.Cells, p.Solution.Cells
+		created = p.Created
+	default:
+		writeJSON(w, http.StatusBadRequest, errMsg("missing date or puzzleId"))
+		return
+	}
+	if got, err := decodeCells(req.Solution); err != nil || !slices.EqualFunc(got, solution, slices.Equal) {
+		writeJSON(w, http.StatusUnprocessableEntity, errMsg("solution does not solve the puzzle"))
+		return
+	}
+	empty := 0
+	for _, row := range givens {
+		for _, v := range row {
+			if v == 0 {
+				empty++
+			}
+		}
+	}
+	// nobody fills a cell in under a second, and every hint fills one
+	if req.Seconds < empty || req.Seconds > maxGameSeconds || req.Hints < 0 || req.Hints > empty || req.Mistakes < 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, errMsg("implausible time, hints or mistakes"))
+		return
+	}
+	// nor can a stored puzzle be finished sooner after the server handed it out
+	if !created.IsZero() && time.Since(created) < time.Duration(empty)*time.Second {
+		writeJSON(w, http.StatusUnprocessableEntity, errMsg("submitted sooner than the puzzle can be solved"))
+		return
+	}
+	c := Completion{Player: player, Seconds: req.Seconds, Hints: req.Hints, Mistakes: req.Mistakes, Completed: time.Now().UTC()}
+	if err := a.completions.AddCompletion(r.Context(), board, c); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errMsg("storage failed"))
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"board": board, "completion": c})
+}
+
+// decodeCells reads a board given as rows of values or an 81-character string.
+func decodeCells(raw json.RawMessage) ([][]int, error) {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		b, err := sudoku.FromString(s)
+		if err != nil {
+			return nil, err
+		}
+		return b.ToGrid().Cells, nil
+	}
+	var rows [][]int
+	err := json.Unmarshal(raw, &rows)
+	return rows, err
+}
+
+// handleLeaderboard lists the fastest completions of the daily puzzle of ?date=
+// or of ?difficulty= (with ?size= for variable grids), up to ?limit= (default 10).
+func (a *api) handleLeaderboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeJSON(w, http.StatusMethodNotAllowed, errMsg("method not allowed"))
+		return
+	}
+	q := r.URL.Query()
+	var board string
+	switch {
+	case q.Get("date") != "":
+		day, err := time.Parse(time.DateOnly, q.Get("date"))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errMsg("invalid date"))
+			return
+		}
+		board = dailyBoard(day)
+	case q.Get("difficulty") != "":
+		d, ok := parseDifficulty(q.Get("difficulty"))
+		size, err := strconv.Atoi(q.Get("size"))
+		if q.Get("size") == "" {
+			size, err = 9, nil
+		}
+		if !ok || err != nil || size < 1 || size > sudoku.MaxGridSize {
+			writeJSON(w, http.StatusBadRequest, errMsg("invalid difficulty or size"))
+			return
+		}
+		board = difficultyBoard(d, size)
+	default:
+		writeJSON(w, http.StatusBadRequest, errMsg("missing date or difficulty"))
+		return
+	}
+	limit := 10
+	if v := q.Get("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > maxLeaderboardLimit {
+			writeJSON(w, http.StatusBadRequest, errMsg("limit must be between 1 and 100"))
+			return
+		}
+	}
+	top, err := a.completions.Fastest(r.Context(), board, limit)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errMsg("storage failed"))
+		return
+	}
+	type entry struct {
+		Rank int `json:"rank"`
+		Completion
+	}
+	entries := make([]entry, len(top))
+	for i, c := range top {
+		entries[i] = entry{i + 1, c}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"board": board, "entries": entries})
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.rumenx.com/sudoku"
+)
+
+func TestCompletionStores(t *testing.T) {
+	db, err := openSQLiteCompletions(filepath.Join(t.TempDir(), "lb.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	at := time.Unix(1000, 0).UTC()
+	for name, s := range map[string]CompletionStore{"memory": newMemCompletionStore(), "sqlite": db} {
+		for _, c := range []Completion{
+			{Player: "ann", Seconds: 300, Completed: at},
+			{Player: "bob", Seconds: 200, Hints: 2, Completed: at},
+			{Player: "ann", Seconds: 400, Completed: at}, // slower, ignored
+			{Player: "cy", Seconds: 200, Hints: 1, Completed: at},
+			{Player: "ann", Seconds: 250, Completed: at.Add(time.Hour)},
+		} {
+			if err := s.AddCompletion(ctx, "daily/2024-01-02", c); err != nil {
+				t.Fatalf("%s: add: %v", name, err)
+			}
+		}
+		s.AddCompletion(ctx, "difficulty/easy", Completion{Player: "dee", Seconds: 100, Completed: at})
+
+		top, err := s.Fastest(ctx, "daily/2024-01-02", 10)
+		if err != nil {
+			t.Fatalf("%s: fastest: %v", name, err)
+		}
+		var got []string
+		for _, c := range top {
+			got = append(got, fmt.Sprintf("%s:%d", c.Player, c.Seconds))
+		}
+		if fmt.Sprint(got) != "[cy:200 bob:200 ann:250]" || !top[2].Completed.Equal(at.Add(time.Hour)) {
+			t.Errorf("%s: fastest = %v", name, top)
+		}
+		if top, _ := s.Fastest(ctx, "daily/2024-01-02", 1); len(top) != 1 {
+			t.Errorf("%s: limit ignored: %v", name, top)
+		}
+		if top, _ := s.Fastest(ctx, "daily/2024-01-03", 5); len(top) != 0 {
+			t.Errorf("%s: empty board = %v", name, top)
+		}
+	}
+}
+
+func TestLeaderboardHTTP(t *testing.T) {
+	h, a, err := newHandler(Options{Quiet: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(h)
+	t.Cleanup(ts.Close)
+
+	post := func(body any) (int, map[string]any) {
+		t.Helper()
+		raw, _ := json.Marshal(body)
+		resp, err := http.Post(ts.URL+"/completions", "application/json", bytes.NewReader(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var out map[string]any
+		json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out
+	}
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	puz, err := sudoku.Daily(day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sol, _ := sudoku.Solve(puz)
+	wrong := sol
+	wrong[0][0], wrong[0][1] = wrong[0][1], wrong[0][0]
+
+	for _, tc := range []struct {
+		name string
+		body map[string]any
+		want int
+	}{
+		{"ok", map[string]any{"date": "2024-01-02", "player": "ann", "solution": sol.String(), "seconds": 600}, http.StatusCreated},
+		{"rows", map[string]any{"date": "2024-01-02", "player": "bob", "solution": sol.ToGrid().Cells, "seconds": 500, "hints": 1}, http.StatusCreated},
+		{"wrong solution", map[string]any{"date": "2024-01-02", "player": "cy", "solution": wrong.String(), "seconds": 600}, http.StatusUnprocessableEntity},
+		{"too fast", map[string]any{"date": "2024-01-02", "player": "cy", "solution": sol.String(), "seconds": 3}, http.StatusUnprocessableEntity},
+		{"no player", map[string]any{"date": "2024-01-02", "solution": sol.String(), "seconds": 600}, http.StatusBadRequest},
+		{"no puzzle", map[string]any{"player": "cy", "solution": sol.String(), "seconds": 600}, http.StatusBadRequest},
+		{"unknown puzzle", map[string]any{"puzzleId": "nope", "player": "cy", "solution": sol.String(), "seconds": 600}, http.StatusNotFound},
+	} {
+		if code, out := post(tc.body); code != tc.want {
+			t.Errorf("%s: status %d, want %d (%v)", tc.name, code, tc.want, out)
+		}
+	}
+
+	get := func(query string) (int, []Completion) {
+		t.Helper()
+		resp, err := http.Get(ts.URL + "/leaderboard?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var out struct {
+			Entries []Completion `json:"entries"`
+		}
+		json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out.Entries
+	}
+	if code, top := get("date=2024-01-02"); code != http.StatusOK || len(top) != 2 || top[0].Player != "bob" || top[1].Player != "ann" {
+		t.Fatalf("daily leaderboard: %d %+v", code, top)
+	}
+
+	// a stored puzzle lands on its difficulty's board
+	resp, err := http.Post(ts.URL+"/generate", "application/json", bytes.NewBufferString(`{"difficulty":"easy"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gen struct {
+		ID string `json:"id"`
+	}
+	json.NewDecoder(resp.Body).Decode(&gen)
+	resp.Body.Close()
+	rec, err := a.puzzles.LoadPuzzle(context.Background(), gen.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	finish := map[string]any{"puzzleId": gen.ID, "player": "dee", "solution": rec.Solution.Cells, "seconds": 900}
+	if code, _ := post(finish); code != http.StatusUnprocessableEntity {
+		t.Fatalf("finished right after generation: %d", code)
+	}
+	rec.Created = rec.Created.Add(-time.Hour) // handed out an hour ago
+	if err := a.puzzles.SavePuzzle(context.Background(), rec); err != nil {
+		t.Fatal(err)
+	}
+	if code, out := post(finish); code != http.StatusCreated || out["board"] != "difficulty/easy" {
+		t.Fatalf("stored puzzle: %d %v", code, out)
+	}
+	rec.ID, rec.Revealed = "seen", true
+	if err := a.puzzles.SavePuzzle(context.Background(), rec); err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := post(map[string]any{"puzzleId": "seen", "player": "eve", "solution": rec.Solution.Cells, "seconds": 900}); code != http.StatusUnprocessableEntity {
+		t.Fatalf("revealed puzzle accepted: %d", code)
+	}
+	if code, top := get("difficulty=easy&limit=5"); code != http.StatusOK || len(top) != 1 || top[0].Player != "dee" {
+		t.Fatalf("difficulty leaderboard: %d %+v", code, top)
+	}
+	for _, q := range []string{"", "date=yesterday", "difficulty=insane", "difficulty=easy&limit=0"} {
+		if code, _ := get(q); code != http.StatusBadRequest {
+			t.Errorf("?%s: status %d", q, code)
+		}
+	}
+}
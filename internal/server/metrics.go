@@ -0,0 +1,234 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// latencyKey groups samples by operation, difficulty and grid size.
+type latencyKey struct {
+	Op         string
+	Difficulty string
+	Size       int
+}
+
+func (k latencyKey) String() string { return fmt.Sprintf("%s/%s/%d", k.Op, k.Difficulty, k.Size) }
+
+// latencyBuckets are the upper bounds, in seconds, of the Prometheus histogram
+// buckets.
+var latencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// latencyTracker keeps the most recent samples per key in a ring buffer, so
+// quantiles reflect current behaviour and memory stays bounded. It also counts
+// every sample into cumulative histogram buckets for Prometheus.
+type latencyTracker struct {
+	mu      sync.Mutex
+	window  int
+	samples map[latencyKey]*ring
+}
+
+type ring struct {
+	vals    []time.Duration
+	next    int
+	total   int // all observations, including evicted ones
+	sum     time.Duration
+	buckets []int // observations per latencyBuckets bound, not cumulative
+}
+
+func newLatencyTracker(window int) *latencyTracker {
+	return &latencyTracker{window: window, samples: make(map[latencyKey]*ring)}
+}
+
+// Observe records one latency sample.
+func (t *latencyTracker) Observe(k latencyKey, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	rg := t.samples[k]
+	if rg == nil {
+		rg = &ring{buckets: make([]int, len(latencyBuckets))}
+		t.samples[k] = rg
+	}
+	if len(rg.vals) < t.window {
+		rg.vals = append(rg.vals, d)
+	} else {
+		rg.vals[rg.next] = d
+		rg.next = (rg.next + 1) % t.window
+	}
+	rg.total++
+	rg.sum += d
+	if i := sort.SearchFloat64s(latencyBuckets, d.Seconds()); i < len(latencyBuckets) {
+		rg.buckets[i]++
+	}
+}
+
+// latencySummary is the JSON shape of one key's quantiles, in milliseconds.
+type latencySummary struct {
+	Op         string  `json:"op"`
+	Difficulty string  `json:"difficulty"`
+	Size       int     `json:"size"`
+	Count      int     `json:"count"`
+	Window     int     `json:"window"`
+	P50        float64 `json:"p50_ms"`
+	P95        float64 `json:"p95_ms"`
+	P99        float64 `json:"p99_ms"`
+}
+
+// Summary returns quantiles for every key, ordered by key.
+func (t *latencyTracker) Summary() []latencySummary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]latencySummary, 0, len(t.samples))
+	for _, k := range t.sortedKeys() {
+		rg := t.samples[k]
+		vals := append([]time.Duration(nil), rg.vals...)
+		sort.Slice(vals, func(i, j int) bool { return vals[i] < vals[j] })
+		out = append(out, latencySummary{
+			Op: k.Op, Difficulty: k.Difficulty, Size: k.Size,
+			Count: rg.total, Window: len(vals),
+			P50: quantileMS(vals, 0.50), P95: quantileMS(vals, 0.95), P99: quantileMS(vals, 0.99),
+		})
+	}
+	return out
+}
+
+// sortedKeys returns the tracked keys ordered by op, difficulty and size.
+// The caller holds t.mu.
+func (t *latencyTracker) sortedKeys() []latencyKey {
+	keys := make([]latencyKey, 0, len(t.samples))
+	for k := range t.samples {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.Op != b.Op {
+			return a.Op < b.Op
+		}
+		if a.Difficulty != b.Difficulty {
+			return a.Difficulty < b.Difficulty
+		}
+		return a.Size < b.Size
+	})
+	return keys
+}
+
+// WritePrometheus writes every key as a sudoku_request_duration_seconds
+// histogram in the Prometheus text format. Unlike Summary it covers all
+// observations, not just the window.
+func (t *latencyTracker) WritePrometheus(w io.Writer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	const name = "sudoku_request_duration_seconds"
+	fmt.Fprintf(w, "# HELP %s Generate and solve latency by operation, difficulty and grid size.\n", name)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	for _, k := range t.sortedKeys() {
+		rg := t.samples[k]
+		labels := fmt.Sprintf("op=%q,difficulty=%q,size=\"%d\"", k.Op, k.Difficulty, k.Size)
+		n := 0
+		for i, le := range latencyBuckets {
+			n += rg.buckets[i]
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"%g\"} %d\n", name, labels, le, n)
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, rg.total)
+		fmt.Fprintf(w, "%s_sum{%s} %g\n", name, labels, rg.sum.Seconds())
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, labels, rg.total)
+	}
+}
+
+// quantileMS returns the nearest-rank q-quantile of sorted in milliseconds.
+func quantileMS(sorted []time.Duration, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(q*float64(len(sorted))+0.999999) - 1
+	i = min(max(i, 0), len(sorted)-1)
+	return float64(sorted[i]) / float64(time.Millisecond)
+}
+
+// slaThresholds maps an operation to its maximum allowed p95 and p99.
+type slaThresholds map[string]struct{ P95, P99 time.Duration }
+
+// slaFromEnv reads SUDOKU_SLA_P95 and SUDOKU_SLA_P99, each a comma-separated list
+// of op=duration pairs (e.g. "generate=500ms,solve=50ms").
+func slaFromEnv(getenv func(string) string) (slaThresholds, error) {
+	th := slaThresholds{}
+	for _, f := range []struct {
+		env string
+		p99 bool
+	}{{"SUDOKU_SLA_P95", false}, {"SUDOKU_SLA_P99", true}} {
+		v := getenv(f.env)
+		if v == "" {
+			continue
+		}
+		for _, pair := range strings.Split(v, ",") {
+			op, ds, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			d, err := time.ParseDuration(ds)
+			if !ok || op == "" || err != nil || d <= 0 {
+				return nil, fmt.Errorf("%s: invalid entry %q", f.env, pair)
+			}
+			lim := th[op]
+			if f.p99 {
+				lim.P99 = d
+			} else {
+				lim.P95 = d
+			}
+			th[op] = lim
+		}
+	}
+	return th, nil
+}
+
+// breaches lists the summaries whose quantiles exceed the thresholds of their op.
+func (th slaThresholds) breaches(sum []latencySummary) []string {
+	out := []string{}
+	for _, s := range sum {
+		lim, ok := th[s.Op]
+		if !ok {
+			continue
+		}
+		key := latencyKey{s.Op, s.Difficulty, s.Size}.String()
+		if lim.P95 > 0 && s.P95 > float64(lim.P95)/float64(time.Millisecond) {
+			out = append(out, fmt.Sprintf("%s p95 %.1fms > %s", key, s.P95, lim.P95))
+		}
+		if lim.P99 > 0 && s.P99 > float64(lim.P99)/float64(time.Millisecond) {
+			out = append(out, fmt.Sprintf("%s p99 %.1fms > %s", key, s.P99, lim.P99))
+		}
+	}
+	return out
+}
+
+// handleMetrics serves the latency histograms for Prometheus to scrape.
+func (a *api) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeJSON(w, http.StatusMethodNotAllowed, errMsg("method not allowed"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	a.latencies.WritePrometheus(w)
+}
+
+// handleSLA serves the latency summary. With thresholds configured it responds 503
+// while any is breached, so cron monitors can simply check the status code.
+func (a *api) handleSLA(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeJSON(w, http.StatusMethodNotAllowed, errMsg("method not allowed"))
+		return
+	}
+	sum := a.latencies.Summary()
+	br := a.slaLimits.breaches(sum)
+	status := http.StatusOK
+	if len(br) > 0 {
+		status = http.StatusServiceUnavailable
+	}
+	res := map[string]any{"ok": len(br) == 0, "breaches": br, "latencies": sum}
+	if a.shadow != nil {
+		res["shadow"] = a.shadow.Stats()
+	}
+	writeJSON(w, status, res)
+}
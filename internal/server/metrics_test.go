@@ -0,0 +1,87 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLatencyTrackerQuantiles(t *testing.T) {
+	lt := newLatencyTracker(100)
+	k := latencyKey{"generate", "hard", 9}
+	for i := 1; i <= 150; i++ { // first 50 samples are evicted
+		lt.Observe(k, time.Duration(i)*time.Millisecond)
+	}
+	lt.Observe(latencyKey{"solve", "any", 9}, time.Millisecond)
+	sum := lt.Summary()
+	if len(sum) != 2 || sum[0].Op != "generate" {
+		t.Fatalf("unexpected summary %+v", sum)
+	}
+	g := sum[0]
+	if g.Count != 150 || g.Window != 100 || g.P50 != 100 || g.P95 != 145 || g.P99 != 149 {
+		t.Fatalf("unexpected quantiles %+v", g)
+	}
+}
+
+func TestMetricsHistogram(t *testing.T) {
+	a := newAPI()
+	a.latencies = newLatencyTracker(2)
+	for _, d := range []time.Duration{3 * time.Millisecond, 20 * time.Millisecond, 30 * time.Second} {
+		a.latencies.Observe(latencyKey{"generate", "easy", 6}, d) // the window keeps two, the histogram all
+	}
+	a.latencies.Observe(latencyKey{"solve", "any", 9}, time.Millisecond)
+	rec := httptest.NewRecorder()
+	a.handleMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("status %d, content type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE sudoku_request_duration_seconds histogram\n",
+		`sudoku_request_duration_seconds_bucket{op="generate",difficulty="easy",size="6",le="0.001"} 0` + "\n",
+		`sudoku_request_duration_seconds_bucket{op="generate",difficulty="easy",size="6",le="0.005"} 1` + "\n",
+		`sudoku_request_duration_seconds_bucket{op="generate",difficulty="easy",size="6",le="10"} 2` + "\n",
+		`sudoku_request_duration_seconds_bucket{op="generate",difficulty="easy",size="6",le="+Inf"} 3` + "\n",
+		`sudoku_request_duration_seconds_sum{op="generate",difficulty="easy",size="6"} 30.023` + "\n",
+		`sudoku_request_duration_seconds_count{op="generate",difficulty="easy",size="6"} 3` + "\n",
+		`sudoku_request_duration_seconds_bucket{op="solve",difficulty="any",size="9",le="0.001"} 1` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %q in\n%s", want, body)
+		}
+	}
+}
+
+func TestSLAEndpointBreaches(t *testing.T) {
+	t.Setenv("SUDOKU_SLA_P95", "generate=10ms")
+	t.Setenv("SUDOKU_SLA_P99", "generate=1s,solve=5ms")
+	th, err := slaFromEnv(os.Getenv)
+	if err != nil {
+		t.Fatalf("sla: %v", err)
+	}
+	if th["generate"].P95 != 10*time.Millisecond || th["generate"].P99 != time.Second || th["solve"].P99 != 5*time.Millisecond {
+		t.Fatalf("unexpected thresholds %+v", th)
+	}
+	a := newAPI()
+	a.latencies, a.slaLimits = newLatencyTracker(10), th
+
+	check := func(want int) {
+		rec := httptest.NewRecorder()
+		a.handleSLA(rec, httptest.NewRequest(http.MethodGet, "/metrics/sla", nil))
+		if rec.Code != want {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, want, rec.Body)
+		}
+	}
+	a.latencies.Observe(latencyKey{"generate", "easy", 9}, 5*time.Millisecond)
+	check(http.StatusOK)
+	a.latencies.Observe(latencyKey{"generate", "easy", 9}, 50*time.Millisecond)
+	check(http.StatusServiceUnavailable)
+
+	t.Setenv("SUDOKU_SLA_P95", "generate")
+	if _, err := slaFromEnv(os.Getenv); err == nil {
+		t.Fatalf("expected invalid entry error")
+	}
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"html"
+	"html/template"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// openAPISpec describes every endpoint; keep it in step with the handlers
+// (TestOpenAPICoversRoutes checks the routes and their methods).
+//
+//go:embed web/openapi.json
+var openAPISpec []byte
+
+//go:embed web/docs.html
+var docsTemplate string
+
+// specDoc is the part of openAPISpec the docs page shows.
+type specDoc struct {
+	Info struct {
+		Title, Version, Description string
+	}
+	Paths      map[string]map[string]specOp
+	Components struct {
+		Parameters map[string]specParam
+		Responses  map[string]specResponse
+		Schemas    map[string]json.RawMessage
+	}
+}
+
+type specOp struct {
+	Summary, Description string
+	Parameters           []specParam
+	RequestBody          *specResponse
+	Responses            map[string]specResponse
+}
+
+type specParam struct {
+	Ref         string `json:"$ref"`
+	Name, In    string
+	Description string
+	Required    bool
+	Schema      json.RawMessage
+}
+
+// specResponse also holds request bodies, which have the same shape.
+type specResponse struct {
+	Ref         string `json:"$ref"`
+	Description string
+	Content     map[string]struct{ Schema json.RawMessage }
+}
+
+// docsPage renders openAPISpec as a self-contained HTML reference: no scripts
+// and nothing loaded from other hosts, so /docs works offline and behind
+// strict content security policies.
+var docsPage = sync.OnceValues(func() ([]byte, error) {
+	var spec specDoc
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New("docs").Parse(docsTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, docsView(spec)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+})
+
+type docsContent struct {
+	Type   string
+	Schema template.HTML
+}
+
+type docsBody struct {
+	Description string
+	Content     []docsContent
+}
+
+type docsResponse struct {
+	Code, Description string
+	Content           []docsContent
+}
+
+type docsOp struct {
+	ID, Method, Class, Path, Summary, Description string
+	Params                                        []docsParam
+	Body                                          *docsBody
+	Responses                                     []docsResponse
+}
+
+type docsParam struct {
+	Name, In, Description string
+	Required              bool
+	Schema                template.HTML
+}
+
+type docsSchema struct{ Name, JSON string }
+
+// docsView flattens spec into template data, resolving component references
+// and ordering paths, methods and status codes.
+func docsView(spec specDoc) map[string]any {
+	var ops []docsOp
+	for _, path := range slices.Sorted(maps.Keys(spec.Paths)) {
+		for _, method := range []string{"get", "post", "put", "patch", "delete"} {
+			op, ok := spec.Paths[path][method]
+			if !ok {
+				continue
+			}
+			class := method
+			if method != "get" && method != "post" && method != "delete" {
+				class = "other"
+			}
+			d := docsOp{
+				ID: method + strings.NewReplacer("/", "-", "{", "", "}", "").Replace(path), Method: method, Class: class,
+				Path: path, Summary: op.Summary, Description: op.Description,
+			}
+			for _, p := range op.Parameters {
+				if p.Ref != "" {
+					p = spec.Components.Parameters[refName(p.Ref)]
+				}
+				d.Params = append(d.Params, docsParam{p.Name, p.In, p.Description, p.Required, schemaHTML(p.Schema)})
+			}
+			if op.RequestBody != nil {
+				d.Body = &docsBody{op.RequestBody.Description, docsContents(op.RequestBody.Content)}
+			}
+			for _, code := range slices.Sorted(maps.Keys(op.Responses)) {
+				r := op.Responses[code]
+				if r.Ref != "" {
+					r = spec.Components.Responses[refName(r.Ref)]
+				}
+				d.Responses = append(d.Responses, docsResponse{code, r.Description, docsContents(r.Content)})
+			}
+			ops = append(ops, d)
+		}
+	}
+	var schemas []docsSchema
+	for _, name := range slices.Sorted(maps.Keys(spec.Components.Schemas)) {
+		var buf bytes.Buffer
+		_ = json.Indent(&buf, spec.Components.Schemas[name], "", "  ")
+		schemas = append(schemas, docsSchema{name, buf.String()})
+	}
+	return map[string]any{
+		"Title": spec.Info.Title, "Version": spec.Info.Version, "Description": spec.Info.Description,
+		"Ops": ops, "Schemas": schemas,
+	}
+}
+
+func docsContents(content map[string]struct{ Schema json.RawMessage }) []docsContent {
+	var out []docsContent
+	for _, typ := range slices.Sorted(maps.Keys(content)) {
+		out = append(out, docsContent{typ, schemaHTML(content[typ].Schema)})
+	}
+	return out
+}
+
+// schemaHTML links a schema reference to its entry under Schemas and prints
+// inline schemas as indented JSON.
+func schemaHTML(raw json.RawMessage) template.HTML {
+	if len(raw) == 0 {
+		return ""
+	}
+	var ref struct {
+		Ref string `json:"$ref"`
+	}
+	if json.Unmarshal(raw, &ref) == nil && ref.Ref != "" {
+		name := html.EscapeString(refName(ref.Ref))
+		return template.HTML(`<a href="#schema-` + name + `"><code>` + name + `</code></a>`)
+	}
+	var buf bytes.Buffer
+	_ = json.Indent(&buf, raw, "", "  ")
+	return template.HTML("<pre>" + html.EscapeString(buf.String()) + "</pre>")
+}
+
+// refName is the last element of a "#/components/..." reference.
+func refName(ref string) string { return ref[strings.LastIndex(ref, "/")+1:] }
+
+func handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeJSON(w, http.StatusMethodNotAllowed, errMsg("method not allowed"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	_, _ = w.Write(openAPISpec)
+}
+
+func handleDocs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeJSON(w, http.StatusMethodNotAllowed, errMsg("method not allowed"))
+		return
+	}
+	page, err := docsPage()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errMsg("docs unavailable"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	_, _ = w.Write(page)
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	var spec struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatalf("spec is not valid JSON: %v", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		t.Fatalf("openapi = %q", spec.OpenAPI)
+	}
+	h, a, err := newHandler(Options{Quiet: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := slices.Sorted(slices.Values(a.routes)), slices.Sorted(maps.Keys(spec.Paths)); !slices.Equal(got, want) {
+		t.Fatalf("mux routes %v\nspec paths %v", got, want)
+	}
+	// every documented method reaches its handler, and the others are refused
+	ts := httptest.NewServer(h)
+	t.Cleanup(ts.Close)
+	for path, ops := range spec.Paths {
+		url := ts.URL + strings.NewReplacer("{id}", "none").Replace(path)
+		for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodDelete} {
+			req, _ := http.NewRequest(method, url, strings.NewReader("{}"))
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			_, documented := ops[strings.ToLower(method)]
+			switch {
+			case documented && resp.StatusCode == http.StatusMethodNotAllowed:
+				t.Errorf("%s %s is documented but refused", method, path)
+			case documented && strings.HasPrefix(string(body), "404 page not found"):
+				t.Errorf("%s %s is documented but not routed", method, path)
+			case !documented && resp.StatusCode != http.StatusMethodNotAllowed:
+				t.Errorf("%s %s is not documented but answered %d", method, path, resp.StatusCode)
+			}
+		}
+	}
+}
+
+func TestOpenAPIEndpoints(t *testing.T) {
+	h, err := Handler(Options{Quiet: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, ctype := range map[string]string{"/openapi.json": "application/json", "/docs": "text/html"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), ctype) {
+			t.Errorf("%s: status %d, content type %q", path, rec.Code, rec.Header().Get("Content-Type"))
+		}
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	page := rec.Body.String()
+	for _, want := range []string{`<code>/generate</code>`, `href="#schema-GenerateRequest"`, `id="schema-GenerateRequest"`, "Idempotency-Key"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("docs page lacks %q", want)
+		}
+	}
+	if strings.Contains(page, "<script") || strings.Contains(page, "https://") {
+		t.Error("docs page loads external resources")
+	}
+}
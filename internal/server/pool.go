@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// defaultQueueWait is how long a generation request waits for a free worker
+// before it is turned away.
+const defaultQueueWait = 5 * time.Second
+
+// GenerationPool bounds the CPU-heavy work of the API: /generate and
+// /generate/batch, the first request for a day's /daily puzzle, variable-size
+// /solve, rating a /collections upload and every puzzle a /ws/game session
+// starts. Workers of them run at
+// once (default twice GOMAXPROCS) and the rest queue for up to QueueWait
+// (default 5s; negative rejects at once) before getting 429 with Retry-After,
+// or an error event on a game socket. A batch request holds one worker however
+// many generators it runs.
+type GenerationPool struct {
+	Workers   int
+	QueueWait time.Duration
+}
+
+// poolFromEnv reads SUDOKU_WORKERS and SUDOKU_QUEUE_WAIT (e.g. 2s; 0 rejects
+// at once).
+func poolFromEnv(getenv func(string) string) (GenerationPool, error) {
+	var p GenerationPool
+	if v := getenv("SUDOKU_WORKERS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return GenerationPool{}, fmt.Errorf("SUDOKU_WORKERS: invalid worker count %q", v)
+		}
+		p.Workers = n
+	}
+	if v := getenv("SUDOKU_QUEUE_WAIT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return GenerationPool{}, fmt.Errorf("SUDOKU_QUEUE_WAIT: invalid duration %q", v)
+		}
+		p.QueueWait = d
+		if d == 0 {
+			p.QueueWait = -1
+		}
+	}
+	return p, nil
+}
+
+// workerPool is a semaphore of generation slots.
+type workerPool struct {
+	slots chan struct{}
+	wait  time.Duration
+}
+
+func newWorkerPool(p GenerationPool) *workerPool {
+	if p.Workers < 1 {
+		p.Workers = 2 * runtime.GOMAXPROCS(0)
+	}
+	if p.QueueWait == 0 {
+		p.QueueWait = defaultQueueWait
+	}
+	return &workerPool{slots: make(chan struct{}, p.Workers), wait: max(p.QueueWait, 0)}
+}
+
+// busy reports whether every worker is taken.
+func (p *workerPool) busy() bool { return len(p.slots) == cap(p.slots) }
+
+// wrap runs next on a worker.
+func (p *workerPool) wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !p.acquire(w, r) {
+			return
+		}
+		defer p.release()
+		next(w, r)
+	}
+}
+
+// acquire takes a worker for r, queueing if all are busy. When it fails, r has
+// been answered with 429 or was canceled.
+func (p *workerPool) acquire(w http.ResponseWriter, r *http.Request) bool {
+	if p.take(r.Context()) {
+		return true
+	}
+	if r.Context().Err() == nil {
+		w.Header().Set("Retry-After", strconv.Itoa(max(1, int(math.Ceil(p.wait.Seconds())))))
+		writeJSON(w, http.StatusTooManyRequests, errMsg("all generation workers are busy"))
+	}
+	return false
+}
+
+// take takes a worker, queueing for up to the pool's wait; false means the
+// wait ran out or ctx ended first.
+func (p *workerPool) take(ctx context.Context) bool {
+	select {
+	case p.slots <- struct{}{}:
+		return true
+	default:
+	}
+	timer := time.NewTimer(p.wait)
+	defer timer.Stop()
+	select {
+	case p.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	return false
+}
+
+func (p *workerPool) release() { <-p.slots }
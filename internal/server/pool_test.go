@@ -0,0 +1,82 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWorkerPool(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}
+	serve := func(h http.HandlerFunc) chan int {
+		done := make(chan int, 1)
+		go func() {
+			rec := httptest.NewRecorder()
+			h(rec, httptest.NewRequest(http.MethodPost, "/generate", nil))
+			done <- rec.Code
+		}()
+		return done
+	}
+
+	// no queue: the second request is turned away while the worker is busy
+	p := newWorkerPool(GenerationPool{Workers: 1, QueueWait: -1})
+	first := serve(p.wrap(slow))
+	<-started
+	if !p.busy() {
+		t.Fatal("pool not busy")
+	}
+	rec := httptest.NewRecorder()
+	p.wrap(slow)(rec, httptest.NewRequest(http.MethodPost, "/generate", nil))
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "1" {
+		t.Fatalf("busy pool: %d Retry-After=%q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	release <- struct{}{}
+	if code := <-first; code != http.StatusOK || p.busy() {
+		t.Fatalf("first = %d, busy = %v", code, p.busy())
+	}
+
+	// with a queue the second request waits for the worker
+	p = newWorkerPool(GenerationPool{Workers: 1, QueueWait: time.Minute})
+	first = serve(p.wrap(slow))
+	<-started
+	second := serve(p.wrap(slow))
+	select {
+	case <-started:
+		t.Fatal("second request ran alongside the first")
+	case <-time.After(20 * time.Millisecond):
+	}
+	release <- struct{}{}
+	<-started
+	release <- struct{}{}
+	if a, b := <-first, <-second; a != http.StatusOK || b != http.StatusOK {
+		t.Fatalf("queued requests = %d, %d", a, b)
+	}
+}
+
+func TestPoolFromEnv(t *testing.T) {
+	t.Setenv("SUDOKU_WORKERS", "3")
+	t.Setenv("SUDOKU_QUEUE_WAIT", "0")
+	p, err := poolFromEnv(os.Getenv)
+	if err != nil || p.Workers != 3 || p.QueueWait >= 0 {
+		t.Fatalf("pool = %+v, %v", p, err)
+	}
+	if cap(newWorkerPool(p).slots) != 3 || newWorkerPool(p).wait != 0 {
+		t.Fatal("pool settings ignored")
+	}
+	for _, kv := range [][2]string{{"SUDOKU_WORKERS", "0"}, {"SUDOKU_QUEUE_WAIT", "soon"}} {
+		t.Setenv(kv[0], kv[1])
+		if _, err := poolFromEnv(os.Getenv); err == nil {
+			t.Errorf("%s=%s accepted", kv[0], kv[1])
+		}
+		t.Setenv("SUDOKU_WORKERS", "3")
+		t.Setenv("SUDOKU_QUEUE_WAIT", "0")
+	}
+}
@@ -0,0 +1,255 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+
+	"go.rumenx.com/sudoku"
+)
+
+// PuzzleRecord is a generated puzzle kept so GET /puzzles/{id} can return it
+// later. Classic puzzles are stored as 9x9 grids.
+type PuzzleRecord struct {
+	ID         string            `json:"id"`
+	Created    time.Time         `json:"created"`
+	Difficulty sudoku.Difficulty `json:"difficulty"`
+	Puzzle     sudoku.Grid       `json:"puzzle"`
+	Solution   sudoku.Grid       `json:"solution"`
+	Rating     *sudoku.Rating    `json:"rating,omitempty"`   // classic puzzles only
+	Revealed   bool              `json:"revealed,omitempty"` // the solution was handed out; not eligible for leaderboards
+}
+
+// PuzzleStore persists generated puzzles. Implementations must be safe for
+// concurrent use.
+type PuzzleStore interface {
+	SavePuzzle(ctx context.Context, p PuzzleRecord) error
+	// LoadPuzzle returns errPuzzleNotFound for unknown and expired ids.
+	LoadPuzzle(ctx context.Context, id string) (PuzzleRecord, error)
+	// DeletePuzzle hides id from LoadPuzzle at once; the janitor removes it
+	// for good after the grace period. Unknown ids give errPuzzleNotFound.
+	DeletePuzzle(ctx context.Context, id string) error
+}
+
+var errPuzzleNotFound = errors.New("puzzle not found")
+
+// Puzzles kept in memory expire after a day unless SUDOKU_RETENTION_PUZZLES
+// says otherwise, and the oldest go first beyond maxMemPuzzles, so a busy
+// /generate cannot exhaust memory. Only SQLite keeps puzzles forever.
+const (
+	defaultMemPuzzleTTL = 24 * time.Hour
+	maxMemPuzzles       = 100_000
+)
+
+// memPuzzleStore keeps puzzles in memory until they expire or are evicted.
+type memPuzzleStore struct{ *memStore[PuzzleRecord] }
+
+// newMemPuzzleStore keeps puzzles for ttl, or defaultMemPuzzleTTL when ttl is
+// 0, and deleted ones for grace.
+func newMemPuzzleStore(ttl, grace time.Duration) memPuzzleStore {
+	return memPuzzleStore{newBoundedMemStore[PuzzleRecord](cmp.Or(ttl, defaultMemPuzzleTTL), grace, maxMemPuzzles)}
+}
+
+func (s memPuzzleStore) SavePuzzle(_ context.Context, p PuzzleRecord) error {
+	s.Put(p.ID, p)
+	return nil
+}
+
+func (s memPuzzleStore) LoadPuzzle(_ context.Context, id string) (PuzzleRecord, error) {
+	p, ok := s.Get(id)
+	if !ok {
+		return PuzzleRecord{}, errPuzzleNotFound
+	}
+	return p, nil
+}
+
+func (s memPuzzleStore) DeletePuzzle(_ context.Context, id string) error {
+	if !s.Delete(id) {
+		return errPuzzleNotFound
+	}
+	return nil
+}
+
+// sqlPuzzleStore keeps puzzles in a SQLite database as JSON, so they survive
+// restarts. Deleting a puzzle expires it; Purge deletes the rows expired for
+// longer than grace.
+type sqlPuzzleStore struct {
+	db    *sql.DB
+	ttl   time.Duration
+	grace time.Duration
+	now   func() time.Time
+}
+
+// openSQLitePuzzles opens (creating if needed) the database at path.
+func openSQLitePuzzles(path string, ttl time.Duration) (*sqlPuzzleStore, error) {
+	db, err := openSQLite(path, `CREATE TABLE IF NOT EXISTS puzzles (
+	id      TEXT PRIMARY KEY,
+	created INTEGER NOT NULL,
+	expires INTEGER NOT NULL, -- unix seconds, 0 for never
+	data    TEXT NOT NULL
+)`)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlPuzzleStore{db: db, ttl: ttl, now: time.Now}, nil
+}
+
+// openSQLite opens the database at path and applies schema. Stores open the
+// same file separately, so writers wait for each other instead of failing.
+func openSQLite(path, schema string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func (s *sqlPuzzleStore) SavePuzzle(ctx context.Context, p PuzzleRecord) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	var expires int64
+	if s.ttl > 0 {
+		expires = s.now().Add(s.ttl).Unix()
+	}
+	_, err = s.db.ExecContext(ctx, `INSERT OR REPLACE INTO puzzles (id, created, expires, data) VALUES (?, ?, ?, ?)`,
+		p.ID, p.Created.Unix(), expires, string(data))
+	return err
+}
+
+func (s *sqlPuzzleStore) LoadPuzzle(ctx context.Context, id string) (PuzzleRecord, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM puzzles WHERE id = ? AND (expires = 0 OR expires > ?)`,
+		id, s.now().Unix()).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return PuzzleRecord{}, errPuzzleNotFound
+	}
+	if err != nil {
+		return PuzzleRecord{}, err
+	}
+	var p PuzzleRecord
+	err = json.Unmarshal([]byte(data), &p)
+	return p, err
+}
+
+func (s *sqlPuzzleStore) DeletePuzzle(ctx context.Context, id string) error {
+	now := s.now().Unix()
+	res, err := s.db.ExecContext(ctx, `UPDATE puzzles SET expires = ? WHERE id = ? AND (expires = 0 OR expires > ?)`, now, id, now)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return cmp.Or(err, errPuzzleNotFound)
+	}
+	return nil
+}
+
+func (s *sqlPuzzleStore) Purge() int {
+	res, err := s.db.Exec(`DELETE FROM puzzles WHERE expires != 0 AND expires <= ?`, s.now().Add(-s.grace).Unix())
+	if err != nil {
+		return 0
+	}
+	n, _ := res.RowsAffected()
+	return int(n)
+}
+
+func (s *sqlPuzzleStore) Ping(ctx context.Context) error { return s.db.PingContext(ctx) }
+
+func (s *sqlPuzzleStore) Close() error { return s.db.Close() }
+
+// puzzleStoreFor picks the store for o: o.PuzzleStore, else a SQLite database
+// at o.Database (or $SUDOKU_DB), else memory. Puzzles expire after
+// SUDOKU_RETENTION_PUZZLES (default never in SQLite, a day in memory), and
+// deleted ones are purged after SUDOKU_RETENTION_GRACE.
+func puzzleStoreFor(o Options, ret retention) (PuzzleStore, error) {
+	switch {
+	case o.PuzzleStore != nil:
+		return o.PuzzleStore, nil
+	case o.Database != "":
+		s, err := openSQLitePuzzles(o.Database, ret.Puzzles)
+		if err != nil {
+			return nil, err
+		}
+		s.grace = ret.Grace
+		return s, nil
+	}
+	return newMemPuzzleStore(ret.Puzzles, ret.Grace), nil
+}
+
+// savePuzzle stores a freshly generated puzzle under a new random id, marked
+// revealed when its solution goes out with it.
+func (a *api) savePuzzle(ctx context.Context, d sudoku.Difficulty, puz, sol sudoku.Grid, rating *sudoku.Rating, revealed bool) (PuzzleRecord, error) {
+	p := PuzzleRecord{ID: newCollectionID(), Created: time.Now().UTC(), Difficulty: d, Puzzle: puz, Solution: sol, Rating: rating, Revealed: revealed}
+	return p, a.puzzles.SavePuzzle(ctx, p)
+}
+
+// handlePuzzle returns a puzzle stored by /generate with, for classic puzzles,
+// its rating. The solution is only included for ?includeSolution=true, which
+// takes the puzzle off the leaderboards. DELETE removes it.
+func (a *api) handlePuzzle(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodDelete:
+		err := a.puzzles.DeletePuzzle(r.Context(), r.PathValue("id"))
+		switch {
+		case errors.Is(err, errPuzzleNotFound):
+			writeJSON(w, http.StatusNotFound, errMsg("puzzle not found"))
+		case err != nil:
+			writeJSON(w, http.StatusInternalServerError, errMsg("storage failed"))
+		default:
+			a.generated.drop(r.PathValue("id"))
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return
+	default:
+		w.Header().Set("Allow", "GET, DELETE")
+		writeJSON(w, http.StatusMethodNotAllowed, errMsg("method not allowed"))
+		return
+	}
+	p, err := a.puzzles.LoadPuzzle(r.Context(), r.PathValue("id"))
+	if errors.Is(err, errPuzzleNotFound) {
+		writeJSON(w, http.StatusNotFound, errMsg("puzzle not found"))
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errMsg("storage failed"))
+		return
+	}
+	includeSolution := r.URL.Query().Get("includeSolution") == "true"
+	if includeSolution && !p.Revealed {
+		p.Revealed = true
+		// drop it from the /generate cache first, so a cache hit cannot save
+		// it again as unrevealed
+		a.generated.drop(p.ID)
+		if err := a.puzzles.SavePuzzle(r.Context(), p); err != nil {
+			writeJSON(w, http.StatusInternalServerError, errMsg("storage failed"))
+			return
+		}
+	}
+	res := map[string]any{
+		"id":         p.ID,
+		"created":    p.Created,
+		"difficulty": p.Difficulty,
+		"puzzle":     p.Puzzle.Cells,
+		"grid":       p.Puzzle,
+		"revealed":   p.Revealed,
+	}
+	if includeSolution {
+		res["solution"] = p.Solution.Cells
+	}
+	if p.Rating != nil {
+		res["rating"] = p.Rating
+	}
+	writeJSON(w, http.StatusOK, res)
+}
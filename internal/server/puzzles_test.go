@@ -0,0 +1,192 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func testPuzzleRecord(t *testing.T) PuzzleRecord {
+	t.Helper()
+	b := sudokutest.MustBoard(sudokutest.Easy)
+	sol, _ := sudoku.Solve(b)
+	rating, _ := sudoku.Rate(b)
+	return PuzzleRecord{ID: "abc123", Created: time.Unix(1000, 0).UTC(), Difficulty: sudoku.Easy,
+		Puzzle: b.ToGrid(), Solution: sol.ToGrid(), Rating: &rating}
+}
+
+func TestPuzzleStores(t *testing.T) {
+	db, err := openSQLitePuzzles(filepath.Join(t.TempDir(), "puzzles.db"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	want := testPuzzleRecord(t)
+	for name, s := range map[string]PuzzleStore{"memory": newMemPuzzleStore(0, 0), "sqlite": db} {
+		if _, err := s.LoadPuzzle(ctx, want.ID); !errors.Is(err, errPuzzleNotFound) {
+			t.Errorf("%s: load before save: %v", name, err)
+		}
+		if err := s.SavePuzzle(ctx, want); err != nil {
+			t.Fatalf("%s: save: %v", name, err)
+		}
+		got, err := s.LoadPuzzle(ctx, want.ID)
+		if err != nil || got.Puzzle.String() != want.Puzzle.String() || got.Solution.String() != want.Solution.String() ||
+			*got.Rating != *want.Rating || !got.Created.Equal(want.Created) {
+			t.Errorf("%s: load = %+v, %v", name, got, err)
+		}
+		if err := s.DeletePuzzle(ctx, want.ID); err != nil {
+			t.Errorf("%s: delete: %v", name, err)
+		}
+		if _, err := s.LoadPuzzle(ctx, want.ID); !errors.Is(err, errPuzzleNotFound) {
+			t.Errorf("%s: load after delete: %v", name, err)
+		}
+		if err := s.DeletePuzzle(ctx, want.ID); !errors.Is(err, errPuzzleNotFound) {
+			t.Errorf("%s: second delete: %v", name, err)
+		}
+	}
+}
+
+func TestSQLitePuzzlesExpire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "puzzles.db")
+	s, err := openSQLitePuzzles(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1000, 0)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+	if err := s.SavePuzzle(ctx, testPuzzleRecord(t)); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	// survives a restart
+	s, err = openSQLitePuzzles(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	s.now = func() time.Time { return now.Add(59 * time.Minute) }
+	if _, err := s.LoadPuzzle(ctx, "abc123"); err != nil || s.Purge() != 0 {
+		t.Fatalf("before expiry: %v", err)
+	}
+	s.now = func() time.Time { return now.Add(time.Hour) }
+	if _, err := s.LoadPuzzle(ctx, "abc123"); !errors.Is(err, errPuzzleNotFound) {
+		t.Fatalf("expired puzzle loaded: %v", err)
+	}
+	if n := s.Purge(); n != 1 {
+		t.Fatalf("purged %d", n)
+	}
+}
+
+func TestMemPuzzlesBounded(t *testing.T) {
+	s := newMemPuzzleStore(0, 0)
+	if s.ttl != defaultMemPuzzleTTL || s.limit != maxMemPuzzles {
+		t.Fatalf("memory store keeps puzzles for %v, up to %d", s.ttl, s.limit)
+	}
+	if s := newMemPuzzleStore(time.Hour, 0); s.ttl != time.Hour {
+		t.Fatalf("retention ignored: %v", s.ttl)
+	}
+}
+
+func TestGenerateStoresPuzzle(t *testing.T) {
+	h, a, err := newHandler(Options{Quiet: true, Database: filepath.Join(t.TempDir(), "p.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { a.puzzles.(*sqlPuzzleStore).Close() })
+	ts := httptest.NewServer(h)
+	t.Cleanup(ts.Close)
+
+	for _, body := range []string{`{"difficulty":"easy"}`, `{"size":6,"box":"2x3"}`} {
+		resp, err := http.Post(ts.URL+"/generate", "application/json", bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var gen struct {
+			ID     string  `json:"id"`
+			Puzzle [][]int `json:"puzzle"`
+		}
+		json.NewDecoder(resp.Body).Decode(&gen)
+		resp.Body.Close()
+		if gen.ID == "" {
+			t.Fatalf("%s: no id", body)
+		}
+		type stored struct {
+			ID       string         `json:"id"`
+			Puzzle   [][]int        `json:"puzzle"`
+			Solution [][]int        `json:"solution"`
+			Rating   *sudoku.Rating `json:"rating"`
+			Revealed bool           `json:"revealed"`
+		}
+		getPuzzle := func(query string) stored {
+			t.Helper()
+			resp, err := http.Get(ts.URL + "/puzzles/" + gen.ID + query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			var got stored
+			json.NewDecoder(resp.Body).Decode(&got)
+			if resp.StatusCode != http.StatusOK || got.ID != gen.ID {
+				t.Fatalf("%s: GET /puzzles%s: %d %+v", body, query, resp.StatusCode, got)
+			}
+			return got
+		}
+		if got := getPuzzle(""); got.Solution != nil || got.Revealed {
+			t.Fatalf("%s: solution sent unasked: %+v", body, got)
+		}
+		got := getPuzzle("?includeSolution=true")
+		if len(got.Solution) != len(gen.Puzzle) || !got.Revealed {
+			t.Fatalf("%s: GET /puzzles with solution: %+v", body, got)
+		}
+		if again := getPuzzle(""); !again.Revealed {
+			t.Fatalf("%s: revealed flag not stored", body)
+		}
+		for r := range gen.Puzzle {
+			for c, v := range gen.Puzzle[r] {
+				if got.Puzzle[r][c] != v || (v != 0 && got.Solution[r][c] != v) {
+					t.Fatalf("%s: stored puzzle differs at r%dc%d", body, r+1, c+1)
+				}
+			}
+		}
+		if classic := len(gen.Puzzle) == 9; classic != (got.Rating != nil) {
+			t.Fatalf("%s: rating = %v", body, got.Rating)
+		}
+		if code := deletePuzzle(t, ts.URL+"/puzzles/"+gen.ID); code != http.StatusNoContent {
+			t.Fatalf("%s: DELETE /puzzles: %d", body, code)
+		}
+		if code := deletePuzzle(t, ts.URL+"/puzzles/"+gen.ID); code != http.StatusNotFound {
+			t.Fatalf("%s: second DELETE /puzzles: %d", body, code)
+		}
+	}
+	resp, err := http.Get(ts.URL + "/puzzles/nope")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown id: %d", resp.StatusCode)
+	}
+}
+
+func deletePuzzle(t *testing.T, url string) int {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodDelete, url, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
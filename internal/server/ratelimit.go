@@ -0,0 +1,144 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is a token bucket: Burst requests at once, refilled at Rate per
+// second. A zero Rate disables limiting.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateStore keeps the token buckets, keyed by client. The in-memory store
+// serves a single instance; a shared store (e.g. Redis) lets replicas enforce
+// one limit. Implementations must be safe for concurrent use.
+type RateStore interface {
+	// Take removes a token from key's bucket at now and reports whether one was
+	// available, or else how long until the next one is.
+	Take(key string, limit RateLimit, now time.Time) (ok bool, retryAfter time.Duration)
+}
+
+// rateFromEnv reads SUDOKU_RATE_LIMIT (requests per second per client, 0 or
+// unset disables) and SUDOKU_RATE_BURST (default: the rate rounded up, at least 1).
+func rateFromEnv(getenv func(string) string) (RateLimit, error) {
+	var lim RateLimit
+	if v := getenv("SUDOKU_RATE_LIMIT"); v != "" {
+		r, err := strconv.ParseFloat(v, 64)
+		if err != nil || r < 0 || math.IsInf(r, 0) {
+			return RateLimit{}, fmt.Errorf("SUDOKU_RATE_LIMIT: invalid rate %q", v)
+		}
+		lim.Rate = r
+	}
+	if v := getenv("SUDOKU_RATE_BURST"); v != "" {
+		b, err := strconv.Atoi(v)
+		if err != nil || b < 1 {
+			return RateLimit{}, fmt.Errorf("SUDOKU_RATE_BURST: invalid burst %q", v)
+		}
+		lim.Burst = b
+	}
+	return lim, nil
+}
+
+// withDefaultBurst fills in a missing burst.
+func (lim RateLimit) withDefaultBurst() RateLimit {
+	if lim.Burst < 1 {
+		lim.Burst = max(1, int(math.Ceil(lim.Rate)))
+	}
+	return lim
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // when the bucket refills completely
+}
+
+// memRateStore is the in-memory RateStore. Purge forgets buckets that have
+// refilled completely, so idle clients cost nothing.
+type memRateStore struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	buckets map[string]*bucket
+}
+
+func newMemRateStore() *memRateStore {
+	return &memRateStore{now: time.Now, buckets: make(map[string]*bucket)}
+}
+
+func (s *memRateStore) Take(key string, limit RateLimit, now time.Time) (bool, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = b
+	}
+	b.tokens = min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+	ok = b.tokens >= 1
+	if ok {
+		b.tokens--
+	}
+	b.full = now.Add(refill(float64(limit.Burst)-b.tokens, limit.Rate))
+	if ok {
+		return true, 0
+	}
+	return false, refill(1-b.tokens, limit.Rate)
+}
+
+// refill is how long rate takes to add tokens.
+func refill(tokens, rate float64) time.Duration {
+	return time.Duration(tokens / rate * float64(time.Second))
+}
+
+func (s *memRateStore) Purge() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now, n := s.now(), 0
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+			n++
+		}
+	}
+	return n
+}
+
+// rateLimiter answers 429 with Retry-After once a client's bucket is empty.
+// Clients are keyed by the connection's peer address, like accessControl;
+// health checks are exempt.
+type rateLimiter struct {
+	limit RateLimit
+	store RateStore
+	now   func() time.Time
+}
+
+func (rl rateLimiter) wrap(next http.Handler) http.Handler {
+	if rl.limit.Rate <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isProbe(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := r.RemoteAddr
+		if ap, err := netip.ParseAddrPort(r.RemoteAddr); err == nil {
+			key = ap.Addr().Unmap().String()
+		}
+		if ok, wait := rl.store.Take(key, rl.limit, rl.now()); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeJSON(w, http.StatusTooManyRequests, errMsg("rate limit exceeded"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
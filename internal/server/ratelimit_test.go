@@ -0,0 +1,97 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMemRateStore(t *testing.T) {
+	s := newMemRateStore()
+	lim := RateLimit{Rate: 2, Burst: 3}
+	now := time.Unix(1000, 0)
+	for i := range 3 {
+		if ok, _ := s.Take("a", lim, now); !ok {
+			t.Fatalf("request %d within burst refused", i+1)
+		}
+	}
+	ok, wait := s.Take("a", lim, now)
+	if ok || wait != 500*time.Millisecond {
+		t.Fatalf("over burst: ok=%v wait=%v, want refused for 500ms", ok, wait)
+	}
+	if ok, _ := s.Take("b", lim, now); !ok {
+		t.Fatal("other client refused")
+	}
+	if ok, _ := s.Take("a", lim, now.Add(500*time.Millisecond)); !ok {
+		t.Fatal("refilled token refused")
+	}
+
+	s.now = func() time.Time { return now.Add(time.Second) }
+	if n := s.Purge(); n != 1 || s.buckets["a"] == nil {
+		t.Fatalf("purged %d buckets, want only b (full again)", n)
+	}
+	s.now = func() time.Time { return now.Add(2 * time.Second) }
+	if n := s.Purge(); n != 1 {
+		t.Fatalf("purged %d buckets, want a", n)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	rl := rateLimiter{limit: RateLimit{Rate: 0.5, Burst: 1}, store: newMemRateStore(), now: func() time.Time { return now }}
+	h := rl.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	do := func(path, addr string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = addr
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	if rec := do("/daily", "192.0.2.1:1000"); rec.Code != http.StatusOK {
+		t.Fatalf("first request: %d", rec.Code)
+	}
+	rec := do("/solve", "192.0.2.1:2000") // same client, new port
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "2" {
+		t.Fatalf("second request: %d, Retry-After %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec := do("/healthz", "192.0.2.1:3000"); rec.Code != http.StatusOK {
+		t.Fatalf("health check limited: %d", rec.Code)
+	}
+	if rec := do("/daily", "192.0.2.2:1000"); rec.Code != http.StatusOK {
+		t.Fatalf("other client limited: %d", rec.Code)
+	}
+}
+
+func TestRateFromEnv(t *testing.T) {
+	t.Setenv("SUDOKU_RATE_LIMIT", "2.5")
+	lim, err := rateFromEnv(os.Getenv)
+	if err != nil || lim.withDefaultBurst() != (RateLimit{Rate: 2.5, Burst: 3}) {
+		t.Fatalf("got %+v, %v", lim.withDefaultBurst(), err)
+	}
+	for env, v := range map[string]string{"SUDOKU_RATE_LIMIT": "-1", "SUDOKU_RATE_BURST": "0"} {
+		t.Setenv(env, v)
+		if _, err := rateFromEnv(os.Getenv); err == nil {
+			t.Errorf("%s=%s accepted", env, v)
+		}
+		t.Setenv(env, "1")
+	}
+}
+
+func TestHandlerRateLimitFlags(t *testing.T) {
+	t.Setenv("SUDOKU_RATE_LIMIT", "100")
+	h, err := Handler(Options{Quiet: true, RateLimit: RateLimit{Burst: 1}, RateStore: newMemRateStore()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := make([]int, 2)
+	for i := range codes {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+		codes[i] = rec.Code
+	}
+	if codes[0] != http.StatusOK || codes[1] != http.StatusTooManyRequests {
+		t.Fatalf("codes = %v, want 200 then 429 (burst 1 from options)", codes)
+	}
+}
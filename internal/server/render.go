@@ -0,0 +1,75 @@
+package server
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"strconv"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/render"
+)
+
+const (
+	defaultRenderSize = 640
+	minRenderSize     = 100
+	maxRenderSize     = 2000
+)
+
+// handleRender draws the board in ?s= (81 characters) as ?format=png (default)
+// or svg, about ?size= pixels square, so chat bots and static pages can embed a
+// puzzle with a plain image URL. Images depend only on the query and may be
+// cached.
+func handleRender(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeJSON(w, http.StatusMethodNotAllowed, errMsg("method not allowed"))
+		return
+	}
+	q := r.URL.Query()
+	b, err := sudoku.FromString(q.Get("s"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errMsg("invalid puzzle string"))
+		return
+	}
+	size := defaultRenderSize
+	if v := q.Get("size"); v != "" {
+		if size, err = strconv.Atoi(v); err != nil || size < minRenderSize || size > maxRenderSize {
+			writeJSON(w, http.StatusBadRequest, errMsg("size must be between 100 and 2000"))
+			return
+		}
+	}
+	// a margin of a third of a cell on each side, as in the render defaults
+	cs := size * 3 / 29
+	opts := render.RenderOptions{CellSize: cs, Margin: max(1, (size-9*cs)/2)}
+	var buf bytes.Buffer
+	var ctype string
+	switch q.Get("format") {
+	case "png", "":
+		img, err := render.Image(b, opts)
+		if err == nil {
+			err = png.Encode(&buf, img)
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, errMsg("render failed"))
+			return
+		}
+		ctype = "image/png"
+	case "svg":
+		data, err := render.SVG(b, opts)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, errMsg("render failed"))
+			return
+		}
+		buf.Write(data)
+		ctype = "image/svg+xml"
+	default:
+		writeJSON(w, http.StatusBadRequest, errMsg("format must be png or svg"))
+		return
+	}
+	w.Header().Set("Content-Type", ctype)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	_, _ = w.Write(buf.Bytes())
+}
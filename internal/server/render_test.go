@@ -0,0 +1,47 @@
+package server
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func getRender(query string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handleRender(rec, httptest.NewRequest(http.MethodGet, "/render?"+query, nil))
+	return rec
+}
+
+func TestRender(t *testing.T) {
+	rec := getRender("s=" + sudokutest.Easy + "&size=300")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("png: %d %v", rec.Code, rec.Header())
+	}
+	img, err := png.Decode(bytes.NewReader(rec.Body.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := img.Bounds().Dx(); w < 295 || w > 300 {
+		t.Fatalf("width = %d, want about 300", w)
+	}
+	rec = getRender("s=" + sudokutest.Easy + "&format=svg")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/svg+xml" || !strings.HasPrefix(rec.Body.String(), "<svg") {
+		t.Fatalf("svg: %d %v", rec.Code, rec.Header())
+	}
+	if rec := getRender("s=" + sudokutest.Easy); rec.Code != http.StatusOK {
+		t.Fatalf("defaults: %d", rec.Code)
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	for _, q := range []string{"", "s=123", "s=" + sudokutest.Easy + "&format=gif", "s=" + sudokutest.Easy + "&size=50", "s=" + sudokutest.Easy + "&size=big"} {
+		if rec := getRender(q); rec.Code != http.StatusBadRequest {
+			t.Errorf("%q: status %d, want 400", q, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,708 @@
+// Package server implements the sudoku HTTP API shared by the sudoku-server
+// binary and the CLI's serve command.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	mrand "math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.rumenx.com/sudoku"
+)
+
+// Options configure Run. Deployment settings such as TLS, access control,
+// CORS and SLA thresholds stay in SUDOKU_* environment variables, which a
+// config file (see Config) can provide instead.
+type Options struct {
+	Port         int           // 0 uses $PORT, then 8080
+	ReadTimeout  time.Duration // whole request, headers and body; 0 uses $SUDOKU_READ_TIMEOUT, then 10s
+	WriteTimeout time.Duration // 0 uses $SUDOKU_WRITE_TIMEOUT, then 10s
+	MaxBodyBytes int64         // request body limit; 0 uses $SUDOKU_MAX_BODY, else unlimited
+	DrainDelay   time.Duration // how long /readyz fails before shutdown; 0 uses $SUDOKU_DRAIN_DELAY, then 5s; <0 none
+	Quiet        bool          // no access log, also set by SUDOKU_LOG_LEVEL=warn or error
+	AccessLog    io.Writer     // nil means os.Stdout
+
+	// ConfigFile is read by LoadConfig into Config, whose settings apply
+	// where the environment leaves a variable unset. PrintConfig asks the
+	// binary to print the effective configuration and exit.
+	ConfigFile  string
+	Config      *Config
+	PrintConfig bool
+
+	// RateLimit applies per client address; fields left zero fall back to
+	// SUDOKU_RATE_LIMIT and SUDOKU_RATE_BURST. RateStore nil keeps the buckets
+	// in memory.
+	RateLimit RateLimit
+	RateStore RateStore
+
+	// Database is a SQLite file keeping generated puzzles and leaderboards
+	// across restarts; empty falls back to $SUDOKU_DB, then memory. PuzzleStore
+	// and Completions override it.
+	Database    string
+	PuzzleStore PuzzleStore
+	Completions CompletionStore
+
+	// Generation bounds concurrent puzzle generation; fields left zero fall
+	// back to SUDOKU_WORKERS and SUDOKU_QUEUE_WAIT.
+	Generation GenerationPool
+
+	// Version, Commit and Date are reported by /healthz.
+	Version, Commit, Date string
+}
+
+// RegisterFlags adds the server flags to fs and returns the Options they fill,
+// so every binary that serves the API accepts the same flags.
+func RegisterFlags(fs *flag.FlagSet) *Options {
+	o := &Options{}
+	fs.IntVar(&o.Port, "port", 0, "listen port (0 = $PORT or 8080)")
+	fs.DurationVar(&o.ReadTimeout, "read-timeout", 0, "limit for reading a request (0 = $SUDOKU_READ_TIMEOUT or 10s)")
+	fs.DurationVar(&o.WriteTimeout, "write-timeout", 0, "limit for writing a response (0 = $SUDOKU_WRITE_TIMEOUT or 10s)")
+	fs.Int64Var(&o.MaxBodyBytes, "max-body", 0, "request body limit in bytes (0 = $SUDOKU_MAX_BODY, unset is unlimited)")
+	fs.DurationVar(&o.DrainDelay, "drain-delay", 0, "how long /readyz reports shutting down before the listener closes (0 = $SUDOKU_DRAIN_DELAY or 5s)")
+	fs.BoolVar(&o.Quiet, "quiet", false, "disable the access log")
+	fs.StringVar(&o.ConfigFile, "config", "", "YAML or TOML config file (default $SUDOKU_CONFIG); environment and flags override it")
+	fs.BoolVar(&o.PrintConfig, "print-config", false, "print the effective configuration and exit")
+	fs.StringVar(&o.Database, "db", "", "SQLite file for puzzles and leaderboards (default $SUDOKU_DB, else memory)")
+	fs.Float64Var(&o.RateLimit.Rate, "rate-limit", 0, "requests per second per client (0 = $SUDOKU_RATE_LIMIT, unset disables)")
+	fs.IntVar(&o.RateLimit.Burst, "rate-burst", 0, "requests a client may burst (0 = $SUDOKU_RATE_BURST or the rate)")
+	fs.IntVar(&o.Generation.Workers, "workers", 0, "generation requests served at once (0 = $SUDOKU_WORKERS or twice the CPUs)")
+	fs.DurationVar(&o.Generation.QueueWait, "queue-wait", 0, "how long generation requests queue for a worker (0 = $SUDOKU_QUEUE_WAIT or 5s)")
+	return o
+}
+
+// Addr is the listen address for o.
+func (o Options) Addr() string {
+	if o.Port > 0 {
+		return ":" + strconv.Itoa(o.Port)
+	}
+	if v := o.getenv("PORT"); v != "" {
+		return ":" + v
+	}
+	return ":8080"
+}
+
+// Handler builds the API with its middleware, reading the SUDOKU_* settings
+// from the environment.
+func Handler(o Options) (http.Handler, error) {
+	if err := o.fromEnv(); err != nil {
+		return nil, err
+	}
+	h, _, err := newHandler(o)
+	return h, err
+}
+
+// api is the state the handlers of one Handler share. Each Handler gets its
+// own, so two in one process, such as parallel tests, do not see each other's
+// stores, caches or settings.
+type api struct {
+	generators  *workerPool
+	solveLimit  time.Duration // bounds each variable-size /solve, see solveGrid
+	generated   *puzzleCache
+	dailies     *memStore[sudoku.Board] // puzzles of the day by date, see daily
+	puzzles     PuzzleStore
+	completions CompletionStore
+	collections *memStore[*collection]
+	cursors     *memStore[*collectionCursor]
+	idemMu      sync.Mutex // makes the lookup and claim of an idempotency key atomic
+	idempotency *memStore[*idemResponse]
+	retention   retention
+	rates       *memRateStore // the default RateStore
+	latencies   *latencyTracker
+	slaLimits   slaThresholds
+	shadow      *shadowSolver // nil unless SUDOKU_SHADOW_SOLVER is set
+	cors        corsPolicy
+	access      accessControl
+	routes      []string    // mux patterns, checked against the OpenAPI spec in tests
+	draining    atomic.Bool // set once Run starts shutting down
+}
+
+// newAPI returns the state with in-memory stores and default settings, which
+// newHandler then adjusts to the Options and environment.
+func newAPI() *api {
+	return &api{
+		generators:  newWorkerPool(GenerationPool{}),
+		solveLimit:  defaultSolveLimit,
+		generated:   newPuzzleCache(defaultCacheTTL),
+		dailies:     newBoundedMemStore[sudoku.Board](0, 0, dailyCacheSize),
+		puzzles:     newMemPuzzleStore(0, time.Hour),
+		completions: newMemCompletionStore(),
+		collections: newBoundedMemStore[*collection](0, time.Hour, maxCollections),
+		cursors:     newBoundedMemStore[*collectionCursor](24*time.Hour, 0, maxCursors), // idle clients start over after a day
+		idempotency: newBoundedMemStore[*idemResponse](idempotencyTTL, 0, maxIdempotentKeys),
+		retention:   retention{Grace: time.Hour, Sweep: time.Minute},
+		rates:       newMemRateStore(),
+		latencies:   newLatencyTracker(1024),
+		slaLimits:   slaThresholds{},
+	}
+}
+
+// newHandler builds the API for o, whose environment settings Handler or Run
+// has already read with fromEnv.
+func newHandler(o Options) (http.Handler, *api, error) {
+	a := newAPI()
+	health := func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"status": "ok", "version": o.Version, "commit": o.Commit, "date": o.Date})
+	}
+	pool, err := poolFromEnv(o.getenv)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o.Generation.Workers > 0 {
+		pool.Workers = o.Generation.Workers
+	}
+	if o.Generation.QueueWait != 0 {
+		pool.QueueWait = o.Generation.QueueWait
+	}
+	a.generators = newWorkerPool(pool)
+	cacheTTL, err := cacheTTLFromEnv(o.getenv)
+	if err != nil {
+		return nil, nil, err
+	}
+	a.generated = newPuzzleCache(cacheTTL)
+	mux := http.NewServeMux()
+	handle := func(pattern string, h http.HandlerFunc) {
+		mux.HandleFunc(pattern, h)
+		a.routes = append(a.routes, pattern)
+	}
+	handle("/healthz", probe(health))
+	handle("/health", probe(health)) // alias
+	handle("/livez", probe(handleLivez))
+	handle("/readyz", probe(a.handleReadyz))
+	handle("/generate", a.idempotent(a.handleGenerate)) // takes a worker only to generate
+	handle("/generate/batch", a.idempotent(a.generators.wrap(handleGenerateBatch)))
+	handle("/solve", a.idempotent(a.handleSolve))
+	handle("/puzzles/{id}", a.handlePuzzle)
+	handle("/daily", a.handleDaily)
+	handle("/completions", a.handleCompletions)
+	handle("/leaderboard", a.handleLeaderboard)
+	handle("/render", handleRender)
+	handle("/metrics", a.handleMetrics)
+	handle("/metrics/sla", a.handleSLA)
+	handle("/collections", a.idempotent(a.handleCollections))
+	handle("/collections/{id}", a.handleCollection)
+	handle("/collections/{id}/next", a.handleCollectionNext)
+	handle("/sudoku-board.js", handleBoardComponent)
+	handle("/ws/game", a.handleGameSocket)
+	handle("/openapi.json", handleOpenAPI)
+	handle("/docs", handleDocs)
+	if a.slaLimits, err = slaFromEnv(o.getenv); err != nil {
+		return nil, nil, err
+	}
+	if a.shadow, err = shadowFromEnv(o.getenv, log.Printf); err != nil {
+		return nil, nil, err
+	}
+	if a.shadow != nil {
+		a.shadow.latencies = a.latencies
+	}
+	if a.access, err = accessFromEnv(o.getenv); err != nil {
+		return nil, nil, err
+	}
+	if a.retention, err = retentionFromEnv(o.getenv); err != nil {
+		return nil, nil, err
+	}
+	a.collections = newBoundedMemStore[*collection](0, a.retention.Grace, maxCollections)
+	if a.puzzles, err = puzzleStoreFor(o, a.retention); err != nil {
+		return nil, nil, err
+	}
+	if a.completions, err = completionStoreFor(o); err != nil {
+		return nil, nil, err
+	}
+	limit, err := rateFromEnv(o.getenv)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o.RateLimit.Rate > 0 {
+		limit.Rate = o.RateLimit.Rate
+	}
+	if o.RateLimit.Burst > 0 {
+		limit.Burst = o.RateLimit.Burst
+	}
+	rl := rateLimiter{limit: limit.withDefaultBurst(), store: o.RateStore, now: time.Now}
+	if rl.store == nil {
+		rl.store = a.rates
+	}
+	a.cors = corsFromEnv(o.getenv)
+	var h http.Handler = a.cors.wrap(a.access.wrap(rl.wrap(mux)))
+	if o.MaxBodyBytes > 0 {
+		h = limitBody(h, o.MaxBodyBytes)
+	}
+	if !o.Quiet {
+		out := o.AccessLog
+		if out == nil {
+			out = os.Stdout
+		}
+		h = logRequest(h, out)
+	}
+	return h, a, nil
+}
+
+// Run serves the API until ctx ends, then shuts down gracefully.
+func Run(ctx context.Context, o Options) error {
+	if err := o.fromEnv(); err != nil {
+		return err
+	}
+	h, a, err := newHandler(o)
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := tlsFromEnv(o.getenv, a.access.needsMTLS())
+	if err != nil {
+		return err
+	}
+	stores := []purger{a.idempotency, a.cursors, a.collections, a.rates}
+	if p, ok := a.puzzles.(purger); ok {
+		stores = append(stores, p)
+	}
+	for _, s := range []any{a.puzzles, a.completions} {
+		if c, ok := s.(io.Closer); ok {
+			defer c.Close()
+		}
+	}
+	go runJanitor(ctx, a.retention.Sweep, log.Printf, stores...)
+
+	s := &http.Server{
+		Addr:              o.Addr(),
+		Handler:           h,
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       o.ReadTimeout,
+		WriteTimeout:      o.WriteTimeout,
+		IdleTimeout:       60 * time.Second,
+	}
+	ln, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	errc := make(chan error, 1)
+	go func() {
+		if tlsConfig != nil {
+			log.Printf("listening on %s (TLS)", ln.Addr())
+			errc <- s.ServeTLS(ln, "", "")
+			return
+		}
+		log.Printf("listening on %s", ln.Addr())
+		errc <- s.Serve(ln)
+	}()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	// fail /readyz first and keep serving for a while, so load balancers see
+	// the instance leave before its listener closes
+	a.draining.Store(true)
+	if o.DrainDelay > 0 {
+		log.Printf("draining for %s", o.DrainDelay)
+		select {
+		case err := <-errc:
+			return err
+		case <-time.After(o.DrainDelay):
+		}
+	}
+	shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.Shutdown(shutdown); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// maxAttempts caps the generation retries a client may ask for, so one
+// request cannot keep a worker busy for long.
+const maxAttempts = 10
+
+func (a *api) handleGenerate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, errMsg("method not allowed"))
+		return
+	}
+	var req struct {
+		Difficulty      string `json:"difficulty"`
+		IncludeSolution bool   `json:"includeSolution"`
+		Size            int    `json:"size"`
+		Box             string `json:"box"`      // e.g. 3x3, 2x3
+		Attempts        int    `json:"attempts"` // generation attempts
+		Fresh           bool   `json:"fresh"`    // skip the cache of recent puzzles
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errMsg("invalid json"))
+		return
+	}
+	d, ok := parseDifficulty(req.Difficulty)
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, errMsg("invalid difficulty"))
+		return
+	}
+	if req.Attempts < 1 {
+		req.Attempts = 3
+	}
+	if req.Attempts > maxAttempts {
+		writeJSON(w, http.StatusBadRequest, errMsg("attempts must be at most 10"))
+		return
+	}
+	classic := req.Size == 0 && req.Box == "" // 9x9 shortcut
+	revealed := classic && req.IncludeSolution
+	var g sudoku.Grid
+	if !classic {
+		var err error
+		if g, err = parseGrid(req.Size, req.Box); err != nil {
+			writeJSON(w, http.StatusBadRequest, errMsg(err.Error()))
+			return
+		}
+	}
+	key := cacheKey{req.Size, req.Box, d}
+	// a puzzle sent with its solution is off the leaderboards, so it is never
+	// shared through the cache
+	if !req.Fresh && !revealed {
+		if rec, ok := a.generated.get(key); ok {
+			// saving again keeps the id valid as long as a new puzzle's
+			if err := a.puzzles.SavePuzzle(r.Context(), rec); err != nil {
+				writeJSON(w, http.StatusInternalServerError, errMsg("storage failed"))
+				return
+			}
+			w.Header().Set("X-Cache", "hit")
+			writeJSON(w, http.StatusOK, generateResponse(rec, classic, req.IncludeSolution))
+			return
+		}
+	}
+	if !a.generators.acquire(w, r) {
+		return
+	}
+	defer a.generators.release()
+
+	start := time.Now()
+	var puz, sol sudoku.Grid
+	var genErr error
+	size := req.Size
+	// a generator per request: the package-level ones share one lock, which
+	// would let only one of the pool's workers generate at a time
+	gen := sudoku.NewGenerator(mrand.Uint64())
+	if classic {
+		var b sudoku.Board
+		b, genErr = gen.Generate(d, req.Attempts)
+		puz, size = b.ToGrid(), 9
+		if genErr == nil {
+			s, _ := sudoku.Solve(b) // unique 9x9 puzzles solve in microseconds
+			sol = s.ToGrid()
+		}
+	} else {
+		// the generator's own solution: solving a 16x16 again can take minutes
+		puz, sol, genErr = gen.GenerateGridWithSolution(g, d, req.Attempts)
+	}
+	a.latencies.Observe(latencyKey{"generate", string(d), size}, time.Since(start))
+	if genErr != nil {
+		writeJSON(w, http.StatusInternalServerError, errMsg("generation failed"))
+		return
+	}
+	var rating *sudoku.Rating
+	if b, err := puz.ToBoard(); err == nil {
+		if rt, err := sudoku.Rate(b); err == nil {
+			rating = &rt
+		}
+	}
+	rec, err := a.savePuzzle(r.Context(), d, puz, sol, rating, revealed)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errMsg("storage failed"))
+		return
+	}
+	if !rec.Revealed {
+		a.generated.add(key, rec)
+	}
+	w.Header().Set("X-Cache", "miss")
+	writeJSON(w, http.StatusOK, generateResponse(rec, classic, req.IncludeSolution))
+}
+
+// generateResponse is the /generate answer for rec. Variable-size answers
+// never carried the solution, and still do not.
+func generateResponse(rec PuzzleRecord, classic, includeSolution bool) map[string]any {
+	if classic {
+		res := map[string]any{"id": rec.ID, "puzzle": rec.Puzzle.Cells}
+		if includeSolution {
+			res["solution"] = rec.Solution.Cells
+		}
+		return res
+	}
+	return map[string]any{
+		"id":     rec.ID,
+		"size":   rec.Puzzle.Size, // size/boxR/boxC/puzzle predate "grid" and are kept for existing clients
+		"boxR":   rec.Puzzle.BoxRows,
+		"boxC":   rec.Puzzle.BoxCols,
+		"puzzle": rec.Puzzle.Cells,
+		"grid":   rec.Puzzle,
+	}
+}
+
+// parseDifficulty maps a request difficulty to a Difficulty; empty means easy.
+func parseDifficulty(s string) (sudoku.Difficulty, bool) {
+	switch s {
+	case string(sudoku.Easy), "":
+		return sudoku.Easy, true
+	case string(sudoku.Medium):
+		return sudoku.Medium, true
+	case string(sudoku.Hard):
+		return sudoku.Hard, true
+	}
+	return "", false
+}
+
+// parseGrid validates the size and box ("RxC") of a variable-size request and
+// returns the empty grid.
+func parseGrid(size int, box string) (sudoku.Grid, error) {
+	if size <= 0 || box == "" {
+		return sudoku.Grid{}, errors.New("size and box required for variable grid")
+	}
+	if size > sudoku.MaxGridSize {
+		return sudoku.Grid{}, fmt.Errorf("grid size %d exceeds maximum allowed (%d)", size, sudoku.MaxGridSize)
+	}
+	var br, bc int
+	if _, err := fmt.Sscanf(box, "%dx%d", &br, &bc); err != nil || br*bc != size {
+		return sudoku.Grid{}, errors.New("invalid box dims")
+	}
+	g, err := sudoku.NewGrid(size, br, bc)
+	if err != nil {
+		return sudoku.Grid{}, errors.New("invalid grid params")
+	}
+	return g, nil
+}
+
+// parseSizedGrid builds a variable-size puzzle from size, box and either rows
+// of cells or a size*size string in sudoku.GridAlphabet.
+func parseSizedGrid(size int, box string, cells [][]int, str string) (sudoku.Grid, error) {
+	g, err := parseGrid(size, box)
+	if err != nil {
+		return sudoku.Grid{}, err
+	}
+	switch {
+	case cells != nil && str != "":
+		return sudoku.Grid{}, errors.New("give either cells or string")
+	case cells != nil:
+		if len(cells) != size {
+			return sudoku.Grid{}, fmt.Errorf("invalid puzzle: cells must have %d rows", size)
+		}
+		g, err = sudoku.FromRowsN(cells, g.BoxRows, g.BoxCols)
+	case str != "":
+		g, err = sudoku.FromStringN(str, size, g.BoxRows, g.BoxCols)
+	default:
+		return sudoku.Grid{}, errors.New("missing puzzle")
+	}
+	if err != nil {
+		return sudoku.Grid{}, fmt.Errorf("invalid puzzle: %w", err)
+	}
+	return g, nil
+}
+
+func (a *api) handleSolve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, errMsg("method not allowed"))
+		return
+	}
+	var req struct {
+		Puzzle json.RawMessage `json:"puzzle"` // 9x9 array or 81-character string
+		String string          `json:"string"` // 81 characters, or size*size with size and box
+		Grid   *sudoku.Grid    `json:"grid"`   // variable size; validated while decoding
+		Size   int             `json:"size"`   // variable size, as for /generate
+		Box    string          `json:"box"`
+		Cells  [][]int         `json:"cells"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errMsg("invalid json"))
+		return
+	}
+	if req.Size != 0 || req.Box != "" {
+		g, err := parseSizedGrid(req.Size, req.Box, req.Cells, req.String)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errMsg(err.Error()))
+			return
+		}
+		sol, err := a.solveGrid(w, r, g)
+		if err != nil {
+			return
+		}
+		// mirrors the variable-size /generate response
+		writeJSON(w, http.StatusOK, map[string]any{
+			"size": sol.Size, "boxR": sol.BoxRows, "boxC": sol.BoxCols, "solution": sol.Cells, "grid": sol,
+		})
+		return
+	}
+	if req.Grid != nil {
+		sol, err := a.solveGrid(w, r, *req.Grid)
+		if err != nil {
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"solution": sol})
+		return
+	}
+	var b sudoku.Board
+	var err error
+	if len(req.Puzzle) != 0 && string(req.Puzzle) != "null" { // "puzzle": null counts as absent
+		if b, err = decodeBoard(req.Puzzle); err != nil {
+			writeJSON(w, http.StatusBadRequest, errMsg("invalid puzzle: "+err.Error()))
+			return
+		}
+	} else if req.String != "" {
+		if b, err = sudoku.FromString(req.String); err != nil {
+			writeJSON(w, http.StatusBadRequest, errMsg("invalid puzzle string"))
+			return
+		}
+	} else {
+		writeJSON(w, http.StatusBadRequest, errMsg("missing puzzle"))
+		return
+	}
+	g, err := a.solveGrid(w, r, b.ToGrid())
+	sol, _ := g.ToBoard()
+	if a.shadow != nil && (err == nil || errors.Is(err, sudoku.ErrUnsolvable) || errors.Is(err, sudoku.ErrInvalidBoard)) {
+		a.shadow.Check(b, sol, err == nil)
+	}
+	if err == nil {
+		writeJSON(w, http.StatusOK, map[string]any{"solution": sol})
+	}
+}
+
+// defaultSolveLimit bounds a variable-size solve. The search settles almost
+// every grid in well under a second, but some sparse 16x16 and 25x25 grids
+// would keep it busy for hours.
+const defaultSolveLimit = 5 * time.Second
+
+// errNoWorker is solveGrid's error when the generation pool turned r away.
+var errNoWorker = errors.New("no generation worker")
+
+// solveGrid solves g on a worker of the generation pool, giving up after
+// a.solveLimit with 503. When it fails, r has been answered (or canceled) and
+// the error says why: sudoku.ErrUnsolvable means g has no solution.
+func (a *api) solveGrid(w http.ResponseWriter, r *http.Request, g sudoku.Grid) (sudoku.Grid, error) {
+	if !a.generators.acquire(w, r) {
+		return sudoku.Grid{}, errNoWorker
+	}
+	defer a.generators.release()
+	ctx, cancel := context.WithTimeout(r.Context(), a.solveLimit)
+	defer cancel()
+	start := time.Now()
+	sol, err := g.SolveContext(ctx)
+	a.latencies.Observe(latencyKey{"solve", "any", g.Size}, time.Since(start))
+	switch {
+	case err == nil:
+		return sol, nil
+	case r.Context().Err() != nil:
+	case errors.Is(err, context.DeadlineExceeded):
+		writeJSON(w, http.StatusServiceUnavailable, errMsg("solve took too long"))
+	default:
+		writeJSON(w, http.StatusUnprocessableEntity, errMsg("unsolvable"))
+	}
+	return sudoku.Grid{}, err
+}
+
+// dailyCacheSize is how many days of puzzles daily keeps; the archive mostly
+// asks for recent ones.
+const dailyCacheSize = 64
+
+// daily returns the puzzle of day. A day's puzzle never changes, so it is
+// generated once, on a worker of the generation pool, and then served from
+// a.dailies. When it fails, r has been answered.
+func (a *api) daily(w http.ResponseWriter, r *http.Request, day time.Time) (sudoku.Board, bool) {
+	key := day.Format(time.DateOnly)
+	if b, ok := a.dailies.Get(key); ok {
+		return b, true
+	}
+	if !a.generators.acquire(w, r) {
+		return sudoku.Board{}, false
+	}
+	defer a.generators.release()
+	if b, ok := a.dailies.Get(key); ok {
+		return b, true // generated while r was queued
+	}
+	b, err := sudoku.Daily(day)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errMsg("generation failed"))
+		return sudoku.Board{}, false
+	}
+	a.dailies.Put(key, b)
+	return b, true
+}
+
+// handleDaily serves the puzzle of the day; ?date=YYYY-MM-DD selects a past day
+// (UTC). Future dates are rejected so the archive cannot be read ahead.
+func (a *api) handleDaily(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeJSON(w, http.StatusMethodNotAllowed, errMsg("method not allowed"))
+		return
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	day := today
+	if v := r.URL.Query().Get("date"); v != "" {
+		d, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errMsg("invalid date"))
+			return
+		}
+		if d.After(today) {
+			writeJSON(w, http.StatusNotFound, errMsg("no puzzle for future date"))
+			return
+		}
+		day = d
+	}
+	puz, ok := a.daily(w, r, day)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"date": day.Format(time.DateOnly), "difficulty": sudoku.DailyDifficulty, "puzzle": puz})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// decodeBoard parses a JSON puzzle given as a 9x9 array or an 81-character
+// string. Arrays must have exactly 9 rows of 9 values; decoding straight into
+// a Board would silently zero-pad or truncate them.
+func decodeBoard(raw json.RawMessage) (sudoku.Board, error) {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return sudoku.FromString(s)
+	}
+	var rows [][]int
+	if err := json.Unmarshal(raw, &rows); err != nil {
+		return sudoku.Board{}, fmt.Errorf("puzzle must be a 9x9 array or an 81-character string")
+	}
+	return sudoku.FromRows(rows)
+}
+
+func errMsg(msg string) map[string]string { return map[string]string{"error": msg} }
+
+func logRequest(next http.Handler, out io.Writer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		fmt.Fprintf(out, "%s %s %s\n", r.Method, r.URL.Path, time.Since(start))
+	})
+}
+
+// limitBody caps request bodies at n bytes; reading past it fails, which the
+// handlers report as invalid input.
+func limitBody(next http.Handler, n int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, n)
+		next.ServeHTTP(w, r)
+	})
+}
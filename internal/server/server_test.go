@@ -0,0 +1,468 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func newMuxForTest() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	a := newAPI()
+	mux.HandleFunc("/generate", a.handleGenerate)
+	mux.HandleFunc("/solve", a.handleSolve)
+	mux.HandleFunc("/daily", a.handleDaily)
+	return mux
+}
+
+func TestHealthz(t *testing.T) {
+	ts := httptest.NewServer(newMuxForTest())
+	t.Cleanup(ts.Close)
+	resp, err := http.Get(ts.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("healthz: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+}
+
+func TestGenerateAPI(t *testing.T) {
+	ts := httptest.NewServer(newMuxForTest())
+	t.Cleanup(ts.Close)
+	body, _ := json.Marshal(map[string]any{"difficulty": "medium", "includeSolution": true})
+	resp, err := http.Post(ts.URL+"/generate", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+}
+
+func TestGenerateAPI_WithSolution(t *testing.T) {
+	ts := httptest.NewServer(newMuxForTest())
+	t.Cleanup(ts.Close)
+	body, _ := json.Marshal(map[string]any{"difficulty": "easy", "includeSolution": true})
+	resp, err := http.Post(ts.URL+"/generate", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+}
+
+func TestGenerateAPI_Errors(t *testing.T) {
+	ts := httptest.NewServer(newMuxForTest())
+	t.Cleanup(ts.Close)
+	// wrong method
+	resp, err := http.Get(ts.URL + "/generate")
+	if err != nil {
+		t.Fatalf("get generate: %v", err)
+	}
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", resp.StatusCode)
+	}
+	// bad difficulty
+	body, _ := json.Marshal(map[string]any{"difficulty": "impossible"})
+	resp, err = http.Post(ts.URL+"/generate", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("post generate: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+	// too many attempts
+	body, _ = json.Marshal(map[string]any{"attempts": maxAttempts + 1})
+	resp, err = http.Post(ts.URL+"/generate", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("post generate: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("attempts over the cap: expected 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestSolveAPI(t *testing.T) {
+	ts := httptest.NewServer(newMuxForTest())
+	t.Cleanup(ts.Close)
+	s := sudokutest.Easy
+	body, _ := json.Marshal(map[string]any{"string": s})
+	resp, err := http.Post(ts.URL+"/solve", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("solve: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+}
+
+func TestSolveAPI_Errors(t *testing.T) {
+	ts := httptest.NewServer(newMuxForTest())
+	t.Cleanup(ts.Close)
+	// method not allowed
+	resp, err := http.Get(ts.URL + "/solve")
+	if err != nil {
+		t.Fatalf("get solve: %v", err)
+	}
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", resp.StatusCode)
+	}
+	// invalid json
+	resp, err = http.Post(ts.URL+"/solve", "application/json", bytes.NewBufferString("{"))
+	if err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+	// missing puzzle
+	resp, err = http.Post(ts.URL+"/solve", "application/json", bytes.NewBufferString(`{}`))
+	if err != nil {
+		t.Fatalf("missing: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+	// a null puzzle is absent, so the string form is used
+	resp, err = http.Post(ts.URL+"/solve", "application/json", bytes.NewBufferString(`{"puzzle":null,"string":"`+sudokutest.Easy+`"}`))
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("null puzzle with string: %v %v", err, resp.StatusCode)
+	}
+	resp, err = http.Post(ts.URL+"/solve", "application/json", bytes.NewBufferString(`{"puzzle":null}`))
+	if err != nil {
+		t.Fatalf("null puzzle: %v", err)
+	}
+	var missing map[string]string
+	_ = json.NewDecoder(resp.Body).Decode(&missing)
+	if resp.StatusCode != http.StatusBadRequest || missing["error"] != "missing puzzle" {
+		t.Fatalf("null puzzle: %d %v", resp.StatusCode, missing)
+	}
+	// invalid string
+	resp, err = http.Post(ts.URL+"/solve", "application/json", bytes.NewBufferString(`{"string":"xxx"}`))
+	if err != nil {
+		t.Fatalf("invalid string: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+	// unsolvable: valid-shaped board with contradictions
+	resp, err = http.Post(ts.URL+"/solve", "application/json", bytes.NewBufferString(`{"puzzle":[[1,1],[],[],[],[],[],[],[],[]]}`))
+	if err != nil {
+		t.Fatalf("unsolvable: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest && resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 400 or 422, got %d", resp.StatusCode)
+	}
+	// short rows used to be zero-padded into an easy-to-solve board
+	resp, err = http.Post(ts.URL+"/solve", "application/json", bytes.NewBufferString(`{"puzzle":[[5,3],[],[],[],[],[],[],[],[]]}`))
+	if err != nil {
+		t.Fatalf("short rows: %v", err)
+	}
+	var body map[string]string
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	if resp.StatusCode != http.StatusBadRequest || !strings.Contains(body["error"], "row 0 has 2 cells") {
+		t.Fatalf("expected 400 for short rows, got %d %v", resp.StatusCode, body)
+	}
+	// the 81-character string form is accepted under "puzzle" too
+	resp, err = http.Post(ts.URL+"/solve", "application/json", bytes.NewBufferString(`{"puzzle":"`+sudokutest.Easy+`"}`))
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("string puzzle: %v %v", err, resp.StatusCode)
+	}
+}
+
+func TestDailyAPI(t *testing.T) {
+	ts := httptest.NewServer(newMuxForTest())
+	t.Cleanup(ts.Close)
+	get := func(q string) (int, map[string]any) {
+		resp, err := http.Get(ts.URL + "/daily" + q)
+		if err != nil {
+			t.Fatalf("daily: %v", err)
+		}
+		defer resp.Body.Close()
+		var out map[string]any
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out
+	}
+	code, a := get("?date=2024-03-09")
+	if code != http.StatusOK || a["date"] != "2024-03-09" {
+		t.Fatalf("status = %d body = %v", code, a)
+	}
+	_, b := get("?date=2024-03-09")
+	if fmt.Sprint(a["puzzle"]) != fmt.Sprint(b["puzzle"]) {
+		t.Fatalf("daily puzzle not stable")
+	}
+	for q, want := range map[string]int{"": http.StatusOK, "?date=9999-01-01": http.StatusNotFound, "?date=yesterday": http.StatusBadRequest} {
+		if code, _ := get(q); code != want {
+			t.Fatalf("%q: status = %d, want %d", q, code, want)
+		}
+	}
+}
+
+func TestDailyCachedOnPool(t *testing.T) {
+	a := newAPI()
+	a.generators = newWorkerPool(GenerationPool{Workers: 1, QueueWait: -1})
+	get := func(date string) int {
+		rec := httptest.NewRecorder()
+		a.handleDaily(rec, httptest.NewRequest(http.MethodGet, "/daily?date="+date, nil))
+		return rec.Code
+	}
+	if code := get("2024-03-09"); code != http.StatusOK {
+		t.Fatalf("first request: %d", code)
+	}
+	a.generators.slots <- struct{}{} // every worker busy
+	if code := get("2024-03-09"); code != http.StatusOK {
+		t.Fatalf("cached day: %d", code)
+	}
+	if code := get("2024-03-10"); code != http.StatusTooManyRequests {
+		t.Fatalf("uncached day with the pool busy: %d, want 429", code)
+	}
+}
+
+func TestGridJSONAPI(t *testing.T) {
+	ts := httptest.NewServer(newMuxForTest())
+	t.Cleanup(ts.Close)
+	body, _ := json.Marshal(map[string]any{"difficulty": "easy", "size": 6, "box": "2x3"})
+	resp, err := http.Post(ts.URL+"/generate", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	var gen struct {
+		Grid sudoku.Grid `json:"grid"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&gen); err != nil || gen.Grid.Size != 6 || gen.Grid.BoxCols != 3 {
+		t.Fatalf("decode grid: %v %+v", err, gen.Grid)
+	}
+	resp.Body.Close()
+
+	body, _ = json.Marshal(map[string]any{"grid": gen.Grid})
+	resp, err = http.Post(ts.URL+"/solve", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("solve: %v", err)
+	}
+	var sol struct {
+		Solution sudoku.Grid `json:"solution"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&sol); err != nil || sol.Solution.CountSolutions(2) != 1 {
+		t.Fatalf("solve grid: status=%d err=%v", resp.StatusCode, err)
+	}
+	resp.Body.Close()
+
+	ragged := `{"grid":{"size":4,"boxRows":2,"boxCols":2,"cells":[[0,0,0,0],[0,0]]}}`
+	resp, err = http.Post(ts.URL+"/solve", "application/json", strings.NewReader(ragged))
+	if err != nil {
+		t.Fatalf("solve: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("ragged grid: status = %d", resp.StatusCode)
+	}
+}
+
+func TestGenerateAPI_KeepsSolution16(t *testing.T) {
+	a := newAPI()
+	rec := httptest.NewRecorder()
+	body := `{"difficulty":"hard","size":16,"box":"4x4","fresh":true}`
+	start := time.Now()
+	a.handleGenerate(rec, httptest.NewRequest(http.MethodPost, "/generate", strings.NewReader(body)))
+	if rec.Code != http.StatusOK || time.Since(start) > 20*time.Second {
+		t.Fatalf("status %d after %v", rec.Code, time.Since(start))
+	}
+	var out struct {
+		ID string `json:"id"`
+	}
+	json.NewDecoder(rec.Body).Decode(&out)
+	saved, err := a.puzzles.LoadPuzzle(context.Background(), out.ID)
+	if err != nil || !saved.Solution.IsSolved() {
+		t.Fatalf("stored solution: %v", err)
+	}
+}
+
+func TestSolveAPI_Bounded(t *testing.T) {
+	a := newAPI()
+	a.generators = newWorkerPool(GenerationPool{Workers: 1, QueueWait: -1})
+	solve := func(body string) int {
+		rec := httptest.NewRecorder()
+		a.handleSolve(rec, httptest.NewRequest(http.MethodPost, "/solve", strings.NewReader(body)))
+		return rec.Code
+	}
+	// row 15 needs 15 and 16 in its last two cells, which column 15 already
+	// has; first-empty backtracking runs for hours before noticing
+	rows := make([][]int, 16)
+	for r := range rows {
+		rows[r] = make([]int, 16)
+	}
+	for c := 0; c < 14; c++ {
+		rows[15][c] = c + 1
+	}
+	rows[0][15], rows[1][15] = 15, 16
+	cells, _ := json.Marshal(rows)
+	dead := `{"size":16,"box":"4x4","cells":` + string(cells) + `}`
+	done := make(chan int, 1)
+	go func() { done <- solve(dead) }()
+	select {
+	case code := <-done:
+		if code != http.StatusUnprocessableEntity {
+			t.Fatalf("dead 16x16: status %d, want 422", code)
+		}
+	case <-time.After(defaultSolveLimit):
+		t.Fatalf("dead 16x16 still solving after %v", defaultSolveLimit)
+	}
+
+	empty25 := `{"size":25,"box":"5x5","string":"` + strings.Repeat("0", 625) + `"}`
+	a.solveLimit = time.Nanosecond
+	if code := solve(empty25); code != http.StatusServiceUnavailable {
+		t.Fatalf("over the limit: status %d, want 503", code)
+	}
+	grid25 := `{"grid":{"size":25,"boxRows":5,"boxCols":5,"cells":[` + strings.Repeat("["+strings.Repeat("0,", 24)+"0],", 24) + "[" + strings.Repeat("0,", 24) + `0]]}}`
+	if code := solve(grid25); code != http.StatusServiceUnavailable {
+		t.Fatalf("grid over the limit: status %d, want 503", code)
+	}
+	classic := `{"string":"` + sudokutest.Hard + `"}`
+	if code := solve(classic); code != http.StatusServiceUnavailable {
+		t.Fatalf("classic over the limit: status %d, want 503", code)
+	}
+	a.solveLimit = defaultSolveLimit
+	a.generators.slots <- struct{}{} // every worker busy
+	if code := solve(empty25); code != http.StatusTooManyRequests {
+		t.Fatalf("pool busy: status %d, want 429", code)
+	}
+	if code := solve(grid25); code != http.StatusTooManyRequests {
+		t.Fatalf("grid with the pool busy: status %d, want 429", code)
+	}
+	if code := solve(classic); code != http.StatusTooManyRequests {
+		t.Fatalf("classic with the pool busy: status %d, want 429", code)
+	}
+}
+
+func TestHandlerOptions(t *testing.T) {
+	var accessLog bytes.Buffer
+	h, err := Handler(Options{Version: "v1.2.3", MaxBodyBytes: 16, AccessLog: &accessLog})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(h)
+	t.Cleanup(ts.Close)
+	resp, err := http.Get(ts.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var health map[string]string
+	json.NewDecoder(resp.Body).Decode(&health)
+	resp.Body.Close()
+	if health["version"] != "v1.2.3" {
+		t.Fatalf("healthz = %v", health)
+	}
+	body, _ := json.Marshal(map[string]any{"string": sudokutest.Easy})
+	resp, err = http.Post(ts.URL+"/solve", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("oversized body: status = %d", resp.StatusCode)
+	}
+	if !strings.Contains(accessLog.String(), "GET /healthz") || !strings.Contains(accessLog.String(), "POST /solve") {
+		t.Fatalf("access log: %q", accessLog.String())
+	}
+}
+
+func TestRunShutdown(t *testing.T) {
+	t.Setenv("PORT", "0")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Run(ctx, Options{Quiet: true, DrainDelay: -1}); err != nil {
+		t.Fatalf("Run after cancel: %v", err)
+	}
+	t.Setenv("SUDOKU_PUBLIC_ALLOW", "not-an-ip")
+	if err := Run(context.Background(), Options{Quiet: true}); err == nil {
+		t.Fatal("want error for invalid SUDOKU_PUBLIC_ALLOW")
+	}
+}
+
+func TestRunDrains(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Run(ctx, Options{Port: port, Quiet: true, DrainDelay: time.Second}) }()
+	readyz := func() int {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/readyz", port))
+		if err != nil {
+			return 0
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	for i := 0; readyz() != http.StatusOK; i++ {
+		if i == 50 {
+			t.Fatal("server did not become ready")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	cancel()
+	time.Sleep(100 * time.Millisecond)
+	// still listening, but no longer ready
+	if code := readyz(); code != http.StatusServiceUnavailable {
+		t.Fatalf("readyz while draining = %d, want 503", code)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+}
+
+func TestSolveAPI_SizedGrids(t *testing.T) {
+	ts := httptest.NewServer(newMuxForTest())
+	defer ts.Close()
+
+	grid16 := "123456789ABCDEFG" + strings.Repeat("0", 240)
+	for _, tc := range []struct {
+		body string
+		want int
+	}{
+		{`{"size":4,"box":"2x2","cells":[[2,0,4,0],[0,1,2,0],[0,4,0,0],[0,2,3,4]]}`, http.StatusOK},
+		{`{"size":6,"box":"2x3","string":"` + sudokutest.Grid6x2x3 + `"}`, http.StatusOK},
+		{`{"size":16,"box":"4x4","string":"` + grid16 + `"}`, http.StatusOK},
+		{`{"size":4,"box":"2x2","cells":[[2,0,4,0],[0,1,2,0]]}`, http.StatusBadRequest},
+		{`{"size":4,"box":"2x2","cells":[[2,2,0,0],[0,0,0,0],[0,0,0,0],[0,0,0,0]]}`, http.StatusBadRequest},
+		{`{"size":4,"box":"2x2","string":"20400120"}`, http.StatusBadRequest},
+		{`{"size":4,"box":"2x3","string":"` + sudokutest.Grid4x2x2 + `"}`, http.StatusBadRequest},
+		{`{"size":4,"box":"2x2"}`, http.StatusBadRequest},
+	} {
+		resp, err := http.Post(ts.URL+"/solve", "application/json", strings.NewReader(tc.body))
+		if err != nil {
+			t.Fatalf("solve: %v", err)
+		}
+		var out struct {
+			Size     int         `json:"size"`
+			Solution [][]int     `json:"solution"`
+			Grid     sudoku.Grid `json:"grid"`
+		}
+		json.NewDecoder(resp.Body).Decode(&out)
+		resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Errorf("%s: status %d, want %d", tc.body, resp.StatusCode, tc.want)
+			continue
+		}
+		if tc.want == http.StatusOK && (len(out.Solution) != out.Size || !out.Grid.IsSolved()) {
+			t.Errorf("%s: solution = %+v", tc.body, out)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.rumenx.com/sudoku"
+)
+
+// shadowBackends are the secondary solvers a shadow can run.
+var shadowBackends = map[string]func(sudoku.Board) (sudoku.Board, bool){
+	"dlx": sudoku.SolveDLX,
+	"trace": func(b sudoku.Board) (sudoku.Board, bool) {
+		sol, ok, _ := sudoku.SolveTrace(b)
+		return sol, ok
+	},
+}
+
+// shadowSolver re-solves requests with a secondary backend in the background and
+// counts discrepancies with the primary result, so a new solver core can be
+// rolled out against production traffic without affecting responses.
+type shadowSolver struct {
+	name  string
+	solve func(sudoku.Board) (sudoku.Board, bool)
+	sem   chan struct{} // bounds concurrent shadow runs; excess requests are skipped
+	logf  func(string, ...any)
+	wg    sync.WaitGroup
+
+	latencies *latencyTracker // where the shadow runs are timed; nil skips timing
+
+	runs, mismatches, skipped atomic.Int64
+}
+
+// shadowFromEnv reads SUDOKU_SHADOW_SOLVER (dlx|trace; empty disables shadowing)
+// and SUDOKU_SHADOW_CONCURRENCY (default 2).
+func shadowFromEnv(getenv func(string) string, logf func(string, ...any)) (*shadowSolver, error) {
+	name := getenv("SUDOKU_SHADOW_SOLVER")
+	if name == "" {
+		return nil, nil
+	}
+	solve, ok := shadowBackends[name]
+	if !ok {
+		return nil, fmt.Errorf("SUDOKU_SHADOW_SOLVER: unknown backend %q", name)
+	}
+	n := 2
+	if v := getenv("SUDOKU_SHADOW_CONCURRENCY"); v != "" {
+		var err error
+		if n, err = strconv.Atoi(v); err != nil || n < 1 {
+			return nil, fmt.Errorf("SUDOKU_SHADOW_CONCURRENCY: invalid value %q", v)
+		}
+	}
+	return &shadowSolver{name: name, solve: solve, sem: make(chan struct{}, n), logf: logf}, nil
+}
+
+// Check compares the primary result for puzzle against the shadow backend
+// asynchronously. It never blocks the caller.
+func (s *shadowSolver) Check(puzzle, primary sudoku.Board, primaryOK bool) {
+	select {
+	case s.sem <- struct{}{}:
+	default:
+		s.skipped.Add(1)
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer func() { <-s.sem; s.wg.Done() }()
+		start := time.Now()
+		got, ok := s.solve(puzzle)
+		if s.latencies != nil {
+			s.latencies.Observe(latencyKey{"shadow-" + s.name, "any", 9}, time.Since(start))
+		}
+		s.runs.Add(1)
+		if why := shadowDiscrepancy(puzzle, primary, primaryOK, got, ok); why != "" {
+			s.mismatches.Add(1)
+			if s.logf != nil {
+				s.logf("shadow %s: %s for puzzle %s", s.name, why, puzzle)
+			}
+		}
+	}()
+}
+
+// shadowDiscrepancy explains how the shadow result disagrees with the primary one,
+// or returns "". Different solutions only count when the puzzle is unique.
+func shadowDiscrepancy(puzzle, primary sudoku.Board, primaryOK bool, shadow sudoku.Board, shadowOK bool) string {
+	switch {
+	case primaryOK != shadowOK:
+		return fmt.Sprintf("solvable mismatch (primary %v, shadow %v)", primaryOK, shadowOK)
+	case !shadowOK:
+		return ""
+	case !solves(puzzle, shadow):
+		return "shadow returned an invalid solution"
+	case shadow != primary && sudoku.CountSolutions(puzzle, 2) == 1:
+		return "solutions differ on a unique puzzle"
+	}
+	return ""
+}
+
+// solves reports whether sol is a complete valid board keeping the givens of puzzle.
+func solves(puzzle, sol sudoku.Board) bool {
+	if sudoku.Validate(sol) != nil {
+		return false
+	}
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if sol[r][c] == 0 || (puzzle[r][c] != 0 && puzzle[r][c] != sol[r][c]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// shadowStats is the JSON summary exposed on /metrics/sla.
+type shadowStats struct {
+	Backend    string `json:"backend"`
+	Runs       int64  `json:"runs"`
+	Mismatches int64  `json:"mismatches"`
+	Skipped    int64  `json:"skipped"`
+}
+
+func (s *shadowSolver) Stats() shadowStats {
+	return shadowStats{Backend: s.name, Runs: s.runs.Load(), Mismatches: s.mismatches.Load(), Skipped: s.skipped.Load()}
+}
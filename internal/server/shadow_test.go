@@ -0,0 +1,84 @@
+package server
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func TestShadowFromEnv(t *testing.T) {
+	if s, err := shadowFromEnv(os.Getenv, nil); s != nil || err != nil {
+		t.Fatalf("expected shadowing disabled by default: %v %v", s, err)
+	}
+	t.Setenv("SUDOKU_SHADOW_SOLVER", "dlx")
+	t.Setenv("SUDOKU_SHADOW_CONCURRENCY", "4")
+	s, err := shadowFromEnv(os.Getenv, nil)
+	if err != nil || s.name != "dlx" || cap(s.sem) != 4 {
+		t.Fatalf("unexpected shadow %+v %v", s, err)
+	}
+	t.Setenv("SUDOKU_SHADOW_SOLVER", "quantum")
+	if _, err := shadowFromEnv(os.Getenv, nil); err == nil {
+		t.Fatalf("expected unknown backend error")
+	}
+}
+
+func TestShadowCountsDiscrepancies(t *testing.T) {
+	puzzle := sudokutest.MustBoard(sudokutest.Hard)
+	sol, ok := sudoku.Solve(puzzle)
+	var mu sync.Mutex
+	var logged []string
+	logf := func(format string, a ...any) { mu.Lock(); logged = append(logged, format); mu.Unlock() }
+
+	good := &shadowSolver{name: "dlx", solve: sudoku.SolveDLX, sem: make(chan struct{}, 1), logf: logf}
+	good.Check(puzzle, sol, ok)
+	good.wg.Wait()
+	if st := good.Stats(); st.Runs != 1 || st.Mismatches != 0 {
+		t.Fatalf("dlx should agree: %+v", st)
+	}
+
+	broken := &shadowSolver{name: "broken", sem: make(chan struct{}, 1), logf: logf,
+		solve: func(b sudoku.Board) (sudoku.Board, bool) { return b, true }} // returns the unsolved puzzle
+	broken.Check(puzzle, sol, ok)
+	broken.wg.Wait()
+	if st := broken.Stats(); st.Mismatches != 1 || len(logged) != 1 {
+		t.Fatalf("expected one logged mismatch: %+v %v", st, logged)
+	}
+
+	// a full semaphore skips instead of blocking the request
+	broken.sem <- struct{}{}
+	broken.Check(puzzle, sol, ok)
+	if st := broken.Stats(); st.Skipped != 1 || st.Runs != 1 {
+		t.Fatalf("expected skip: %+v", st)
+	}
+}
+
+func TestShadowDiscrepancyNonUnique(t *testing.T) {
+	puzzle := sudokutest.MustBoard(sudokutest.NonUnique)
+	a, _ := sudoku.Solve(puzzle)
+	other := a // the fixture's second solution swaps the blank rectangle's columns
+	for _, r := range []int{1, 2} {
+		other[r][0], other[r][8] = a[r][8], a[r][0]
+	}
+	if sudoku.Validate(other) != nil || other == a {
+		t.Fatalf("bad test setup")
+	}
+	if why := shadowDiscrepancy(puzzle, a, true, other, true); why != "" {
+		t.Fatalf("different solutions of a non-unique puzzle flagged: %s", why)
+	}
+	if why := shadowDiscrepancy(puzzle, a, true, puzzle, true); why == "" {
+		t.Fatalf("incomplete shadow solution not flagged")
+	}
+	if why := shadowDiscrepancy(puzzle, a, true, sudoku.Board{}, false); why == "" {
+		t.Fatalf("solvable mismatch not flagged")
+	}
+	unique := sudokutest.MustBoard(sudokutest.Hard)
+	sol, _ := sudoku.Solve(unique)
+	wrong := sol
+	wrong[0][0], wrong[0][1] = sol[0][1], sol[0][0]
+	if why := shadowDiscrepancy(unique, sol, true, wrong, true); why == "" {
+		t.Fatalf("invalid shadow solution not flagged")
+	}
+}
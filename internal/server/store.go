@@ -0,0 +1,197 @@
+package server
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// retention configures how long stored records live. A zero TTL keeps records until
+// they are deleted; Grace is how long soft-deleted records linger before purge.
+type retention struct {
+	Puzzles time.Duration
+	Grace   time.Duration
+	Sweep   time.Duration // janitor interval
+}
+
+// retentionFromEnv reads SUDOKU_RETENTION_{PUZZLES,GRACE,SWEEP} as Go durations.
+func retentionFromEnv(getenv func(string) string) (retention, error) {
+	r := retention{Grace: time.Hour, Sweep: time.Minute}
+	for _, f := range []struct {
+		env string
+		dst *time.Duration
+	}{
+		{"SUDOKU_RETENTION_PUZZLES", &r.Puzzles},
+		{"SUDOKU_RETENTION_GRACE", &r.Grace},
+		{"SUDOKU_RETENTION_SWEEP", &r.Sweep},
+	} {
+		v := getenv(f.env)
+		if v == "" {
+			continue
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return retention{}, fmt.Errorf("%s: invalid duration %q", f.env, v)
+		}
+		*f.dst = d
+	}
+	if r.Sweep <= 0 {
+		return retention{}, fmt.Errorf("SUDOKU_RETENTION_SWEEP must be positive")
+	}
+	return r, nil
+}
+
+type record[T any] struct {
+	value   T
+	expires time.Time     // zero: never
+	deleted time.Time     // zero: live
+	put     *list.Element // id in memStore.order
+}
+
+// memStore is an in-memory keyed store with expiry and soft delete. Expired and
+// deleted records are invisible to Get and removed for good by Purge. A store
+// with a limit evicts its least recently put records to stay within it.
+type memStore[T any] struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	grace time.Duration
+	limit int // 0: unbounded
+	now   func() time.Time
+	items map[string]*record[T]
+	order *list.List // ids, least recently put first
+}
+
+func newMemStore[T any](ttl, grace time.Duration) *memStore[T] {
+	return &memStore[T]{ttl: ttl, grace: grace, now: time.Now, items: make(map[string]*record[T]), order: list.New()}
+}
+
+// newBoundedMemStore is newMemStore holding at most limit records.
+func newBoundedMemStore[T any](ttl, grace time.Duration, limit int) *memStore[T] {
+	s := newMemStore[T](ttl, grace)
+	s.limit = limit
+	return s
+}
+
+// Put stores v under id, resetting its expiry.
+func (s *memStore[T]) Put(id string, v T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.put(id, v)
+}
+
+// GetOrPut returns the live record for id, first storing create() when there
+// is none, and resets its expiry. Concurrent callers for one id share a value.
+func (s *memStore[T]) GetOrPut(id string, create func() T) T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var v T
+	if rec, ok := s.items[id]; ok && s.live(rec) {
+		v = rec.value
+	} else {
+		v = create()
+	}
+	s.put(id, v)
+	return v
+}
+
+// put stores v under id; s.mu must be held.
+func (s *memStore[T]) put(id string, v T) {
+	if old, ok := s.items[id]; ok {
+		s.order.Remove(old.put)
+	}
+	rec := &record[T]{value: v, put: s.order.PushBack(id)}
+	if s.ttl > 0 {
+		rec.expires = s.now().Add(s.ttl)
+	}
+	s.items[id] = rec
+	for s.limit > 0 && len(s.items) > s.limit {
+		s.drop(s.order.Front().Value.(string))
+	}
+}
+
+// drop removes id for good; s.mu must be held.
+func (s *memStore[T]) drop(id string) {
+	s.order.Remove(s.items[id].put)
+	delete(s.items, id)
+}
+
+// Get returns the live record for id.
+func (s *memStore[T]) Get(id string) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.items[id]
+	if !ok || !s.live(rec) {
+		var zero T
+		return zero, false
+	}
+	return rec.value, true
+}
+
+// Delete soft-deletes id and reports whether a live record existed.
+func (s *memStore[T]) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.items[id]
+	if !ok || !s.live(rec) {
+		return false
+	}
+	rec.deleted = s.now()
+	return true
+}
+
+// Purge drops expired records and soft-deleted ones past the grace period,
+// returning how many were removed.
+func (s *memStore[T]) Purge() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	n := 0
+	for id, rec := range s.items {
+		expired := !rec.expires.IsZero() && !now.Before(rec.expires)
+		gone := !rec.deleted.IsZero() && !now.Before(rec.deleted.Add(s.grace))
+		if expired || gone {
+			s.drop(id)
+			n++
+		}
+	}
+	return n
+}
+
+// Len returns the number of records held, including soft-deleted ones.
+func (s *memStore[T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.items)
+}
+
+func (s *memStore[T]) live(rec *record[T]) bool {
+	if !rec.deleted.IsZero() {
+		return false
+	}
+	return rec.expires.IsZero() || s.now().Before(rec.expires)
+}
+
+// purger is implemented by stores the janitor sweeps.
+type purger interface{ Purge() int }
+
+// runJanitor purges every store each interval until ctx is done.
+func runJanitor(ctx context.Context, interval time.Duration, logf func(string, ...any), stores ...purger) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			n := 0
+			for _, s := range stores {
+				n += s.Purge()
+			}
+			if n > 0 && logf != nil {
+				logf("janitor: purged %d records", n)
+			}
+		}
+	}
+}
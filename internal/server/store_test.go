@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemStoreExpiryAndSoftDelete(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := newMemStore[string](time.Minute, 10*time.Second)
+	s.now = func() time.Time { return now }
+	s.Put("a", "alpha")
+	s.Put("b", "beta")
+	if v, ok := s.Get("a"); !ok || v != "alpha" {
+		t.Fatalf("get a = %q %v", v, ok)
+	}
+	if !s.Delete("b") || s.Delete("b") {
+		t.Fatalf("delete should succeed once")
+	}
+	if _, ok := s.Get("b"); ok {
+		t.Fatalf("soft-deleted record still visible")
+	}
+	// deleted record lingers for the grace period
+	if n := s.Purge(); n != 0 || s.Len() != 2 {
+		t.Fatalf("purged %d early, len=%d", n, s.Len())
+	}
+	now = now.Add(11 * time.Second)
+	if n := s.Purge(); n != 1 {
+		t.Fatalf("expected deleted record purged, got %d", n)
+	}
+	now = now.Add(time.Minute)
+	if _, ok := s.Get("a"); ok {
+		t.Fatalf("expired record still visible")
+	}
+	if n := s.Purge(); n != 1 || s.Len() != 0 {
+		t.Fatalf("expected expired record purged, got %d len=%d", n, s.Len())
+	}
+}
+
+func TestRetentionFromEnv(t *testing.T) {
+	t.Setenv("SUDOKU_RETENTION_PUZZLES", "72h")
+	t.Setenv("SUDOKU_RETENTION_GRACE", "5m")
+	r, err := retentionFromEnv(os.Getenv)
+	if err != nil {
+		t.Fatalf("retention: %v", err)
+	}
+	if r.Puzzles != 72*time.Hour || r.Grace != 5*time.Minute || r.Sweep != time.Minute {
+		t.Fatalf("unexpected retention: %+v", r)
+	}
+	t.Setenv("SUDOKU_RETENTION_SWEEP", "soon")
+	if _, err := retentionFromEnv(os.Getenv); err == nil {
+		t.Fatalf("expected invalid duration error")
+	}
+}
+
+func TestRunJanitorPurges(t *testing.T) {
+	s := newMemStore[int](time.Nanosecond, 0)
+	s.Put("x", 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() { runJanitor(ctx, time.Millisecond, nil, s); close(done) }()
+	deadline := time.Now().Add(time.Second)
+	for s.Len() != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+	if s.Len() != 0 {
+		t.Fatalf("janitor did not purge expired record")
+	}
+}
+
+func TestMemStoreLimitEvictsOldest(t *testing.T) {
+	s := newBoundedMemStore[int](0, 0, 2)
+	s.Put("a", 1)
+	s.Put("b", 2)
+	s.Put("a", 3) // putting again makes a the newest
+	s.Put("c", 4)
+	if _, ok := s.Get("b"); ok || s.Len() != 2 {
+		t.Fatalf("oldest record kept, len=%d", s.Len())
+	}
+	if v, ok := s.Get("a"); !ok || v != 3 {
+		t.Fatalf("get a = %d %v", v, ok)
+	}
+}
+
+func TestMemStoreGetOrPutShared(t *testing.T) {
+	s := newMemStore[*int](time.Hour, 0)
+	var wg sync.WaitGroup
+	got := make([]*int, 8)
+	for i := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i] = s.GetOrPut("k", func() *int { return new(int) })
+		}()
+	}
+	wg.Wait()
+	for _, p := range got {
+		if p != got[0] {
+			t.Fatalf("concurrent callers got different values")
+		}
+	}
+	s.Delete("k")
+	if s.GetOrPut("k", func() *int { return new(int) }) == got[0] {
+		t.Fatalf("deleted record reused")
+	}
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	_ "embed"
+	"net/http"
+	"strings"
+)
+
+// boardComponent is the <sudoku-board> web component, served so sites can embed a
+// playable board with one script tag pointing at this server.
+//
+//go:embed web/sudoku-board.js
+var boardComponent []byte
+
+func handleBoardComponent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeJSON(w, http.StatusMethodNotAllowed, errMsg("method not allowed"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	_, _ = w.Write(boardComponent)
+}
+
+// corsPolicy lists the origins allowed to call the API from a browser ("*"
+// allows any) and what their preflight requests are told they may send.
+type corsPolicy struct {
+	origins []string
+	methods string
+	headers string
+}
+
+// corsFromEnv reads SUDOKU_CORS_ORIGINS, a comma-separated list of origins such
+// as "https://example.com" or "*" (empty disables CORS headers), and the
+// optional SUDOKU_CORS_METHODS and SUDOKU_CORS_HEADERS lists that replace the
+// defaults "GET, POST" and "Content-Type, Idempotency-Key, X-Client-ID".
+func corsFromEnv(getenv func(string) string) corsPolicy {
+	co := corsPolicy{
+		methods: corsList(getenv("SUDOKU_CORS_METHODS"), "GET, POST", strings.ToUpper),
+		headers: corsList(getenv("SUDOKU_CORS_HEADERS"), "Content-Type, Idempotency-Key, X-Client-ID", http.CanonicalHeaderKey),
+	}
+	for _, o := range strings.Split(getenv("SUDOKU_CORS_ORIGINS"), ",") {
+		if o = strings.TrimRight(strings.TrimSpace(o), "/"); o != "" {
+			co.origins = append(co.origins, o)
+		}
+	}
+	return co
+}
+
+// corsList normalizes a comma-separated header value, falling back to def
+// when it is empty.
+func corsList(v, def string, norm func(string) string) string {
+	var out []string
+	for _, f := range strings.Split(v, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			out = append(out, norm(f))
+		}
+	}
+	if len(out) == 0 {
+		return def
+	}
+	return strings.Join(out, ", ")
+}
+
+func (co corsPolicy) allowed(origin string) string {
+	for _, o := range co.origins {
+		if o == "*" || o == origin {
+			return o
+		}
+	}
+	return ""
+}
+
+// wrap adds CORS headers for allowed origins and answers preflight requests.
+// While an allowlist is set every response varies by Origin, so a shared cache
+// never serves one origin's headers (or their absence) to another.
+func (co corsPolicy) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(co.origins) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		h := w.Header()
+		h.Add("Vary", "Origin")
+		origin := r.Header.Get("Origin")
+		allow := co.allowed(origin)
+		if origin == "" || allow == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		h.Set("Access-Control-Allow-Origin", allow)
+		h.Set("Access-Control-Expose-Headers", "Idempotent-Replayed")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			h.Set("Access-Control-Allow-Methods", co.methods)
+			h.Set("Access-Control-Allow-Headers", co.headers)
+			h.Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,179 @@
+// <sudoku-board> — a playable sudoku board backed by a go-sudoku server.
+//
+//   <script src="https://sudoku.example.com/sudoku-board.js"></script>
+//   <sudoku-board difficulty="easy"></sudoku-board>
+//
+// Attributes:
+//   server      API base URL; defaults to the origin this script was loaded from
+//   difficulty  easy | medium | hard (default medium)
+//
+// Events: "sudoku-solved" (detail: {seconds}) when the player completes the grid.
+// No framework and no build step; the server needs SUDOKU_CORS_ORIGINS to allow
+// the embedding site.
+(() => {
+  const scriptOrigin = document.currentScript ? new URL(document.currentScript.src).origin : location.origin;
+
+  const style = `
+    :host { display: inline-block; font-family: system-ui, sans-serif; --accent: #2563eb; }
+    .bar { display: flex; gap: 6px; margin-bottom: 8px; }
+    button { font: inherit; padding: 4px 10px; border: 1px solid #cbd5e1; border-radius: 4px; background: #fff; cursor: pointer; }
+    button:hover { border-color: var(--accent); }
+    .grid { display: grid; grid-template-columns: repeat(9, 2.2em); border: 2px solid #334155; width: max-content; }
+    input { width: 2.2em; height: 2.2em; box-sizing: border-box; border: 1px solid #e2e8f0; text-align: center;
+            font: inherit; font-size: 1.1em; padding: 0; outline: none; background: #fff; }
+    input:focus { background: #dbeafe; }
+    input[readonly] { background: #f1f5f9; font-weight: 600; }
+    input.c3 { border-right: 2px solid #334155; }
+    input.r3 { border-bottom: 2px solid #334155; }
+    input.bad { background: #fecaca; }
+    input.hint { color: var(--accent); }
+    .status { min-height: 1.4em; margin-top: 6px; font-size: .9em; color: #475569; }
+  `;
+
+  class SudokuBoard extends HTMLElement {
+    constructor() {
+      super();
+      this.root = this.attachShadow({ mode: "open" });
+      this.puzzle = null;
+      this.solution = null;
+      this.started = 0;
+    }
+
+    get server() { return (this.getAttribute("server") || scriptOrigin).replace(/\/$/, ""); }
+    get difficulty() { return this.getAttribute("difficulty") || "medium"; }
+
+    connectedCallback() {
+      if (this.cells) return;
+      this.root.innerHTML = `<style>${style}</style>
+        <div class="bar">
+          <button data-act="new">New</button><button data-act="hint">Hint</button>
+          <button data-act="check">Check</button><button data-act="solve">Solve</button>
+        </div>
+        <div class="grid" part="grid"></div>
+        <div class="status" part="status" aria-live="polite"></div>`;
+      const grid = this.root.querySelector(".grid");
+      this.status = this.root.querySelector(".status");
+      this.cells = [];
+      for (let i = 0; i < 81; i++) {
+        const r = Math.floor(i / 9), c = i % 9;
+        const cell = document.createElement("input");
+        cell.inputMode = "numeric";
+        cell.maxLength = 1;
+        cell.setAttribute("aria-label", `r${r + 1}c${c + 1}`);
+        if (c === 2 || c === 5) cell.classList.add("c3");
+        if (r === 2 || r === 5) cell.classList.add("r3");
+        cell.addEventListener("input", () => {
+          cell.value = cell.value.replace(/[^1-9]/g, "");
+          cell.classList.remove("bad", "hint");
+          this.completed();
+        });
+        grid.appendChild(cell);
+        this.cells.push(cell);
+      }
+      this.root.querySelector(".bar").addEventListener("click", (e) => {
+        const act = e.target.dataset && e.target.dataset.act;
+        if (act) this[act]().catch((err) => this.say(err.message));
+      });
+      this.new().catch((err) => this.say(err.message));
+    }
+
+    async api(path, body) {
+      const res = await fetch(this.server + path, {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify(body),
+      });
+      const data = await res.json().catch(() => ({}));
+      if (!res.ok) throw new Error(data.error || `${path}: HTTP ${res.status}`);
+      return data;
+    }
+
+    say(msg) { this.status.textContent = msg; }
+
+    values() { return this.cells.map((c) => Number(c.value) || 0); }
+
+    async new() {
+      this.say("Generating…");
+      const data = await this.api("/generate", { difficulty: this.difficulty });
+      this.puzzle = data.puzzle.flat();
+      this.solution = null;
+      this.cells.forEach((cell, i) => {
+        const v = this.puzzle[i];
+        cell.value = v ? String(v) : "";
+        cell.readOnly = v !== 0;
+        cell.classList.remove("bad", "hint");
+      });
+      this.started = Date.now();
+      this.say(`New ${this.difficulty} puzzle`);
+    }
+
+    async solved() {
+      if (!this.solution) {
+        const rows = [];
+        for (let r = 0; r < 9; r++) rows.push(this.puzzle.slice(r * 9, r * 9 + 9));
+        this.solution = (await this.api("/solve", { puzzle: rows })).solution.flat();
+      }
+      return this.solution;
+    }
+
+    async hint() {
+      if (!this.puzzle) return;
+      const sol = await this.solved();
+      const focused = this.root.activeElement;
+      let i = this.cells.indexOf(focused);
+      if (i < 0 || this.cells[i].readOnly || Number(this.cells[i].value) === sol[i]) {
+        i = this.cells.findIndex((c, j) => Number(c.value) !== sol[j]);
+      }
+      if (i < 0) return this.say("Nothing left to hint");
+      this.cells[i].value = String(sol[i]);
+      this.cells[i].classList.remove("bad");
+      this.cells[i].classList.add("hint");
+      this.say(`Hint: r${Math.floor(i / 9) + 1}c${(i % 9) + 1} is ${sol[i]}`);
+      this.completed();
+    }
+
+    async check() {
+      if (!this.puzzle) return;
+      const sol = await this.solved();
+      let wrong = 0;
+      this.cells.forEach((cell, i) => {
+        const bad = cell.value !== "" && Number(cell.value) !== sol[i];
+        cell.classList.toggle("bad", bad);
+        if (bad) wrong++;
+      });
+      this.say(wrong ? `${wrong} wrong ${wrong === 1 ? "cell" : "cells"}` : "So far so good");
+    }
+
+    async solve() {
+      if (!this.puzzle) return;
+      const sol = await this.solved();
+      this.cells.forEach((cell, i) => { cell.value = String(sol[i]); cell.classList.remove("bad"); });
+      this.say("Solved");
+    }
+
+    completed() {
+      const v = this.values();
+      if (v.includes(0) || !this.solution && !this.validGrid(v)) return;
+      if (this.solution && v.some((x, i) => x !== this.solution[i])) return;
+      const seconds = Math.round((Date.now() - this.started) / 1000);
+      this.say(`Solved in ${seconds}s!`);
+      this.dispatchEvent(new CustomEvent("sudoku-solved", { detail: { seconds }, bubbles: true, composed: true }));
+    }
+
+    // validGrid checks rows, columns and boxes without the solution.
+    validGrid(v) {
+      for (let u = 0; u < 9; u++) {
+        const row = new Set(), col = new Set(), box = new Set();
+        for (let k = 0; k < 9; k++) {
+          row.add(v[u * 9 + k]);
+          col.add(v[k * 9 + u]);
+          box.add(v[(Math.floor(u / 3) * 3 + Math.floor(k / 3)) * 9 + (u % 3) * 3 + (k % 3)]);
+        }
+        if (row.size < 9 || col.size < 9 || box.size < 9) return false;
+      }
+      return true;
+    }
+  }
+
+  if (!customElements.get("sudoku-board")) customElements.define("sudoku-board", SudokuBoard);
+})();
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestBoardComponent(t *testing.T) {
+	rec := httptest.NewRecorder()
+	handleBoardComponent(rec, httptest.NewRequest(http.MethodGet, "/sudoku-board.js", nil))
+	body, _ := io.ReadAll(rec.Body)
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/javascript") {
+		t.Fatalf("status %d, content type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(string(body), `customElements.define("sudoku-board"`) {
+		t.Fatal("component not served")
+	}
+	rec = httptest.NewRecorder()
+	handleBoardComponent(rec, httptest.NewRequest(http.MethodPost, "/sudoku-board.js", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST: status %d", rec.Code)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	t.Setenv("SUDOKU_CORS_ORIGINS", "https://a.example, https://b.example/")
+	co := corsFromEnv(os.Getenv)
+	h := co.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) }))
+
+	req := httptest.NewRequest(http.MethodOptions, "/generate", nil)
+	req.Header.Set("Origin", "https://b.example")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Origin") != "https://b.example" ||
+		!strings.Contains(rec.Header().Get("Access-Control-Allow-Headers"), "Idempotency-Key") {
+		t.Fatalf("preflight: %d %v", rec.Code, rec.Header())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/generate", nil)
+	req.Header.Set("Origin", "https://a.example")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTeapot || rec.Header().Get("Access-Control-Allow-Origin") != "https://a.example" {
+		t.Fatalf("simple request: %d %v", rec.Code, rec.Header())
+	}
+
+	req.Header.Set("Origin", "https://evil.example")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Header().Get("Access-Control-Allow-Origin") != "" || rec.Header().Get("Vary") != "Origin" {
+		t.Fatalf("unlisted origin: %v", rec.Header())
+	}
+
+	req.Header.Del("Origin")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Header().Get("Vary") != "Origin" {
+		t.Fatalf("no Vary without Origin: %v", rec.Header())
+	}
+
+	t.Setenv("SUDOKU_CORS_ORIGINS", "")
+	rec = httptest.NewRecorder()
+	corsFromEnv(os.Getenv).wrap(http.NotFoundHandler()).ServeHTTP(rec, req)
+	if rec.Header().Get("Vary") != "" {
+		t.Fatalf("Vary without an allowlist: %v", rec.Header())
+	}
+
+	t.Setenv("SUDOKU_CORS_ORIGINS", "*")
+	if corsFromEnv(os.Getenv).allowed("https://any.example") != "*" {
+		t.Fatal("wildcard not honoured")
+	}
+}
+
+func TestCORSMethodsAndHeaders(t *testing.T) {
+	t.Setenv("SUDOKU_CORS_ORIGINS", "*")
+	t.Setenv("SUDOKU_CORS_METHODS", "get, post ,delete")
+	t.Setenv("SUDOKU_CORS_HEADERS", "content-type,x-api-key")
+	h := corsFromEnv(os.Getenv).wrap(http.NotFoundHandler())
+	req := httptest.NewRequest(http.MethodOptions, "/solve", nil)
+	req.Header.Set("Origin", "https://c.example")
+	req.Header.Set("Access-Control-Request-Method", "DELETE")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Allow-Methods"); got != "GET, POST, DELETE" {
+		t.Errorf("methods = %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); got != "Content-Type, X-Api-Key" {
+		t.Errorf("headers = %q", got)
+	}
+}
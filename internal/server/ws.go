@@ -0,0 +1,200 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	mrand "math/rand/v2"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"go.rumenx.com/sudoku"
+)
+
+const (
+	wsIdleTimeout = 10 * time.Minute // a session with no message for this long is closed
+	wsMaxMessage  = 4 << 10
+)
+
+// wsNewLimit bounds the puzzles one session may start, the first included. A
+// socket is a single request to the rate limiter, so without it one
+// connection could generate without end.
+var wsNewLimit = RateLimit{Rate: 1.0 / 10, Burst: 5}
+
+// wsCommand is a client message on /ws/game. Type is one of new (with an
+// optional difficulty), set (row, col and value, 0 to clear; 0-based), undo,
+// redo, restart and hint.
+type wsCommand struct {
+	Type       string `json:"type"`
+	Difficulty string `json:"difficulty,omitempty"`
+	Row        int    `json:"row"`
+	Col        int    `json:"col"`
+	Value      int    `json:"value"`
+}
+
+// wsEvent is a server message on /ws/game. Type is state (after new, undo,
+// redo and restart), move (after set), hint, solved or error; each carries the
+// fields that apply.
+type wsEvent struct {
+	Type       string        `json:"type"`
+	Board      *sudoku.Board `json:"board,omitempty"`
+	Givens     *sudoku.Board `json:"givens,omitempty"`
+	Difficulty string        `json:"difficulty,omitempty"`
+	Row        int           `json:"row"`
+	Col        int           `json:"col"`
+	Value      int           `json:"value"`
+	Legal      bool          `json:"legal,omitempty"`     // move: no peer holds the value
+	Conflicts  []sudoku.Cell `json:"conflicts,omitempty"` // move: peers holding the value
+	Mistakes   []sudoku.Cell `json:"mistakes,omitempty"`  // state and move: entries disagreeing with the solution
+	Step       *sudoku.Step  `json:"step,omitempty"`      // hint: the reasoning, when singles find it
+	CanUndo    bool          `json:"canUndo,omitempty"`
+	CanRedo    bool          `json:"canRedo,omitempty"`
+	ElapsedMs  int64         `json:"elapsedMs,omitempty"`
+	Moves      int           `json:"moves,omitempty"` // solved: moves played
+	Error      string        `json:"error,omitempty"`
+}
+
+// handleGameSocket runs one sudoku.Game per WebSocket connection, starting with
+// a puzzle of ?difficulty= (default easy), so thin clients can play without a
+// local solver. When that first puzzle fails, the session waits for a new
+// message and rejects game commands until then. Browsers must connect from the server's own origin or one
+// listed in SUDOKU_CORS_ORIGINS.
+func (a *api) handleGameSocket(w http.ResponseWriter, r *http.Request) {
+	s := websocket.Server{Handshake: a.cors.wsHandshake, Handler: a.playGame}
+	s.ServeHTTP(w, r)
+}
+
+func (co corsPolicy) wsHandshake(cfg *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" { // not a browser
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return err
+	}
+	if u.Host == r.Host || co.allowed(origin) != "" {
+		return nil
+	}
+	return websocket.ErrBadWebSocketOrigin
+}
+
+func (a *api) playGame(ws *websocket.Conn) {
+	defer ws.Close()
+	ws.MaxPayloadBytes = wsMaxMessage
+	send := func(ev wsEvent) bool { return websocket.JSON.Send(ws, ev) == nil }
+
+	var game *sudoku.Game
+	starts := newMemRateStore() // this session's wsNewLimit bucket
+	start := func(difficulty string) bool {
+		d, ok := parseDifficulty(difficulty)
+		if !ok {
+			return send(wsEvent{Type: "error", Error: "invalid difficulty"})
+		}
+		if ok, wait := starts.Take("new", wsNewLimit, time.Now()); !ok {
+			return send(wsEvent{Type: "error", Error: fmt.Sprintf("too many new puzzles; retry in %ds", max(1, int(math.Ceil(wait.Seconds()))))})
+		}
+		if !a.generators.take(ws.Request().Context()) {
+			return send(wsEvent{Type: "error", Error: "all generation workers are busy"})
+		}
+		p, err := sudoku.NewGenerator(mrand.Uint64()).GeneratePuzzle(d, 3)
+		a.generators.release()
+		if err != nil {
+			return send(wsEvent{Type: "error", Error: "generation failed"})
+		}
+		game = sudoku.NewGame(p)
+		return send(gameState(game))
+	}
+	if !start(ws.Request().URL.Query().Get("difficulty")) {
+		return
+	}
+	for {
+		ws.SetReadDeadline(time.Now().Add(wsIdleTimeout))
+		var cmd wsCommand
+		if err := websocket.JSON.Receive(ws, &cmd); err != nil {
+			return // closed, idle, oversized or not JSON
+		}
+		if game == nil && cmd.Type != "new" { // the first start failed
+			if !send(wsEvent{Type: "error", Error: "no game in progress; send new"}) {
+				return
+			}
+			continue
+		}
+		var ok bool
+		switch cmd.Type {
+		case "new":
+			ok = start(cmd.Difficulty)
+		case "set":
+			ok = send(gameMove(game, cmd))
+			if ok && game.Solved() {
+				ok = send(wsEvent{Type: "solved", ElapsedMs: game.Elapsed().Milliseconds(), Moves: len(game.Moves())})
+			}
+		case "undo":
+			game.Undo()
+			ok = send(gameState(game))
+		case "redo":
+			game.Redo()
+			ok = send(gameState(game))
+		case "restart":
+			game.Restart()
+			ok = send(gameState(game))
+		case "hint":
+			ok = send(gameHint(game))
+		default:
+			ok = send(wsEvent{Type: "error", Error: "unknown message type"})
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+func gameState(g *sudoku.Game) wsEvent {
+	b, p := g.Board(), g.Puzzle()
+	return wsEvent{
+		Type: "state", Board: &b, Givens: &p.Givens, Difficulty: string(p.Difficulty),
+		Mistakes: p.Mistakes(b), CanUndo: g.CanUndo(), CanRedo: g.CanRedo(), ElapsedMs: g.Elapsed().Milliseconds(),
+	}
+}
+
+// gameMove applies a set command; the entry is kept even when it conflicts, as
+// on paper, and the event says what is wrong with it.
+func gameMove(g *sudoku.Game, cmd wsCommand) wsEvent {
+	if err := g.Set(cmd.Row, cmd.Col, cmd.Value); err != nil {
+		return wsEvent{Type: "error", Row: cmd.Row, Col: cmd.Col, Value: cmd.Value, Error: err.Error()}
+	}
+	b := g.Board()
+	conflicts := sudoku.Conflicts(b, cmd.Row, cmd.Col, cmd.Value)
+	return wsEvent{
+		Type: "move", Board: &b, Row: cmd.Row, Col: cmd.Col, Value: cmd.Value,
+		Legal: len(conflicts) == 0, Conflicts: conflicts, Mistakes: g.Puzzle().Mistakes(b),
+		CanUndo: g.CanUndo(), CanRedo: g.CanRedo(),
+	}
+}
+
+// gameHint explains the next single when the player's entries allow one and
+// otherwise reveals a cell of the solution, preferring one the player got wrong.
+func gameHint(g *sudoku.Game) wsEvent {
+	b, p := g.Board(), g.Puzzle()
+	if len(p.Mistakes(b)) == 0 {
+		if st, ok := sudoku.HintExplain(b); ok {
+			return wsEvent{Type: "hint", Row: st.Cell.Row, Col: st.Cell.Col, Value: st.Value, Step: &st}
+		}
+	}
+	cells := p.Mistakes(b)
+	for r := 0; r < 9 && len(cells) == 0; r++ {
+		for c := 0; c < 9; c++ {
+			if b[r][c] == 0 {
+				cells = append(cells, sudoku.Cell{Row: r, Col: c})
+				break
+			}
+		}
+	}
+	if len(cells) == 0 {
+		return wsEvent{Type: "error", Error: "puzzle already solved"}
+	}
+	c := cells[0]
+	return wsEvent{Type: "hint", Row: c.Row, Col: c.Col, Value: p.Solution[c.Row][c.Col]}
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/websocket"
+
+	"go.rumenx.com/sudoku"
+)
+
+func dialGame(t *testing.T, origin, difficulty string) (*websocket.Conn, error) {
+	t.Helper()
+	a := newAPI()
+	a.cors = corsFromEnv(os.Getenv)
+	return dialGameAPI(t, a, origin, difficulty)
+}
+
+func dialGameAPI(t *testing.T, a *api, origin, difficulty string) (*websocket.Conn, error) {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(a.handleGameSocket))
+	t.Cleanup(ts.Close)
+	if origin == "" {
+		origin = ts.URL
+	}
+	return websocket.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/ws/game?difficulty="+difficulty, "", origin)
+}
+
+func exchange(t *testing.T, ws *websocket.Conn, cmd wsCommand) wsEvent {
+	t.Helper()
+	if err := websocket.JSON.Send(ws, cmd); err != nil {
+		t.Fatal(err)
+	}
+	return receive(t, ws)
+}
+
+func receive(t *testing.T, ws *websocket.Conn) wsEvent {
+	t.Helper()
+	var ev wsEvent
+	if err := websocket.JSON.Receive(ws, &ev); err != nil {
+		t.Fatal(err)
+	}
+	return ev
+}
+
+func TestGameSocket(t *testing.T) {
+	ws, err := dialGame(t, "", "easy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	state := receive(t, ws)
+	if state.Type != "state" || state.Difficulty != "easy" || state.Board == nil || *state.Board != *state.Givens {
+		t.Fatalf("initial state = %+v", state)
+	}
+	hint := exchange(t, ws, wsCommand{Type: "hint"})
+	if hint.Type != "hint" || hint.Value == 0 || state.Board[hint.Row][hint.Col] != 0 {
+		t.Fatalf("hint = %+v", hint)
+	}
+	move := exchange(t, ws, wsCommand{Type: "set", Row: hint.Row, Col: hint.Col, Value: hint.Value})
+	if move.Type != "move" || !move.Legal || len(move.Mistakes) != 0 || !move.CanUndo {
+		t.Fatalf("correct move = %+v", move)
+	}
+	// a value already in the row conflicts and is a mistake
+	r := hint.Row
+	var clash, col int
+	for c := 0; c < 9; c++ {
+		if v := state.Givens[r][c]; v != 0 && clash == 0 {
+			clash = v
+		} else if state.Board[r][c] == 0 && c != hint.Col {
+			col = c
+		}
+	}
+	move = exchange(t, ws, wsCommand{Type: "set", Row: r, Col: col, Value: clash})
+	if move.Legal || len(move.Conflicts) == 0 || len(move.Mistakes) != 1 {
+		t.Fatalf("conflicting move = %+v", move)
+	}
+	undo := exchange(t, ws, wsCommand{Type: "undo"})
+	if undo.Type != "state" || undo.Board[r][col] != 0 || !undo.CanRedo {
+		t.Fatalf("undo = %+v", undo)
+	}
+	for _, cmd := range []wsCommand{{Type: "jump"}, {Type: "set", Row: 9}, {Type: "new", Difficulty: "impossible"}} {
+		if ev := exchange(t, ws, cmd); ev.Type != "error" {
+			t.Errorf("%+v: got %+v, want error", cmd, ev)
+		}
+	}
+	if ev := exchange(t, ws, wsCommand{Type: "new", Difficulty: "medium"}); ev.Type != "state" || ev.Difficulty != "medium" {
+		t.Fatalf("new = %+v", ev)
+	}
+}
+
+func TestGameSocketWithoutGame(t *testing.T) {
+	ws, err := dialGame(t, "", "bogus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	if ev := receive(t, ws); ev.Type != "error" {
+		t.Fatalf("invalid difficulty: %+v", ev)
+	}
+	for _, typ := range []string{"undo", "redo", "restart", "set", "hint"} {
+		if ev := exchange(t, ws, wsCommand{Type: typ}); ev.Type != "error" {
+			t.Fatalf("%s before a game: %+v", typ, ev)
+		}
+	}
+	if ev := exchange(t, ws, wsCommand{Type: "new"}); ev.Type != "state" {
+		t.Fatalf("new after a failed start: %+v", ev)
+	}
+}
+
+func TestGameSocketBoundsGeneration(t *testing.T) {
+	a := newAPI()
+	a.generators = newWorkerPool(GenerationPool{Workers: 1, QueueWait: -1})
+	ws, err := dialGameAPI(t, a, "", "easy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	receive(t, ws)
+	a.generators.slots <- struct{}{} // every worker busy
+	if ev := exchange(t, ws, wsCommand{Type: "new"}); ev.Type != "error" || !strings.Contains(ev.Error, "busy") {
+		t.Fatalf("new with the pool busy: %+v", ev)
+	}
+	a.generators.release()
+	// the first puzzle and the refused one used two of the burst
+	for i := 2; i < wsNewLimit.Burst; i++ {
+		if ev := exchange(t, ws, wsCommand{Type: "new"}); ev.Type != "state" {
+			t.Fatalf("new #%d: %+v", i+1, ev)
+		}
+	}
+	if ev := exchange(t, ws, wsCommand{Type: "new"}); ev.Type != "error" || !strings.Contains(ev.Error, "too many") {
+		t.Fatalf("new past the session limit: %+v", ev)
+	}
+}
+
+func TestGameSocketSolved(t *testing.T) {
+	ws, err := dialGame(t, "", "easy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	receive(t, ws)
+	for {
+		hint := exchange(t, ws, wsCommand{Type: "hint"})
+		if hint.Type != "hint" {
+			t.Fatalf("hint = %+v", hint)
+		}
+		move := exchange(t, ws, wsCommand{Type: "set", Row: hint.Row, Col: hint.Col, Value: hint.Value})
+		if move.Type != "move" {
+			t.Fatalf("move = %+v", move)
+		}
+		if sudoku.IsComplete(*move.Board) {
+			break
+		}
+	}
+	if ev := receive(t, ws); ev.Type != "solved" || ev.Moves == 0 {
+		t.Fatalf("after last move: %+v", ev)
+	}
+}
+
+func TestGameSocketOrigin(t *testing.T) {
+	if _, err := dialGame(t, "https://evil.example", "easy"); err == nil {
+		t.Fatal("foreign origin accepted")
+	}
+	t.Setenv("SUDOKU_CORS_ORIGINS", "https://evil.example")
+	ws, err := dialGame(t, "https://evil.example", "easy")
+	if err != nil {
+		t.Fatalf("allowed origin rejected: %v", err)
+	}
+	ws.Close()
+}
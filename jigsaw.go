@@ -0,0 +1,222 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+)
+
+// jigsawCheckBudget bounds the search used to prove a random layout is solvable.
+const jigsawCheckBudget = 20000
+
+// NewJigsawGrid creates an empty grid whose regions follow the given layout:
+// regions[r][c] is the region id (0..size-1) of each cell, and every region must
+// hold exactly size orthogonally connected cells. BoxRows/BoxCols are left zero.
+func NewJigsawGrid(regions [][]int) (Grid, error) {
+	if err := validateRegions(regions); err != nil {
+		return Grid{}, err
+	}
+	size := len(regions)
+	g := Grid{Size: size, Cells: make([][]int, size), Regions: make([][]int, size)}
+	for r := range g.Cells {
+		g.Cells[r] = make([]int, size)
+		g.Regions[r] = append([]int(nil), regions[r]...)
+	}
+	return g, nil
+}
+
+// validateRegions checks that regions is a square partition into size connected
+// regions of size cells each.
+func validateRegions(regions [][]int) error {
+	size := len(regions)
+	if size == 0 || size > MaxGridSize {
+		return fmt.Errorf("invalid jigsaw size %d", size)
+	}
+	counts := make([]int, size)
+	for r, row := range regions {
+		if len(row) != size {
+			return fmt.Errorf("jigsaw row %d has %d cells, want %d", r, len(row), size)
+		}
+		for _, id := range row {
+			if id < 0 || id >= size {
+				return fmt.Errorf("jigsaw region id %d out of range", id)
+			}
+			counts[id]++
+		}
+	}
+	for id, n := range counts {
+		if n != size {
+			return fmt.Errorf("jigsaw region %d has %d cells, want %d", id, n, size)
+		}
+		if !regionConnected(regions, id) {
+			return fmt.Errorf("jigsaw region %d is not connected", id)
+		}
+	}
+	return nil
+}
+
+// regionConnected reports whether region id forms one orthogonally connected piece.
+func regionConnected(regions [][]int, id int) bool {
+	size := len(regions)
+	seen := make([]bool, size*size)
+	var stack []int
+	total := 0
+	for i := 0; i < size*size; i++ {
+		if regions[i/size][i%size] == id {
+			total++
+			if stack == nil {
+				stack = append(stack, i)
+				seen[i] = true
+			}
+		}
+	}
+	reached := 0
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		reached++
+		r, c := i/size, i%size
+		for _, d := range [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+			r2, c2 := r+d[0], c+d[1]
+			if r2 < 0 || r2 >= size || c2 < 0 || c2 >= size {
+				continue
+			}
+			j := r2*size + c2
+			if !seen[j] && regions[r2][c2] == id {
+				seen[j] = true
+				stack = append(stack, j)
+			}
+		}
+	}
+	return reached == total
+}
+
+// RandomJigsawRegions returns a random connected jigsaw layout of the given size.
+// It starts from the most square box layout (rows for prime sizes) and applies
+// random boundary swaps that keep every region connected and of equal size.
+// The layout is not guaranteed to admit a solution; see NewRandomJigsawGrid.
+func RandomJigsawRegions(size int) ([][]int, error) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return randomJigsawRegions(defaultGenerator().rng, size)
+}
+
+func randomJigsawRegions(rng *rand.Rand, size int) ([][]int, error) {
+	if size < 2 || size > MaxGridSize {
+		return nil, fmt.Errorf("invalid jigsaw size %d", size)
+	}
+	br := 1
+	for d := 1; d*d <= size; d++ {
+		if size%d == 0 {
+			br = d
+		}
+	}
+	bc := size / br
+	regions := make([][]int, size)
+	for r := range regions {
+		regions[r] = make([]int, size)
+		for c := range regions[r] {
+			regions[r][c] = (r/br)*(size/bc) + c/bc
+		}
+	}
+	dirs := [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+	for iter := 0; iter < size*size*8; iter++ {
+		// move a boundary cell a from region A into neighbouring region B ...
+		ai := rng.IntN(size * size)
+		ar, ac := ai/size, ai%size
+		d := dirs[rng.IntN(4)]
+		nr, nc := ar+d[0], ac+d[1]
+		if nr < 0 || nr >= size || nc < 0 || nc >= size || regions[nr][nc] == regions[ar][ac] {
+			continue
+		}
+		regA, regB := regions[ar][ac], regions[nr][nc]
+		// ... and a cell of B touching A back into A, keeping sizes equal.
+		var cands []int
+		for i := 0; i < size*size; i++ {
+			r, c := i/size, i%size
+			if regions[r][c] != regB {
+				continue
+			}
+			for _, d2 := range dirs {
+				r2, c2 := r+d2[0], c+d2[1]
+				if r2 >= 0 && r2 < size && c2 >= 0 && c2 < size && regions[r2][c2] == regA && (r2 != ar || c2 != ac) {
+					cands = append(cands, i)
+					break
+				}
+			}
+		}
+		if len(cands) == 0 {
+			continue
+		}
+		bi := cands[rng.IntN(len(cands))]
+		regions[ar][ac] = regB
+		regions[bi/size][bi%size] = regA
+		if !regionConnected(regions, regA) || !regionConnected(regions, regB) {
+			regions[ar][ac] = regA
+			regions[bi/size][bi%size] = regB
+		}
+	}
+	return regions, nil
+}
+
+// NewRandomJigsawGrid returns an empty jigsaw grid with a random layout that is
+// known to admit at least one solution.
+func NewRandomJigsawGrid(size int) (Grid, error) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return defaultGenerator().RandomJigsawGrid(size)
+}
+
+func newRandomJigsawGrid(rng *rand.Rand, size int) (Grid, error) {
+	for try := 0; try < 50; try++ {
+		regions, err := randomJigsawRegions(rng, size)
+		if err != nil {
+			return Grid{}, err
+		}
+		g, err := NewJigsawGrid(regions)
+		if err != nil {
+			return Grid{}, err
+		}
+		sols, _ := KillerGrid{Grid: g}.solutions(1, jigsawCheckBudget)
+		if len(sols) == 1 {
+			return g, nil
+		}
+	}
+	return Grid{}, errors.New("no solvable jigsaw layout found")
+}
+
+// maskFill completes w in place with a random solution. Random value orders have
+// heavy-tailed search times on irregular layouts, so the search restarts with a
+// fresh order whenever it exceeds a small node budget. The last run has no
+// budget, so false means w has no solution.
+func (g Grid) maskFill(w *Grid, rng *rand.Rand) bool {
+	const restarts = 50
+	for restart := 0; restart < restarts; restart++ {
+		ks := newMaskSolver(*w)
+		ks.rng = rng
+		if restart < restarts-1 {
+			ks.maxNodes = jigsawCheckBudget
+		}
+		found := false
+		ks.search(func(sol Grid) bool {
+			for r := range sol.Cells {
+				copy(w.Cells[r], sol.Cells[r])
+			}
+			found = true
+			return true
+		})
+		if found {
+			return true
+		}
+		if ks.nodes <= ks.maxNodes {
+			return false // the whole space was searched
+		}
+	}
+	return false
+}
+
+// maskUnique is hasUniqueSolution backed by the most-constrained-cell search.
+func (g Grid) maskUnique(w Grid, limit int) bool {
+	sols, _ := KillerGrid{Grid: w}.solutions(limit, 0)
+	return len(sols) == 1
+}
@@ -0,0 +1,87 @@
+package sudoku
+
+import "testing"
+
+func TestNewJigsawGridValidation(t *testing.T) {
+	// 4x4 with L-shaped regions
+	ok := [][]int{
+		{0, 0, 0, 1},
+		{0, 2, 1, 1},
+		{2, 2, 3, 1},
+		{2, 3, 3, 3},
+	}
+	g, err := NewJigsawGrid(ok)
+	if err != nil {
+		t.Fatalf("valid layout rejected: %v", err)
+	}
+	if g.Size != 4 || g.regionOf(1, 1) != 2 {
+		t.Fatalf("unexpected grid: %+v", g)
+	}
+	for name, bad := range map[string][][]int{
+		"ragged":       {{0, 0, 0, 1}, {0, 2, 1}, {2, 2, 3, 1}, {2, 3, 3, 3}},
+		"uneven":       {{0, 0, 0, 0}, {0, 2, 1, 1}, {2, 2, 3, 1}, {2, 3, 3, 3}},
+		"disconnected": {{0, 0, 1, 0}, {2, 2, 1, 1}, {2, 2, 3, 1}, {0, 3, 3, 3}},
+	} {
+		if _, err := NewJigsawGrid(bad); err == nil {
+			t.Fatalf("%s layout accepted", name)
+		}
+	}
+	// region rule applies instead of 2x2 boxes: (1,0) and (0,2) share region 0
+	g.Cells[1][0], g.Cells[0][2] = 1, 1
+	if err := g.Validate(); err == nil {
+		t.Fatalf("expected region duplicate to be invalid")
+	}
+}
+
+func TestRandomJigsawGenerateSolveHint(t *testing.T) {
+	SetRandSeed(3)
+	for _, size := range []int{5, 6, 9} {
+		g, err := NewRandomJigsawGrid(size)
+		if err != nil {
+			t.Fatalf("layout %d: %v", size, err)
+		}
+		if err := validateRegions(g.Regions); err != nil {
+			t.Fatalf("random layout invalid: %v", err)
+		}
+		puz, err := g.Generate(Medium, 3)
+		if err != nil {
+			t.Fatalf("generate %d: %v", size, err)
+		}
+		if err := puz.Validate(); err != nil || !puz.maskUnique(puz, 2) {
+			t.Fatalf("size %d: invalid or non-unique puzzle (%v)", size, err)
+		}
+		sol, ok := puz.Solve()
+		if !ok || sol.Validate() != nil || sol.countClues(sol) != size*size {
+			t.Fatalf("size %d: solve failed", size)
+		}
+		r, c, v, ok := HintGrid(puz)
+		if !ok || puz.Cells[r][c] != 0 || v != sol.Cells[r][c] {
+			t.Fatalf("size %d: bad hint r=%d c=%d v=%d", size, r, c, v)
+		}
+	}
+}
+
+func TestJigsawSolveUnsolvable(t *testing.T) {
+	regions := make([][]int, 9)
+	for r := range regions {
+		regions[r] = make([]int, 9)
+		for c := range regions[r] {
+			regions[r][c] = r/3*3 + c/3
+		}
+	}
+	g, err := NewJigsawGrid(regions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := g.Solve(); !ok {
+		t.Fatal("empty jigsaw reported unsolvable")
+	}
+	// r1c9 has no candidate left, so the search must give up for good
+	for c := 0; c < 8; c++ {
+		g.Cells[0][c] = c + 1
+	}
+	g.Cells[1][8] = 9
+	if _, ok := g.Solve(); ok {
+		t.Fatal("unsolvable jigsaw solved")
+	}
+}
@@ -0,0 +1,399 @@
+package sudoku
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// Cell identifies a grid position by zero-based row and column.
+type Cell struct {
+	Row int
+	Col int
+}
+
+// Cage is a killer sudoku cage: its cells must sum to Sum with no repeated value.
+type Cage struct {
+	Sum   int
+	Cells []Cell
+}
+
+// KillerGrid is a Grid with killer cages. Cages must not overlap; cells outside
+// every cage are only bound by the classic rules.
+type KillerGrid struct {
+	Grid
+	Cages []Cage
+}
+
+// NewKillerGrid attaches cages to g after checking the layout fits the grid.
+func NewKillerGrid(g Grid, cages []Cage) (KillerGrid, error) {
+	k := KillerGrid{Grid: g, Cages: cages}
+	if _, err := k.cageIndex(); err != nil {
+		return KillerGrid{}, err
+	}
+	return k, nil
+}
+
+// cageIndex maps every cell (r*Size+c) to its cage index or -1, validating the layout.
+func (k KillerGrid) cageIndex() ([]int, error) {
+	s := k.Size
+	idx := make([]int, s*s)
+	for i := range idx {
+		idx[i] = -1
+	}
+	for ci, cg := range k.Cages {
+		if len(cg.Cells) == 0 || len(cg.Cells) > s {
+			return nil, fmt.Errorf("invalid cage %d: %d cells", ci, len(cg.Cells))
+		}
+		n := len(cg.Cells)
+		lo, hi := n*(n+1)/2, n*(2*s-n+1)/2
+		if cg.Sum < lo || cg.Sum > hi {
+			return nil, fmt.Errorf("invalid cage %d: sum %d outside [%d,%d]", ci, cg.Sum, lo, hi)
+		}
+		for _, c := range cg.Cells {
+			if c.Row < 0 || c.Row >= s || c.Col < 0 || c.Col >= s {
+				return nil, fmt.Errorf("invalid cage %d: cell %v out of range", ci, c)
+			}
+			if idx[c.Row*s+c.Col] != -1 {
+				return nil, fmt.Errorf("invalid cage %d: cell %v already caged", ci, c)
+			}
+			idx[c.Row*s+c.Col] = ci
+		}
+	}
+	return idx, nil
+}
+
+// CageConstraint returns the killer rule for cages: no value repeats within a
+// cage, and the cells of each cage sum to its Sum. The layout is not checked;
+// NewKillerGrid does that.
+func CageConstraint(cages []Cage) Constraint {
+	k := &cageConstraint{cages: cages, index: make(map[Cell]int)}
+	for ci, cg := range cages {
+		for _, c := range cg.Cells {
+			k.index[c] = ci
+		}
+	}
+	return k
+}
+
+type cageConstraint struct {
+	cages []Cage
+	index map[Cell]int // cell -> cage
+}
+
+// Allows also rejects v when the cells left empty in the cage could no longer
+// reach its Sum with distinct unused values.
+func (k *cageConstraint) Allows(g *Grid, r, c, v int) bool {
+	ci, ok := k.index[Cell{r, c}]
+	if !ok {
+		return true
+	}
+	cg := k.cages[ci]
+	used := uint64(1) << v
+	sum, left := v, 0
+	for _, cell := range cg.Cells {
+		if cell == (Cell{r, c}) {
+			continue
+		}
+		x := g.Cells[cell.Row][cell.Col]
+		if x == 0 {
+			left++
+			continue
+		}
+		if x == v {
+			return false
+		}
+		used |= 1 << x
+		sum += x
+	}
+	if left == 0 {
+		return sum == cg.Sum
+	}
+	// bound the remaining cells by the smallest/largest unused distinct values
+	lo, hi := 0, 0
+	for n, x := 0, 1; n < left && x <= g.Size; x++ {
+		if used&(1<<x) == 0 {
+			lo += x
+			n++
+		}
+	}
+	for n, x := 0, g.Size; n < left && x >= 1; x-- {
+		if used&(1<<x) == 0 {
+			hi += x
+			n++
+		}
+	}
+	return sum+lo <= cg.Sum && sum+hi >= cg.Sum
+}
+
+// Validate checks for repeats, partial sums above the target and complete
+// cages that miss it.
+func (k *cageConstraint) Validate(g *Grid) error {
+	for _, cg := range k.cages {
+		seen := make([]bool, g.Size+1)
+		sum, filled := 0, 0
+		for _, c := range cg.Cells {
+			v := g.Cells[c.Row][c.Col]
+			if v == 0 {
+				continue
+			}
+			if seen[v] {
+				return ErrInvalidBoard
+			}
+			seen[v] = true
+			sum += v
+			filled++
+		}
+		if sum > cg.Sum || (filled == len(cg.Cells) && sum != cg.Sum) {
+			return ErrInvalidBoard
+		}
+	}
+	return nil
+}
+
+// grid returns k's grid with its cages appended to the constraints, as the
+// solver sees it.
+func (k KillerGrid) grid() Grid {
+	g := k.Grid
+	if len(k.Cages) > 0 {
+		g.Constraints = append(g.Constraints[:len(g.Constraints):len(g.Constraints)], CageConstraint(k.Cages))
+	}
+	return g
+}
+
+// Validate checks the classic rules plus every cage: no repeats, partial sums not
+// above the target, and complete cages summing exactly to it.
+func (k KillerGrid) Validate() error {
+	if _, err := k.cageIndex(); err != nil {
+		return err
+	}
+	return k.grid().Validate()
+}
+
+// Solve solves the killer grid. It returns false for invalid or unsolvable input.
+func (k KillerGrid) Solve() (KillerGrid, bool) {
+	if err := k.Validate(); err != nil {
+		return KillerGrid{}, false
+	}
+	sols, _ := k.solutions(1, 0)
+	if len(sols) == 0 {
+		return KillerGrid{}, false
+	}
+	return KillerGrid{Grid: sols[0], Cages: k.Cages}, true
+}
+
+// CountSolutions returns the number of solutions of k under its cages, stopping
+// once limit are found. Invalid grids have none.
+func (k KillerGrid) CountSolutions(limit int) int {
+	if k.Validate() != nil {
+		return 0
+	}
+	sols, _ := k.solutions(limit, 0)
+	return len(sols)
+}
+
+// killerNodeBudget bounds a single uniqueness check during generation; when it is
+// exhausted another given is revealed instead of searching on.
+const killerNodeBudget = 50000
+
+// solutions returns up to limit solutions of k. With maxNodes > 0 the search may
+// stop early, reported by complete == false.
+func (k KillerGrid) solutions(limit, maxNodes int) (sols []Grid, complete bool) {
+	ks := newMaskSolver(k.grid())
+	ks.maxNodes = maxNodes
+	ks.search(func(w Grid) bool {
+		sol := w.Clone()
+		sol.Constraints = k.Constraints
+		sols = append(sols, sol)
+		return len(sols) >= limit
+	})
+	return sols, maxNodes <= 0 || ks.nodes <= maxNodes
+}
+
+// GenerateKiller creates a killer puzzle with the dimensions of g and a unique
+// solution. Difficulty controls cage sizes; givens are only added where the cages
+// alone leave the solution ambiguous (easy puzzles get a few extra).
+// Concurrent calls are safe, as for Generate.
+func GenerateKiller(g Grid, d Difficulty, attempts int) (KillerGrid, error) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return defaultGenerator().GenerateKiller(g, d, attempts)
+}
+
+func generateKiller(rng *rand.Rand, g Grid, d Difficulty, attempts int) (KillerGrid, error) {
+	if attempts < 1 {
+		attempts = 1
+	}
+	minCage, maxCage, extra := 2, 4, 0
+	switch d {
+	case Easy:
+		minCage, maxCage, extra = 1, 3, g.Size
+	case Hard:
+		minCage, maxCage = 3, 5
+	}
+	var lastErr error
+	for try := 0; try < attempts; try++ {
+		solved, _ := NewGrid(g.Size, g.BoxRows, g.BoxCols)
+		if !g.backtrack(&solved, rng) {
+			lastErr = errors.New("failed to build solved grid")
+			continue
+		}
+		cages := carveCages(rng, solved, minCage, maxCage)
+		empty, _ := NewGrid(g.Size, g.BoxRows, g.BoxCols)
+		k := KillerGrid{Grid: empty, Cages: cages}
+		for _, idx := range rng.Perm(g.Size * g.Size)[:min(extra, g.Size*g.Size)] {
+			r, c := idx/g.Size, idx%g.Size
+			k.Cells[r][c] = solved.Cells[r][c]
+		}
+		for {
+			sols, complete := k.solutions(2, killerNodeBudget)
+			if complete && len(sols) == 1 {
+				return k, nil
+			}
+			if complete && len(sols) == 0 {
+				lastErr = errors.New("killer layout unsolvable")
+				break
+			}
+			// reveal a cell on which two solutions disagree, or any hidden cell
+			// when the search ran out of budget
+			var alt *Grid
+			for i := range sols {
+				if !gridEqual(sols[i], solved) {
+					alt = &sols[i]
+					break
+				}
+			}
+			revealed := false
+			for _, idx := range rng.Perm(g.Size * g.Size) {
+				r, c := idx/g.Size, idx%g.Size
+				if k.Cells[r][c] == 0 && (alt == nil || alt.Cells[r][c] != solved.Cells[r][c]) {
+					k.Cells[r][c] = solved.Cells[r][c]
+					revealed = true
+					break
+				}
+			}
+			if !revealed {
+				lastErr = errors.New("puzzle uniqueness not achieved")
+				break
+			}
+		}
+	}
+	if lastErr == nil {
+		lastErr = errors.New("generation failed")
+	}
+	return KillerGrid{}, lastErr
+}
+
+// carveCages partitions the solved grid into connected cages of random sizes
+// within [minSize,maxSize] (smaller when boxed in) with no repeated values.
+func carveCages(rng *rand.Rand, solved Grid, minSize, maxSize int) []Cage {
+	s := solved.Size
+	taken := make([]bool, s*s)
+	var cages []Cage
+	for _, start := range rng.Perm(s * s) {
+		if taken[start] {
+			continue
+		}
+		want := minSize + rng.IntN(maxSize-minSize+1)
+		cells := []Cell{{start / s, start % s}}
+		taken[start] = true
+		var seen uint64 = 1 << solved.Cells[start/s][start%s]
+		for len(cells) < want {
+			var options []int
+			for _, c := range cells {
+				for _, d := range [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+					r2, c2 := c.Row+d[0], c.Col+d[1]
+					if r2 < 0 || r2 >= s || c2 < 0 || c2 >= s {
+						continue
+					}
+					i := r2*s + c2
+					if !taken[i] && seen&(1<<solved.Cells[r2][c2]) == 0 {
+						options = append(options, i)
+					}
+				}
+			}
+			if len(options) == 0 {
+				break
+			}
+			next := options[rng.IntN(len(options))]
+			taken[next] = true
+			seen |= 1 << solved.Cells[next/s][next%s]
+			cells = append(cells, Cell{next / s, next % s})
+		}
+		sum := 0
+		for _, c := range cells {
+			sum += solved.Cells[c.Row][c.Col]
+		}
+		cages = append(cages, Cage{Sum: sum, Cells: cells})
+	}
+	return cages
+}
+
+func gridEqual(a, b Grid) bool {
+	if a.Size != b.Size {
+		return false
+	}
+	for r := range a.Cells {
+		for c := range a.Cells[r] {
+			if a.Cells[r][c] != b.Cells[r][c] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// FormatCages serialises a cage layout, one cage per line as "sum: r1c1 r1c2 ...",
+// with 1-based row/column numbers.
+func FormatCages(cages []Cage) string {
+	var sb strings.Builder
+	for _, cg := range cages {
+		sb.WriteString(strconv.Itoa(cg.Sum))
+		sb.WriteByte(':')
+		for _, c := range cg.Cells {
+			sb.WriteByte(' ')
+			sb.WriteString(c.String())
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// ParseCages reads a layout written by FormatCages; cells may also use A1 notation
+// (see ParseCell). Blank lines and lines starting with '#' are ignored.
+func ParseCages(s string) ([]Cage, error) {
+	var cages []Cage
+	sc := bufio.NewScanner(strings.NewReader(s))
+	line := 0
+	for sc.Scan() {
+		line++
+		t := strings.TrimSpace(sc.Text())
+		if t == "" || strings.HasPrefix(t, "#") {
+			continue
+		}
+		head, rest, ok := strings.Cut(t, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: missing ':'", line)
+		}
+		sum, err := strconv.Atoi(strings.TrimSpace(head))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid sum %q", line, head)
+		}
+		cg := Cage{Sum: sum}
+		for _, f := range strings.Fields(rest) {
+			c, err := ParseCell(f)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			cg.Cells = append(cg.Cells, c)
+		}
+		if len(cg.Cells) == 0 {
+			return nil, fmt.Errorf("line %d: cage without cells", line)
+		}
+		cages = append(cages, cg)
+	}
+	return cages, sc.Err()
+}
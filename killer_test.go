@@ -0,0 +1,71 @@
+package sudoku
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCagesRoundtrip(t *testing.T) {
+	in := []Cage{{Sum: 3, Cells: []Cell{{0, 0}, {0, 1}}}, {Sum: 9, Cells: []Cell{{8, 8}}}}
+	s := FormatCages(in)
+	if s != "3: r1c1 r1c2\n9: r9c9\n" {
+		t.Fatalf("unexpected format: %q", s)
+	}
+	out, err := ParseCages("# layout\n" + s)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(out) != 2 || out[0].Sum != 3 || out[0].Cells[1] != (Cell{0, 1}) || out[1].Cells[0] != (Cell{8, 8}) {
+		t.Fatalf("roundtrip mismatch: %+v", out)
+	}
+	for _, bad := range []string{"3 r1c1", "x: r1c1", "3: r1c1x", "3:"} {
+		if _, err := ParseCages(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestKillerValidate(t *testing.T) {
+	g, _ := NewGrid(4, 2, 2)
+	if _, err := NewKillerGrid(g, []Cage{{Sum: 20, Cells: []Cell{{0, 0}, {0, 1}}}}); err == nil {
+		t.Fatalf("expected infeasible sum error")
+	}
+	if _, err := NewKillerGrid(g, []Cage{{Sum: 3, Cells: []Cell{{0, 0}, {0, 1}}}, {Sum: 4, Cells: []Cell{{0, 1}}}}); err == nil {
+		t.Fatalf("expected overlap error")
+	}
+	k, err := NewKillerGrid(g, []Cage{{Sum: 3, Cells: []Cell{{0, 0}, {1, 0}}}})
+	if err != nil {
+		t.Fatalf("new killer: %v", err)
+	}
+	k.Cells[0][0], k.Cells[1][0] = 2, 2 // repeat inside cage and column
+	if err := k.Validate(); !errors.Is(err, ErrInvalidBoard) {
+		t.Fatalf("expected invalid board, got %v", err)
+	}
+	k.Cells[0][0], k.Cells[1][0] = 3, 4 // distinct but wrong sum
+	if err := k.Validate(); err == nil {
+		t.Fatalf("expected cage sum violation")
+	}
+}
+
+func TestGenerateKillerUnique(t *testing.T) {
+	for _, cfg := range []struct{ size, br, bc int }{{4, 2, 2}, {6, 2, 3}, {9, 3, 3}} {
+		g, _ := NewGrid(cfg.size, cfg.br, cfg.bc)
+		k, err := GenerateKiller(g, Medium, 3)
+		if err != nil {
+			t.Fatalf("generate %d: %v", cfg.size, err)
+		}
+		if err := k.Validate(); err != nil {
+			t.Fatalf("generated invalid killer: %v", err)
+		}
+		if n := k.CountSolutions(2); n != 1 {
+			t.Fatalf("size %d: expected unique solution, got %d", cfg.size, n)
+		}
+		if n := k.Grid.CountSolutions(2); n < 2 {
+			t.Fatalf("size %d: cages ignored, still %d solutions", cfg.size, n)
+		}
+		sol, ok := k.Solve()
+		if !ok || sol.Validate() != nil || sol.countClues(sol.Grid) != cfg.size*cfg.size {
+			t.Fatalf("size %d: solve failed", cfg.size)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package sudoku
+
+import (
+	"context"
+	"math/rand/v2"
+)
+
+// maskSolver is a most-constrained-cell DFS for grids with jigsaw regions and
+// extra constraints, such as killer cages or parity. Row, column and region usage
+// is tracked as bitmasks so the standard checks are O(1); everything else is
+// asked of g.Constraints. Values are tried in ascending order unless rng is set.
+// A non-nil ctx is checked every 1024 nodes and stops the search like the node
+// budget, setting stopped.
+type maskSolver struct {
+	g                 Grid
+	w                 Grid
+	rows, cols, boxes []uint64
+	nodes, maxNodes   int
+	rng               *rand.Rand
+	ctx               context.Context
+	stopped           bool
+}
+
+func newMaskSolver(g Grid) *maskSolver {
+	s := g.Size
+	ks := &maskSolver{g: g, w: g.Clone(), rows: make([]uint64, s), cols: make([]uint64, s), boxes: make([]uint64, s)}
+	for r := 0; r < s; r++ {
+		for c := 0; c < s; c++ {
+			if v := g.Cells[r][c]; v != 0 {
+				ks.place(r, c, v)
+			}
+		}
+	}
+	return ks
+}
+
+func (ks *maskSolver) box(r, c int) int { return ks.g.regionOf(r, c) }
+
+// allows reports whether v fits at r,c under the standard rules and g's
+// extra constraints.
+func (ks *maskSolver) allows(r, c, v int) bool {
+	bit := uint64(1) << v
+	if (ks.rows[r]|ks.cols[c]|ks.boxes[ks.box(r, c)])&bit != 0 {
+		return false
+	}
+	return ks.g.Constraints == nil || ks.g.allowsExtra(&ks.w, r, c, v)
+}
+
+func (ks *maskSolver) place(r, c, v int) {
+	bit := uint64(1) << v
+	ks.w.Cells[r][c] = v
+	ks.rows[r] |= bit
+	ks.cols[c] |= bit
+	ks.boxes[ks.box(r, c)] |= bit
+}
+
+func (ks *maskSolver) unplace(r, c, v int) {
+	bit := uint64(1) << v
+	ks.w.Cells[r][c] = 0
+	ks.rows[r] &^= bit
+	ks.cols[c] &^= bit
+	ks.boxes[ks.box(r, c)] &^= bit
+}
+
+// search visits solutions until found returns true, the node budget (if any)
+// runs out or ctx ends.
+func (ks *maskSolver) search(found func(Grid) bool) bool {
+	ks.nodes++
+	if ks.maxNodes > 0 && ks.nodes > ks.maxNodes {
+		return true
+	}
+	if ks.ctx != nil && ks.nodes%1024 == 0 && ks.ctx.Err() != nil {
+		ks.stopped = true
+		return true
+	}
+	s := ks.g.Size
+	br, bc, best := -1, -1, s+1
+	var bestVals []int
+	vals := make([]int, 0, s)
+	for r := 0; r < s && best > 1; r++ {
+		for c := 0; c < s; c++ {
+			if ks.w.Cells[r][c] != 0 {
+				continue
+			}
+			vals = vals[:0]
+			for v := 1; v <= s; v++ {
+				if ks.allows(r, c, v) {
+					vals = append(vals, v)
+				}
+			}
+			if len(vals) < best {
+				br, bc, best = r, c, len(vals)
+				bestVals = append(bestVals[:0], vals...)
+				if best <= 1 {
+					break
+				}
+			}
+		}
+	}
+	if br < 0 {
+		return found(ks.w)
+	}
+	if ks.rng != nil {
+		ks.rng.Shuffle(len(bestVals), func(i, j int) { bestVals[i], bestVals[j] = bestVals[j], bestVals[i] })
+	}
+	for _, v := range bestVals {
+		ks.place(br, bc, v)
+		if ks.search(found) {
+			return true
+		}
+		ks.unplace(br, bc, v)
+	}
+	return false
+}
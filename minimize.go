@@ -0,0 +1,164 @@
+package sudoku
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// Symmetry is a set of geometric symmetries of a givens pattern (which cells are
+// filled, not their values), combined with |.
+type Symmetry uint8
+
+const (
+	// SymmetryRotational is invariance under a 180° rotation.
+	SymmetryRotational Symmetry = 1 << iota
+	// SymmetryQuarterTurn is invariance under a 90° rotation.
+	SymmetryQuarterTurn
+	// SymmetryMirror is invariance under a left-right reflection.
+	SymmetryMirror
+	// SymmetryFlip is invariance under a top-bottom reflection.
+	SymmetryFlip
+	// SymmetryDiagonal is invariance under reflection about the main diagonal.
+	SymmetryDiagonal
+	// SymmetryAntiDiagonal is invariance under reflection about the anti-diagonal.
+	SymmetryAntiDiagonal
+
+	// SymmetryNone imposes no symmetry.
+	SymmetryNone Symmetry = 0
+)
+
+// symmetryMaps pairs each Symmetry bit with the cell map it stands for.
+var symmetryMaps = [...]struct {
+	sym Symmetry
+	f   func(r, c int) (int, int)
+}{
+	{SymmetryRotational, func(r, c int) (int, int) { return 8 - r, 8 - c }},
+	{SymmetryQuarterTurn, func(r, c int) (int, int) { return c, 8 - r }},
+	{SymmetryMirror, func(r, c int) (int, int) { return r, 8 - c }},
+	{SymmetryFlip, func(r, c int) (int, int) { return 8 - r, c }},
+	{SymmetryDiagonal, func(r, c int) (int, int) { return c, r }},
+	{SymmetryAntiDiagonal, func(r, c int) (int, int) { return 8 - c, 8 - r }},
+}
+
+// SymmetryOf returns every symmetry the givens pattern of b has. Passing it to
+// Minimize keeps all of them.
+func SymmetryOf(b Board) Symmetry {
+	var out Symmetry
+	for _, m := range symmetryMaps {
+		ok := true
+		for r := 0; r < 9 && ok; r++ {
+			for c := 0; c < 9; c++ {
+				r2, c2 := m.f(r, c)
+				if (b[r][c] != 0) != (b[r2][c2] != 0) {
+					ok = false
+					break
+				}
+			}
+		}
+		if ok {
+			out |= m.sym
+		}
+	}
+	return out
+}
+
+// orbits partitions the cells into the classes sym maps onto each other, in
+// row-major order of their first cell.
+func (sym Symmetry) orbits() [][]Cell {
+	var seen [9][9]bool
+	var out [][]Cell
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if seen[r][c] {
+				continue
+			}
+			seen[r][c] = true
+			orbit := []Cell{{r, c}}
+			for i := 0; i < len(orbit); i++ {
+				for _, m := range symmetryMaps {
+					if sym&m.sym == 0 {
+						continue
+					}
+					r2, c2 := m.f(orbit[i].Row, orbit[i].Col)
+					if !seen[r2][c2] {
+						seen[r2][c2] = true
+						orbit = append(orbit, Cell{r2, c2})
+					}
+				}
+			}
+			out = append(out, orbit)
+		}
+	}
+	return out
+}
+
+// ErrSymmetryMismatch is returned by Minimize when the givens lack the requested symmetry.
+var ErrSymmetryMismatch = errors.New("givens do not have the requested symmetry")
+
+// Minimize removes clues from a uniquely solvable b until none can go without
+// losing uniqueness. Clues are only removed together with their images under sym,
+// so symmetric givens stay symmetric; with SymmetryNone every clue is tried on its
+// own. Use SymmetryOf(b) to keep whatever symmetry b already has. Clues are tried
+// in row-major order, so the result is deterministic.
+func Minimize(b Board, sym Symmetry) (Board, error) {
+	if err := Validate(b); err != nil {
+		return Board{}, err
+	}
+	if countSolutionsMCV(b, 2) != 1 {
+		return Board{}, errors.New("puzzle does not have a unique solution")
+	}
+	if SymmetryOf(b)&sym != sym {
+		return Board{}, ErrSymmetryMismatch
+	}
+	for _, orbit := range sym.orbits() {
+		if b[orbit[0].Row][orbit[0].Col] == 0 {
+			continue // symmetric pattern: the whole orbit is empty
+		}
+		saved := b
+		for _, c := range orbit {
+			b[c.Row][c.Col] = 0
+		}
+		if countSolutionsMCV(b, 2) != 1 {
+			b = saved
+		}
+	}
+	return b, nil
+}
+
+// countSolutionsMCV is countSolutions branching on the cell with the fewest
+// candidates, which stays fast on the sparse boards minimization produces.
+func countSolutionsMCV(b Board, limit int) int {
+	count := 0
+	var dfs func() bool
+	dfs = func() bool {
+		br, bc, best := -1, -1, uint16(0)
+		for r := 0; r < 9; r++ {
+			for c := 0; c < 9; c++ {
+				if b[r][c] != 0 {
+					continue
+				}
+				m := candidateMask(&b, r, c)
+				if br < 0 || bits.OnesCount16(m) < bits.OnesCount16(best) {
+					br, bc, best = r, c, m
+				}
+			}
+		}
+		if br < 0 {
+			count++
+			return count >= limit
+		}
+		for v := 1; v <= 9; v++ {
+			if best&(1<<v) == 0 {
+				continue
+			}
+			b[br][bc] = v
+			if dfs() {
+				return true
+			}
+		}
+		b[br][bc] = 0
+		return false
+	}
+	dfs()
+	return count
+}
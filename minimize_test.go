@@ -0,0 +1,67 @@
+package sudoku
+
+import "testing"
+
+func TestMinimizeKeepsSymmetry(t *testing.T) {
+	// the classic example puzzle is symmetric under a 180° rotation
+	b, _ := FromString(easyPuzzle)
+	sym := SymmetryOf(b)
+	if sym&SymmetryRotational == 0 {
+		t.Fatalf("fixture lost its rotational symmetry: %b", sym)
+	}
+	plain, err := Minimize(b, SymmetryNone)
+	if err != nil {
+		t.Fatalf("minimize: %v", err)
+	}
+	kept, err := Minimize(b, sym)
+	if err != nil {
+		t.Fatalf("symmetric minimize: %v", err)
+	}
+	for name, m := range map[string]Board{"plain": plain, "symmetric": kept} {
+		if countSolutionsMCV(m, 2) != 1 || countClues(m) > countClues(b) {
+			t.Fatalf("%s result not a unique reduction:\n%s", name, m)
+		}
+		// minimal: no single remaining clue can be removed
+		for r := 0; r < 9 && name == "plain"; r++ {
+			for c := 0; c < 9; c++ {
+				if m[r][c] == 0 {
+					continue
+				}
+				x := m
+				x[r][c] = 0
+				if countSolutionsMCV(x, 2) == 1 {
+					t.Fatalf("clue r%dc%d is redundant", r+1, c+1)
+				}
+			}
+		}
+	}
+	if SymmetryOf(kept)&sym != sym {
+		t.Fatalf("symmetry lost: got %b want %b", SymmetryOf(kept), sym)
+	}
+}
+
+func TestMinimizeErrors(t *testing.T) {
+	b, _ := FromString(easyPuzzle)
+	if SymmetryOf(b)&SymmetryDiagonal != 0 {
+		t.Fatalf("fixture unexpectedly diagonal-symmetric")
+	}
+	if _, err := Minimize(b, SymmetryDiagonal); err != ErrSymmetryMismatch {
+		t.Fatalf("want ErrSymmetryMismatch, got %v", err)
+	}
+	if _, err := Minimize(Board{}, SymmetryNone); err == nil {
+		t.Fatalf("empty board minimized")
+	}
+}
+
+func TestSymmetryOrbits(t *testing.T) {
+	if n := len(SymmetryNone.orbits()); n != 81 {
+		t.Fatalf("no symmetry: %d orbits", n)
+	}
+	if n := len(SymmetryRotational.orbits()); n != 41 {
+		t.Fatalf("rotational: %d orbits", n)
+	}
+	// 90° turns plus a mirror generate all eight symmetries of the square
+	if n := len((SymmetryQuarterTurn | SymmetryMirror).orbits()); n != 15 {
+		t.Fatalf("full group: %d orbits", n)
+	}
+}
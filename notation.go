@@ -0,0 +1,133 @@
+package sudoku
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// rowLetters names the rows of a 9x9 board in A1 notation; I is skipped so it is
+// not confused with 1.
+const rowLetters = "ABCDEFGHJ"
+
+// String formats c in RC notation with 1-based indices, e.g. "r4c7".
+func (c Cell) String() string { return "r" + strconv.Itoa(c.Row+1) + "c" + strconv.Itoa(c.Col+1) }
+
+// A1 formats c in letter-row notation, "A1" (top left) to "J9", for 9x9 boards.
+// It returns "" for cells outside a 9x9 board.
+func (c Cell) A1() string {
+	if c.Row < 0 || c.Row > 8 || c.Col < 0 || c.Col > 8 {
+		return ""
+	}
+	return string(rowLetters[c.Row]) + strconv.Itoa(c.Col+1)
+}
+
+// ParseCell reads a cell in RC ("r4c7") or A1 ("D7") notation, case-insensitively.
+func ParseCell(s string) (Cell, error) {
+	t := strings.ToLower(strings.TrimSpace(s))
+	if rest, ok := strings.CutPrefix(t, "r"); ok {
+		rs, cs, ok := strings.Cut(rest, "c")
+		r, err1 := strconv.Atoi(rs)
+		c, err2 := strconv.Atoi(cs)
+		cell := Cell{r - 1, c - 1}
+		if ok && err1 == nil && err2 == nil && r >= 1 && c >= 1 && cell.String() == t {
+			return cell, nil
+		}
+		return Cell{}, fmt.Errorf("invalid cell %q", s)
+	}
+	if len(t) == 2 && t[1] >= '1' && t[1] <= '9' {
+		if r := strings.IndexByte(strings.ToLower(rowLetters), t[0]); r >= 0 {
+			return Cell{r, int(t[1] - '1')}, nil
+		}
+	}
+	return Cell{}, fmt.Errorf("invalid cell %q", s)
+}
+
+// UnitKind is the kind of a sudoku unit: a row, column or box, or a unit an
+// extra Grid constraint adds.
+type UnitKind int
+
+const (
+	UnitRow UnitKind = iota
+	UnitColumn
+	UnitBox
+	UnitDiagonal   // DiagonalConstraint: index 0 is the main diagonal, 1 the anti-diagonal
+	UnitWindow     // HyperConstraint: windows numbered left to right, top to bottom
+	UnitConstraint // any other entry of Grid.Constraints, by its index there
+)
+
+func (k UnitKind) String() string {
+	switch k {
+	case UnitRow:
+		return "row"
+	case UnitColumn:
+		return "column"
+	case UnitBox:
+		return "box"
+	case UnitDiagonal:
+		return "diagonal"
+	case UnitWindow:
+		return "window"
+	case UnitConstraint:
+		return "constraint"
+	}
+	return "unit(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Unit is one row, column or box, indexed from 0. Boxes are numbered left to
+// right, top to bottom.
+type Unit struct {
+	Kind  UnitKind
+	Index int
+}
+
+// String names u the way technique explanations do, e.g. "row 4" or "box 5".
+func (u Unit) String() string { return u.Kind.String() + " " + strconv.Itoa(u.Index+1) }
+
+// Cells lists the cells of u on a 9x9 board, or nil for UnitConstraint, which
+// has no fixed cells.
+func (u Unit) Cells() []Cell {
+	if u.Kind == UnitConstraint {
+		return nil
+	}
+	out := make([]Cell, 9)
+	for i := range out {
+		switch u.Kind {
+		case UnitRow:
+			out[i] = Cell{u.Index, i}
+		case UnitColumn:
+			out[i] = Cell{i, u.Index}
+		case UnitDiagonal:
+			out[i] = Cell{i, i + u.Index*(8-2*i)}
+		case UnitWindow:
+			out[i] = Cell{1 + (u.Index/2)*4 + i/3, 1 + (u.Index%2)*4 + i%3}
+		default:
+			out[i] = Cell{(u.Index/3)*3 + i/3, (u.Index%3)*3 + i%3}
+		}
+	}
+	return out
+}
+
+// UnitsOf returns the row, column and box containing c on a 9x9 board.
+func UnitsOf(c Cell) [3]Unit {
+	return [3]Unit{{UnitRow, c.Row}, {UnitColumn, c.Col}, {UnitBox, (c.Row/3)*3 + c.Col/3}}
+}
+
+// ParseUnit reads a unit name as written by Unit.String ("row 4", "column 7",
+// "box 5") or in short form ("r4", "c7", "b5"), case-insensitively.
+func ParseUnit(s string) (Unit, error) {
+	t := strings.ToLower(strings.Join(strings.Fields(s), ""))
+	for _, p := range []struct {
+		prefix string
+		kind   UnitKind
+	}{{"column", UnitColumn}, {"row", UnitRow}, {"box", UnitBox}, {"col", UnitColumn}, {"r", UnitRow}, {"c", UnitColumn}, {"b", UnitBox}} {
+		if rest, ok := strings.CutPrefix(t, p.prefix); ok {
+			n, err := strconv.Atoi(rest)
+			if err != nil || n < 1 || strconv.Itoa(n) != rest {
+				break
+			}
+			return Unit{p.kind, n - 1}, nil
+		}
+	}
+	return Unit{}, fmt.Errorf("invalid unit %q", s)
+}
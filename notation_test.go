@@ -0,0 +1,51 @@
+package sudoku
+
+import "testing"
+
+func TestCellNotation(t *testing.T) {
+	c := Cell{Row: 3, Col: 6}
+	if c.String() != "r4c7" || c.A1() != "D7" {
+		t.Fatalf("got %s %s", c, c.A1())
+	}
+	if (Cell{8, 8}).A1() != "J9" || (Cell{9, 0}).A1() != "" {
+		t.Fatalf("A1 bounds wrong")
+	}
+	for in, want := range map[string]Cell{"r4c7": c, "R4C7": c, "d7": c, "J9": {8, 8}, "r12c16": {11, 15}} {
+		got, err := ParseCell(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseCell(%q) = %v, %v", in, got, err)
+		}
+	}
+	for _, in := range []string{"", "r0c1", "r1c", "r01c1", "I1", "A0", "A10", "r1c1x"} {
+		if _, err := ParseCell(in); err == nil {
+			t.Fatalf("ParseCell(%q) should fail", in)
+		}
+	}
+}
+
+func TestUnitNotation(t *testing.T) {
+	units := UnitsOf(Cell{4, 7})
+	if units[0].String() != "row 5" || units[1].String() != "column 8" || units[2].String() != "box 6" {
+		t.Fatalf("got %v", units)
+	}
+	for _, u := range units {
+		found := false
+		for _, c := range u.Cells() {
+			found = found || c == (Cell{4, 7})
+		}
+		if !found {
+			t.Fatalf("%s does not contain r5c8", u)
+		}
+	}
+	for in, want := range map[string]Unit{"row 5": {UnitRow, 4}, "Column 8": {UnitColumn, 7}, "b6": {UnitBox, 5}, "c1": {UnitColumn, 0}, "col 2": {UnitColumn, 1}} {
+		got, err := ParseUnit(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseUnit(%q) = %v, %v", in, got, err)
+		}
+	}
+	for _, in := range []string{"", "row", "row 0", "square 1", "r01"} {
+		if _, err := ParseUnit(in); err == nil {
+			t.Fatalf("ParseUnit(%q) should fail", in)
+		}
+	}
+}
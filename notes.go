@@ -0,0 +1,74 @@
+package sudoku
+
+import "math/bits"
+
+// Notes holds pencil marks for a 9x9 board: bit v of a cell's mask is set when v
+// is noted as a candidate there. The zero value has no marks. Values outside
+// 1..9 are ignored by every method.
+type Notes [9][9]uint16
+
+// CandidateNotes returns notes filled with every value allowed in each empty cell
+// of b (filled cells get no marks).
+func CandidateNotes(b Board) Notes {
+	var n Notes
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if b[r][c] == 0 {
+				n[r][c] = candidateMask(&b, r, c)
+			}
+		}
+	}
+	return n
+}
+
+// Has reports whether v is noted at r,c.
+func (n *Notes) Has(r, c, v int) bool { return v >= 1 && v <= 9 && n[r][c]&(1<<v) != 0 }
+
+// Add notes v at r,c.
+func (n *Notes) Add(r, c, v int) {
+	if v >= 1 && v <= 9 {
+		n[r][c] |= 1 << v
+	}
+}
+
+// Remove clears the note v at r,c.
+func (n *Notes) Remove(r, c, v int) {
+	if v >= 1 && v <= 9 {
+		n[r][c] &^= 1 << v
+	}
+}
+
+// Toggle flips the note v at r,c and reports whether it is now set.
+func (n *Notes) Toggle(r, c, v int) bool {
+	if v < 1 || v > 9 {
+		return false
+	}
+	n[r][c] ^= 1 << v
+	return n.Has(r, c, v)
+}
+
+// Clear removes every note at r,c.
+func (n *Notes) Clear(r, c int) { n[r][c] = 0 }
+
+// Candidates lists the values noted at r,c in ascending order.
+func (n *Notes) Candidates(r, c int) []int {
+	m := n[r][c]
+	out := make([]int, 0, bits.OnesCount16(m))
+	for v := 1; v <= 9; v++ {
+		if m&(1<<v) != 0 {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Place records that v was entered at r,c: the cell's notes are cleared and v is
+// pruned from every peer in the same row, column and box.
+func (n *Notes) Place(r, c, v int) {
+	n.Clear(r, c)
+	for _, u := range UnitsOf(Cell{r, c}) {
+		for _, p := range u.Cells() {
+			n.Remove(p.Row, p.Col, v)
+		}
+	}
+}
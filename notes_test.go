@@ -0,0 +1,54 @@
+package sudoku
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNotesEditing(t *testing.T) {
+	var n Notes
+	n.Add(0, 0, 3)
+	n.Add(0, 0, 7)
+	n.Add(0, 0, 10) // ignored
+	if got := n.Candidates(0, 0); !reflect.DeepEqual(got, []int{3, 7}) {
+		t.Fatalf("candidates = %v", got)
+	}
+	if n.Toggle(0, 0, 3) || n.Has(0, 0, 3) {
+		t.Fatalf("toggle did not clear 3")
+	}
+	if !n.Toggle(0, 0, 5) || !n.Has(0, 0, 5) {
+		t.Fatalf("toggle did not set 5")
+	}
+	n.Remove(0, 0, 7)
+	n.Clear(0, 0)
+	if len(n.Candidates(0, 0)) != 0 {
+		t.Fatalf("clear left marks: %v", n.Candidates(0, 0))
+	}
+}
+
+func TestNotesPlacePrunesPeers(t *testing.T) {
+	n := CandidateNotes(Board{})
+	n.Place(4, 4, 5)
+	if len(n.Candidates(4, 4)) != 0 {
+		t.Fatalf("placed cell kept notes")
+	}
+	for _, p := range []Cell{{4, 0}, {0, 4}, {3, 3}, {5, 5}} {
+		if n.Has(p.Row, p.Col, 5) {
+			t.Fatalf("5 not pruned from peer %v", p)
+		}
+	}
+	if !n.Has(0, 0, 5) || !n.Has(4, 0, 4) {
+		t.Fatalf("non-peer or other values were pruned")
+	}
+}
+
+func TestCandidateNotes(t *testing.T) {
+	b, _ := FromString(easyPuzzle)
+	n := CandidateNotes(b)
+	if len(n.Candidates(0, 0)) != 0 {
+		t.Fatalf("given cell has notes")
+	}
+	if got := n.Candidates(0, 2); !reflect.DeepEqual(got, []int{1, 2, 4}) {
+		t.Fatalf("r1c3 candidates = %v", got)
+	}
+}
@@ -0,0 +1,238 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+// Parity restricts a cell to even or odd values.
+type Parity uint8
+
+const (
+	ParityAny  Parity = iota // unconstrained
+	ParityEven               // 2, 4, 6 or 8 (usually shaded)
+	ParityOdd                // 1, 3, 5, 7 or 9
+)
+
+// ParityMask holds the parity constraint of every cell of a Board.
+type ParityMask [9][9]Parity
+
+// ParityConstraint returns the even/odd rule for mask: a cell marked ParityEven
+// or ParityOdd only takes values of that parity. Cells outside mask are
+// unconstrained.
+func ParityConstraint(mask [][]Parity) Constraint { return &parityConstraint{mask} }
+
+type parityConstraint struct{ mask [][]Parity }
+
+func (k *parityConstraint) Allows(_ *Grid, r, c, v int) bool {
+	if r >= len(k.mask) || c >= len(k.mask[r]) {
+		return true
+	}
+	switch k.mask[r][c] {
+	case ParityEven:
+		return v%2 == 0
+	case ParityOdd:
+		return v%2 == 1
+	}
+	return true
+}
+
+// Validate also rejects mask entries other than ParityAny, ParityEven and ParityOdd.
+func (k *parityConstraint) Validate(g *Grid) error {
+	for _, row := range k.mask {
+		for _, m := range row {
+			if m > ParityOdd {
+				return ErrInvalidBoard
+			}
+		}
+	}
+	return validateByAllows(k, g)
+}
+
+// ParityBoard is an even/odd sudoku: a Board plus a parity mask its cells must honour.
+type ParityBoard struct {
+	Board
+	Mask ParityMask
+}
+
+// grid returns p as a 9x9 Grid carrying its mask as a ParityConstraint.
+func (p ParityBoard) grid() Grid {
+	g := p.Board.ToGrid()
+	mask := make([][]Parity, 9)
+	for r := range mask {
+		mask[r] = p.Mask[r][:]
+	}
+	g.Constraints = []Constraint{ParityConstraint(mask)}
+	return g
+}
+
+// Validate checks the classic rules and that every filled cell matches its parity.
+func (p ParityBoard) Validate() error {
+	return p.grid().Validate()
+}
+
+// Solve solves the board under the parity mask. It returns false for invalid or
+// unsolvable input.
+func (p ParityBoard) Solve() (ParityBoard, bool) {
+	if err := p.Validate(); err != nil {
+		return ParityBoard{}, false
+	}
+	sols, _ := KillerGrid{Grid: p.grid()}.solutions(1, 0)
+	if len(sols) == 0 {
+		return p, false
+	}
+	out := p
+	for r := range out.Board {
+		copy(out.Board[r][:], sols[0].Cells[r])
+	}
+	return out, true
+}
+
+// CountSolutions returns the number of solutions of p under its mask, stopping
+// once limit are found. Invalid boards have none.
+func (p ParityBoard) CountSolutions(limit int) int {
+	if p.Validate() != nil {
+		return 0
+	}
+	sols, _ := KillerGrid{Grid: p.grid()}.solutions(limit, 0)
+	return len(sols)
+}
+
+// unique reports whether p has exactly one solution.
+func (p ParityBoard) unique() bool {
+	sols, _ := KillerGrid{Grid: p.grid()}.solutions(2, 0)
+	return len(sols) == 1
+}
+
+// parityShaded is the number of cells a generated mask constrains.
+const parityShaded = 27
+
+// GenerateParity creates an even/odd puzzle with a unique solution. About a third
+// of the cells carry a parity constraint; difficulty sets the clue count as in Generate.
+// Concurrent calls are safe, as for Generate.
+func GenerateParity(d Difficulty, attempts int) (ParityBoard, error) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return defaultGenerator().GenerateParity(d, attempts)
+}
+
+// GenerateParity creates an even/odd puzzle with a unique solution (see GenerateParity).
+func (gen *Generator) GenerateParity(d Difficulty, attempts int) (ParityBoard, error) {
+	return generateParity(gen.rng, d, attempts)
+}
+
+func generateParity(rng *rand.Rand, d Difficulty, attempts int) (ParityBoard, error) {
+	if attempts < 1 {
+		attempts = 1
+	}
+	for try := 0; try < attempts; try++ {
+		var solution Board
+		fillDiagonalBoxes(&solution, rng)
+		if !backtrack(&solution, rng) {
+			continue
+		}
+		p := ParityBoard{Board: solution}
+		for _, idx := range rng.Perm(81)[:parityShaded] {
+			r, c := idx/9, idx%9
+			if solution[r][c]%2 == 0 {
+				p.Mask[r][c] = ParityEven
+			} else {
+				p.Mask[r][c] = ParityOdd
+			}
+		}
+		target := cluesFor(d)
+		clues := 81
+		for _, idx := range rng.Perm(81) {
+			if clues <= target {
+				break
+			}
+			r, c := idx/9, idx%9
+			old := p.Board[r][c]
+			p.Board[r][c] = 0
+			if !p.unique() {
+				p.Board[r][c] = old
+				continue
+			}
+			clues--
+		}
+		return p, nil
+	}
+	return ParityBoard{}, errors.New("failed to build solved board")
+}
+
+// parityJSON is the wire form of a ParityBoard; without it the embedded Board's
+// methods would drop the mask.
+type parityJSON struct {
+	Board Board      `json:"board"`
+	Mask  ParityMask `json:"mask"`
+}
+
+// MarshalJSON encodes p as its board plus mask.
+func (p ParityBoard) MarshalJSON() ([]byte, error) {
+	return json.Marshal(parityJSON{Board: p.Board, Mask: p.Mask})
+}
+
+// UnmarshalJSON decodes a ParityBoard and checks it.
+func (p *ParityBoard) UnmarshalJSON(data []byte) error {
+	var in parityJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	out := ParityBoard{Board: in.Board, Mask: in.Mask}
+	if err := out.Validate(); err != nil {
+		return err
+	}
+	*p = out
+	return nil
+}
+
+// parityLetters encodes ParityAny, ParityEven and ParityOdd in text form.
+const parityLetters = ".eo"
+
+// MarshalText encodes p as the 81-character board, a colon and 81 mask
+// characters ('.' any, 'e' even, 'o' odd).
+func (p ParityBoard) MarshalText() ([]byte, error) {
+	board, err := p.Board.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	sb.Write(board)
+	sb.WriteByte(':')
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if p.Mask[r][c] > ParityOdd {
+				return nil, ErrInvalidBoard
+			}
+			sb.WriteByte(parityLetters[p.Mask[r][c]])
+		}
+	}
+	return []byte(sb.String()), nil
+}
+
+// UnmarshalText parses the MarshalText form.
+func (p *ParityBoard) UnmarshalText(text []byte) error {
+	board, mask, ok := strings.Cut(strings.TrimSpace(string(text)), ":")
+	if !ok || len(mask) != 81 {
+		return fmt.Errorf("parity board must be 81 cells, ':' and 81 mask characters")
+	}
+	var out ParityBoard
+	if err := out.Board.UnmarshalText([]byte(board)); err != nil {
+		return err
+	}
+	for i := 0; i < 81; i++ {
+		k := strings.IndexByte(parityLetters, mask[i])
+		if k < 0 {
+			return fmt.Errorf("invalid parity character %q", mask[i])
+		}
+		out.Mask[i/9][i%9] = Parity(k)
+	}
+	if err := out.Validate(); err != nil {
+		return err
+	}
+	*p = out
+	return nil
+}
@@ -0,0 +1,70 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParityValidate(t *testing.T) {
+	var p ParityBoard
+	p.Mask[0][0] = ParityEven
+	p.Board[0][0] = 3
+	if err := p.Validate(); err == nil {
+		t.Fatalf("expected odd value in even cell to be invalid")
+	}
+	p.Board[0][0] = 4
+	if err := p.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.Mask[1][1] = Parity(7)
+	if err := p.Validate(); err == nil {
+		t.Fatalf("expected unknown parity to be invalid")
+	}
+}
+
+func TestParityGenerateSolve(t *testing.T) {
+	p, err := NewGenerator(11).GenerateParity(Hard, 2)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("generated invalid puzzle: %v", err)
+	}
+	if n := p.CountSolutions(2); n != 1 || !p.unique() {
+		t.Fatalf("generated puzzle not unique: %d solutions", n)
+	}
+	sol, ok := p.Solve()
+	if !ok || countClues(sol.Board) != 81 || sol.Validate() != nil {
+		t.Fatalf("solve failed: %v", ok)
+	}
+	if sol.Mask != p.Mask {
+		t.Fatalf("solve changed the mask")
+	}
+}
+
+func TestParityMarshaling(t *testing.T) {
+	p, err := NewGenerator(4).GenerateParity(Easy, 1)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	text, err := p.MarshalText()
+	if err != nil || len(text) != 163 {
+		t.Fatalf("marshal text: %d %v", len(text), err)
+	}
+	var back ParityBoard
+	if err := back.UnmarshalText(text); err != nil || back != p {
+		t.Fatalf("text round trip: %v", err)
+	}
+	data, err := json.Marshal(p)
+	if err != nil || !strings.Contains(string(data), `"mask":`) {
+		t.Fatalf("marshal json: %s %v", data, err)
+	}
+	back = ParityBoard{}
+	if err := json.Unmarshal(data, &back); err != nil || back != p {
+		t.Fatalf("json round trip: %v", err)
+	}
+	if err := back.UnmarshalText([]byte(p.Board.String() + ":" + strings.Repeat("x", 81))); err == nil {
+		t.Fatalf("expected invalid mask error")
+	}
+}
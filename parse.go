@@ -1,7 +1,10 @@
 package sudoku
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 )
 
 // FromString parses an 81-char string into a Board. Digits 1-9 are values, 0 or '.' are empty.
@@ -29,6 +32,42 @@ func FromString(s string) (Board, error) {
 	return b, nil
 }
 
+// FromRows builds a Board from 9 rows of 9 values (0 for empty). Unlike decoding
+// JSON into a Board, short or long rows are rejected instead of zero-padded or
+// truncated, and the result is validated like FromString.
+func FromRows(rows [][]int) (Board, error) {
+	if len(rows) != 9 {
+		return Board{}, fmt.Errorf("board has %d rows, want 9", len(rows))
+	}
+	var b Board
+	for r, row := range rows {
+		if len(row) != 9 {
+			return Board{}, fmt.Errorf("board row %d has %d cells, want 9", r, len(row))
+		}
+		copy(b[r][:], row)
+	}
+	if err := Validate(b); err != nil {
+		return Board{}, err
+	}
+	return b, nil
+}
+
+// FromSlice builds a Board from 81 values in row-major order, validated like
+// FromRows.
+func FromSlice(cells []int) (Board, error) {
+	if len(cells) != 81 {
+		return Board{}, fmt.Errorf("board has %d cells, want 81", len(cells))
+	}
+	var b Board
+	for i, v := range cells {
+		b[i/9][i%9] = v
+	}
+	if err := Validate(b); err != nil {
+		return Board{}, err
+	}
+	return b, nil
+}
+
 // String returns 81-char representation of the board, '0' for empty.
 func (b Board) String() string {
 	buf := make([]byte, 0, 81)
@@ -44,3 +83,48 @@ func (b Board) String() string {
 	}
 	return string(buf)
 }
+
+// MarshalText encodes b in the 81-character String form, so boards work as flag
+// values, map keys and text columns.
+func (b Board) MarshalText() ([]byte, error) {
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if b[r][c] < 0 || b[r][c] > 9 {
+				return nil, ErrInvalidBoard
+			}
+		}
+	}
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText parses the FromString form; surrounding whitespace is ignored.
+func (b *Board) UnmarshalText(text []byte) error {
+	out, err := FromString(strings.TrimSpace(string(text)))
+	if err != nil {
+		return err
+	}
+	*b = out
+	return nil
+}
+
+// MarshalJSON keeps the JSON form a 9x9 array of numbers; without it encoding/json
+// would switch to the string from MarshalText.
+func (b Board) MarshalJSON() ([]byte, error) { return json.Marshal([9][9]int(b)) }
+
+// UnmarshalJSON accepts the 9x9 array form (not rule-checked, as before) or an
+// 81-character string, which is parsed like UnmarshalText.
+func (b *Board) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return b.UnmarshalText([]byte(s))
+	}
+	var cells [9][9]int
+	if err := json.Unmarshal(data, &cells); err != nil {
+		return err
+	}
+	*b = cells
+	return nil
+}
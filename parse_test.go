@@ -1,6 +1,11 @@
 package sudoku
 
-import "testing"
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
 
 func TestFromStringErrors(t *testing.T) {
 	// wrong length
@@ -42,3 +47,80 @@ func makeStr(ch byte, n int) string {
 	}
 	return string(buf)
 }
+
+func TestBoardTextMarshaling(t *testing.T) {
+	in := easyPuzzle
+	var b Board
+	if err := b.UnmarshalText([]byte(" " + in + "\n")); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	text, err := b.MarshalText()
+	if err != nil || string(text) != in {
+		t.Fatalf("marshal: %q %v", text, err)
+	}
+	if err := b.UnmarshalText([]byte("55" + in[2:])); err == nil {
+		t.Fatalf("expected invalid board error")
+	}
+	b[0][0] = 12
+	if _, err := b.MarshalText(); err == nil {
+		t.Fatalf("expected out-of-range error")
+	}
+}
+
+func TestBoardJSONKeepsArrayForm(t *testing.T) {
+	b, _ := FromString(easyPuzzle)
+	data, err := json.Marshal(map[string]Board{"puzzle": b})
+	if err != nil || !strings.HasPrefix(string(data), `{"puzzle":[[5,3,0,0,7`) {
+		t.Fatalf("unexpected encoding %s %v", data, err)
+	}
+	// map keys use the text form
+	keyed, _ := json.Marshal(map[Board]int{b: 1})
+	if !strings.Contains(string(keyed), `"`+b.String()+`"`) {
+		t.Fatalf("unexpected key encoding %s", keyed)
+	}
+	for _, in := range []string{string(data[len(`{"puzzle":`) : len(data)-1]), `"` + b.String() + `"`} {
+		var back Board
+		if err := json.Unmarshal([]byte(in), &back); err != nil || back != b {
+			t.Fatalf("decode %s: %v", in, err)
+		}
+	}
+	var bad Board
+	if err := json.Unmarshal([]byte(`"123"`), &bad); err == nil {
+		t.Fatalf("expected short string to fail")
+	}
+}
+
+func TestFromRowsAndSlice(t *testing.T) {
+	want, _ := FromString(easyPuzzle)
+	rows := make([][]int, 9)
+	var flat []int
+	for r := range want {
+		rows[r] = append([]int(nil), want[r][:]...)
+		flat = append(flat, want[r][:]...)
+	}
+	if b, err := FromRows(rows); err != nil || b != want {
+		t.Fatalf("FromRows = %v, %v", b, err)
+	}
+	if b, err := FromSlice(flat); err != nil || b != want {
+		t.Fatalf("FromSlice = %v, %v", b, err)
+	}
+	if _, err := FromRows(rows[:8]); err == nil {
+		t.Fatal("expected row count error")
+	}
+	rows[3] = rows[3][:8]
+	if _, err := FromRows(rows); err == nil || !strings.Contains(err.Error(), "row 3") {
+		t.Fatalf("expected short row error, got %v", err)
+	}
+	if _, err := FromSlice(flat[:80]); err == nil {
+		t.Fatal("expected cell count error")
+	}
+	flat[2] = 10
+	var re *RangeError
+	if _, err := FromSlice(flat); !errors.As(err, &re) {
+		t.Fatalf("expected RangeError, got %v", err)
+	}
+	flat[2] = 5 // duplicates r1c1
+	if _, err := FromSlice(flat); !errors.Is(err, ErrInvalidBoard) {
+		t.Fatalf("expected ErrInvalidBoard, got %v", err)
+	}
+}
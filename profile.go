@@ -0,0 +1,155 @@
+package sudoku
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// Propagation selects how much work the generator's search does per node.
+type Propagation int
+
+const (
+	// PropagateNone fills the first empty cell and checks each value against the
+	// grid directly. Cheapest per node; fine up to 9x9.
+	PropagateNone Propagation = iota
+	// PropagateMRV tracks row, column and box candidates as bitmasks and branches
+	// on the most constrained cell. Required for jigsaw regions and extra
+	// constraints, and far faster on 16x16 and larger.
+	PropagateMRV
+)
+
+// ValueOrder selects the order candidate values are tried while filling a grid.
+type ValueOrder int
+
+const (
+	// ValuesRandom shuffles candidates with the Generator's source.
+	ValuesRandom ValueOrder = iota
+	// ValuesAscending tries candidates in increasing order. Only the prefilled
+	// diagonal boxes vary between seeds, and restarts cannot change the path.
+	ValuesAscending
+)
+
+// Profile names accepted by Profile.
+const (
+	ProfileFast     = "fast"
+	ProfileBalanced = "balanced"
+	ProfileThorough = "thorough"
+)
+
+// SolverProfile holds the search parameters used by GenerateWithProfile.
+// Node budgets trade quality for speed: a fill that exceeds RestartNodes starts
+// over with a fresh value order, and a uniqueness check that exceeds UniqueNodes
+// counts as ambiguous, so the clue is kept and the puzzle ends up with more
+// givens than the difficulty asks for. Zero budgets mean unlimited.
+type SolverProfile struct {
+	Name         string
+	Propagation  Propagation
+	ValueOrder   ValueOrder
+	RestartNodes int // fill node budget per run (PropagateMRV only)
+	MaxRestarts  int // fill runs before the attempt fails
+	UniqueNodes  int // node budget per uniqueness check (PropagateMRV only)
+}
+
+// Profile returns the named profile tuned for size x size grids. The budgets
+// come from benchmarks of 9x9, 16x16 and 25x25 generation: balanced keeps 16x16
+// puzzles within a few clues of the target in well under a second, fast roughly
+// halves that at the cost of extra givens, and thorough allows five times the
+// uniqueness budget of balanced for sparser puzzles. On 9x9 and smaller grids
+// balanced and thorough search without budgets.
+func Profile(name string, size int) (SolverProfile, error) {
+	p := SolverProfile{Name: name, Propagation: PropagateMRV, RestartNodes: 1000, MaxRestarts: 300}
+	switch name {
+	case ProfileFast:
+		p.UniqueNodes = 200
+	case ProfileBalanced:
+		p.UniqueNodes = 1000
+	case ProfileThorough:
+		p.UniqueNodes = 5000
+	default:
+		return SolverProfile{}, fmt.Errorf("unknown solver profile %q", name)
+	}
+	if size <= 9 {
+		// small searches are cheap; only the jigsaw/constraint path uses the fill budget
+		p.RestartNodes, p.MaxRestarts = jigsawCheckBudget, 50
+		if name == ProfileFast {
+			p.UniqueNodes = 0
+		} else {
+			p.Propagation, p.UniqueNodes = PropagateNone, 0
+		}
+	}
+	return p, nil
+}
+
+// ProfileDefault names the profile Generate uses.
+const ProfileDefault = "default"
+
+// DefaultProfile returns the profile Generate uses for size x size grids. Up
+// to 9x9 that is plain backtracking with an exhaustive uniqueness check, so
+// puzzles always reach the clue target; jigsaw and constrained grids fill with
+// restarts. Larger grids get the balanced budgets of Profile: the exhaustive
+// search takes minutes per 16x16 puzzle in the benchmarks, balanced well under
+// a second for a few clues above the target.
+func DefaultProfile(size int) SolverProfile {
+	if size > 9 {
+		p, _ := Profile(ProfileBalanced, size)
+		p.Name = ProfileDefault
+		return p
+	}
+	return SolverProfile{Name: ProfileDefault, Propagation: PropagateNone, RestartNodes: jigsawCheckBudget, MaxRestarts: 50}
+}
+
+// GenerateWithProfile is Generate with explicit search parameters.
+// Concurrent calls are safe, as for the package-level Generate.
+func (g Grid) GenerateWithProfile(d Difficulty, attempts int, p SolverProfile) (Grid, error) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return defaultGenerator().GenerateGridWithProfile(g, d, attempts, p)
+}
+
+// GenerateGridWithProfile is GenerateGrid with explicit search parameters.
+func (gen *Generator) GenerateGridWithProfile(g Grid, d Difficulty, attempts int, p SolverProfile) (Grid, error) {
+	return g.generateIn(gen.rng, d, attempts, genOptions{profile: p})
+}
+
+// profileFill completes w in place following p.
+func (g Grid) profileFill(p SolverProfile) func(*Grid, *rand.Rand) bool {
+	if p.Propagation == PropagateNone && !g.useMaskSolver() {
+		if p.ValueOrder == ValuesAscending {
+			return func(w *Grid, _ *rand.Rand) bool { return g.backtrack(w, nil) }
+		}
+		return g.backtrack
+	}
+	return func(w *Grid, rng *rand.Rand) bool {
+		if p.ValueOrder == ValuesAscending {
+			rng = nil
+		}
+		for restart := 0; restart < max(p.MaxRestarts, 1); restart++ {
+			ks := newMaskSolver(*w)
+			ks.rng = rng
+			ks.maxNodes = p.RestartNodes
+			found := false
+			ks.search(func(sol Grid) bool {
+				for r := range sol.Cells {
+					copy(w.Cells[r], sol.Cells[r])
+				}
+				found = true
+				return true
+			})
+			if found {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// profileUnique reports whether w has exactly one solution within p's budget.
+func (g Grid) profileUnique(p SolverProfile) func(Grid, int) bool {
+	if p.Propagation == PropagateNone && !g.useMaskSolver() {
+		return g.hasUniqueSolution
+	}
+	return func(w Grid, limit int) bool {
+		sols, complete := KillerGrid{Grid: w}.solutions(limit, p.UniqueNodes)
+		return complete && len(sols) == 1
+	}
+}
@@ -0,0 +1,90 @@
+package sudoku
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestProfileLookup(t *testing.T) {
+	if _, err := Profile("turbo", 9); err == nil {
+		t.Fatalf("unknown profile accepted")
+	}
+	if p := DefaultProfile(9); p.Propagation != PropagateNone || p.UniqueNodes != 0 {
+		t.Fatalf("9x9 default should search exhaustively: %+v", p)
+	}
+	for _, size := range []int{16, 25} {
+		balanced, _ := Profile(ProfileBalanced, size)
+		if p := DefaultProfile(size); p.Name != ProfileDefault || p.Propagation != PropagateMRV || p.UniqueNodes != balanced.UniqueNodes {
+			t.Fatalf("%dx%d default should use the balanced budgets: %+v", size, size, p)
+		}
+	}
+	fast, _ := Profile(ProfileFast, 16)
+	thorough, _ := Profile(ProfileThorough, 16)
+	if fast.Propagation != PropagateMRV || fast.UniqueNodes >= thorough.UniqueNodes {
+		t.Fatalf("fast %+v thorough %+v", fast, thorough)
+	}
+}
+
+func TestGenerateWithProfile16(t *testing.T) {
+	g, _ := NewGrid(16, 4, 4)
+	p, _ := Profile(ProfileFast, 16)
+	puz, err := NewGenerator(3).GenerateGridWithProfile(g, Medium, 1, p)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if err := puz.Validate(); err != nil {
+		t.Fatalf("invalid puzzle: %v", err)
+	}
+	if n := puz.CountSolutions(2); n != 1 {
+		t.Fatalf("want unique solution, got %d", n)
+	}
+	if clues := g.countClues(puz); clues < g.cluesFor(Medium) {
+		t.Fatalf("clues %d below target %d", clues, g.cluesFor(Medium))
+	}
+}
+
+func TestGenerateWithProfileAscending(t *testing.T) {
+	g, _ := NewGrid(9, 3, 3)
+	p := DefaultProfile(9)
+	p.ValueOrder = ValuesAscending
+	a, err := NewGenerator(5).GenerateGridWithProfile(g, Easy, 1, p)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	b, _ := NewGenerator(5).GenerateGridWithProfile(g, Easy, 1, p)
+	if a.String() != b.String() || a.CountSolutions(2) != 1 {
+		t.Fatalf("ascending profile not reproducible or not unique")
+	}
+}
+
+// BenchmarkGenerateProfile compares the default search with the budgeted
+// profiles; clues/op shows how far each lands above the Medium target. Above
+// 9x9 the default is balanced, so it only runs on 9x9; the exhaustive search
+// it replaces there takes minutes per 16x16 puzzle.
+func BenchmarkGenerateProfile(b *testing.B) {
+	for _, tc := range []struct{ size, box int }{{9, 3}, {16, 4}} {
+		g, _ := NewGrid(tc.size, tc.box, tc.box)
+		var profiles []SolverProfile
+		if tc.size <= 9 {
+			profiles = append(profiles, DefaultProfile(tc.size))
+		}
+		for _, name := range []string{ProfileFast, ProfileBalanced, ProfileThorough} {
+			p, _ := Profile(name, tc.size)
+			profiles = append(profiles, p)
+		}
+		for _, p := range profiles {
+			b.Run(fmt.Sprintf("%dx%d/%s", tc.size, tc.size, p.Name), func(b *testing.B) {
+				gen := NewGenerator(1)
+				clues := 0
+				for range b.N {
+					puz, err := gen.GenerateGridWithProfile(g, Medium, 1, p)
+					if err != nil {
+						b.Fatal(err)
+					}
+					clues += g.countClues(puz)
+				}
+				b.ReportMetric(float64(clues)/float64(b.N), "clues/op")
+			})
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package sudoku
+
+// Progress is a snapshot of a running generation. Counters cover the current
+// attempt except Checks, which accumulates over all attempts.
+type Progress struct {
+	Attempt  int `json:"attempt"`  // 1-based
+	Attempts int `json:"attempts"` // attempt limit
+	Clues    int `json:"clues"`    // givens left in the current candidate puzzle
+	Target   int `json:"target"`   // clue count the difficulty aims for
+	Removed  int `json:"removed"`  // clues removed so far
+	Checks   int `json:"checks"`   // uniqueness checks performed
+}
+
+// GenerateWithProgress is Generate, calling progress once the solved board of
+// each attempt is built and after every uniqueness check. progress runs on the
+// generating goroutine and should return quickly; to feed a channel, send
+// without blocking (select with a default case) so a slow reader cannot stall
+// generation.
+// Concurrent calls are safe, as for Generate.
+func GenerateWithProgress(d Difficulty, attempts int, progress func(Progress)) (Board, error) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return defaultGenerator().GenerateWithProgress(d, attempts, progress)
+}
+
+// GenerateWithProgress is Generate with progress reports (see GenerateWithProgress).
+func (gen *Generator) GenerateWithProgress(d Difficulty, attempts int, progress func(Progress)) (Board, error) {
+	return generateBoard(gen.rng, d, attempts, SymmetryNone, progress)
+}
+
+// GenerateWithProgress is Generate with progress reports (see the package-level
+// GenerateWithProgress). Large grids run thousands of checks, so this is the
+// way to keep a UI or API client informed.
+// Concurrent calls are safe, as for the package-level Generate.
+func (g Grid) GenerateWithProgress(d Difficulty, attempts int, progress func(Progress)) (Grid, error) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return defaultGenerator().GenerateGridWithProgress(g, d, attempts, progress)
+}
+
+// GenerateGridWithProgress is GenerateGrid with progress reports.
+func (gen *Generator) GenerateGridWithProgress(g Grid, d Difficulty, attempts int, progress func(Progress)) (Grid, error) {
+	return g.generateIn(gen.rng, d, attempts, genOptions{profile: DefaultProfile(g.Size), progress: progress})
+}
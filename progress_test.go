@@ -0,0 +1,39 @@
+package sudoku
+
+import "testing"
+
+func TestGenerateWithProgress(t *testing.T) {
+	var reports []Progress
+	b, err := NewGenerator(11).GenerateWithProgress(Medium, 1, func(p Progress) { reports = append(reports, p) })
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if want, _ := NewGenerator(11).Generate(Medium, 1); want != b {
+		t.Fatalf("progress reporting changed the output")
+	}
+	if len(reports) < 2 || reports[0].Clues != 81 || reports[0].Checks != 0 {
+		t.Fatalf("first report %+v of %d", reports[0], len(reports))
+	}
+	for i := 1; i < len(reports); i++ {
+		if reports[i].Checks != reports[i-1].Checks+1 || reports[i].Clues > reports[i-1].Clues {
+			t.Fatalf("report %d not monotonic: %+v after %+v", i, reports[i], reports[i-1])
+		}
+	}
+	last := reports[len(reports)-1]
+	if last.Clues != countClues(b) || last.Removed != 81-last.Clues || last.Target != 32 || last.Attempts != 1 {
+		t.Fatalf("last report %+v for %d clues", last, countClues(b))
+	}
+}
+
+func TestGridGenerateWithProgress(t *testing.T) {
+	g, _ := NewGrid(16, 4, 4)
+	var last Progress
+	calls := 0
+	p, err := NewGenerator(2).GenerateGridWithProgress(g, Easy, 1, func(pr Progress) { last, calls = pr, calls+1 })
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if calls != last.Checks+1 || last.Clues != g.countClues(p) || last.Removed != 256-last.Clues {
+		t.Fatalf("%d calls, last %+v, clues %d", calls, last, g.countClues(p))
+	}
+}
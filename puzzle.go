@@ -0,0 +1,90 @@
+package sudoku
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+// Puzzle bundles a generated board with what callers lose once they start editing
+// it: which cells were given, the solution, and how the puzzle was made.
+type Puzzle struct {
+	Givens     Board      `json:"givens"`   // original clues; play happens on a copy
+	Solution   Board      `json:"solution"` // the unique solution of Givens
+	Difficulty Difficulty `json:"difficulty"`
+	Clues      int        `json:"clues"`
+	Seed       uint64     `json:"seed"`             // NewGenerator(Seed).Generate(Difficulty, attempts) rebuilds Givens (GenerateSymmetric for WithSymmetry); 0 when imported
+	ID         string     `json:"id"`               // PuzzleID(Givens)
+	Rating     Rating     `json:"rating"`           // Rate(Givens); may differ from the Difficulty asked of the generator
+	Themes     []Theme    `json:"themes,omitempty"` // Themes(Givens)
+}
+
+// GeneratePuzzle is Generate returning the givens together with their solution
+// and metadata. It is safe for concurrent use.
+func GeneratePuzzle(d Difficulty, attempts int) (Puzzle, error) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return defaultGenerator().GeneratePuzzle(d, attempts)
+}
+
+// GeneratePuzzle draws a per-puzzle seed from the Generator and builds the puzzle
+// from it, so each Puzzle can be regenerated on its own from Seed.
+func (gen *Generator) GeneratePuzzle(d Difficulty, attempts int) (Puzzle, error) {
+	return generatePuzzle(gen.rng.Uint64(), d, attempts, SymmetryNone)
+}
+
+// generatePuzzle builds the puzzle a fresh Generator seeded with seed produces.
+func generatePuzzle(seed uint64, d Difficulty, attempts int, sym Symmetry) (Puzzle, error) {
+	b, err := NewGenerator(seed).GenerateSymmetric(d, attempts, sym)
+	if err != nil {
+		return Puzzle{}, err
+	}
+	return newPuzzle(b, d, seed)
+}
+
+// NewPuzzle wraps givens that were not generated here, such as a parsed file,
+// in a Puzzle. Difficulty is taken from the rating. Boards without a unique
+// solution are rejected.
+func NewPuzzle(b Board) (Puzzle, error) {
+	p, err := newPuzzle(b, "", 0)
+	if err != nil {
+		return Puzzle{}, err
+	}
+	p.Difficulty = p.Rating.Difficulty
+	return p, nil
+}
+
+func newPuzzle(b Board, d Difficulty, seed uint64) (Puzzle, error) {
+	rating, err := Rate(b)
+	if err != nil {
+		return Puzzle{}, err
+	}
+	sol, ok := SolveDLX(b)
+	if !ok {
+		return Puzzle{}, errors.New("puzzle has no solution")
+	}
+	return Puzzle{Givens: b, Solution: sol, Difficulty: d, Clues: countClues(b), Seed: seed, ID: PuzzleID(b), Rating: rating, Themes: Themes(b)}, nil
+}
+
+// IsGiven reports whether r,c holds a clue of the original puzzle.
+func (p Puzzle) IsGiven(r, c int) bool { return p.Givens[r][c] != 0 }
+
+// Mistakes lists the filled cells of b that disagree with the solution.
+func (p Puzzle) Mistakes(b Board) []Cell {
+	var out []Cell
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if b[r][c] != 0 && b[r][c] != p.Solution[r][c] {
+				out = append(out, Cell{r, c})
+			}
+		}
+	}
+	return out
+}
+
+// PuzzleID returns a short stable identifier for the givens of b: the first 16 hex
+// digits of the SHA-256 of its Canonical form, so isomorphic puzzles share an ID.
+func PuzzleID(b Board) string {
+	sum := sha256.Sum256([]byte(Canonical(b).String()))
+	return hex.EncodeToString(sum[:8])
+}
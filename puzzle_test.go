@@ -0,0 +1,74 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGeneratePuzzle(t *testing.T) {
+	p, err := NewGenerator(11).GeneratePuzzle(Medium, 3)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if p.Clues != countClues(p.Givens) || p.Difficulty != Medium || p.ID != PuzzleID(p.Givens) {
+		t.Fatalf("metadata mismatch: %+v", p)
+	}
+	if want, _ := Rate(p.Givens); p.Rating != want {
+		t.Fatalf("rating = %+v, want %+v", p.Rating, want)
+	}
+	if !reflect.DeepEqual(p.Themes, Themes(p.Givens)) {
+		t.Fatalf("themes = %v, want %v", p.Themes, Themes(p.Givens))
+	}
+	if countClues(p.Solution) != 81 || Validate(p.Solution) != nil || len(p.Mistakes(p.Solution)) != 0 {
+		t.Fatalf("bad solution:\n%s", p.Solution)
+	}
+	again, _ := NewGenerator(p.Seed).Generate(p.Difficulty, 3)
+	if again != p.Givens {
+		t.Fatalf("seed %d does not rebuild the givens", p.Seed)
+	}
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if p.IsGiven(r, c) != (p.Givens[r][c] != 0) {
+				t.Fatalf("IsGiven(%d,%d) wrong", r, c)
+			}
+		}
+	}
+}
+
+func TestPuzzleMistakesAndJSON(t *testing.T) {
+	b, _ := FromString(easyPuzzle)
+	p, err := newPuzzle(b, Easy, 1)
+	if err != nil {
+		t.Fatalf("newPuzzle: %v", err)
+	}
+	play := p.Givens
+	play[0][2] = 4 // solution has 4
+	play[0][3] = 5 // solution has 6
+	if got := p.Mistakes(play); len(got) != 1 || got[0] != (Cell{0, 3}) {
+		t.Fatalf("mistakes = %v", got)
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var back Puzzle
+	if err := json.Unmarshal(data, &back); err != nil || !reflect.DeepEqual(back, p) {
+		t.Fatalf("round trip: %v\n%+v", err, back)
+	}
+}
+
+func TestNewPuzzle(t *testing.T) {
+	b, _ := FromString(easyPuzzle)
+	p, err := NewPuzzle(b)
+	if err != nil {
+		t.Fatalf("NewPuzzle: %v", err)
+	}
+	if p.Rating.Difficulty != Easy || p.Difficulty != Easy || p.Rating.Clues != 30 || p.Seed != 0 {
+		t.Fatalf("imported puzzle: %+v", p)
+	}
+	var empty Board
+	if _, err := NewPuzzle(empty); err == nil {
+		t.Fatalf("ambiguous board accepted")
+	}
+}
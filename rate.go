@@ -0,0 +1,250 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// Techniques beyond singles used by Rate, named like the Trace reasons. See
+// Technique for the full ordered list.
+const (
+	ReasonLockedCandidates = "locked-candidates"
+	ReasonNakedPair        = "naked-pair"
+)
+
+// Rating grades a puzzle by the hardest technique a human needs to solve it.
+type Rating struct {
+	Difficulty Difficulty `json:"difficulty"`
+	Hardest    string     `json:"hardest"` // a Reason* constant; ReasonGuess when logic stalls
+	Clues      int        `json:"clues"`
+}
+
+// Rate solves b by logic, trying the simplest technique first after every step:
+// naked and hidden singles rate Easy, locked candidates and naked pairs Medium,
+// and puzzles that still need guessing rate Hard. Invalid puzzles and puzzles
+// without a unique solution return an error.
+func Rate(b Board) (Rating, error) {
+	if err := checkUnique(b); err != nil {
+		return Rating{}, err
+	}
+	st := newLogicState(b)
+	level := 0
+	for countClues(st.b) < 81 {
+		step := -1
+		for i, tech := range logicTechniques {
+			if tech.apply(&st) {
+				step = i
+				break
+			}
+		}
+		if step < 0 {
+			level = len(logicTechniques)
+			break
+		}
+		level = max(level, step)
+	}
+	hardest := TechniqueGuess
+	if level < len(logicTechniques) {
+		hardest = logicTechniques[level].tech
+	}
+	info := hardest.Info()
+	return Rating{Clues: countClues(b), Difficulty: info.Difficulty, Hardest: info.ID}, nil
+}
+
+// checkUnique rejects invalid puzzles and those without exactly one solution.
+func checkUnique(b Board) error {
+	if err := Validate(b); err != nil {
+		return err
+	}
+	switch countSolutionsMCV(b, 2) {
+	case 0:
+		return errors.New("puzzle has no solution")
+	case 2:
+		return errors.New("puzzle has multiple solutions")
+	}
+	return nil
+}
+
+// logicTechniques in increasing difficulty; each reports whether it made progress.
+var logicTechniques = [...]struct {
+	tech  Technique
+	apply func(*logicState) bool
+}{
+	{TechniqueNakedSingle, (*logicState).nakedSingle},
+	{TechniqueHiddenSingle, (*logicState).hiddenSingle},
+	{TechniqueLockedCandidates, (*logicState).lockedCandidates},
+	{TechniqueNakedPair, (*logicState).nakedPair},
+}
+
+// logicState is a board with the candidate mask of every empty cell. With
+// trace set, every technique that makes progress appends a Step.
+type logicState struct {
+	b     Board
+	cand  [9][9]uint16
+	trace bool
+	steps []Step
+}
+
+func newLogicState(b Board) logicState {
+	st := logicState{b: b}
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if b[r][c] == 0 {
+				st.cand[r][c] = candidateMask(&st.b, r, c)
+			}
+		}
+	}
+	return st
+}
+
+func (st *logicState) record(s Step) {
+	if st.trace {
+		s.FromCandidates = true
+		st.steps = append(st.steps, s)
+	}
+}
+
+func (st *logicState) place(r, c, v int) {
+	st.b[r][c], st.cand[r][c] = v, 0
+	for _, u := range UnitsOf(Cell{r, c}) {
+		for _, p := range u.Cells() {
+			st.cand[p.Row][p.Col] &^= 1 << v
+		}
+	}
+}
+
+// eliminate removes the values in m from cell p and reports whether any went.
+func (st *logicState) eliminate(p Cell, m uint16) bool {
+	if st.cand[p.Row][p.Col]&m == 0 {
+		return false
+	}
+	st.cand[p.Row][p.Col] &^= m
+	return true
+}
+
+func (st *logicState) nakedSingle() bool {
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if m := st.cand[r][c]; st.b[r][c] == 0 && bits.OnesCount16(m) == 1 {
+				v := maskValue(m)
+				st.place(r, c, v)
+				st.record(Step{Technique: TechniqueNakedSingle, Cell: Cell{r, c}, Value: v,
+					Text: fmt.Sprintf("%s can only be %d: the steps above removed its other candidates.", Cell{r, c}, v)})
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (st *logicState) hiddenSingle() bool {
+	for u := 0; u < 27; u++ {
+		unit := Unit{UnitKind(u / 9), u % 9}
+		cells := unit.Cells()
+		for v := 1; v <= 9; v++ {
+			var at Cell
+			n := 0
+			for _, p := range cells {
+				if st.cand[p.Row][p.Col]&(1<<v) != 0 {
+					at, n = p, n+1
+				}
+			}
+			if n == 1 {
+				st.place(at.Row, at.Col, v)
+				st.record(Step{Technique: TechniqueHiddenSingle, Cell: at, Value: v, Unit: &unit,
+					Text: fmt.Sprintf("With the candidates removed above, %d fits nowhere else in %s, so %s is %d.", v, unit, at, v)})
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lockedCandidates handles pointing (a box's spots for v all in one row or
+// column) and claiming (a line's spots for v all in one box).
+func (st *logicState) lockedCandidates() bool {
+	for u := 0; u < 27; u++ {
+		unit := Unit{UnitKind(u / 9), u % 9}
+		cells := unit.Cells()
+		for v := 1; v <= 9; v++ {
+			bit := uint16(1) << v
+			var spots []Cell
+			for _, p := range cells {
+				if st.cand[p.Row][p.Col]&bit != 0 {
+					spots = append(spots, p)
+				}
+			}
+			if len(spots) < 2 {
+				continue
+			}
+			for _, other := range UnitsOf(spots[0]) {
+				if other == unit || (unit.Kind != UnitBox && other.Kind != UnitBox) {
+					continue
+				}
+				shared := true
+				for _, p := range spots[1:] {
+					if !containsUnit(UnitsOf(p), other) {
+						shared = false
+						break
+					}
+				}
+				if !shared {
+					continue
+				}
+				var removed []Cell
+				for _, p := range other.Cells() {
+					if !containsUnit(UnitsOf(p), unit) && st.eliminate(p, bit) {
+						removed = append(removed, p)
+					}
+				}
+				if len(removed) > 0 {
+					unit := unit
+					st.record(Step{Technique: TechniqueLockedCandidates, Unit: &unit, Cells: spots,
+						Eliminated: removed, Digits: []int{v},
+						Text: fmt.Sprintf("In %s, %d can only go in %s, which all lie in %s, so %d is removed from %s.",
+							unit, v, cellList(spots), other, v, cellList(removed))})
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (st *logicState) nakedPair() bool {
+	for u := 0; u < 27; u++ {
+		unit := Unit{UnitKind(u / 9), u % 9}
+		cells := unit.Cells()
+		for i, a := range cells {
+			m := st.cand[a.Row][a.Col]
+			if bits.OnesCount16(m) != 2 {
+				continue
+			}
+			for _, b := range cells[i+1:] {
+				if st.cand[b.Row][b.Col] != m {
+					continue
+				}
+				var removed []Cell
+				for _, p := range cells {
+					if p != a && p != b && st.eliminate(p, m) {
+						removed = append(removed, p)
+					}
+				}
+				if len(removed) > 0 {
+					d := maskValues(m)
+					st.record(Step{Technique: TechniqueNakedPair, Unit: &unit, Cells: []Cell{a, b},
+						Eliminated: removed, Digits: d,
+						Text: fmt.Sprintf("%s and %s in %s can only hold %d and %d, so those digits are removed from %s.",
+							a, b, unit, d[0], d[1], cellList(removed))})
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func containsUnit(units [3]Unit, u Unit) bool {
+	return units[0] == u || units[1] == u || units[2] == u
+}
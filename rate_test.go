@@ -0,0 +1,60 @@
+package sudoku
+
+import "testing"
+
+func TestRate(t *testing.T) {
+	for _, tc := range []struct {
+		puzzle string
+		want   Difficulty
+	}{
+		{easyPuzzle, Easy},
+		{"000700000000005040381000007000071400000000600093082000020050300010020004800049260", Medium},
+		// needs more than singles: Arto Inkala's "world's hardest" puzzle
+		{"800000000003600000070090200050007000000045700000100030001000068008500010090000400", Hard},
+	} {
+		b, _ := FromString(tc.puzzle)
+		r, err := Rate(b)
+		if err != nil {
+			t.Fatalf("rate %s: %v", tc.puzzle, err)
+		}
+		if r.Difficulty != tc.want || r.Clues != countClues(b) {
+			t.Fatalf("rate %s = %+v, want %s", tc.puzzle, r, tc.want)
+		}
+	}
+}
+
+func TestRateRejects(t *testing.T) {
+	amb, _ := FromString("239718546014965320056423190681549273945372861327681954178296435493857612562134789")
+	if _, err := Rate(amb); err == nil {
+		t.Fatalf("non-unique puzzle rated")
+	}
+	bad := Board{}
+	bad[0][0], bad[0][1] = 5, 5
+	if _, err := Rate(bad); err == nil {
+		t.Fatalf("invalid puzzle rated")
+	}
+}
+
+func TestLockedCandidatesPointing(t *testing.T) {
+	// In box 1 the only spots for 1 are r1c1 and r1c2, so 1 leaves the rest of row 1.
+	var st logicState
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			st.cand[r][c] = 0x3fe
+		}
+	}
+	for _, p := range []Cell{{0, 2}, {1, 0}, {1, 1}, {1, 2}, {2, 0}, {2, 1}, {2, 2}} {
+		st.cand[p.Row][p.Col] &^= 1 << 1
+	}
+	if !st.lockedCandidates() {
+		t.Fatalf("no progress")
+	}
+	for c := 3; c < 9; c++ {
+		if st.cand[0][c]&(1<<1) != 0 {
+			t.Fatalf("1 not eliminated from r1c%d", c+1)
+		}
+	}
+	if st.cand[3][0]&(1<<1) == 0 {
+		t.Fatalf("unrelated cell lost its candidate")
+	}
+}
@@ -0,0 +1,49 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func TestLaTeX(t *testing.T) {
+	givens, _ := sudoku.FromString(sudokutest.Easy)
+	b := givens
+	b[0][2] = 4 // player entry
+	data, err := LaTeX(b, LaTeXOptions{Givens: givens, ShadeBoxes: true, BoldGivens: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	if !strings.HasPrefix(out, `\begin{tikzpicture}[scale=0.6`) || !strings.HasSuffix(out, "\\end{tikzpicture}\n") {
+		t.Fatalf("not a tikzpicture:\n%s", out)
+	}
+	for _, want := range []string{
+		`\node at (0.5,8.5) {\textbf{5}};`,
+		`\node at (2.5,8.5) {\textit{4}};`,
+		`\fill[gray!20] (3,6) rectangle (6,9);`,
+		`\draw[step=3, very thick] (0,0) grid (9,9);`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, `\fill`); n != 4 {
+		t.Fatalf("%d shaded boxes, want 4", n)
+	}
+	if n := strings.Count(out, `\node`); n != 31 {
+		t.Fatalf("%d values, want 31", n)
+	}
+
+	plain, _ := LaTeX(givens, LaTeXOptions{Scale: 0.5})
+	if s := string(plain); strings.Contains(s, `\fill`) || strings.Contains(s, `\text`) || !strings.Contains(s, "scale=0.5") {
+		t.Fatalf("plain options should not shade or style:\n%s", s)
+	}
+
+	b[4][4] = 10
+	if _, err := LaTeX(b, LaTeXOptions{}); err == nil {
+		t.Fatal("expected range error")
+	}
+}
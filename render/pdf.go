@@ -0,0 +1,240 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.rumenx.com/sudoku"
+)
+
+// WorksheetPuzzle is one puzzle of a printable worksheet.
+type WorksheetPuzzle struct {
+	Puzzle     sudoku.Grid
+	Solution   sudoku.Grid       // optional; needed when solutions are printed
+	Difficulty sudoku.Difficulty // printed next to the number; may be empty
+}
+
+// WorksheetOptions lay out a worksheet.
+type WorksheetOptions struct {
+	Title     string // printed at the top of every page; ASCII only
+	PerPage   int    // 1, 2, 4 or 6 puzzles per page; zero means 4
+	Solutions bool   // append pages with the solutions in the same layout
+	Letter    bool   // US Letter instead of A4
+}
+
+// Worksheet writes puzzles as a PDF with opts.PerPage puzzles per page, each
+// numbered and labelled with its difficulty, followed by the solution pages when
+// opts.Solutions is set. Grids of any size up to 25x25 are drawn with their
+// box borders, or their region borders for jigsaw grids. The PDF uses only the built-in Helvetica fonts, so it needs no
+// font files and prints anywhere.
+func Worksheet(w io.Writer, puzzles []WorksheetPuzzle, opts WorksheetOptions) error {
+	if len(puzzles) == 0 {
+		return errors.New("worksheet needs at least one puzzle")
+	}
+	cols, rows, ok := worksheetLayout(opts.PerPage)
+	if !ok {
+		return fmt.Errorf("unsupported puzzles per page: %d (want 1, 2, 4 or 6)", opts.PerPage)
+	}
+	for i, p := range puzzles {
+		if !wellFormed(p.Puzzle) {
+			return fmt.Errorf("puzzle %d: empty or malformed grid", i+1)
+		}
+		if opts.Solutions && (p.Solution.Size != p.Puzzle.Size || !wellFormed(p.Solution)) {
+			return fmt.Errorf("puzzle %d: solution missing, malformed or of a different size", i+1)
+		}
+	}
+	pageW, pageH := 595.0, 842.0 // A4 in points
+	if opts.Letter {
+		pageW, pageH = 612, 792
+	}
+	per := cols * rows
+	var pages []string
+	for _, solutions := range []bool{false, true} {
+		if solutions && !opts.Solutions {
+			break
+		}
+		for start := 0; start < len(puzzles); start += per {
+			var c pdfContent
+			title := opts.Title
+			if solutions {
+				title = strings.TrimSpace(title + " Solutions")
+			}
+			if title != "" {
+				c.text(pageW/2, pageH-48, 18, true, title)
+			}
+			top, bottom, side := pageH-72, 48.0, 48.0
+			slotW, slotH := (pageW-2*side)/float64(cols), (top-bottom)/float64(rows)
+			for i := start; i < min(start+per, len(puzzles)); i++ {
+				k := i - start
+				x0, y0 := side+float64(k%cols)*slotW, top-float64(k/cols)*slotH // slot top-left
+				p := puzzles[i]
+				g, givens := p.Puzzle, sudoku.Grid{}
+				if solutions {
+					g, givens = p.Solution, p.Puzzle
+				}
+				label := fmt.Sprintf("%d", i+1)
+				if p.Difficulty != "" {
+					label += ". " + strings.ToUpper(string(p.Difficulty[:1])) + string(p.Difficulty[1:])
+				}
+				c.text(x0+slotW/2, y0-16, 11, false, label)
+				size := min(slotW, slotH-28) * 0.9
+				c.grid(g, givens, x0+(slotW-size)/2, y0-24-size, size)
+			}
+			pages = append(pages, c.String())
+		}
+	}
+	return writePDF(w, pages, pageW, pageH)
+}
+
+// wellFormed reports whether g is non-empty and its cells, and regions if any,
+// are Size rows of Size values, so drawing it cannot index out of range.
+func wellFormed(g sudoku.Grid) bool {
+	square := func(rows [][]int) bool {
+		if len(rows) != g.Size {
+			return false
+		}
+		for _, row := range rows {
+			if len(row) != g.Size {
+				return false
+			}
+		}
+		return true
+	}
+	return g.Size > 0 && square(g.Cells) && (g.Regions == nil || square(g.Regions))
+}
+
+func worksheetLayout(perPage int) (cols, rows int, ok bool) {
+	switch perPage {
+	case 0, 4:
+		return 2, 2, true
+	case 1:
+		return 1, 1, true
+	case 2:
+		return 1, 2, true
+	case 6:
+		return 2, 3, true
+	}
+	return 0, 0, false
+}
+
+// pdfContent builds a page content stream. Coordinates are in points from the
+// bottom-left corner.
+type pdfContent struct{ bytes.Buffer }
+
+// text draws s centred on x with its baseline at y.
+func (c *pdfContent) text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	width := 0.0
+	for _, r := range s {
+		width += helveticaWidth(r)
+	}
+	fmt.Fprintf(c, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x-width*size/2, y, pdfEscape(s))
+}
+
+// grid draws g in a size x size square with its bottom-left corner at x, y.
+// Thick lines follow the boxes, or the regions of a jigsaw grid. Non-zero
+// cells of givens are bold; a zero givens makes every value bold.
+func (c *pdfContent) grid(g, givens sudoku.Grid, x, y, size float64) {
+	n := g.Size
+	cell := size / float64(n)
+	c.WriteString("0.6 G 0.5 w\n")
+	for i := 1; i < n; i++ {
+		fmt.Fprintf(c, "%.2f %.2f m %.2f %.2f l S\n", x+float64(i)*cell, y, x+float64(i)*cell, y+size)
+		fmt.Fprintf(c, "%.2f %.2f m %.2f %.2f l S\n", x, y+size-float64(i)*cell, x+size, y+size-float64(i)*cell)
+	}
+	c.WriteString("0 G 1.8 w 2 J\n")
+	region := regionOf(g)
+	for r := 0; r < n; r++ {
+		for col := 0; col <= n; col++ {
+			// the left edge of r,col and the top edge of col,r
+			if col == 0 || col == n || region(r, col-1) != region(r, col) {
+				fmt.Fprintf(c, "%.2f %.2f m %.2f %.2f l S\n",
+					x+float64(col)*cell, y+size-float64(r)*cell, x+float64(col)*cell, y+size-float64(r+1)*cell)
+			}
+			if col == 0 || col == n || region(col-1, r) != region(col, r) {
+				fmt.Fprintf(c, "%.2f %.2f m %.2f %.2f l S\n",
+					x+float64(r)*cell, y+size-float64(col)*cell, x+float64(r+1)*cell, y+size-float64(col)*cell)
+			}
+		}
+	}
+	c.WriteString("0 J\n")
+	fs := cell * 0.55
+	for r := 0; r < n; r++ {
+		for col := 0; col < n; col++ {
+			v := g.Cells[r][col]
+			if v <= 0 || v >= len(sudoku.GridAlphabet) {
+				continue
+			}
+			given := givens.Cells == nil || givens.Cells[r][col] != 0
+			c.text(x+(float64(col)+0.5)*cell, y+size-(float64(r)+0.5)*cell-fs*0.35, fs, given, sudoku.GridAlphabet[v:v+1])
+		}
+	}
+}
+
+// helveticaWidth approximates Helvetica advance widths in ems.
+func helveticaWidth(r rune) float64 {
+	switch {
+	case r >= '0' && r <= '9':
+		return 0.556
+	case r == ' ' || r == '.':
+		return 0.278
+	case r >= 'A' && r <= 'Z':
+		return 0.667
+	}
+	return 0.5
+}
+
+// pdfEscape makes s safe inside a PDF string literal; non-ASCII becomes '?'.
+func pdfEscape(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r < 32 || r > 126:
+			sb.WriteByte('?')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// writePDF writes a PDF 1.4 file with one page per content stream.
+func writePDF(w io.Writer, pages []string, pageW, pageH float64) error {
+	var buf bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, content := range pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageW, pageH, 6+2*i))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,130 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func worksheetPuzzles() []WorksheetPuzzle {
+	var out []WorksheetPuzzle
+	for _, f := range []struct {
+		s          string
+		size, r, c int
+		d          sudoku.Difficulty
+	}{
+		{sudokutest.Easy, 9, 3, 3, sudoku.Easy},
+		{sudokutest.Grid6x2x3, 6, 2, 3, sudoku.Medium},
+		{sudokutest.Grid4x2x2, 4, 2, 2, ""},
+	} {
+		p := sudokutest.MustGrid(f.s, f.size, f.r, f.c)
+		sol, _ := p.Solve()
+		out = append(out, WorksheetPuzzle{Puzzle: p, Solution: sol, Difficulty: f.d})
+	}
+	return out
+}
+
+// checkPDF verifies the file structure: header, trailer and that every xref
+// offset points at its object.
+func checkPDF(t *testing.T, data []byte) (pages int) {
+	t.Helper()
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatal("missing PDF header or trailer")
+	}
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	if m == nil {
+		t.Fatal("no startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	lines := strings.Split(string(data[xref:]), "\n")
+	if lines[0] != "xref" {
+		t.Fatalf("startxref points at %q", lines[0])
+	}
+	var n int
+	fmt.Sscanf(lines[1], "0 %d", &n)
+	for i := 1; i < n; i++ {
+		off, _ := strconv.Atoi(lines[2+i][:10])
+		if want := fmt.Sprintf("%d 0 obj\n", i); !bytes.HasPrefix(data[off:], []byte(want)) {
+			t.Fatalf("xref entry %d points at %q", i, data[off:off+10])
+		}
+	}
+	for _, s := range regexp.MustCompile(`/Length (\d+) >>\nstream\n`).FindAllSubmatchIndex(data, -1) {
+		length, _ := strconv.Atoi(string(data[s[2]:s[3]]))
+		if !bytes.HasPrefix(data[s[1]+length:], []byte("endstream")) {
+			t.Fatal("stream length does not match its content")
+		}
+	}
+	return bytes.Count(data, []byte("/Type /Page /Parent"))
+}
+
+func TestWorksheet(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Worksheet(&buf, worksheetPuzzles(), WorksheetOptions{Title: "Class (4B)", Solutions: true}); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if pages := checkPDF(t, data); pages != 2 {
+		t.Fatalf("%d pages, want 2 (puzzles and solutions)", pages)
+	}
+	for _, want := range []string{"(Class \\(4B\\)) Tj", "(Class \\(4B\\) Solutions) Tj", "(1. Easy) Tj", "(2. Medium) Tj", "(3) Tj", "/MediaBox [0 0 595 842]"} {
+		if !bytes.Contains(data, []byte(want)) {
+			t.Fatalf("PDF lacks %q", want)
+		}
+	}
+
+	buf.Reset()
+	if err := Worksheet(&buf, worksheetPuzzles(), WorksheetOptions{PerPage: 1, Letter: true}); err != nil {
+		t.Fatal(err)
+	}
+	if pages := checkPDF(t, buf.Bytes()); pages != 3 || !bytes.Contains(buf.Bytes(), []byte("/MediaBox [0 0 612 792]")) {
+		t.Fatalf("%d letter pages, want 3", pages)
+	}
+}
+
+func TestWorksheetJigsaw(t *testing.T) {
+	g, err := sudoku.NewJigsawGrid([][]int{{0, 0, 0, 1}, {0, 2, 1, 1}, {2, 2, 3, 1}, {2, 3, 3, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := Worksheet(&buf, []WorksheetPuzzle{{Puzzle: g}}, WorksheetOptions{PerPage: 1}); err != nil {
+		t.Fatal(err)
+	}
+	_, thick, _ := strings.Cut(buf.String(), "0 G 1.8 w 2 J\n")
+	thick, _, _ = strings.Cut(thick, "0 J\n")
+	// 16 outer edges plus the 12 cell edges between different regions
+	if n := strings.Count(thick, " l S\n"); n != 28 {
+		t.Fatalf("%d thick segments, want 28", n)
+	}
+}
+
+func TestWorksheetErrors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Worksheet(&buf, nil, WorksheetOptions{}); err == nil {
+		t.Fatal("expected error for no puzzles")
+	}
+	if err := Worksheet(&buf, worksheetPuzzles(), WorksheetOptions{PerPage: 3}); err == nil {
+		t.Fatal("expected error for 3 per page")
+	}
+	ps := worksheetPuzzles()
+	ps[1].Solution = sudoku.Grid{}
+	if err := Worksheet(&buf, ps, WorksheetOptions{Solutions: true}); err == nil {
+		t.Fatal("expected error for a missing solution")
+	}
+	ps = worksheetPuzzles()
+	ps[0].Puzzle.Cells[4] = ps[0].Puzzle.Cells[4][:8]
+	if err := Worksheet(&buf, ps, WorksheetOptions{}); err == nil {
+		t.Fatal("expected error for a short row")
+	}
+	ps = worksheetPuzzles()
+	ps[2].Solution.Cells[3] = nil
+	if err := Worksheet(&buf, ps, WorksheetOptions{Solutions: true}); err == nil {
+		t.Fatal("expected error for a short solution row")
+	}
+}
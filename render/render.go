@@ -0,0 +1,201 @@
+// Package render draws sudoku grids as images for sharing and export: raster
+// images for PNG and SVG for the web and print.
+//
+// Text uses the fixed 7x13 basic font scaled up with nearest-neighbour sampling,
+// so images are identical on every platform and need no font files.
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"go.rumenx.com/sudoku"
+)
+
+// CellSize is the side of one cell in pixels; Margin surrounds the board.
+const (
+	CellSize = 48
+	Margin   = 16
+)
+
+var (
+	background = color.NRGBA{R: 250, G: 252, B: 255, A: 255}
+	thinLine   = color.NRGBA{R: 203, G: 213, B: 225, A: 255}
+	boxLine    = color.NRGBA{R: 15, G: 23, B: 42, A: 255}
+	givenInk   = color.NRGBA{R: 15, G: 23, B: 42, A: 255}
+	playerInk  = color.NRGBA{R: 37, G: 99, B: 235, A: 255}
+	mutedInk   = color.NRGBA{R: 71, G: 85, B: 105, A: 255}
+)
+
+// Grid draws g with a Margin border. Cells that are non-zero in givens are drawn
+// as clues and the rest as player entries; pass a zero Grid to draw every value
+// as a clue.
+func Grid(g, givens sudoku.Grid) *image.RGBA {
+	side := g.Size*CellSize + 2*Margin
+	img := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	drawBoard(img, g, givens, image.Pt(Margin, Margin), CellSize)
+	return img
+}
+
+// Image is the raster form of SVG: b drawn with the same options, ready for
+// png.Encode. It fails for the same boards SVG rejects.
+func Image(b sudoku.Board, opts RenderOptions) (*image.RGBA, error) {
+	if err := checkBoard(b, opts.Givens); err != nil {
+		return nil, err
+	}
+	cs, m := opts.CellSize, opts.Margin
+	if cs <= 0 {
+		cs = CellSize
+	}
+	if m <= 0 {
+		m = Margin
+	}
+	var givens sudoku.Grid
+	if opts.Givens != (sudoku.Board{}) {
+		givens = opts.Givens.ToGrid()
+	}
+	side := 9*cs + 2*m
+	img := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	drawBoard(img, b.ToGrid(), givens, image.Pt(m, m), cs)
+	if opts.Notes != nil {
+		for r := 0; r < 9; r++ {
+			for c := 0; c < 9; c++ {
+				if b[r][c] != 0 {
+					continue
+				}
+				for _, n := range opts.Notes.Candidates(r, c) {
+					x, y := m+c*cs+(n-1)%3*cs/3, m+r*cs+(n-1)/3*cs/3
+					drawCentered(img, fmt.Sprint(n), image.Rect(x, y, x+cs/3, y+cs/3), max(1, cs/48), mutedInk)
+				}
+			}
+		}
+	}
+	return img, nil
+}
+
+// drawBoard draws g at at with cells of side cs; digits scale with cs. Thick
+// lines follow the boxes, or the regions of a jigsaw grid.
+func drawBoard(img *image.RGBA, g, givens sudoku.Grid, at image.Point, cs int) {
+	n := g.Size
+	for i := 0; i <= n; i++ {
+		fill(img, image.Rect(at.X+i*cs, at.Y, at.X+i*cs+1, at.Y+n*cs+1), thinLine)
+		fill(img, image.Rect(at.X, at.Y+i*cs, at.X+n*cs+1, at.Y+i*cs+1), thinLine)
+	}
+	region := regionOf(g)
+	for r := 0; r < n; r++ {
+		for c := 0; c <= n; c++ {
+			// the left edge of r,c and the top edge of c,r
+			if c == 0 || c == n || region(r, c-1) != region(r, c) {
+				fill(img, image.Rect(at.X+c*cs-1, at.Y+r*cs-1, at.X+c*cs+2, at.Y+(r+1)*cs+2), boxLine)
+			}
+			if c == 0 || c == n || region(c-1, r) != region(c, r) {
+				fill(img, image.Rect(at.X+r*cs-1, at.Y+c*cs-1, at.X+(r+1)*cs+2, at.Y+c*cs+2), boxLine)
+			}
+		}
+	}
+	for r := 0; r < n; r++ {
+		for c := 0; c < n; c++ {
+			v := g.Cells[r][c]
+			if v <= 0 || v >= len(sudoku.GridAlphabet) {
+				continue
+			}
+			ink := givenInk
+			if givens.Cells != nil && givens.Cells[r][c] == 0 {
+				ink = playerInk
+			}
+			cell := image.Rect(at.X+c*cs, at.Y+r*cs, at.X+(c+1)*cs, at.Y+(r+1)*cs)
+			drawCentered(img, sudoku.GridAlphabet[v:v+1], cell, max(1, cs/16), ink)
+		}
+	}
+}
+
+// regionOf returns the region id of each cell of g: its jigsaw region, its
+// box, or 0 for all when g has neither.
+func regionOf(g sudoku.Grid) func(r, c int) int {
+	switch {
+	case g.Regions != nil:
+		return func(r, c int) int { return g.Regions[r][c] }
+	case g.BoxRows > 0 && g.BoxCols > 0:
+		return func(r, c int) int { return r/g.BoxRows*g.Size + c/g.BoxCols }
+	}
+	return func(r, c int) int { return 0 }
+}
+
+// Recap is the summary of a finished game shown in a shareable image.
+type Recap struct {
+	Final      sudoku.Grid // completed board
+	Givens     sudoku.Grid // original clues; zero to draw every value as a clue
+	Elapsed    time.Duration
+	Difficulty sudoku.Difficulty
+	Mistakes   int
+	Date       time.Time
+}
+
+// recapWidth keeps the text lines readable under small boards.
+const recapWidth = 9*CellSize + 2*Margin
+
+// Image draws the recap: a title with the date, the final board and a line with
+// difficulty, time and mistakes.
+func (r Recap) Image() *image.RGBA {
+	board := r.Final.Size * CellSize
+	width := max(recapWidth, board+2*Margin)
+	title, stats := 40, 48
+	img := image.NewRGBA(image.Rect(0, 0, width, title+board+stats+2*Margin))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	drawCentered(img, "Sudoku "+r.Date.Format(time.DateOnly), image.Rect(0, Margin, width, Margin+title), 2, givenInk)
+	drawBoard(img, r.Final, r.Givens, image.Pt((width-board)/2, Margin+title), CellSize)
+	top := Margin + title + board
+	drawCentered(img, r.statsLine(), image.Rect(0, top, width, top+stats), 2, mutedInk)
+	return img
+}
+
+// Summary is a one-line text form of the recap, for chats and the clipboard.
+func (r Recap) Summary() string {
+	return fmt.Sprintf("Sudoku %s: solved %s", r.Date.Format(time.DateOnly), r.statsLine())
+}
+
+func (r Recap) statsLine() string {
+	d := r.Elapsed.Round(time.Second)
+	s := fmt.Sprintf("%s in %02d:%02d", r.Difficulty, int(d.Minutes()), int(d.Seconds())%60)
+	if r.Difficulty == "" {
+		s = fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	switch r.Mistakes {
+	case 0:
+		return s + ", no mistakes"
+	case 1:
+		return s + ", 1 mistake"
+	}
+	return fmt.Sprintf("%s, %d mistakes", s, r.Mistakes)
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// drawCentered draws s centred in box, magnified scale times.
+func drawCentered(img *image.RGBA, s string, box image.Rectangle, scale int, c color.Color) {
+	face := basicfont.Face7x13
+	w := font.MeasureString(face, s).Ceil()
+	h := face.Metrics().Height.Ceil()
+	if w == 0 {
+		return
+	}
+	glyphs := image.NewRGBA(image.Rect(0, 0, w, h))
+	d := font.Drawer{Dst: glyphs, Src: image.NewUniform(c), Face: face, Dot: fixed.P(0, face.Metrics().Ascent.Ceil())}
+	d.DrawString(s)
+	sw, sh := w*scale, h*scale
+	x := box.Min.X + (box.Dx()-sw)/2
+	y := box.Min.Y + (box.Dy()-sh)/2
+	xdraw.NearestNeighbor.Scale(img, image.Rect(x, y, x+sw, y+sh), glyphs, glyphs.Bounds(), xdraw.Over, nil)
+}
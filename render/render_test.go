@@ -0,0 +1,114 @@
+package render
+
+import (
+	"image/color"
+	"testing"
+	"time"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+func TestGridImage(t *testing.T) {
+	g, _ := sudoku.FromStringN("2040012004000234", 4, 2, 2)
+	img := Grid(g, sudoku.Grid{})
+	if side := 4*CellSize + 2*Margin; img.Bounds().Dx() != side || img.Bounds().Dy() != side {
+		t.Fatalf("bounds %v, want %dx%d", img.Bounds(), side, side)
+	}
+	if got := img.At(Margin, Margin+CellSize/2); got != color.RGBA(boxLine) {
+		t.Fatalf("outer border pixel = %v", got)
+	}
+	if got := img.At(1, 1); got != color.RGBA(background) {
+		t.Fatalf("margin pixel = %v", got)
+	}
+}
+
+func TestGridImageJigsaw(t *testing.T) {
+	g, err := sudoku.NewJigsawGrid([][]int{{0, 0, 0, 1}, {0, 2, 1, 1}, {2, 2, 3, 1}, {2, 3, 3, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Cells[0][0] = 1
+	img := Grid(g, sudoku.Grid{})
+	if got := img.At(Margin+3*CellSize, Margin+CellSize/2); got != color.RGBA(boxLine) {
+		t.Fatalf("region border pixel = %v", got)
+	}
+	if got := img.At(Margin+2*CellSize, Margin+CellSize/2); got != color.RGBA(thinLine) {
+		t.Fatalf("pixel inside a region = %v", got)
+	}
+	Recap{Final: g}.Image() // must not panic either
+}
+
+func TestRecap(t *testing.T) {
+	final, _ := sudoku.FromString(sudokutest.EasySolution)
+	givens, _ := sudoku.FromString(sudokutest.Easy)
+	toGrid := func(b sudoku.Board) sudoku.Grid {
+		g, _ := sudoku.NewGrid(9, 3, 3)
+		for r := range b {
+			copy(g.Cells[r], b[r][:])
+		}
+		return g
+	}
+	r := Recap{Final: toGrid(final), Givens: toGrid(givens), Elapsed: 312 * time.Second,
+		Difficulty: sudoku.Medium, Mistakes: 1, Date: time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)}
+	if got, want := r.Summary(), "Sudoku 2026-10-15: solved medium in 05:12, 1 mistake"; got != want {
+		t.Fatalf("summary %q, want %q", got, want)
+	}
+	img := r.Image()
+	if img.Bounds().Dx() != recapWidth || img.Bounds().Dy() <= recapWidth {
+		t.Fatalf("recap bounds %v", img.Bounds())
+	}
+	// player entries use a different ink than givens
+	var player, given bool
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			switch img.At(x, y) {
+			case color.RGBA(playerInk):
+				player = true
+			case color.RGBA(givenInk):
+				given = true
+			}
+		}
+	}
+	if !player || !given {
+		t.Fatalf("recap missing inks: player=%v given=%v", player, given)
+	}
+}
+
+func TestImageOptions(t *testing.T) {
+	givens, _ := sudoku.FromString(sudokutest.Easy)
+	b := givens
+	b[0][2] = 4 // player entry
+	notes := sudoku.CandidateNotes(b)
+	img, err := Image(b, RenderOptions{Givens: givens, Notes: &notes, CellSize: 64, Margin: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if side := 9*64 + 16; img.Bounds().Dx() != side || img.Bounds().Dy() != side {
+		t.Fatalf("bounds %v, want %dx%d", img.Bounds(), side, side)
+	}
+	count := func(c color.RGBA) int {
+		n := 0
+		for y := 0; y < img.Bounds().Dy(); y++ {
+			for x := 0; x < img.Bounds().Dx(); x++ {
+				if img.At(x, y) == c {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	if count(color.RGBA(playerInk)) == 0 || count(color.RGBA(mutedInk)) == 0 {
+		t.Fatal("expected the entry in player ink and pencil marks in muted ink")
+	}
+
+	plain, _ := Image(givens, RenderOptions{})
+	if plain.Bounds().Dx() != 9*CellSize+2*Margin {
+		t.Fatalf("default bounds %v", plain.Bounds())
+	}
+	b[4][4] = 10
+	if _, err := Image(b, RenderOptions{}); err == nil {
+		t.Fatal("expected range error")
+	}
+}
@@ -0,0 +1,99 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+
+	"go.rumenx.com/sudoku"
+)
+
+// RenderOptions tune SVG and Image.
+type RenderOptions struct {
+	// Givens marks the clues: cells non-zero here are drawn as givens and the
+	// other values of the board as player entries. A zero Board draws every
+	// value as a given.
+	Givens sudoku.Board
+	// Notes, when set, draws pencil marks in the empty cells.
+	Notes *sudoku.Notes
+	// CellSize and Margin are in SVG user units; zero uses the package defaults.
+	CellSize, Margin int
+}
+
+// SVG draws b as a scalable vector image with thin cell lines, heavy box
+// borders, givens in dark ink and entries in blue. It fails for values outside
+// 0..9 and for givens the board does not keep.
+func SVG(b sudoku.Board, opts RenderOptions) ([]byte, error) {
+	cs, m := opts.CellSize, opts.Margin
+	if cs <= 0 {
+		cs = CellSize
+	}
+	if m <= 0 {
+		m = Margin
+	}
+	var noGivens sudoku.Board
+	if err := checkBoard(b, opts.Givens); err != nil {
+		return nil, err
+	}
+	side := 9*cs + 2*m
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", side, side, side, side)
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hex(background))
+	fmt.Fprintf(&buf, `<g font-family="Helvetica, Arial, sans-serif" text-anchor="middle" dominant-baseline="central">`+"\n")
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			x, y := m+c*cs, m+r*cs
+			switch v := b[r][c]; {
+			case v != 0:
+				ink, weight := givenInk, "bold"
+				if opts.Givens != noGivens && opts.Givens[r][c] == 0 {
+					ink, weight = playerInk, "normal"
+				}
+				fmt.Fprintf(&buf, `<text x="%d" y="%d" font-size="%d" font-weight="%s" fill="%s">%d</text>`+"\n",
+					x+cs/2, y+cs/2, cs*3/5, weight, hex(ink), v)
+			case opts.Notes != nil:
+				for _, n := range opts.Notes.Candidates(r, c) {
+					nx, ny := x+((n-1)%3*2+1)*cs/6, y+((n-1)/3*2+1)*cs/6
+					fmt.Fprintf(&buf, `<text x="%d" y="%d" font-size="%d" fill="%s">%d</text>`+"\n", nx, ny, cs/4, hex(mutedInk), n)
+				}
+			}
+		}
+	}
+	buf.WriteString("</g>\n")
+	// thin lines first so the box borders are drawn over them
+	for _, box := range []bool{false, true} {
+		col, w := thinLine, 1
+		if box {
+			col, w = boxLine, 3
+		}
+		fmt.Fprintf(&buf, `<g stroke="%s" stroke-width="%d" stroke-linecap="square">`+"\n", hex(col), w)
+		for i := 0; i <= 9; i++ {
+			if (i%3 == 0) != box {
+				continue
+			}
+			p := m + i*cs
+			fmt.Fprintf(&buf, `<line x1="%d" y1="%d" x2="%d" y2="%d"/>`+"\n", p, m, p, m+9*cs)
+			fmt.Fprintf(&buf, `<line x1="%d" y1="%d" x2="%d" y2="%d"/>`+"\n", m, p, m+9*cs, p)
+		}
+		buf.WriteString("</g>\n")
+	}
+	buf.WriteString("</svg>\n")
+	return buf.Bytes(), nil
+}
+
+// checkBoard rejects values outside 0..9 and givens b does not keep.
+func checkBoard(b, givens sudoku.Board) error {
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if v := b[r][c]; v < 0 || v > 9 {
+				return fmt.Errorf("r%dc%d: value %d out of range", r+1, c+1, v)
+			}
+			if g := givens[r][c]; g != 0 && g != b[r][c] {
+				return fmt.Errorf("r%dc%d: given %d is %d on the board", r+1, c+1, g, b[r][c])
+			}
+		}
+	}
+	return nil
+}
+
+func hex(c color.NRGBA) string { return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B) }
@@ -0,0 +1,114 @@
+package render
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/sudoku"
+	"go.rumenx.com/sudoku/sudokutest"
+)
+
+// svgDoc is the subset of the SVG output the tests inspect.
+type svgDoc struct {
+	Width  int `xml:"width,attr"`
+	Groups []struct {
+		Stroke string     `xml:"stroke,attr"`
+		Lines  []struct{} `xml:"line"`
+		Texts  []struct {
+			Fill   string `xml:"fill,attr"`
+			Weight string `xml:"font-weight,attr"`
+			Size   int    `xml:"font-size,attr"`
+			Value  string `xml:",chardata"`
+		} `xml:"text"`
+	} `xml:"g"`
+}
+
+func parseSVG(t *testing.T, data []byte) svgDoc {
+	t.Helper()
+	var doc svgDoc
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid SVG: %v\n%s", err, data)
+	}
+	return doc
+}
+
+func TestSVG(t *testing.T) {
+	givens, _ := sudoku.FromString(sudokutest.Easy)
+	b := givens
+	b[0][2] = 4 // player entry
+	data, err := SVG(b, RenderOptions{Givens: givens})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := parseSVG(t, data)
+	if doc.Width != 9*CellSize+2*Margin || len(doc.Groups) != 3 {
+		t.Fatalf("unexpected layout: width %d, %d groups", doc.Width, len(doc.Groups))
+	}
+	texts := doc.Groups[0].Texts
+	if len(texts) != 31 {
+		t.Fatalf("%d values drawn, want 31", len(texts))
+	}
+	entries := 0
+	for _, tx := range texts {
+		if tx.Fill == hex(playerInk) {
+			entries++
+			if tx.Value != "4" || tx.Weight != "normal" {
+				t.Fatalf("entry drawn as %+v", tx)
+			}
+		}
+	}
+	if entries != 1 {
+		t.Fatalf("%d entries styled as player ink, want 1", entries)
+	}
+	// 6 thin and 4 heavy lines in each direction
+	if thin, heavy := len(doc.Groups[1].Lines), len(doc.Groups[2].Lines); thin != 12 || heavy != 8 || doc.Groups[2].Stroke != hex(boxLine) {
+		t.Fatalf("lines: %d thin, %d heavy", thin, heavy)
+	}
+}
+
+func TestSVGNotesAndOptions(t *testing.T) {
+	b, _ := sudoku.FromString(sudokutest.Easy)
+	notes := sudoku.CandidateNotes(b)
+	data, err := SVG(b, RenderOptions{Notes: &notes, CellSize: 60, Margin: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := parseSVG(t, data)
+	if doc.Width != 9*60+20 {
+		t.Fatalf("width %d", doc.Width)
+	}
+	marks := 0
+	for _, tx := range doc.Groups[0].Texts {
+		if tx.Size == 15 {
+			marks++
+		} else if tx.Fill != hex(givenInk) {
+			t.Fatalf("without Givens every value is a given, got %+v", tx)
+		}
+	}
+	want := 0
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if b[r][c] == 0 {
+				want += len(notes.Candidates(r, c))
+			}
+		}
+	}
+	if marks != want {
+		t.Fatalf("%d pencil marks, want %d", marks, want)
+	}
+}
+
+func TestSVGErrors(t *testing.T) {
+	var b sudoku.Board
+	b[4][4] = 10
+	if _, err := SVG(b, RenderOptions{}); err == nil || !strings.Contains(err.Error(), "r5c5") {
+		t.Fatalf("expected range error, got %v", err)
+	}
+	b[4][4] = 0
+	var givens sudoku.Board
+	givens[0][0] = 5
+	if _, err := SVG(b, RenderOptions{Givens: givens}); err == nil {
+		t.Fatal("expected error for a given missing from the board")
+	}
+}
@@ -0,0 +1,293 @@
+package sudoku
+
+import (
+	"errors"
+	"math/rand/v2"
+)
+
+// Samurai is a samurai sudoku: five 9x9 boards laid out in a 21x21 square where
+// the centre board shares each of its corner boxes with one outer board. Boards
+// are ordered top-left, top-right, centre, bottom-left, bottom-right, and cells
+// in shared boxes must hold the same value in both boards.
+type Samurai [5]Board
+
+// samuraiOffsets are the top-left positions of each board in the 21x21 layout.
+var samuraiOffsets = [5][2]int{{0, 0}, {0, 12}, {6, 6}, {12, 0}, {12, 12}}
+
+const samuraiSpan = 21
+
+// samuraiNodeBudget bounds one randomized fill attempt before restarting.
+const samuraiNodeBudget = 50000
+
+// Validate checks every board and that shared boxes agree.
+func (s Samurai) Validate() error {
+	for _, b := range s {
+		if err := Validate(b); err != nil {
+			return err
+		}
+	}
+	if _, ok := s.merge(); !ok {
+		return ErrInvalidBoard
+	}
+	return nil
+}
+
+// merge projects the boards onto the 21x21 layout; ok is false if shared cells differ.
+func (s Samurai) merge() (cells [samuraiSpan][samuraiSpan]int, ok bool) {
+	var set [samuraiSpan][samuraiSpan]bool
+	for k, off := range samuraiOffsets {
+		for r := 0; r < 9; r++ {
+			for c := 0; c < 9; c++ {
+				R, C := off[0]+r, off[1]+c
+				v := s[k][r][c]
+				if set[R][C] && cells[R][C] != v {
+					return cells, false
+				}
+				cells[R][C], set[R][C] = v, true
+			}
+		}
+	}
+	return cells, true
+}
+
+func splitSamurai(cells *[samuraiSpan][samuraiSpan]int) Samurai {
+	var s Samurai
+	for k, off := range samuraiOffsets {
+		for r := 0; r < 9; r++ {
+			for c := 0; c < 9; c++ {
+				s[k][r][c] = cells[off[0]+r][off[1]+c]
+			}
+		}
+	}
+	return s
+}
+
+// Solve solves all five boards together, propagating through shared boxes.
+func (s Samurai) Solve() (Samurai, bool) {
+	if err := s.Validate(); err != nil {
+		return Samurai{}, false
+	}
+	cells, _ := s.merge()
+	ss := newSamuraiSolver(cells)
+	var out Samurai
+	found := ss.search(func(c *[samuraiSpan][samuraiSpan]int) bool {
+		out = splitSamurai(c)
+		return true
+	})
+	return out, found
+}
+
+// CountSolutions returns the number of solutions of s, stopping once limit are
+// found. Invalid puzzles have none.
+func (s Samurai) CountSolutions(limit int) int {
+	if s.Validate() != nil {
+		return 0
+	}
+	cells, _ := s.merge()
+	return newSamuraiSolver(cells).count(limit)
+}
+
+// GenerateSamurai creates a samurai puzzle with a unique solution. Each board
+// gets roughly the clue count of a classic puzzle of difficulty d.
+// Concurrent calls are safe, as for Generate.
+func GenerateSamurai(d Difficulty, attempts int) (Samurai, error) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return defaultGenerator().GenerateSamurai(d, attempts)
+}
+
+// GenerateSamurai creates a samurai puzzle with a unique solution (see GenerateSamurai).
+func (gen *Generator) GenerateSamurai(d Difficulty, attempts int) (Samurai, error) {
+	return generateSamurai(gen.rng, d, attempts)
+}
+
+func generateSamurai(rng *rand.Rand, d Difficulty, attempts int) (Samurai, error) {
+	if attempts < 1 {
+		attempts = 1
+	}
+	var lastErr error
+	for try := 0; try < attempts; try++ {
+		var solved [samuraiSpan][samuraiSpan]int
+		filled := false
+		for restart := 0; restart < 50 && !filled; restart++ {
+			ss := newSamuraiSolver(solved)
+			ss.rng, ss.maxNodes = rng, samuraiNodeBudget
+			ss.search(func(c *[samuraiSpan][samuraiSpan]int) bool {
+				solved, filled = *c, true
+				return true
+			})
+		}
+		if !filled {
+			lastErr = errors.New("failed to build solved samurai")
+			continue
+		}
+		active := samuraiActive()
+		target := len(active) * cluesFor(d) / 81
+		puzzle := solved
+		clues := len(active)
+		for _, i := range rng.Perm(len(active)) {
+			if clues <= target {
+				break
+			}
+			R, C := active[i]/samuraiSpan, active[i]%samuraiSpan
+			old := puzzle[R][C]
+			puzzle[R][C] = 0
+			if newSamuraiSolver(puzzle).count(2) != 1 {
+				puzzle[R][C] = old
+				continue
+			}
+			clues--
+		}
+		return splitSamurai(&puzzle), nil
+	}
+	if lastErr == nil {
+		lastErr = errors.New("generation failed")
+	}
+	return Samurai{}, lastErr
+}
+
+// samuraiActive lists the indices (R*21+C) of cells covered by some board.
+func samuraiActive() []int {
+	var covered [samuraiSpan * samuraiSpan]bool
+	for _, off := range samuraiOffsets {
+		for r := 0; r < 9; r++ {
+			for c := 0; c < 9; c++ {
+				covered[(off[0]+r)*samuraiSpan+off[1]+c] = true
+			}
+		}
+	}
+	var out []int
+	for i, ok := range covered {
+		if ok {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// samuraiSolver is a most-constrained-cell search over the 21x21 layout where
+// every cell belongs to the rows, columns and boxes of each board covering it.
+type samuraiSolver struct {
+	cells           [samuraiSpan][samuraiSpan]int
+	units           [][]int  // unit -> cell indices
+	cellUnits       [][]int  // cell index -> units
+	used            []uint16 // unit -> value bitmask
+	active          []int    // covered cell indices
+	nodes, maxNodes int
+	rng             *rand.Rand
+}
+
+func newSamuraiSolver(cells [samuraiSpan][samuraiSpan]int) *samuraiSolver {
+	ss := &samuraiSolver{cells: cells, cellUnits: make([][]int, samuraiSpan*samuraiSpan), active: samuraiActive()}
+	seen := map[[2]int]bool{} // shared boxes appear in two boards; keep one copy
+	for _, off := range samuraiOffsets {
+		for i := 0; i < 9; i++ {
+			var row, col, box []int
+			for j := 0; j < 9; j++ {
+				row = append(row, (off[0]+i)*samuraiSpan+off[1]+j)
+				col = append(col, (off[0]+j)*samuraiSpan+off[1]+i)
+				box = append(box, (off[0]+(i/3)*3+j/3)*samuraiSpan+off[1]+(i%3)*3+j%3)
+			}
+			ss.addUnit(row)
+			ss.addUnit(col)
+			if key := [2]int{off[0] + (i/3)*3, off[1] + (i%3)*3}; !seen[key] {
+				seen[key] = true
+				ss.addUnit(box)
+			}
+		}
+	}
+	ss.used = make([]uint16, len(ss.units))
+	for _, i := range ss.active {
+		if v := ss.cells[i/samuraiSpan][i%samuraiSpan]; v != 0 {
+			for _, u := range ss.cellUnits[i] {
+				ss.used[u] |= 1 << v
+			}
+		}
+	}
+	return ss
+}
+
+func (ss *samuraiSolver) addUnit(cells []int) {
+	u := len(ss.units)
+	ss.units = append(ss.units, cells)
+	for _, i := range cells {
+		ss.cellUnits[i] = append(ss.cellUnits[i], u)
+	}
+}
+
+func (ss *samuraiSolver) candidates(i int) uint16 {
+	var used uint16
+	for _, u := range ss.cellUnits[i] {
+		used |= ss.used[u]
+	}
+	return ^used & 0x3fe
+}
+
+func (ss *samuraiSolver) set(i, v int) {
+	ss.cells[i/samuraiSpan][i%samuraiSpan] = v
+	for _, u := range ss.cellUnits[i] {
+		ss.used[u] |= 1 << v
+	}
+}
+
+func (ss *samuraiSolver) unset(i, v int) {
+	ss.cells[i/samuraiSpan][i%samuraiSpan] = 0
+	for _, u := range ss.cellUnits[i] {
+		ss.used[u] &^= 1 << v
+	}
+}
+
+// search visits solutions until found returns true or the node budget runs out.
+func (ss *samuraiSolver) search(found func(*[samuraiSpan][samuraiSpan]int) bool) bool {
+	ss.nodes++
+	if ss.maxNodes > 0 && ss.nodes > ss.maxNodes {
+		return true
+	}
+	best, bestMask, bestCount := -1, uint16(0), 10
+	for _, i := range ss.active {
+		if ss.cells[i/samuraiSpan][i%samuraiSpan] != 0 {
+			continue
+		}
+		m := ss.candidates(i)
+		n := 0
+		for x := m; x != 0; x &= x - 1 {
+			n++
+		}
+		if n < bestCount {
+			best, bestMask, bestCount = i, m, n
+			if n <= 1 {
+				break
+			}
+		}
+	}
+	if best < 0 {
+		return found(&ss.cells)
+	}
+	vals := make([]int, 0, 9)
+	for v := 1; v <= 9; v++ {
+		if bestMask&(1<<v) != 0 {
+			vals = append(vals, v)
+		}
+	}
+	if ss.rng != nil {
+		ss.rng.Shuffle(len(vals), func(i, j int) { vals[i], vals[j] = vals[j], vals[i] })
+	}
+	for _, v := range vals {
+		ss.set(best, v)
+		if ss.search(found) {
+			return true
+		}
+		ss.unset(best, v)
+	}
+	return false
+}
+
+// count returns the number of solutions, stopping at limit.
+func (ss *samuraiSolver) count(limit int) int {
+	n := 0
+	ss.search(func(*[samuraiSpan][samuraiSpan]int) bool {
+		n++
+		return n >= limit
+	})
+	return n
+}
@@ -0,0 +1,45 @@
+package sudoku
+
+import "testing"
+
+func TestSamuraiGenerateSolve(t *testing.T) {
+	s, err := NewGenerator(5).GenerateSamurai(Hard, 2)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("generated invalid samurai: %v", err)
+	}
+	if n := s.CountSolutions(2); n != 1 {
+		t.Fatalf("expected unique solution, got %d", n)
+	}
+	sol, ok := s.Solve()
+	if !ok {
+		t.Fatalf("solve failed")
+	}
+	for k, b := range sol {
+		if err := Validate(b); err != nil || countClues(b) != 81 {
+			t.Fatalf("board %d not solved: %v", k, err)
+		}
+	}
+	// shared corner boxes agree: top-left board's bottom-right box is the centre's top-left box
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			if sol[0][6+r][6+c] != sol[2][r][c] || sol[4][r][c] != sol[2][6+r][6+c] {
+				t.Fatalf("shared box mismatch at %d,%d", r, c)
+			}
+		}
+	}
+}
+
+func TestSamuraiValidateSharedMismatch(t *testing.T) {
+	var s Samurai
+	s[0][6][6] = 1 // shared with centre (0,0)
+	s[2][0][0] = 2
+	if err := s.Validate(); err == nil {
+		t.Fatalf("expected mismatch between shared cells to be invalid")
+	}
+	if _, ok := s.Solve(); ok {
+		t.Fatalf("expected inconsistent samurai to be unsolvable")
+	}
+}
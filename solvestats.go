@@ -0,0 +1,75 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrUnsolvable is returned by SolveContext for valid boards without a solution.
+var ErrUnsolvable = errors.New("puzzle has no solution")
+
+// SolveStats describes a search, finished or not. Deduction passes are not
+// search nodes, so puzzles solved by singles alone report zero nodes.
+type SolveStats struct {
+	Nodes      int           `json:"nodes"`      // search nodes explored
+	Backtracks int           `json:"backtracks"` // guesses undone
+	MaxDepth   int           `json:"maxDepth"`   // most guesses open at once
+	Elapsed    time.Duration `json:"elapsed"`    // wall time, deduction included
+	Best       Board         `json:"best"`       // fullest board reached: givens, deductions and guesses
+	BestFilled int           `json:"bestFilled"` // filled cells in Best
+}
+
+// SolveWithStats solves b with the deterministic solver behind SolveContext and
+// reports how hard it had to work, for benchmarking, rating heuristics or
+// finding out why a puzzle is slow. ok is false for invalid and unsolvable
+// boards; the stats still cover the work done.
+func SolveWithStats(b Board) (Board, SolveStats, bool) {
+	sol, st, err := SolveContext(context.Background(), b)
+	return sol, st, err == nil
+}
+
+// SolveContext solves b like SolveBestEffort, checking ctx between deduction
+// passes and every 1024 search nodes. When ctx ends first it returns
+// ctx's error together with the statistics gathered so far, so callers can
+// report "gave up after N nodes, M cells filled" rather than nothing. Invalid
+// boards return ErrInvalidBoard and contradictions ErrUnsolvable; the stats
+// are filled in either way.
+func SolveContext(ctx context.Context, b Board) (sol Board, st SolveStats, err error) {
+	start := time.Now()
+	defer func() { st.Elapsed = time.Since(start) }()
+	st = SolveStats{Best: b, BestFilled: countClues(b)}
+	if err := Validate(b); err != nil {
+		return Board{}, st, err
+	}
+	work := b
+	for {
+		if err := ctx.Err(); err != nil {
+			st.Best, st.BestFilled = work, countClues(work)
+			return Board{}, st, err
+		}
+		progress, ok := applySingles(&work)
+		if !ok {
+			return Board{}, st, ErrUnsolvable
+		}
+		st.Best, st.BestFilled = work, countClues(work)
+		if !progress {
+			break
+		}
+	}
+	if st.BestFilled == 81 {
+		return work, st, nil
+	}
+	s := &ctxSearch{ctx: ctx, filled: st.BestFilled, best: st.Best, bestFilled: st.BestFilled}
+	solved := s.solve(&work)
+	st.Nodes, st.Backtracks, st.MaxDepth = s.nodes, s.backtracks, s.maxDepth
+	st.Best, st.BestFilled = s.best, s.bestFilled
+	switch {
+	case solved:
+		st.Best, st.BestFilled = work, 81
+		return work, st, nil
+	case s.stopped:
+		return Board{}, st, ctx.Err()
+	}
+	return Board{}, st, ErrUnsolvable
+}
@@ -0,0 +1,87 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// inkala needs a few thousand search nodes once singles stall.
+const inkala = "800000000003600000070090200050007000000045700000100030001000068008500010090000400"
+
+// countdownCtx reports cancellation after its first n Err calls.
+type countdownCtx struct {
+	context.Context
+	n int
+}
+
+func (c *countdownCtx) Err() error {
+	if c.n--; c.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestSolveContext(t *testing.T) {
+	b, _ := FromString(inkala)
+	sol, st, err := SolveContext(context.Background(), b)
+	if err != nil || !IsComplete(sol) || Validate(sol) != nil {
+		t.Fatalf("solve: %v", err)
+	}
+	if st.Nodes <= bestEffortCheckEvery || st.BestFilled != 81 || st.Best != sol {
+		t.Fatalf("stats %d nodes, %d filled", st.Nodes, st.BestFilled)
+	}
+
+	// cancelled during the search: partial stats, no solution
+	ctx := &countdownCtx{Context: context.Background(), n: 3}
+	got, part, err := SolveContext(ctx, b)
+	if !errors.Is(err, context.Canceled) || got != (Board{}) {
+		t.Fatalf("want cancellation, got %v", err)
+	}
+	if part.Nodes == 0 || part.Nodes >= st.Nodes || part.BestFilled <= countClues(b) || part.BestFilled >= 81 {
+		t.Fatalf("partial stats %d nodes, %d filled", part.Nodes, part.BestFilled)
+	}
+	if Validate(part.Best) != nil || countClues(part.Best) != part.BestFilled {
+		t.Fatalf("best partial board inconsistent")
+	}
+}
+
+func TestSolveContextErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b, _ := FromString(inkala)
+	if _, st, err := SolveContext(ctx, b); !errors.Is(err, context.Canceled) || st.Nodes != 0 || st.BestFilled != 21 {
+		t.Fatalf("pre-cancelled: %v %+v", err, st)
+	}
+	bad := b
+	bad[0][1] = 8
+	if _, _, err := SolveContext(context.Background(), bad); !errors.Is(err, ErrInvalidBoard) {
+		t.Fatalf("invalid board: %v", err)
+	}
+	dead, _ := FromString("123456780000000009" + "000000000000000000000000000000000000000000000000000000000000000")
+	if _, _, err := SolveContext(context.Background(), dead); !errors.Is(err, ErrUnsolvable) {
+		t.Fatalf("unsolvable board: %v", err)
+	}
+}
+
+func TestSolveWithStats(t *testing.T) {
+	b, _ := FromString(inkala)
+	sol, st, ok := SolveWithStats(b)
+	if !ok || Validate(sol) != nil || !IsComplete(sol) {
+		t.Fatalf("not solved")
+	}
+	if st.Nodes == 0 || st.Backtracks == 0 || st.MaxDepth == 0 || st.Elapsed <= 0 {
+		t.Fatalf("stats %+v", st)
+	}
+	// the search places one value per level, so depth is bounded by the open cells
+	if st.MaxDepth > 81-21 || st.Backtracks >= st.Nodes {
+		t.Fatalf("inconsistent stats %+v", st)
+	}
+	easy, _ := FromString(easyPuzzle)
+	if _, st, ok := SolveWithStats(easy); !ok || st.Nodes != 0 || st.MaxDepth != 0 {
+		t.Fatalf("singles-only puzzle searched: %+v", st)
+	}
+	if _, _, ok := SolveWithStats(Board{{1, 1}}); ok {
+		t.Fatalf("invalid board solved")
+	}
+}
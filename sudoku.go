@@ -20,85 +20,67 @@ const (
 	Hard   Difficulty = "hard"
 )
 
-var (
-	// ErrInvalidBoard is returned when a board violates Sudoku rules.
-	ErrInvalidBoard = errors.New("invalid board")
-	// globalRand is the random source used by generator; overridden via SetRandSeed.
-	globalRand = rand.New(rand.NewPCG(uint64(rand.Uint32()), uint64(rand.Uint32())))
-)
+// ErrInvalidBoard is returned when a board violates Sudoku rules. Validate wraps
+// it in a *ConflictError or *RangeError that says where; test with errors.Is.
+var ErrInvalidBoard = errors.New("invalid board")
 
-// SetRandSeed sets the seed for the library's random generator ensuring reproducible generation.
-// Safe for tests; not concurrency guarded (call during init).
-func SetRandSeed(seed uint64) { globalRand = rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)) }
+// SetRandSeed reseeds the default Generator behind the package-level functions,
+// ensuring reproducible generation. Generation only draws from this PCG source in a
+// fixed order (no map iteration or platform-sized arithmetic), so a seed yields the
+// same puzzles on every OS/architecture. Reproducibility assumes no other goroutine
+// uses the package-level functions meanwhile; use NewGenerator for isolation.
+func SetRandSeed(seed uint64) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultGen = NewGenerator(seed)
+}
 
 // Validate checks that values are in [0,9] and no row/col/box duplicates (ignoring zeros).
+// Failures are a *RangeError or a *ConflictError naming the offending cells; both
+// wrap ErrInvalidBoard. Rows are checked before columns, columns before boxes.
 func Validate(b Board) error {
-	// rows and cols
-	for i := 0; i < 9; i++ {
-		var row, col [10]bool
-		for j := 0; j < 9; j++ {
-			rv := b[i][j]
-			cv := b[j][i]
-			if rv < 0 || rv > 9 || cv < 0 || cv > 9 {
-				return ErrInvalidBoard
-			}
-			if rv != 0 {
-				if row[rv] {
-					return ErrInvalidBoard
-				}
-				row[rv] = true
-			}
-			if cv != 0 {
-				if col[cv] {
-					return ErrInvalidBoard
-				}
-				col[cv] = true
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if v := b[r][c]; v < 0 || v > 9 {
+				return &RangeError{Cell{r, c}, v}
 			}
 		}
 	}
-	// 3x3 boxes
-	for br := 0; br < 9; br += 3 {
-		for bc := 0; bc < 9; bc += 3 {
-			var seen [10]bool
-			for r := br; r < br+3; r++ {
-				for c := bc; c < bc+3; c++ {
-					v := b[r][c]
-					if v != 0 {
-						if seen[v] {
-							return ErrInvalidBoard
-						}
-						seen[v] = true
-					}
-				}
-			}
+	var first [1]Conflict
+	for u := 0; u < 27; u++ {
+		if cs := appendBoardConflicts(first[:0], &b, u, false); len(cs) > 0 {
+			return &ConflictError{cs[0]}
 		}
 	}
 	return nil
 }
 
 // Solve tries to solve the board using backtracking. Returns solved board and ok.
-func Solve(b Board) (Board, bool) {
+// It is safe for concurrent use.
+func Solve(b Board) (Board, bool) { return solveBoard(b, forkDefaultRand()) }
+
+func solveBoard(b Board, rng *rand.Rand) (Board, bool) {
 	var solved Board
 	copyBoard(&solved, &b)
-	if !backtrack(&solved) {
+	if !backtrack(&solved, rng) {
 		return Board{}, false
 	}
 	return solved, true
 }
 
 // backtrack fills empty cells; standard DFS.
-func backtrack(b *Board) bool {
+func backtrack(b *Board, rng *rand.Rand) bool {
 	r, c, ok := findEmpty(b)
 	if !ok {
 		return true
 	}
 	// try 1..9 shuffled for some variety
 	vals := [9]int{1, 2, 3, 4, 5, 6, 7, 8, 9}
-	globalRand.Shuffle(9, func(i, j int) { vals[i], vals[j] = vals[j], vals[i] })
+	rng.Shuffle(9, func(i, j int) { vals[i], vals[j] = vals[j], vals[i] })
 	for _, v := range vals {
 		if isSafe(*b, r, c, v) {
 			b[r][c] = v
-			if backtrack(b) {
+			if backtrack(b, rng) {
 				return true
 			}
 			b[r][c] = 0
@@ -144,36 +126,74 @@ func copyBoard(dst, src *Board) {
 
 // Generate creates a Sudoku puzzle with a unique solution.
 // attempts controls how many removal passes to try; set to >= 1.
+// It is safe for concurrent use: Generate and the other package-level
+// generators share the default Generator and take turns on it, so parallel
+// callers wait for each other. Use a NewGenerator per goroutine to run them
+// side by side.
 func Generate(d Difficulty, attempts int) (Board, error) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return defaultGenerator().Generate(d, attempts)
+}
+
+// GenerateSymmetric is Generate with givens laid out with every symmetry in
+// sym, e.g. SymmetryRotational for the classic newspaper look. Strong
+// symmetries can leave more clues than the difficulty's target.
+func GenerateSymmetric(d Difficulty, attempts int, sym Symmetry) (Board, error) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return defaultGenerator().GenerateSymmetric(d, attempts, sym)
+}
+
+// generateBoard builds a puzzle, calling progress (if non-nil) as in
+// GenerateWithProgress. Clues are removed together with their images under
+// sym; with SymmetryNone each cell is its own orbit, in row-major order, so
+// the random sequence matches plain generation.
+func generateBoard(rng *rand.Rand, d Difficulty, attempts int, sym Symmetry, progress func(Progress)) (Board, error) {
 	if attempts < 1 {
 		attempts = 1
 	}
+	orbits := sym.orbits()
+	var pr Progress
+	report := func() {
+		if progress != nil {
+			progress(pr)
+		}
+	}
 	var lastErr error
 	for try := 0; try < attempts; try++ {
+		pr = Progress{Attempt: try + 1, Attempts: attempts, Target: cluesFor(d), Checks: pr.Checks}
 		var b Board
-		fillDiagonalBoxes(&b)
-		if !backtrack(&b) {
+		fillDiagonalBoxes(&b, rng)
+		if !backtrack(&b, rng) {
 			lastErr = errors.New("failed to build solved board")
 			continue
 		}
+		pr.Clues = 81
+		report()
 		solution := b
-		target := cluesFor(d)
 		puzzle := solution
-		rmOrder := globalRand.Perm(81)
+		rmOrder := rng.Perm(len(orbits))
 		for _, idx := range rmOrder {
-			if countClues(puzzle) <= target {
+			if pr.Clues <= pr.Target {
 				break
 			}
-			r := idx / 9
-			c := idx % 9
-			old := puzzle[r][c]
-			if old == 0 {
+			orbit := orbits[idx]
+			if puzzle[orbit[0].Row][orbit[0].Col] == 0 {
 				continue
 			}
-			puzzle[r][c] = 0
-			if !hasUniqueSolution(puzzle, 2) {
-				puzzle[r][c] = old
+			saved := puzzle
+			for _, p := range orbit {
+				puzzle[p.Row][p.Col] = 0
 			}
+			pr.Checks++
+			if hasUniqueSolution(puzzle, 2) {
+				pr.Removed += len(orbit)
+				pr.Clues -= len(orbit)
+			} else {
+				puzzle = saved
+			}
+			report()
 		}
 		if hasUniqueSolution(puzzle, 2) { // uniqueness sanity
 			return puzzle, nil
@@ -211,8 +231,19 @@ func countClues(b Board) int {
 	return cnt
 }
 
+// CountSolutions returns the number of solutions of b, stopping once limit are
+// found (limit 2 is enough to tell unique from ambiguous). Invalid boards have none.
+func CountSolutions(b Board, limit int) int {
+	if Validate(b) != nil {
+		return 0
+	}
+	return countSolutions(b, limit)
+}
+
 // hasUniqueSolution returns true if the board has exactly one solution, early stopping after 'limit' found.
-func hasUniqueSolution(b Board, limit int) bool {
+func hasUniqueSolution(b Board, limit int) bool { return countSolutions(b, limit) == 1 }
+
+func countSolutions(b Board, limit int) int {
 	count := 0
 	var work Board
 	copyBoard(&work, &b)
@@ -235,17 +266,17 @@ func hasUniqueSolution(b Board, limit int) bool {
 		return false
 	}
 	dfs(&work)
-	return count == 1
+	return count
 }
 
-func fillDiagonalBoxes(b *Board) {
+func fillDiagonalBoxes(b *Board, rng *rand.Rand) {
 	for d := 0; d < 9; d += 3 {
-		fillBox(b, d, d)
+		fillBox(b, d, d, rng)
 	}
 }
 
-func fillBox(b *Board, br, bc int) {
-	vals := globalRand.Perm(9)
+func fillBox(b *Board, br, bc int, rng *rand.Rand) {
+	vals := rng.Perm(9)
 	idx := 0
 	for r := 0; r < 3; r++ {
 		for c := 0; c < 3; c++ {
@@ -53,7 +53,7 @@ func TestGenerateClueCounts(t *testing.T) {
 }
 
 func TestHint(t *testing.T) {
-	in := "530070000600195000098000060800060003400803001700020006060000280000419005000080079"
+	in := easyPuzzle
 	b, err := FromString(in)
 	if err != nil {
 		t.Fatalf("parse: %v", err)
@@ -66,3 +66,17 @@ func TestHint(t *testing.T) {
 		t.Fatalf("bad hint: r=%d c=%d v=%d", r, c, v)
 	}
 }
+
+func TestCountSolutions(t *testing.T) {
+	b, _ := FromString(easyPuzzle)
+	if n := CountSolutions(b, 2); n != 1 {
+		t.Fatalf("expected 1 solution, got %d", n)
+	}
+	if n := CountSolutions(Board{}, 3); n != 3 {
+		t.Fatalf("expected empty board to hit the limit, got %d", n)
+	}
+	b[0][1] = 5
+	if n := CountSolutions(b, 2); n != 0 {
+		t.Fatalf("expected invalid board to have no solutions, got %d", n)
+	}
+}
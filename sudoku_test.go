@@ -2,6 +2,9 @@ package sudoku
 
 import "testing"
 
+// easyPuzzle is sudokutest.Easy, which tests inside this package cannot import.
+const easyPuzzle = "530070000600195000098000060800060003400803001700020006060000280000419005000080079"
+
 func TestParseAndString(t *testing.T) {
 	in := "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
 	b, err := FromString(in)
@@ -14,7 +17,7 @@ func TestParseAndString(t *testing.T) {
 }
 
 func TestSolveSimple(t *testing.T) {
-	in := "530070000600195000098000060800060003400803001700020006060000280000419005000080079"
+	in := easyPuzzle
 	b, err := FromString(in)
 	if err != nil {
 		t.Fatalf("parse: %v", err)
@@ -0,0 +1,59 @@
+package sudokutest
+
+import (
+	"testing"
+
+	"go.rumenx.com/sudoku"
+)
+
+// AssertValid fails the test if b breaks a sudoku rule.
+func AssertValid(tb testing.TB, b sudoku.Board) {
+	tb.Helper()
+	if err := sudoku.Validate(b); err != nil {
+		tb.Fatalf("board is not valid: %v\n%s", err, b)
+	}
+}
+
+// AssertSolved fails the test unless b is complete and valid.
+func AssertSolved(tb testing.TB, b sudoku.Board) {
+	tb.Helper()
+	AssertValid(tb, b)
+	for r := range b {
+		for c := range b[r] {
+			if b[r][c] == 0 {
+				tb.Fatalf("board is not solved: r%dc%d empty\n%s", r+1, c+1, b)
+			}
+		}
+	}
+}
+
+// AssertUnique fails the test unless b has exactly one solution.
+func AssertUnique(tb testing.TB, b sudoku.Board) {
+	tb.Helper()
+	if n := sudoku.CountSolutions(b, 2); n != 1 {
+		tb.Fatalf("expected a unique solution, found %s\n%s", solutionCount(n), b)
+	}
+}
+
+// AssertValidGrid fails the test if g breaks a rule of its size or regions.
+func AssertValidGrid(tb testing.TB, g sudoku.Grid) {
+	tb.Helper()
+	if err := g.Validate(); err != nil {
+		tb.Fatalf("grid is not valid: %v\n%s", err, g)
+	}
+}
+
+// AssertUniqueGrid fails the test unless g has exactly one solution.
+func AssertUniqueGrid(tb testing.TB, g sudoku.Grid) {
+	tb.Helper()
+	if n := g.CountSolutions(2); n != 1 {
+		tb.Fatalf("expected a unique solution, found %s\n%s", solutionCount(n), g)
+	}
+}
+
+func solutionCount(n int) string {
+	if n == 0 {
+		return "none"
+	}
+	return "several"
+}
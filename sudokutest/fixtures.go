@@ -0,0 +1,262 @@
+// Package sudokutest provides canonical fixture puzzles and assertion helpers for
+// tests of code built on go.rumenx.com/sudoku.
+//
+// Fixtures are plain strings in the formats the sudoku package parses ('0' for an
+// empty cell), so they can be fed to FromString/FromStringN directly or through
+// the Must* helpers here. Every size and variant has easy, medium and hard
+// puzzles plus an unsolvable and a non-unique one. Classic puzzles are graded by
+// sudoku.Rate; elsewhere harder means fewer clues (or fewer, larger cages), as
+// with the generator. Their properties (rating, unique, ...) are checked by this
+// package's own tests and never change between releases.
+package sudokutest
+
+import (
+	"fmt"
+
+	"go.rumenx.com/sudoku"
+)
+
+// Classic 9x9 puzzles.
+const (
+	// Easy is a well-known 30-clue puzzle that singles solve.
+	Easy = "530070000600195000098000060800060003400803001700020006060000280000419005000080079"
+	// EasySolution is the solution of Easy.
+	EasySolution = "534678912672195348198342567859761423426853791713924856961537284287419635345286179"
+	// Medium is a 32-clue puzzle that needs locked candidates.
+	Medium = "367008104800000006100600893002030500030000209090027000400009630010053000000760400"
+	// Hard is a 26-clue puzzle that logic alone does not finish.
+	Hard = "000000000105000470029000500000000800030705200400809000000090006070306002053081090"
+	// Unsolvable breaks no rule yet has no solution: r1c9 has no candidate left.
+	Unsolvable = "123456780000000009" + empty7Rows
+	// NonUnique has exactly two solutions (a 2x2 rectangle of swappable values).
+	NonUnique = "239718546014965320056423190681549273945372861327681954178296435493857612562134789"
+	// Invalid repeats 5 in the first row; sudoku.FromString rejects it.
+	Invalid = "550070000600195000098000060800060003400803001700020006060000280000419005000080079"
+
+	empty7Rows = "000000000000000000000000000000000000000000000000000000000000000"
+)
+
+// Variable-size puzzles for FromStringN with the box shape in the name. The
+// unsuffixed puzzle of each size is the easy one.
+const (
+	// Grid4x2x2 is a 4x4 puzzle with 2x2 boxes and a unique solution.
+	Grid4x2x2 = "2040012004000234"
+	// Grid4x2x2Medium is Grid4x2x2 down to 6 clues.
+	Grid4x2x2Medium = "0000012004000234"
+	// Grid4x2x2Hard is Grid4x2x2 down to 5 clues.
+	Grid4x2x2Hard = "0000012000000234"
+	// Grid4x2x2Unsolvable adds a 4 to r1c3 of Grid4x2x2, leaving r1c2 no value.
+	Grid4x2x2Unsolvable = "2043012004000234"
+	// Grid4x2x2NonUnique has exactly two solutions.
+	Grid4x2x2NonUnique = "0000012000000204"
+
+	// Grid6x2x3 is a 6x6 puzzle with 2x3 boxes and a unique solution.
+	Grid6x2x3 = "156020000061500006642000000600000053"
+	// Grid6x2x3Medium is Grid6x2x3 down to 12 clues.
+	Grid6x2x3Medium = "050020000061500006642000000600000053"
+	// Grid6x2x3Hard is Grid6x2x3 down to 10 clues.
+	Grid6x2x3Hard = "050020000001500006042000000600000053"
+	// Grid6x2x3Unsolvable breaks no rule yet has no solution.
+	Grid6x2x3Unsolvable = "156420000061500006642000000600000053"
+	// Grid6x2x3NonUnique has exactly two solutions.
+	Grid6x2x3NonUnique = "050020000001000006042000000600000053"
+
+	// Grid16x4x4 is a 126-clue 16x16 puzzle with 4x4 boxes and a unique solution.
+	Grid16x4x4 = "0C00000740B30000380E0GF000000B00000B90E0A0F5G00100000BC560D003F7F0B0G2030060000076A40F0E35000800800D60B0004C5E0F0G000810F000260000058C0BD0100FG204F2E001C0370A00A0C600090F00D1E3008130GFE2A400000260500D0A0FE01015GABE020090F7DC0037008C0E50BG000D00000G00013020"
+	// Grid16x4x4Medium is a 105-clue 16x16 puzzle with a unique solution.
+	Grid16x4x4Medium = "0C6000500430G0000A201D00B0008000100D83040025000045E00C00A600D012E00B0A700008000000F000009040700BD001E0G0000003045G06000870A01E00201070F030D0B00090BA0G0007000D300E7091AD050F0280G00F0E0040695A00FB00008010009000600200C000E0005800000036090AE0000000G00020040F0C"
+	// Grid16x4x4Hard is a 99-clue 16x16 puzzle with a unique solution.
+	Grid16x4x4Hard = "0000C006A00004D017C000G03000600505000E306B7002AC004D9100000080F0BG00D761F00A302000040F000C5000B1000C0A00020000048300000200DG07000013A08E900B76005000690000200010C002000000F6080000D005C00G07BA0000800024D0A9007000E0000706C0D0099001000000E00B067006000000000000"
+	// Grid16x4x4Unsolvable breaks no rule yet has no solution.
+	Grid16x4x4Unsolvable = "2C00000740B30000380E0GF000000B00000B90E0A0F5G00100000BC560D003F7F0B0G2030060000076A40F0E35000800800D60B0004C5E0F0G000810F000260000058C0BD0100FG204F2E001C0370A00A0C600090F00D1E3008130GFE2A400000260500D0A0FE01015GABE020090F7DC0037008C0E50BG000D00000G00013020"
+	// Grid16x4x4NonUnique has exactly two solutions.
+	Grid16x4x4NonUnique = "0000C006A000040017C000G03000600505000E306B7002AC004D9100000080F0BG00D761F00A302000040F000C5000B1000C0A00020000048300000200DG07000013A08E900B76005000690000200010C002000000F6080000D005C00G07BA0000800024D0A9007000E0000706C0D0099001000000E00B067006000000000000"
+)
+
+// Variant puzzles. Each has a unique solution under its variant's rules.
+const (
+	// JigsawRegions6 lists the region id of each cell of a 6x6 jigsaw, row by row.
+	JigsawRegions6 = "221111200031200031223335444355444555"
+	// Jigsaw6 is a puzzle on the JigsawRegions6 layout.
+	Jigsaw6 = "020640500003010002000304600420200100"
+	// Jigsaw6Medium is Jigsaw6 down to 11 clues.
+	Jigsaw6Medium = "000000500003010002000304600420200100"
+	// Jigsaw6Hard is Jigsaw6 down to 10 clues.
+	Jigsaw6Hard = "000000500000010002000304600420200100"
+	// Jigsaw6Unsolvable breaks no rule on the layout yet has no solution.
+	Jigsaw6Unsolvable = "120640500003010002000304600420200100"
+	// Jigsaw6NonUnique has exactly two solutions on the layout.
+	Jigsaw6NonUnique = "000000500000010000000304600420200100"
+
+	// Killer4Cages are the cages of Killer4, in FormatCages syntax.
+	Killer4Cages = "9: r4c2 r4c3 r4c1\n10: r1c3 r2c3 r3c3 r2c4\n5: r4c4 r3c4\n2: r1c4\n6: r2c2 r2c1\n4: r1c1 r1c2\n4: r3c2 r3c1\n"
+	// Killer4 holds the givens of a 4x4 killer puzzle (2x2 boxes).
+	Killer4 = "0100020000000000"
+	// Killer4MediumCages merges two cages of Killer4Cages; use it with the Killer4 givens.
+	Killer4MediumCages = "9: r4c2 r4c3 r4c1\n10: r1c3 r2c3 r3c3 r2c4\n5: r4c4 r3c4\n2: r1c4\n10: r2c2 r2c1 r1c1 r1c2\n4: r3c2 r3c1\n"
+	// Killer4HardCages are four 4-cell cages for Killer4Hard.
+	Killer4HardCages = "10: r4c4 r4c3 r3c4 r3c3\n10: r1c2 r2c2 r1c1 r2c1\n10: r4c2 r3c2 r4c1 r3c1\n10: r1c3 r2c3 r1c4 r2c4\n"
+	// Killer4Hard holds the givens of a 4x4 killer puzzle on Killer4HardCages.
+	Killer4Hard = "0100300002000004"
+	// Killer4Unsolvable breaks no rule on Killer4Cages yet has no solution.
+	Killer4Unsolvable = "0120020000000000"
+	// Killer4NonUnique has exactly two solutions on Killer4Cages.
+	Killer4NonUnique = "0100000000000000"
+
+	// ParityMask marks each cell of Parity as any ('.'), even ('e') or odd ('o').
+	ParityMask = "....o..e.......e..o.eeoo.o......e.............eo..e.e.e.o...oo.....eoeeee...eo.o."
+	// Parity holds the givens of an even/odd puzzle with the 26 clues of a hard one.
+	Parity = "050016004204000617060430005006080700001005000080900000007000000500000400800000071"
+	// ParityEasy is Parity with 40 clues.
+	ParityEasy = "750016304204500617160430205026080750071005800380902006007800900500000400800000071"
+	// ParityMedium is Parity with 32 clues.
+	ParityMedium = "750016004204000617160430005006080750001005000380900000007800000500020400800000071"
+	// ParityUnsolvable breaks no rule under ParityMask yet has no solution.
+	ParityUnsolvable = "350016004204000617060430005006080700001005000080900000007000000500000400800000071"
+	// ParityNonUnique has exactly two solutions under ParityMask.
+	ParityNonUnique = "050016004204000617060430005006080700000005000080900000007000000500000400800000071"
+)
+
+// Samurai holds the five boards of a samurai puzzle in sudoku.Samurai order,
+// with the 118 clues of a hard one.
+var Samurai = [5]string{
+	"030950200000010050704300000098030407300000020420600038800000600000005000003009000",
+	"000500000001027500305040020000308600000000080983600700030406000000930058509000001",
+	"600009030000004000000600509000090000000020060000100970500007000007380000000905000",
+	"003402500200003007010750000000370004048600200070000000000020805000001000009800001",
+	"000005000000078030000003405000500007524730009000804010600000000070000000240600750",
+}
+
+// SamuraiEasy is Samurai with 182 clues.
+var SamuraiEasy = [5]string{
+	"630950280002010750704308006198030467300700120420601038810070605200805090503069002",
+	"070503004091027506305049020004308605050090083983605700130406200007930058549002001",
+	"605009130090054007002600549700590010050023060200140970510067002007382001003905080",
+	"703402510250083007010759003060370104348600200070000000000020805000001000009800001",
+	"002005100001078036080013405030506007524730009000804010600000000070000000240600750",
+}
+
+// SamuraiMedium is Samurai with 145 clues.
+var SamuraiMedium = [5]string{
+	"630950200000010750704308000198030407300000020420601038800070600000005090503009000",
+	"000503000001027500305040020000308605050000080983600700030406200000930058549000001",
+	"600009030090004000000600549000590000000023060000100970500007002007380000000905080",
+	"703402500200083007010750000000370104348600200075000000000020845400001000009800001",
+	"002005000000078036080003405000500007524730009007804010600000000079000000240600750",
+}
+
+// SamuraiUnsolvable breaks no rule yet has no solution.
+var SamuraiUnsolvable = [5]string{
+	"630954280002010750704308006198030467300700120420601038810070605200805090503069002",
+	"070503004091027506305049020004308605050090083983605700130406200007930058549002001",
+	"605009130090054007002600549700590010050023060200140970510067002007382001003905080",
+	"703402510250083007010759003060370104348600200070000000000020805000001000009800001",
+	"002005100001078036080013405030506007524730009000804010600000000070000000240600750",
+}
+
+// SamuraiNonUnique has exactly two solutions.
+var SamuraiNonUnique = [5]string{
+	"000000000000000000704308006198030467300700120420601038810070605200805090503069002",
+	"000000000091027506305049020004308605050090083983605700130406200007930058549002001",
+	"605009130090054007002600549700590010050023060200140970510067002007382001003905080",
+	"703402510250083007010759003060370104348600200070000000000020805000001000009800001",
+	"002005100001078036080013405030506007524730009000804010600000000070000000240600750",
+}
+
+// MustBoard parses a classic fixture, panicking on malformed input. Unlike
+// sudoku.FromString it does not check the rules, so Invalid can be loaded too.
+func MustBoard(s string) sudoku.Board {
+	if len(s) != 81 {
+		panic(fmt.Sprintf("sudokutest: board needs 81 cells, got %d", len(s)))
+	}
+	var b sudoku.Board
+	for i := 0; i < 81; i++ {
+		if s[i] < '0' || s[i] > '9' {
+			panic(fmt.Sprintf("sudokutest: invalid cell %q at %d", s[i], i))
+		}
+		b[i/9][i%9] = int(s[i] - '0')
+	}
+	return b
+}
+
+// MustGrid parses a variable-size fixture, panicking on malformed input.
+func MustGrid(s string, size, boxRows, boxCols int) sudoku.Grid {
+	g, err := sudoku.FromStringN(s, size, boxRows, boxCols)
+	if err != nil {
+		panic(fmt.Sprintf("sudokutest: %v", err))
+	}
+	return g
+}
+
+// MustJigsaw returns Jigsaw6 on its JigsawRegions6 layout.
+func MustJigsaw() sudoku.Grid { return MustJigsawOf(Jigsaw6) }
+
+// MustJigsawOf returns the givens of a Jigsaw6* fixture on the JigsawRegions6 layout.
+func MustJigsawOf(givens string) sudoku.Grid {
+	if len(givens) != 36 {
+		panic(fmt.Sprintf("sudokutest: jigsaw needs 36 cells, got %d", len(givens)))
+	}
+	regions := make([][]int, 6)
+	for r := range regions {
+		regions[r] = make([]int, 6)
+		for c := range regions[r] {
+			regions[r][c] = int(JigsawRegions6[r*6+c] - '0')
+		}
+	}
+	g, err := sudoku.NewJigsawGrid(regions)
+	if err != nil {
+		panic(fmt.Sprintf("sudokutest: %v", err))
+	}
+	for i := 0; i < 36; i++ {
+		if givens[i] < '0' || givens[i] > '6' {
+			panic(fmt.Sprintf("sudokutest: invalid cell %q at %d", givens[i], i))
+		}
+		g.Cells[i/6][i%6] = int(givens[i] - '0')
+	}
+	return g
+}
+
+// MustKiller returns Killer4 with its Killer4Cages.
+func MustKiller() sudoku.KillerGrid { return MustKillerOf(Killer4, Killer4Cages) }
+
+// MustKillerOf returns the 4x4 killer puzzle with the given givens and cages.
+func MustKillerOf(givens, cages string) sudoku.KillerGrid {
+	cg, err := sudoku.ParseCages(cages)
+	if err != nil {
+		panic(fmt.Sprintf("sudokutest: %v", err))
+	}
+	k, err := sudoku.NewKillerGrid(MustGrid(givens, 4, 2, 2), cg)
+	if err != nil {
+		panic(fmt.Sprintf("sudokutest: %v", err))
+	}
+	return k
+}
+
+// MustParity returns Parity with its ParityMask.
+func MustParity() sudoku.ParityBoard { return MustParityOf(Parity) }
+
+// MustParityOf returns the givens of a Parity* fixture with ParityMask.
+func MustParityOf(givens string) sudoku.ParityBoard {
+	p := sudoku.ParityBoard{Board: MustBoard(givens)}
+	for i, ch := range ParityMask {
+		switch ch {
+		case 'e':
+			p.Mask[i/9][i%9] = sudoku.ParityEven
+		case 'o':
+			p.Mask[i/9][i%9] = sudoku.ParityOdd
+		}
+	}
+	return p
+}
+
+// MustSamurai returns the Samurai fixture.
+func MustSamurai() sudoku.Samurai { return MustSamuraiOf(Samurai) }
+
+// MustSamuraiOf returns one of the Samurai* fixtures.
+func MustSamuraiOf(boards [5]string) sudoku.Samurai {
+	var s sudoku.Samurai
+	for i, b := range boards {
+		s[i] = MustBoard(b)
+	}
+	return s
+}
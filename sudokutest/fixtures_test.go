@@ -0,0 +1,168 @@
+package sudokutest
+
+import (
+	"fmt"
+	"testing"
+
+	"go.rumenx.com/sudoku"
+)
+
+// recorder captures failures instead of stopping the test.
+type recorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recorder) Helper()                        {}
+func (r *recorder) Fatalf(format string, a ...any) { r.failed = true }
+
+func TestClassicFixtures(t *testing.T) {
+	for s, want := range map[string]sudoku.Difficulty{Easy: sudoku.Easy, Medium: sudoku.Medium, Hard: sudoku.Hard} {
+		b := MustBoard(s)
+		AssertValid(t, b)
+		AssertUnique(t, b)
+		if r, err := sudoku.Rate(b); err != nil || r.Difficulty != want {
+			t.Fatalf("%s rated %+v (%v), want %s", s, r, err, want)
+		}
+	}
+	sol, ok := sudoku.Solve(MustBoard(Easy))
+	if !ok || sol.String() != EasySolution {
+		t.Fatalf("EasySolution does not match: %s", sol)
+	}
+	AssertSolved(t, MustBoard(EasySolution))
+	if n := sudoku.CountSolutions(MustBoard(Unsolvable), 2); n != 0 || sudoku.Validate(MustBoard(Unsolvable)) != nil {
+		t.Fatalf("Unsolvable: valid=%v solutions=%d", sudoku.Validate(MustBoard(Unsolvable)), n)
+	}
+	if n := sudoku.CountSolutions(MustBoard(NonUnique), 3); n != 2 {
+		t.Fatalf("NonUnique: %d solutions", n)
+	}
+	if sudoku.Validate(MustBoard(Invalid)) == nil {
+		t.Fatalf("Invalid validates")
+	}
+}
+
+func TestGridFixtures(t *testing.T) {
+	jigsaw := func(s string, _, _, _ int) sudoku.Grid { return MustJigsawOf(s) }
+	for _, tc := range []struct {
+		name                                 string
+		easy, medium, hard, unsolvable, many string
+		size, boxRows, boxCols               int
+		load                                 func(string, int, int, int) sudoku.Grid
+	}{
+		{"4x4", Grid4x2x2, Grid4x2x2Medium, Grid4x2x2Hard, Grid4x2x2Unsolvable, Grid4x2x2NonUnique, 4, 2, 2, MustGrid},
+		{"6x6", Grid6x2x3, Grid6x2x3Medium, Grid6x2x3Hard, Grid6x2x3Unsolvable, Grid6x2x3NonUnique, 6, 2, 3, MustGrid},
+		{"16x16", Grid16x4x4, Grid16x4x4Medium, Grid16x4x4Hard, Grid16x4x4Unsolvable, Grid16x4x4NonUnique, 16, 4, 4, MustGrid},
+		{"jigsaw", Jigsaw6, Jigsaw6Medium, Jigsaw6Hard, Jigsaw6Unsolvable, Jigsaw6NonUnique, 6, 2, 3, jigsaw},
+	} {
+		clues := 0
+		for i, s := range []string{tc.easy, tc.medium, tc.hard} {
+			g := tc.load(s, tc.size, tc.boxRows, tc.boxCols)
+			AssertValidGrid(t, g)
+			AssertUniqueGrid(t, g)
+			n := gridClues(g)
+			if i > 0 && n >= clues {
+				t.Fatalf("%s: %s has %d clues, the easier one %d", tc.name, s, n, clues)
+			}
+			clues = n
+		}
+		if g := tc.load(tc.unsolvable, tc.size, tc.boxRows, tc.boxCols); g.Validate() != nil || g.CountSolutions(2) != 0 {
+			t.Fatalf("%s: unsolvable fixture: valid=%v solutions=%d", tc.name, g.Validate(), g.CountSolutions(2))
+		}
+		if n := tc.load(tc.many, tc.size, tc.boxRows, tc.boxCols).CountSolutions(3); n != 2 {
+			t.Fatalf("%s: non-unique fixture has %d solutions", tc.name, n)
+		}
+	}
+}
+
+func TestVariantFixtures(t *testing.T) {
+	killers := []sudoku.KillerGrid{MustKiller(), MustKillerOf(Killer4, Killer4MediumCages), MustKillerOf(Killer4Hard, Killer4HardCages)}
+	for i, k := range killers {
+		if n := k.CountSolutions(2); k.Validate() != nil || n != 1 {
+			t.Fatalf("killer %d: valid=%v solutions=%d", i, k.Validate(), n)
+		}
+		if i > 0 && len(k.Cages) >= len(killers[i-1].Cages) {
+			t.Fatalf("killer %d has %d cages, the easier one %d", i, len(k.Cages), len(killers[i-1].Cages))
+		}
+	}
+	if k := MustKillerOf(Killer4Unsolvable, Killer4Cages); k.Validate() != nil || k.CountSolutions(2) != 0 {
+		t.Fatalf("unsolvable killer: valid=%v solutions=%d", k.Validate(), k.CountSolutions(2))
+	}
+	if n := MustKillerOf(Killer4NonUnique, Killer4Cages).CountSolutions(3); n != 2 {
+		t.Fatalf("non-unique killer has %d solutions", n)
+	}
+
+	clues := 82
+	for _, s := range []string{ParityEasy, ParityMedium, Parity} {
+		p := MustParityOf(s)
+		if n := p.CountSolutions(2); p.Validate() != nil || n != 1 {
+			t.Fatalf("parity %s: valid=%v solutions=%d", s, p.Validate(), n)
+		}
+		n := countFilled(p.Board)
+		if n >= clues {
+			t.Fatalf("parity %s has %d clues, the easier one %d", s, n, clues)
+		}
+		clues = n
+	}
+	if p := MustParityOf(ParityUnsolvable); p.Validate() != nil || p.CountSolutions(2) != 0 {
+		t.Fatalf("unsolvable parity: valid=%v solutions=%d", p.Validate(), p.CountSolutions(2))
+	}
+	if n := MustParityOf(ParityNonUnique).CountSolutions(3); n != 2 {
+		t.Fatalf("non-unique parity has %d solutions", n)
+	}
+
+	clues = 5*81 + 1
+	for i, boards := range [][5]string{SamuraiEasy, SamuraiMedium, Samurai} {
+		s := MustSamuraiOf(boards)
+		if n := s.CountSolutions(2); s.Validate() != nil || n != 1 {
+			t.Fatalf("samurai %d: valid=%v solutions=%d", i, s.Validate(), n)
+		}
+		n := 0
+		for _, b := range s {
+			n += countFilled(b)
+		}
+		if n >= clues {
+			t.Fatalf("samurai %d has %d clues, the easier one %d", i, n, clues)
+		}
+		clues = n
+	}
+	if s := MustSamuraiOf(SamuraiUnsolvable); s.Validate() != nil || s.CountSolutions(2) != 0 {
+		t.Fatalf("unsolvable samurai: valid=%v solutions=%d", s.Validate(), s.CountSolutions(2))
+	}
+	if n := MustSamuraiOf(SamuraiNonUnique).CountSolutions(3); n != 2 {
+		t.Fatalf("non-unique samurai has %d solutions", n)
+	}
+}
+
+func gridClues(g sudoku.Grid) int {
+	n := 0
+	for _, row := range g.Cells {
+		for _, v := range row {
+			if v != 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestAssertionsFail(t *testing.T) {
+	for name, check := range map[string]func(testing.TB){
+		"valid":       func(tb testing.TB) { AssertValid(tb, MustBoard(Invalid)) },
+		"solved":      func(tb testing.TB) { AssertSolved(tb, MustBoard(Easy)) },
+		"unique":      func(tb testing.TB) { AssertUnique(tb, MustBoard(NonUnique)) },
+		"unsolvable":  func(tb testing.TB) { AssertUnique(tb, MustBoard(Unsolvable)) },
+		"unique-grid": func(tb testing.TB) { g, _ := sudoku.NewGrid(4, 2, 2); AssertUniqueGrid(tb, g) },
+	} {
+		r := &recorder{TB: t}
+		check(r)
+		if !r.failed {
+			t.Fatalf("%s: assertion did not fail", name)
+		}
+	}
+}
+
+func ExampleAssertUnique() {
+	b := MustBoard(Hard)
+	fmt.Println(sudoku.CountSolutions(b, 2))
+	// Output: 1
+}
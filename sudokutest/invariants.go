@@ -0,0 +1,178 @@
+package sudokutest
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"go.rumenx.com/sudoku"
+)
+
+// InvariantOptions tune the randomized invariant checks. The zero value runs
+// 10 rounds from seed 1.
+type InvariantOptions struct {
+	Seed   uint64 // first seed; rounds use Seed, Seed+1, ...
+	Rounds int    // random puzzles or transformations per check
+}
+
+func (o InvariantOptions) withDefaults() InvariantOptions {
+	if o.Seed == 0 {
+		o.Seed = 1
+	}
+	if o.Rounds <= 0 {
+		o.Rounds = 10
+	}
+	return o
+}
+
+// CheckSolverInvariants runs property tests against solve, which must behave
+// like sudoku.Solve:
+//
+//   - fixtures and random generated puzzles are solved, keeping every given,
+//     and the solution is the unique one
+//   - Invalid and Unsolvable report false
+//   - rotating, transposing, reflecting, relabeling or swapping bands of a
+//     puzzle transforms its solution the same way
+//
+// Failures are reported through tb with the seed and puzzle, so they can be
+// replayed.
+func CheckSolverInvariants(tb testing.TB, solve func(sudoku.Board) (sudoku.Board, bool), opts InvariantOptions) {
+	tb.Helper()
+	opts = opts.withDefaults()
+	puzzles := []sudoku.Board{MustBoard(Easy), MustBoard(Medium), MustBoard(Hard)}
+	for i := 0; i < opts.Rounds; i++ {
+		p, err := sudoku.NewGenerator(opts.Seed+uint64(i)).Generate(sudoku.Medium, 3)
+		if err != nil {
+			tb.Fatalf("seed %d: generate: %v", opts.Seed+uint64(i), err)
+			return
+		}
+		puzzles = append(puzzles, p)
+	}
+	for _, p := range puzzles {
+		if !checkSolves(tb, solve, p) {
+			return
+		}
+	}
+	for _, s := range []string{Invalid, Unsolvable} {
+		if _, ok := solve(MustBoard(s)); ok {
+			tb.Fatalf("solver reported a solution for %s", s)
+			return
+		}
+	}
+	for i := 0; i < opts.Rounds; i++ {
+		seed := opts.Seed + uint64(i)
+		rng := rand.New(rand.NewPCG(seed, 0))
+		p := puzzles[rng.IntN(len(puzzles))]
+		sol, _ := solve(p)
+		name, transform := randomTransform(rng)
+		tp, want := transform(p), transform(sol)
+		got, ok := solve(tp)
+		if !ok || got != want {
+			tb.Fatalf("seed %d: %s changes the solution of %s\ngot %v (ok=%v), want %s", seed, name, p, got, ok, want)
+			return
+		}
+	}
+}
+
+// CheckGeneratorInvariants runs property tests against generate, which must
+// behave like (*sudoku.Generator).Generate on a generator seeded with seed:
+// every puzzle is valid and has a unique solution, harder difficulties do not
+// get more clues than easier ones from the same seed, and equal seeds give
+// equal puzzles.
+func CheckGeneratorInvariants(tb testing.TB, generate func(seed uint64, d sudoku.Difficulty) (sudoku.Board, error), opts InvariantOptions) {
+	tb.Helper()
+	opts = opts.withDefaults()
+	for i := 0; i < opts.Rounds; i++ {
+		seed := opts.Seed + uint64(i)
+		clues := 82
+		for _, d := range []sudoku.Difficulty{sudoku.Easy, sudoku.Medium, sudoku.Hard} {
+			p, err := generate(seed, d)
+			if err != nil {
+				tb.Fatalf("seed %d, %s: %v", seed, d, err)
+				return
+			}
+			if err := sudoku.Validate(p); err != nil {
+				tb.Fatalf("seed %d, %s: invalid puzzle %s: %v", seed, d, p, err)
+				return
+			}
+			if n := sudoku.CountSolutions(p, 2); n != 1 {
+				tb.Fatalf("seed %d, %s: puzzle %s has %s", seed, d, p, solutionCount(n))
+				return
+			}
+			n := countFilled(p)
+			if n > clues {
+				tb.Fatalf("seed %d: %s puzzle has %d clues, more than the easier one (%d)", seed, d, n, clues)
+				return
+			}
+			clues = n
+			again, err := generate(seed, d)
+			if err != nil || again != p {
+				tb.Fatalf("seed %d, %s: not deterministic: %s then %s (%v)", seed, d, p, again, err)
+				return
+			}
+		}
+	}
+}
+
+// checkSolves reports whether solve solved p correctly, failing tb otherwise.
+func checkSolves(tb testing.TB, solve func(sudoku.Board) (sudoku.Board, bool), p sudoku.Board) bool {
+	tb.Helper()
+	got, ok := solve(p)
+	if !ok {
+		tb.Fatalf("solver failed on %s", p)
+		return false
+	}
+	if !sudoku.IsSolved(got) {
+		tb.Fatalf("solver returned an invalid grid for %s:\n%s", p, sudoku.Format(got, sudoku.FormatASCII))
+		return false
+	}
+	for _, ch := range sudoku.Diff(p, got) {
+		if ch.Old != 0 {
+			tb.Fatalf("solver changed given %s of %s", ch.Cell, p)
+			return false
+		}
+	}
+	if want, _ := sudoku.Solve(p); sudoku.CountSolutions(p, 2) == 1 && got != want {
+		tb.Fatalf("solver returned %s for %s, the unique solution is %s", got, p, want)
+		return false
+	}
+	return true
+}
+
+// randomTransform picks a solvability-preserving symmetry of the board.
+func randomTransform(rng *rand.Rand) (string, func(sudoku.Board) sudoku.Board) {
+	switch rng.IntN(5) {
+	case 0:
+		return "rotation", sudoku.Rotate
+	case 1:
+		return "transposition", sudoku.Transpose
+	case 2:
+		return "reflection", sudoku.ReflectHorizontal
+	case 3:
+		var perm [9]int
+		for i, v := range rng.Perm(9) {
+			perm[i] = v + 1
+		}
+		return "relabeling", func(b sudoku.Board) sudoku.Board {
+			out, _ := sudoku.Relabel(b, perm)
+			return out
+		}
+	default:
+		b1, b2 := rng.IntN(3), rng.IntN(3)
+		return "band swap", func(b sudoku.Board) sudoku.Board {
+			out, _ := sudoku.SwapBands(b, b1, b2)
+			return out
+		}
+	}
+}
+
+func countFilled(b sudoku.Board) int {
+	n := 0
+	for r := range b {
+		for c := range b[r] {
+			if b[r][c] != 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
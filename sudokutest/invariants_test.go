@@ -0,0 +1,50 @@
+package sudokutest
+
+import (
+	"testing"
+
+	"go.rumenx.com/sudoku"
+)
+
+func TestSolverInvariants(t *testing.T) {
+	CheckSolverInvariants(t, sudoku.Solve, InvariantOptions{})
+	CheckSolverInvariants(t, sudoku.SolveDLX, InvariantOptions{Seed: 42, Rounds: 5})
+}
+
+func TestGeneratorInvariants(t *testing.T) {
+	CheckGeneratorInvariants(t, func(seed uint64, d sudoku.Difficulty) (sudoku.Board, error) {
+		return sudoku.NewGenerator(seed).Generate(d, 3)
+	}, InvariantOptions{Rounds: 5})
+}
+
+func TestInvariantsCatchBrokenImplementations(t *testing.T) {
+	for name, check := range map[string]func(testing.TB){
+		"solver ignores givens": func(tb testing.TB) {
+			CheckSolverInvariants(tb, func(sudoku.Board) (sudoku.Board, bool) {
+				return MustBoard(EasySolution), true
+			}, InvariantOptions{Rounds: 1})
+		},
+		"solver accepts invalid": func(tb testing.TB) {
+			CheckSolverInvariants(tb, func(b sudoku.Board) (sudoku.Board, bool) {
+				sol, _ := sudoku.Solve(b)
+				return sol, true
+			}, InvariantOptions{Rounds: 1})
+		},
+		"generator not deterministic": func(tb testing.TB) {
+			CheckGeneratorInvariants(tb, func(uint64, sudoku.Difficulty) (sudoku.Board, error) {
+				return sudoku.Generate(sudoku.Easy, 3)
+			}, InvariantOptions{Rounds: 1})
+		},
+		"generator not unique": func(tb testing.TB) {
+			CheckGeneratorInvariants(tb, func(uint64, sudoku.Difficulty) (sudoku.Board, error) {
+				return MustBoard(NonUnique), nil
+			}, InvariantOptions{Rounds: 1})
+		},
+	} {
+		r := &recorder{TB: t}
+		check(r)
+		if !r.failed {
+			t.Fatalf("%s: invariant check passed", name)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package sudoku
+
+import "fmt"
+
+// Technique is a human solving technique. Values are ordered from easiest to
+// hardest, so they compare by difficulty; the string IDs (the Reason*
+// constants) are stable and are what flags, JSON and saved data should use.
+type Technique int
+
+const (
+	TechniqueNakedSingle Technique = iota
+	TechniqueHiddenSingle
+	TechniqueLockedCandidates
+	TechniqueNakedPair
+	TechniqueGuess // trial and error once logic stalls
+)
+
+// TechniqueInfo describes a Technique for display and rating.
+type TechniqueInfo struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Weight      int        `json:"weight"`     // relative effort, increasing with difficulty
+	Difficulty  Difficulty `json:"difficulty"` // the grade Rate gives a puzzle needing it
+}
+
+var techniqueInfo = [...]TechniqueInfo{
+	TechniqueNakedSingle: {ReasonNakedSingle, "Naked single",
+		"A cell whose row, column and box already hold every other digit.", 1, Easy},
+	TechniqueHiddenSingle: {ReasonHiddenSingle, "Hidden single",
+		"The only cell of a row, column or box that can still hold a digit.", 2, Easy},
+	TechniqueLockedCandidates: {ReasonLockedCandidates, "Locked candidates",
+		"A digit confined to one line within a box, or to one box within a line, is removed from the rest of that line or box.", 4, Medium},
+	TechniqueNakedPair: {ReasonNakedPair, "Naked pair",
+		"Two cells of a unit with the same two candidates; those digits are removed from the unit's other cells.", 5, Medium},
+	TechniqueGuess: {ReasonGuess, "Guess",
+		"Try a candidate and backtrack on contradiction.", 10, Hard},
+}
+
+// Techniques returns every technique from easiest to hardest.
+func Techniques() []Technique {
+	out := make([]Technique, len(techniqueInfo))
+	for i := range out {
+		out[i] = Technique(i)
+	}
+	return out
+}
+
+// ParseTechnique looks up a technique by its ID.
+func ParseTechnique(id string) (Technique, error) {
+	for i, info := range techniqueInfo {
+		if info.ID == id {
+			return Technique(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown technique %q", id)
+}
+
+// Info returns the metadata of t; unknown values get an empty ID.
+func (t Technique) Info() TechniqueInfo {
+	if t < 0 || int(t) >= len(techniqueInfo) {
+		return TechniqueInfo{}
+	}
+	return techniqueInfo[t]
+}
+
+// String returns the stable ID, e.g. "hidden-single".
+func (t Technique) String() string {
+	if id := t.Info().ID; id != "" {
+		return id
+	}
+	return fmt.Sprintf("Technique(%d)", int(t))
+}
+
+// MarshalText encodes t as its ID, so JSON payloads and flags carry strings.
+func (t Technique) MarshalText() ([]byte, error) {
+	id := t.Info().ID
+	if id == "" {
+		return nil, fmt.Errorf("unknown technique %d", int(t))
+	}
+	return []byte(id), nil
+}
+
+// UnmarshalText parses an ID written by MarshalText.
+func (t *Technique) UnmarshalText(text []byte) error {
+	v, err := ParseTechnique(string(text))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
@@ -0,0 +1,65 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTechniquesOrderedAndStable(t *testing.T) {
+	ids := []string{"naked-single", "hidden-single", "locked-candidates", "naked-pair", "guess"}
+	all := Techniques()
+	if len(all) != len(ids) {
+		t.Fatalf("got %d techniques, want %d", len(all), len(ids))
+	}
+	for i, tech := range all {
+		info := tech.Info()
+		if tech.String() != ids[i] || info.Name == "" || info.Description == "" {
+			t.Fatalf("technique %d = %+v", i, info)
+		}
+		if i > 0 && info.Weight <= all[i-1].Info().Weight {
+			t.Fatalf("%s weight not increasing", tech)
+		}
+		if got, err := ParseTechnique(ids[i]); err != nil || got != tech {
+			t.Fatalf("ParseTechnique(%q) = %v, %v", ids[i], got, err)
+		}
+	}
+	if _, err := ParseTechnique("x-wing"); err == nil {
+		t.Fatal("expected unknown technique error")
+	}
+	if Technique(99).String() != "Technique(99)" {
+		t.Fatal("unexpected String for unknown value")
+	}
+}
+
+func TestTechniqueJSON(t *testing.T) {
+	data, err := json.Marshal(map[string]Technique{"t": TechniqueNakedPair})
+	if err != nil || string(data) != `{"t":"naked-pair"}` {
+		t.Fatalf("marshal = %s, %v", data, err)
+	}
+	var back map[string]Technique
+	if err := json.Unmarshal(data, &back); err != nil || back["t"] != TechniqueNakedPair {
+		t.Fatalf("unmarshal = %v, %v", back, err)
+	}
+	if err := json.Unmarshal([]byte(`{"t":"nope"}`), &back); err == nil {
+		t.Fatal("expected error for unknown ID")
+	}
+	if _, err := json.Marshal(Technique(-1)); err == nil {
+		t.Fatal("expected error for unknown value")
+	}
+}
+
+func TestRateUsesTechniqueTable(t *testing.T) {
+	for _, s := range []string{
+		easyPuzzle,
+	} {
+		b, _ := FromString(s)
+		r, err := Rate(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tech, err := ParseTechnique(r.Hardest)
+		if err != nil || tech.Info().Difficulty != r.Difficulty {
+			t.Fatalf("rating %+v disagrees with technique %v", r, tech)
+		}
+	}
+}
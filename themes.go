@@ -0,0 +1,96 @@
+package sudoku
+
+// Theme is a machine-readable tag describing a notable property of a puzzle's
+// givens, used to curate collections.
+type Theme string
+
+const (
+	// ThemeLowClues marks puzzles with at most LowClueThreshold givens.
+	ThemeLowClues Theme = "low-clues"
+	// ThemeRotational marks givens symmetric under 180° rotation.
+	ThemeRotational Theme = "rotational-symmetry"
+	// ThemeMirror marks givens symmetric about the vertical or horizontal axis.
+	ThemeMirror Theme = "mirror-symmetry"
+	// ThemeDiagonalSymmetry marks givens symmetric about either main diagonal.
+	ThemeDiagonalSymmetry Theme = "diagonal-symmetry"
+	// ThemeFullySymmetric marks givens invariant under every rotation and reflection of the square.
+	ThemeFullySymmetric Theme = "fully-symmetric"
+	// ThemeEmptyBox marks puzzles with exactly one 3x3 box without givens.
+	ThemeEmptyBox Theme = "single-empty-box"
+	// ThemeDiagonalGivens marks puzzles whose givens all lie on the two main diagonals.
+	ThemeDiagonalGivens Theme = "diagonal-givens"
+)
+
+// LowClueThreshold is the clue count at or below which ThemeLowClues applies.
+const LowClueThreshold = 22
+
+// Themes returns the themes that apply to the givens of b, in the order the
+// Theme constants are declared. An empty board has no themes.
+func Themes(b Board) []Theme {
+	clues := countClues(b)
+	if clues == 0 {
+		return nil
+	}
+	given := func(r, c int) bool { return b[r][c] != 0 }
+	sym := func(f func(r, c int) (int, int)) bool {
+		for r := 0; r < 9; r++ {
+			for c := 0; c < 9; c++ {
+				r2, c2 := f(r, c)
+				if given(r, c) != given(r2, c2) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	rot180 := sym(func(r, c int) (int, int) { return 8 - r, 8 - c })
+	rot90 := sym(func(r, c int) (int, int) { return c, 8 - r })
+	mirrorV := sym(func(r, c int) (int, int) { return r, 8 - c })
+	mirrorH := sym(func(r, c int) (int, int) { return 8 - r, c })
+	diag := sym(func(r, c int) (int, int) { return c, r })
+	anti := sym(func(r, c int) (int, int) { return 8 - c, 8 - r })
+
+	var out []Theme
+	if clues <= LowClueThreshold {
+		out = append(out, ThemeLowClues)
+	}
+	if rot180 {
+		out = append(out, ThemeRotational)
+	}
+	if mirrorV || mirrorH {
+		out = append(out, ThemeMirror)
+	}
+	if diag || anti {
+		out = append(out, ThemeDiagonalSymmetry)
+	}
+	if rot90 && mirrorV {
+		// rotation by 90° plus one reflection generates the whole symmetry group
+		out = append(out, ThemeFullySymmetric)
+	}
+	emptyBoxes := 0
+	for bx := 0; bx < 9; bx++ {
+		empty := true
+		for i := 0; i < 9 && empty; i++ {
+			empty = !given((bx/3)*3+i/3, (bx%3)*3+i%3)
+		}
+		if empty {
+			emptyBoxes++
+		}
+	}
+	if emptyBoxes == 1 {
+		out = append(out, ThemeEmptyBox)
+	}
+	onDiagonals := true
+	for r := 0; r < 9 && onDiagonals; r++ {
+		for c := 0; c < 9; c++ {
+			if given(r, c) && r != c && r != 8-c {
+				onDiagonals = false
+				break
+			}
+		}
+	}
+	if onDiagonals {
+		out = append(out, ThemeDiagonalGivens)
+	}
+	return out
+}
@@ -0,0 +1,43 @@
+package sudoku
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestThemes(t *testing.T) {
+	if got := Themes(Board{}); got != nil {
+		t.Fatalf("empty board themes = %v", got)
+	}
+	// Single given in the centre: symmetric under everything, on a diagonal, low clue.
+	var centre Board
+	centre[4][4] = 5
+	got := Themes(centre)
+	want := []Theme{ThemeLowClues, ThemeRotational, ThemeMirror, ThemeDiagonalSymmetry, ThemeFullySymmetric, ThemeDiagonalGivens}
+	if !slices.Equal(got, want) {
+		t.Fatalf("centre themes = %v, want %v", got, want)
+	}
+	// Givens everywhere except box 0 (top-left): one empty box, not symmetric.
+	sol, ok := Solve(Board{})
+	if !ok {
+		t.Fatalf("solve empty board failed")
+	}
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			sol[r][c] = 0
+		}
+	}
+	got = Themes(sol)
+	if !slices.Contains(got, ThemeEmptyBox) || slices.Contains(got, ThemeRotational) || slices.Contains(got, ThemeLowClues) {
+		t.Fatalf("box-hole themes = %v", got)
+	}
+}
+
+func TestThemesClassicPuzzleIsRotational(t *testing.T) {
+	// The well-known Wikipedia puzzle has 180° rotational symmetry.
+	b, _ := FromString(easyPuzzle)
+	got := Themes(b)
+	if !slices.Contains(got, ThemeRotational) || slices.Contains(got, ThemeDiagonalGivens) {
+		t.Fatalf("themes = %v", got)
+	}
+}
@@ -0,0 +1,167 @@
+package sudoku
+
+// TraceSchemaVersion identifies the layout of Trace. It changes only when fields
+// are renamed or removed or event semantics change, so recorded traces stay comparable.
+const TraceSchemaVersion = 1
+
+// Trace event operations.
+const (
+	TraceAssign    = "assign"    // Value placed in Cell
+	TraceEliminate = "eliminate" // Value removed from Cell's candidates by an assignment
+	TraceBacktrack = "backtrack" // guess of Value in Cell undone after a contradiction
+)
+
+// Trace reasons for assignments.
+const (
+	ReasonNakedSingle  = "naked-single"
+	ReasonHiddenSingle = "hidden-single"
+	ReasonGuess        = "guess"
+)
+
+// Trace is the full decision log of SolveTrace. Puzzle and Solution use the
+// FromString format; Solution is empty when Solved is false.
+type Trace struct {
+	Schema   int          `json:"schema"`
+	Puzzle   string       `json:"puzzle"`
+	Solution string       `json:"solution,omitempty"`
+	Solved   bool         `json:"solved"`
+	Events   []TraceEvent `json:"events"`
+}
+
+// TraceEvent is one solver step. Cell is in RC notation ("r4c7") and Depth is the
+// number of open guesses when the event happened.
+type TraceEvent struct {
+	Op     string `json:"op"`
+	Cell   string `json:"cell"`
+	Value  int    `json:"value"`
+	Depth  int    `json:"depth"`
+	Reason string `json:"reason,omitempty"` // assignments only
+}
+
+// SolveTrace solves b deterministically and records every decision: forced
+// assignments (naked and hidden singles), the candidate eliminations each
+// assignment causes, guesses on the most constrained cell (lowest value first)
+// and backtracks. Givens produce no events.
+func SolveTrace(b Board) (Board, bool, *Trace) {
+	tr := &Trace{Schema: TraceSchemaVersion, Puzzle: b.String(), Events: []TraceEvent{}}
+	if Validate(b) != nil {
+		return Board{}, false, tr
+	}
+	st := traceState{b: b}
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if b[r][c] == 0 {
+				st.cand[r][c] = candidateMask(&st.b, r, c)
+			}
+		}
+	}
+	solved, ok := st.solve(tr, 0)
+	if ok {
+		tr.Solved, tr.Solution = true, solved.String()
+	}
+	return solved, ok, tr
+}
+
+// traceState is a board with the candidate mask of every empty cell.
+type traceState struct {
+	b    Board
+	cand [9][9]uint16
+}
+
+func (st *traceState) assign(tr *Trace, r, c, v, depth int, reason string) {
+	st.b[r][c], st.cand[r][c] = v, 0
+	tr.Events = append(tr.Events, TraceEvent{Op: TraceAssign, Cell: Cell{r, c}.String(), Value: v, Depth: depth, Reason: reason})
+	for _, u := range UnitsOf(Cell{r, c}) {
+		for _, p := range u.Cells() {
+			if st.cand[p.Row][p.Col]&(1<<v) != 0 {
+				st.cand[p.Row][p.Col] &^= 1 << v
+				tr.Events = append(tr.Events, TraceEvent{Op: TraceEliminate, Cell: p.String(), Value: v, Depth: depth})
+			}
+		}
+	}
+}
+
+// propagate applies singles until none remain; false means a contradiction.
+func (st *traceState) propagate(tr *Trace, depth int) bool {
+	for progress := true; progress; {
+		progress = false
+		for r := 0; r < 9; r++ {
+			for c := 0; c < 9; c++ {
+				if st.b[r][c] != 0 {
+					continue
+				}
+				m := st.cand[r][c]
+				if m == 0 {
+					return false
+				}
+				if m&(m-1) == 0 {
+					st.assign(tr, r, c, maskValue(m), depth, ReasonNakedSingle)
+					progress = true
+				}
+			}
+		}
+		for u := 0; u < 27; u++ {
+			unit := Unit{UnitKind(u / 9), u % 9}
+			for v := 1; v <= 9; v++ {
+				var at Cell
+				n, placed := 0, false
+				for _, p := range unit.Cells() {
+					if st.b[p.Row][p.Col] == v {
+						placed = true
+						break
+					}
+					if st.cand[p.Row][p.Col]&(1<<v) != 0 {
+						at, n = p, n+1
+					}
+				}
+				if placed {
+					continue
+				}
+				if n == 0 {
+					return false
+				}
+				if n == 1 {
+					st.assign(tr, at.Row, at.Col, v, depth, ReasonHiddenSingle)
+					progress = true
+				}
+			}
+		}
+	}
+	return true
+}
+
+func (st traceState) solve(tr *Trace, depth int) (Board, bool) {
+	if !st.propagate(tr, depth) {
+		return Board{}, false
+	}
+	br, bc, best := -1, -1, 10
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if st.b[r][c] != 0 {
+				continue
+			}
+			n := 0
+			for m := st.cand[r][c]; m != 0; m &= m - 1 {
+				n++
+			}
+			if n < best {
+				br, bc, best = r, c, n
+			}
+		}
+	}
+	if br < 0 {
+		return st.b, true
+	}
+	for v := 1; v <= 9; v++ {
+		if st.cand[br][bc]&(1<<v) == 0 {
+			continue
+		}
+		next := st
+		next.assign(tr, br, bc, v, depth+1, ReasonGuess)
+		if out, ok := next.solve(tr, depth+1); ok {
+			return out, true
+		}
+		tr.Events = append(tr.Events, TraceEvent{Op: TraceBacktrack, Cell: Cell{br, bc}.String(), Value: v, Depth: depth + 1})
+	}
+	return Board{}, false
+}
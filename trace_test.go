@@ -0,0 +1,65 @@
+package sudoku
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSolveTrace(t *testing.T) {
+	b, _ := FromString("009700500010900320050020108000049073000000001020080000170000000090057000002100080")
+	sol, ok, tr := SolveTrace(b)
+	if !ok || !tr.Solved || tr.Solution != sol.String() || tr.Schema != TraceSchemaVersion {
+		t.Fatalf("trace not solved: %v %+v", ok, tr.Solution)
+	}
+	if want, _ := Solve(b); want != sol {
+		t.Fatalf("trace solution differs from Solve")
+	}
+	ops := map[string]int{}
+	for _, e := range tr.Events {
+		ops[e.Op]++
+		if _, err := ParseCell(e.Cell); err != nil || e.Value < 1 || e.Value > 9 {
+			t.Fatalf("bad event %+v", e)
+		}
+	}
+	if ops[TraceAssign] < 81-countClues(b) || ops[TraceEliminate] == 0 {
+		t.Fatalf("unexpected event counts %v", ops)
+	}
+	_, _, again := SolveTrace(b)
+	if !reflect.DeepEqual(tr, again) {
+		t.Fatalf("trace is not deterministic")
+	}
+}
+
+func TestSolveTraceUnsolvable(t *testing.T) {
+	b, _ := FromString("123456780000000009" + strings.Repeat("0", 63))
+	_, ok, tr := SolveTrace(b)
+	if ok || tr.Solved || tr.Solution != "" {
+		t.Fatalf("expected unsolved trace")
+	}
+}
+
+func TestSolveTraceBacktracks(t *testing.T) {
+	// singles stall on this puzzle and one of the guesses leads to a contradiction
+	b, _ := FromString("010050000204100730000640009001006000070230090000000340300000067806300000020800000")
+	_, ok, tr := SolveTrace(b)
+	if !ok {
+		t.Fatalf("puzzle should solve")
+	}
+	guessed := map[TraceEvent]bool{}
+	backtracks := 0
+	for _, e := range tr.Events {
+		switch {
+		case e.Reason == ReasonGuess:
+			guessed[TraceEvent{Op: TraceBacktrack, Cell: e.Cell, Value: e.Value, Depth: e.Depth}] = true
+		case e.Op == TraceBacktrack:
+			if !guessed[e] {
+				t.Fatalf("backtrack without matching guess: %+v", e)
+			}
+			backtracks++
+		}
+	}
+	if backtracks == 0 {
+		t.Fatalf("expected a backtrack")
+	}
+}
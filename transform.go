@@ -0,0 +1,257 @@
+package sudoku
+
+import "fmt"
+
+// The transformations below map a puzzle to an isomorphic one: validity, the
+// number of solutions and the difficulty are all preserved. Together they generate
+// the sudoku symmetry group used to anonymise puzzles and to build canonical forms.
+
+// remapBoard builds a board whose cell r,c is taken from b at src(r, c).
+func remapBoard(b Board, src func(r, c int) (int, int)) Board {
+	var out Board
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			sr, sc := src(r, c)
+			out[r][c] = b[sr][sc]
+		}
+	}
+	return out
+}
+
+// Rotate turns b 90° clockwise.
+func Rotate(b Board) Board { return remapBoard(b, func(r, c int) (int, int) { return 8 - c, r }) }
+
+// Transpose reflects b about the main diagonal, swapping rows and columns.
+func Transpose(b Board) Board { return remapBoard(b, func(r, c int) (int, int) { return c, r }) }
+
+// ReflectHorizontal mirrors b left to right.
+func ReflectHorizontal(b Board) Board {
+	return remapBoard(b, func(r, c int) (int, int) { return r, 8 - c })
+}
+
+// ReflectVertical mirrors b top to bottom.
+func ReflectVertical(b Board) Board {
+	return remapBoard(b, func(r, c int) (int, int) { return 8 - r, c })
+}
+
+// Relabel renames the digits of b: value v becomes perm[v-1]. perm must be a
+// permutation of 1..9; empty cells stay empty.
+func Relabel(b Board, perm [9]int) (Board, error) {
+	if err := checkPermutation(perm[:]); err != nil {
+		return Board{}, err
+	}
+	out := b
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if v := b[r][c]; v >= 1 && v <= 9 {
+				out[r][c] = perm[v-1]
+			}
+		}
+	}
+	return out, nil
+}
+
+// SwapRows exchanges rows r1 and r2, which must lie in the same band of three.
+func SwapRows(b Board, r1, r2 int) (Board, error) {
+	if err := checkSwap("rows", r1, r2, 9, 3); err != nil {
+		return Board{}, err
+	}
+	b[r1], b[r2] = b[r2], b[r1]
+	return b, nil
+}
+
+// SwapColumns exchanges columns c1 and c2, which must lie in the same stack of three.
+func SwapColumns(b Board, c1, c2 int) (Board, error) {
+	if err := checkSwap("columns", c1, c2, 9, 3); err != nil {
+		return Board{}, err
+	}
+	for r := 0; r < 9; r++ {
+		b[r][c1], b[r][c2] = b[r][c2], b[r][c1]
+	}
+	return b, nil
+}
+
+// SwapBands exchanges the horizontal bands (row triples) b1 and b2, numbered 0-2.
+func SwapBands(b Board, b1, b2 int) (Board, error) {
+	if err := checkSwap("bands", b1, b2, 3, 3); err != nil {
+		return Board{}, err
+	}
+	for i := 0; i < 3; i++ {
+		b[b1*3+i], b[b2*3+i] = b[b2*3+i], b[b1*3+i]
+	}
+	return b, nil
+}
+
+// SwapStacks exchanges the vertical stacks (column triples) s1 and s2, numbered 0-2.
+func SwapStacks(b Board, s1, s2 int) (Board, error) {
+	if err := checkSwap("stacks", s1, s2, 3, 3); err != nil {
+		return Board{}, err
+	}
+	for r := 0; r < 9; r++ {
+		for i := 0; i < 3; i++ {
+			b[r][s1*3+i], b[r][s2*3+i] = b[r][s2*3+i], b[r][s1*3+i]
+		}
+	}
+	return b, nil
+}
+
+// checkSwap validates indices i, j in [0,n) that must share a group of width
+// group (pass group == n to allow any pair).
+func checkSwap(what string, i, j, n, group int) error {
+	if i < 0 || i >= n || j < 0 || j >= n {
+		return fmt.Errorf("%s %d and %d: index out of range [0,%d)", what, i, j, n)
+	}
+	if group < n && i/group != j/group {
+		return fmt.Errorf("%s %d and %d are in different groups of %d", what, i, j, group)
+	}
+	return nil
+}
+
+// checkPermutation reports whether perm holds each of 1..len(perm) once.
+func checkPermutation(perm []int) error {
+	seen := make([]bool, len(perm)+1)
+	for _, v := range perm {
+		if v < 1 || v > len(perm) || seen[v] {
+			return fmt.Errorf("relabel: %v is not a permutation of 1..%d", perm, len(perm))
+		}
+		seen[v] = true
+	}
+	return nil
+}
+
+// remapGrid is remapBoard for grids; jigsaw regions move with their cells.
+func (g Grid) remapGrid(boxRows, boxCols int, src func(r, c int) (int, int)) Grid {
+	out := g.Clone()
+	out.BoxRows, out.BoxCols = boxRows, boxCols
+	for r := 0; r < g.Size; r++ {
+		for c := 0; c < g.Size; c++ {
+			sr, sc := src(r, c)
+			out.Cells[r][c] = g.Cells[sr][sc]
+			if g.Regions != nil {
+				out.Regions[r][c] = g.Regions[sr][sc]
+			}
+		}
+	}
+	return out
+}
+
+// The Grid transformations mirror the Board ones. Rotations and reflections keep
+// jigsaw regions and the built-in variant constraints, which are symmetric under
+// them; rotating or transposing a grid with non-square boxes swaps BoxRows and
+// BoxCols. Row, column, band and stack swaps need plain boxes and no extra
+// constraints, and return an error otherwise.
+
+// Rotate turns g 90° clockwise.
+func (g Grid) Rotate() Grid {
+	n := g.Size - 1
+	return g.remapGrid(g.BoxCols, g.BoxRows, func(r, c int) (int, int) { return n - c, r })
+}
+
+// Transpose reflects g about the main diagonal.
+func (g Grid) Transpose() Grid {
+	return g.remapGrid(g.BoxCols, g.BoxRows, func(r, c int) (int, int) { return c, r })
+}
+
+// ReflectHorizontal mirrors g left to right.
+func (g Grid) ReflectHorizontal() Grid {
+	n := g.Size - 1
+	return g.remapGrid(g.BoxRows, g.BoxCols, func(r, c int) (int, int) { return r, n - c })
+}
+
+// ReflectVertical mirrors g top to bottom.
+func (g Grid) ReflectVertical() Grid {
+	n := g.Size - 1
+	return g.remapGrid(g.BoxRows, g.BoxCols, func(r, c int) (int, int) { return n - r, c })
+}
+
+// Relabel renames the values of g: v becomes perm[v-1], where perm is a
+// permutation of 1..Size.
+func (g Grid) Relabel(perm []int) (Grid, error) {
+	if len(perm) != g.Size {
+		return Grid{}, fmt.Errorf("relabel: need %d values, got %d", g.Size, len(perm))
+	}
+	if err := checkPermutation(perm); err != nil {
+		return Grid{}, err
+	}
+	out := g.Clone()
+	for r := 0; r < g.Size; r++ {
+		for c := 0; c < g.Size; c++ {
+			if v := g.Cells[r][c]; v >= 1 && v <= g.Size {
+				out.Cells[r][c] = perm[v-1]
+			}
+		}
+	}
+	return out, nil
+}
+
+// permutable reports whether rows/columns of g may be permuted within bands.
+func (g Grid) permutable() error {
+	if g.Regions != nil || len(g.Constraints) > 0 {
+		return fmt.Errorf("row and column permutations need plain boxes without extra constraints")
+	}
+	return nil
+}
+
+// SwapRows exchanges rows r1 and r2, which must lie in the same band of BoxRows.
+func (g Grid) SwapRows(r1, r2 int) (Grid, error) {
+	if err := g.permutable(); err != nil {
+		return Grid{}, err
+	}
+	if err := checkSwap("rows", r1, r2, g.Size, g.BoxRows); err != nil {
+		return Grid{}, err
+	}
+	out := g.Clone()
+	out.Cells[r1], out.Cells[r2] = out.Cells[r2], out.Cells[r1]
+	return out, nil
+}
+
+// SwapColumns exchanges columns c1 and c2, which must lie in the same stack of BoxCols.
+func (g Grid) SwapColumns(c1, c2 int) (Grid, error) {
+	if err := g.permutable(); err != nil {
+		return Grid{}, err
+	}
+	if err := checkSwap("columns", c1, c2, g.Size, g.BoxCols); err != nil {
+		return Grid{}, err
+	}
+	out := g.Clone()
+	for r := 0; r < g.Size; r++ {
+		out.Cells[r][c1], out.Cells[r][c2] = out.Cells[r][c2], out.Cells[r][c1]
+	}
+	return out, nil
+}
+
+// SwapBands exchanges the bands (groups of BoxRows rows) b1 and b2.
+func (g Grid) SwapBands(b1, b2 int) (Grid, error) {
+	if err := g.permutable(); err != nil {
+		return Grid{}, err
+	}
+	bands := g.Size / g.BoxRows
+	if err := checkSwap("bands", b1, b2, bands, bands); err != nil {
+		return Grid{}, err
+	}
+	out := g.Clone()
+	for i := 0; i < g.BoxRows; i++ {
+		x, y := b1*g.BoxRows+i, b2*g.BoxRows+i
+		out.Cells[x], out.Cells[y] = out.Cells[y], out.Cells[x]
+	}
+	return out, nil
+}
+
+// SwapStacks exchanges the stacks (groups of BoxCols columns) s1 and s2.
+func (g Grid) SwapStacks(s1, s2 int) (Grid, error) {
+	if err := g.permutable(); err != nil {
+		return Grid{}, err
+	}
+	stacks := g.Size / g.BoxCols
+	if err := checkSwap("stacks", s1, s2, stacks, stacks); err != nil {
+		return Grid{}, err
+	}
+	out := g.Clone()
+	for r := 0; r < g.Size; r++ {
+		for i := 0; i < g.BoxCols; i++ {
+			x, y := s1*g.BoxCols+i, s2*g.BoxCols+i
+			out.Cells[r][x], out.Cells[r][y] = out.Cells[r][y], out.Cells[r][x]
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,86 @@
+package sudoku
+
+import "testing"
+
+func TestBoardTransformsPreservePuzzle(t *testing.T) {
+	b, _ := FromString(easyPuzzle)
+	swapped := func(f func(Board, int, int) (Board, error), i, j int) func(Board) Board {
+		return func(b Board) Board {
+			out, err := f(b, i, j)
+			if err != nil {
+				t.Fatalf("swap %d,%d: %v", i, j, err)
+			}
+			return out
+		}
+	}
+	relabeled := func(b Board) Board {
+		out, err := Relabel(b, [9]int{9, 8, 7, 6, 5, 4, 3, 2, 1})
+		if err != nil {
+			t.Fatalf("relabel: %v", err)
+		}
+		return out
+	}
+	for name, f := range map[string]func(Board) Board{
+		"rotate": Rotate, "transpose": Transpose, "reflectH": ReflectHorizontal, "reflectV": ReflectVertical,
+		"relabel": relabeled, "rows": swapped(SwapRows, 3, 5), "columns": swapped(SwapColumns, 6, 8),
+		"bands": swapped(SwapBands, 0, 2), "stacks": swapped(SwapStacks, 1, 2),
+	} {
+		out := f(b)
+		if countClues(out) != countClues(b) || Validate(out) != nil || CountSolutions(out, 2) != 1 {
+			t.Fatalf("%s broke the puzzle:\n%s", name, out)
+		}
+		if out == b {
+			t.Fatalf("%s left the board unchanged", name)
+		}
+	}
+	if Rotate(Rotate(Rotate(Rotate(b)))) != b || Transpose(Transpose(b)) != b {
+		t.Fatalf("rotations or transposition are not involutive")
+	}
+	if ReflectHorizontal(Transpose(b)) != Rotate(b) {
+		t.Fatalf("transpose then mirror should equal a clockwise rotation")
+	}
+}
+
+func TestBoardTransformErrors(t *testing.T) {
+	b, _ := FromString(easyPuzzle)
+	if _, err := SwapRows(b, 2, 3); err == nil {
+		t.Fatalf("rows in different bands swapped")
+	}
+	if _, err := SwapStacks(b, 0, 3); err == nil {
+		t.Fatalf("out of range stack swapped")
+	}
+	if _, err := Relabel(b, [9]int{1, 1, 3, 4, 5, 6, 7, 8, 9}); err == nil {
+		t.Fatalf("non-permutation accepted")
+	}
+}
+
+func TestGridTransforms(t *testing.T) {
+	g, _ := FromStringN("156020000061500006642000000600000053", 6, 2, 3)
+	rot := g.Rotate()
+	if rot.BoxRows != 3 || rot.BoxCols != 2 || rot.Validate() != nil || rot.CountSolutions(2) != 1 {
+		t.Fatalf("rotated 6x6 is not an equivalent puzzle: %s (%dx%d boxes)", rot, rot.BoxRows, rot.BoxCols)
+	}
+	if back := rot.Rotate().Rotate().Rotate(); back.String() != g.String() || back.BoxRows != 2 {
+		t.Fatalf("four rotations did not restore the grid")
+	}
+	for name, f := range map[string]func() (Grid, error){
+		"rows":    func() (Grid, error) { return g.SwapRows(0, 1) },
+		"columns": func() (Grid, error) { return g.SwapColumns(3, 5) },
+		"bands":   func() (Grid, error) { return g.SwapBands(0, 2) },
+		"stacks":  func() (Grid, error) { return g.SwapStacks(0, 1) },
+		"relabel": func() (Grid, error) { return g.Relabel([]int{2, 3, 4, 5, 6, 1}) },
+	} {
+		out, err := f()
+		if err != nil || out.Validate() != nil || out.CountSolutions(2) != 1 {
+			t.Fatalf("%s: %v %s", name, err, out)
+		}
+	}
+	if _, err := g.SwapRows(1, 2); err == nil {
+		t.Fatalf("rows in different bands swapped")
+	}
+	x := g.Clone()
+	x.Constraints = []Constraint{DiagonalConstraint}
+	if _, err := x.SwapColumns(0, 1); err == nil {
+		t.Fatalf("column swap allowed with a diagonal constraint")
+	}
+}
@@ -0,0 +1,193 @@
+package sudoku
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Conflict is a value repeated within one row, column or box (a jigsaw region
+// on irregular grids), or within a unit an extra constraint adds. For
+// UnitConstraint it is a value the constraint rejects wherever it stands.
+type Conflict struct {
+	Kind  UnitKind
+	Index int    // 0-based row, column or box/region index
+	Value int    // the repeated value
+	Cells []Cell // every cell of the unit holding Value, in row-major order
+}
+
+func (c Conflict) String() string {
+	cells := make([]string, len(c.Cells))
+	for i, cell := range c.Cells {
+		cells[i] = cell.String()
+	}
+	verb := " repeated in "
+	if c.Kind == UnitConstraint {
+		verb = " breaks "
+	}
+	return strconv.Itoa(c.Value) + verb + Unit{c.Kind, c.Index}.String() + " (" + strings.Join(cells, ", ") + ")"
+}
+
+// ConflictError is the error Validate returns for the first Conflict found. It
+// wraps ErrInvalidBoard, so errors.Is(err, ErrInvalidBoard) keeps working; use
+// errors.As to find out where the board is wrong.
+type ConflictError struct {
+	Conflict
+}
+
+func (e *ConflictError) Error() string { return "invalid board: " + e.Conflict.String() }
+
+func (e *ConflictError) Unwrap() error { return ErrInvalidBoard }
+
+// RangeError reports a cell value outside 0..size. It wraps ErrInvalidBoard.
+type RangeError struct {
+	Cell  Cell
+	Value int
+}
+
+func (e *RangeError) Error() string {
+	return "invalid board: value " + strconv.Itoa(e.Value) + " out of range at " + e.Cell.String()
+}
+
+func (e *RangeError) Unwrap() error { return ErrInvalidBoard }
+
+// ValidateAll returns every conflict of b at once, rows first, then columns,
+// then boxes, each by index and value, so editors can highlight all bad cells in
+// one pass. Out-of-range values are skipped here; Validate reports them. It
+// returns nil for boards without duplicates.
+func ValidateAll(b Board) []Conflict {
+	var out []Conflict
+	for u := 0; u < 27; u++ {
+		out = appendBoardConflicts(out, &b, u, true)
+	}
+	return out
+}
+
+// ValidateAll is ValidateAll for grids: every repeated value in a row, column,
+// box or jigsaw region, then what each extra constraint rejects, in the order
+// of g.Constraints. Diagonals and hyper windows report as units of their own,
+// anti-knight repeats as UnitConstraint conflicts of the two cells, and other
+// constraints as UnitConstraint conflicts listing the cells they reject.
+func (g Grid) ValidateAll() []Conflict {
+	var out []Conflict
+	for _, kind := range []UnitKind{UnitRow, UnitColumn, UnitBox} {
+		out = append(out, gridConflicts(&g, kind)...)
+	}
+	for i, con := range g.Constraints {
+		if l, ok := con.(conflictLister); ok {
+			out = append(out, l.conflicts(&g)...)
+			continue
+		}
+		out = append(out, rejectedCells(&g, i, con)...)
+	}
+	return out
+}
+
+// conflictLister is implemented by constraints whose violations are repeats
+// within units of their own.
+type conflictLister interface {
+	conflicts(g *Grid) []Conflict
+}
+
+// rejectedCells returns, per value, the filled cells of g that con (entry i
+// of g.Constraints) does not allow.
+func rejectedCells(g *Grid, i int, con Constraint) []Conflict {
+	at := make([][]Cell, g.Size+1)
+	for r := 0; r < g.Size; r++ {
+		for c := 0; c < g.Size; c++ {
+			if v := g.Cells[r][c]; v > 0 && v <= g.Size && !con.Allows(g, r, c, v) {
+				at[v] = append(at[v], Cell{r, c})
+			}
+		}
+	}
+	var out []Conflict
+	for v, cells := range at {
+		if len(cells) > 0 {
+			out = append(out, Conflict{Kind: UnitConstraint, Index: i, Value: v, Cells: cells})
+		}
+	}
+	return out
+}
+
+// unitConflicts returns the repeats within units of one kind, where unit lists
+// the indexes (below units) of the units holding r,c.
+func unitConflicts(g *Grid, kind UnitKind, units int, unit func(r, c int) []int) []Conflict {
+	s := g.Size
+	at := make([][]Cell, units*(s+1)) // unit index * (s+1) + value -> cells
+	for r := 0; r < s; r++ {
+		for c := 0; c < s; c++ {
+			if v := g.Cells[r][c]; v > 0 && v <= s {
+				for _, idx := range unit(r, c) {
+					at[idx*(s+1)+v] = append(at[idx*(s+1)+v], Cell{r, c})
+				}
+			}
+		}
+	}
+	var out []Conflict
+	for i, cells := range at {
+		if len(cells) > 1 {
+			out = append(out, Conflict{Kind: kind, Index: i / (s + 1), Value: i % (s + 1), Cells: cells})
+		}
+	}
+	return out
+}
+
+// appendBoardConflicts appends the conflicts in unit u of b (unitCells
+// numbering), lowest value first, stopping after the first one unless all.
+func appendBoardConflicts(out []Conflict, b *Board, u int, all bool) []Conflict {
+	cells := unitCells(u)
+	var count [10]int
+	for _, rc := range cells {
+		if v := b[rc[0]][rc[1]]; v > 0 && v <= 9 {
+			count[v]++
+		}
+	}
+	for v := 1; v <= 9; v++ {
+		if count[v] < 2 {
+			continue
+		}
+		c := Conflict{Kind: UnitKind(u / 9), Index: u % 9, Value: v, Cells: make([]Cell, 0, count[v])}
+		for _, rc := range cells {
+			if b[rc[0]][rc[1]] == v {
+				c.Cells = append(c.Cells, Cell{rc[0], rc[1]})
+			}
+		}
+		out = append(out, c)
+		if !all {
+			break
+		}
+	}
+	return out
+}
+
+// gridConflicts returns the conflicts among g's units of the given kind, by
+// unit index and then value.
+func gridConflicts(g *Grid, kind UnitKind) []Conflict {
+	s := g.Size
+	at := make([][]Cell, s*(s+1)) // unit index * (s+1) + value -> cells
+	for r := 0; r < s; r++ {
+		for c := 0; c < s; c++ {
+			v := g.Cells[r][c]
+			if v <= 0 || v > s {
+				continue
+			}
+			idx := r
+			switch kind {
+			case UnitColumn:
+				idx = c
+			case UnitBox:
+				idx = g.regionOf(r, c)
+			}
+			at[idx*(s+1)+v] = append(at[idx*(s+1)+v], Cell{r, c})
+		}
+	}
+	var out []Conflict
+	for i, cells := range at {
+		if len(cells) > 1 {
+			out = append(out, Conflict{Kind: kind, Index: i / (s + 1), Value: i % (s + 1), Cells: cells})
+		}
+	}
+	return out
+}
+
+// gridConflict returns the first of gridConflicts as an error, or nil.
+func gridConflict(g *Grid, kind UnitKind) error { return firstConflict(gridConflicts(g, kind)) }
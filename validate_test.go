@@ -0,0 +1,117 @@
+package sudoku
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateConflictError(t *testing.T) {
+	b, _ := FromString(easyPuzzle)
+	for _, tc := range []struct {
+		r, c, v int
+		want    Conflict
+	}{
+		{4, 1, 3, Conflict{Kind: UnitRow, Index: 4, Value: 3, Cells: []Cell{{4, 1}, {4, 5}}}},
+		{8, 1, 3, Conflict{Kind: UnitColumn, Index: 1, Value: 3, Cells: []Cell{{0, 1}, {8, 1}}}},
+		{1, 1, 8, Conflict{Kind: UnitBox, Index: 0, Value: 8, Cells: []Cell{{1, 1}, {2, 2}}}},
+	} {
+		bad := b
+		bad[tc.r][tc.c] = tc.v
+		err := Validate(bad)
+		var ce *ConflictError
+		if !errors.Is(err, ErrInvalidBoard) || !errors.As(err, &ce) {
+			t.Fatalf("%v: not a wrapped conflict", err)
+		}
+		if !reflect.DeepEqual(ce.Conflict, tc.want) {
+			t.Fatalf("got %+v, want %+v", *ce, tc.want)
+		}
+	}
+	bad := b
+	bad[4][1] = 3
+	if got, want := Validate(bad).Error(), "invalid board: 3 repeated in row 5 (r5c2, r5c6)"; got != want {
+		t.Fatalf("message %q, want %q", got, want)
+	}
+}
+
+func TestValidateRangeError(t *testing.T) {
+	var b Board
+	b[2][7] = 10
+	var re *RangeError
+	if err := Validate(b); !errors.Is(err, ErrInvalidBoard) || !errors.As(err, &re) || re.Cell != (Cell{2, 7}) || re.Value != 10 {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestGridValidateConflictError(t *testing.T) {
+	g, _ := NewGrid(6, 2, 3)
+	g.Cells[1][0], g.Cells[0][2] = 4, 4 // same 2x3 box, different row and column
+	var ce *ConflictError
+	if err := g.Validate(); !errors.As(err, &ce) || ce.Kind != UnitBox || ce.Index != 0 ||
+		!reflect.DeepEqual(ce.Cells, []Cell{{0, 2}, {1, 0}}) {
+		t.Fatalf("got %v", err)
+	}
+	g.Cells[1][0] = 7
+	var re *RangeError
+	if err := g.Validate(); !errors.As(err, &re) || re.Cell != (Cell{1, 0}) {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	b, _ := FromString(easyPuzzle)
+	if ValidateAll(b) != nil {
+		t.Fatalf("valid board has conflicts")
+	}
+	b[4][1] = 3 // row 5 and column 2
+	b[1][1] = 8 // box 1
+	got := ValidateAll(b)
+	want := []Conflict{
+		{Kind: UnitRow, Index: 4, Value: 3, Cells: []Cell{{4, 1}, {4, 5}}},
+		{Kind: UnitColumn, Index: 1, Value: 3, Cells: []Cell{{0, 1}, {4, 1}}},
+		{Kind: UnitBox, Index: 0, Value: 8, Cells: []Cell{{1, 1}, {2, 2}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v\nwant %v", got, want)
+	}
+	var first *ConflictError
+	if !errors.As(Validate(b), &first) || !reflect.DeepEqual(first.Conflict, want[0]) {
+		t.Fatalf("Validate should report the first of ValidateAll")
+	}
+
+	g, _ := NewGrid(4, 2, 2)
+	g.Cells[0][0], g.Cells[0][3], g.Cells[3][0] = 2, 2, 2
+	if cs := g.ValidateAll(); len(cs) != 2 || cs[0].Kind != UnitRow || cs[1].Kind != UnitColumn {
+		t.Fatalf("grid conflicts %v", cs)
+	}
+}
+
+func TestGridValidateAllConstraints(t *testing.T) {
+	g, _ := NewGrid(9, 3, 3)
+	g.Constraints = []Constraint{DiagonalConstraint, HyperConstraint, AntiKnightConstraint}
+	g.Cells[0][0], g.Cells[8][8] = 5, 5 // main diagonal
+	g.Cells[1][1], g.Cells[3][3] = 4, 4 // top-left window, main diagonal again
+	g.Cells[5][6], g.Cells[6][4] = 7, 7 // a knight's move apart
+	want := []Conflict{
+		{Kind: UnitDiagonal, Index: 0, Value: 4, Cells: []Cell{{1, 1}, {3, 3}}},
+		{Kind: UnitDiagonal, Index: 0, Value: 5, Cells: []Cell{{0, 0}, {8, 8}}},
+		{Kind: UnitWindow, Index: 0, Value: 4, Cells: []Cell{{1, 1}, {3, 3}}},
+		{Kind: UnitConstraint, Index: 2, Value: 7, Cells: []Cell{{5, 6}, {6, 4}}},
+	}
+	if got := g.ValidateAll(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v\nwant %v", got, want)
+	}
+	var ce *ConflictError
+	if err := g.Validate(); !errors.As(err, &ce) || !reflect.DeepEqual(ce.Conflict, want[0]) {
+		t.Fatalf("Validate = %v", err)
+	}
+	if got := want[3].String(); got != "7 breaks constraint 3 (r6c7, r7c5)" {
+		t.Fatalf("String = %q", got)
+	}
+	if cells := (Unit{UnitWindow, 3}).Cells(); cells[0] != (Cell{5, 5}) || cells[8] != (Cell{7, 7}) {
+		t.Fatalf("window cells %v", cells)
+	}
+	if cells := (Unit{UnitDiagonal, 1}).Cells(); cells[0] != (Cell{0, 8}) || cells[8] != (Cell{8, 0}) {
+		t.Fatalf("anti-diagonal cells %v", cells)
+	}
+}